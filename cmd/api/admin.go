@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/WessleyAI/wessley-mvp/engine/graph"
+	"github.com/WessleyAI/wessley-mvp/engine/ingest"
+)
+
+// RetractRequest is the JSON body for POST /api/v1/admin/retract. Either
+// doc_ids or a source / url_pattern filter selects the documents.
+type RetractRequest struct {
+	DocIDs     []string `json:"doc_ids,omitempty"`
+	Source     string   `json:"source,omitempty"`      // "reddit" also matches "reddit:<sub>"
+	URLPattern string   `json:"url_pattern,omitempty"` // glob, e.g. "https://example.com/forum/*"
+	Reason     string   `json:"reason"`
+	DryRun     bool     `json:"dry_run,omitempty"`
+}
+
+// RetractResponse is the JSON response for POST /api/v1/admin/retract.
+type RetractResponse struct {
+	DryRun    bool                   `json:"dry_run,omitempty"`
+	Retracted int                    `json:"retracted"`
+	Failed    int                    `json:"failed"`
+	Documents []ingest.RetractResult `json:"documents"`
+}
+
+func handleRetract(r *ingest.Retractor, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		var body RetractRequest
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			http.Error(w, `{"error":"invalid request body"}`, http.StatusBadRequest)
+			return
+		}
+		if len(body.DocIDs) == 0 && body.Source == "" && body.URLPattern == "" {
+			http.Error(w, `{"error":"doc_ids, source or url_pattern is required"}`, http.StatusBadRequest)
+			return
+		}
+		if body.Reason == "" {
+			http.Error(w, `{"error":"reason is required"}`, http.StatusBadRequest)
+			return
+		}
+
+		var results []ingest.RetractResult
+		for _, id := range body.DocIDs {
+			if body.DryRun {
+				results = append(results, ingest.RetractResult{DocID: id})
+				continue
+			}
+			results = append(results, r.Retract(req.Context(), id, body.Reason))
+		}
+		if body.Source != "" || body.URLPattern != "" {
+			filter := graph.DocumentFilter{Source: body.Source, URLPattern: body.URLPattern}
+			matched, err := r.RetractWhere(req.Context(), filter, body.Reason, body.DryRun)
+			results = append(results, matched...)
+			if err != nil {
+				logger.Error("bulk retract failed", "source", body.Source, "url_pattern", body.URLPattern, "err", err)
+				http.Error(w, fmt.Sprintf(`{"error":%q}`, err.Error()), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		resp := RetractResponse{DryRun: body.DryRun, Documents: results}
+		for _, res := range results {
+			if res.Error != "" {
+				resp.Failed++
+			} else if !body.DryRun {
+				resp.Retracted++
+			}
+		}
+		logger.Info("retract request", "reason", body.Reason, "dry_run", body.DryRun, "retracted", resp.Retracted, "failed", resp.Failed)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func handleTombstones(gs *graph.GraphStore, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tombstones, err := gs.Tombstones(r.Context())
+		if err != nil {
+			logger.Error("list tombstones failed", "err", err)
+			http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+		if tombstones == nil {
+			tombstones = []graph.Tombstone{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(tombstones)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/WessleyAI/wessley-mvp/engine/dtc"
+	"github.com/WessleyAI/wessley-mvp/engine/graph"
+)
+
+// DTCResponse is the JSON response for GET /api/v1/dtc/{code}.
+type DTCResponse struct {
+	graph.DTC
+	Vehicle   string              `json:"vehicle,omitempty"`
+	Causes    []graph.DTCCause    `json:"causes"`
+	Documents []graph.DTCDocument `json:"documents"`
+}
+
+// handleDTC describes a trouble code as GET /api/v1/dtc/P0420, with its
+// likely causes ranked by how many documents blame them and the best
+// documents mentioning it. ?vehicle=ford-f-150-2018 narrows causes and
+// documents to one vehicle; ?limit= caps both (default 5).
+func handleDTC(gs *graph.GraphStore, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		code, ok := dtc.Lookup(r.PathValue("code"))
+		if !ok {
+			http.Error(w, `{"error":"invalid trouble code"}`, http.StatusBadRequest)
+			return
+		}
+		vehicle := strings.ToLower(r.URL.Query().Get("vehicle"))
+		limit := 5
+		if s := r.URL.Query().Get("limit"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n <= 0 || n > 50 {
+				http.Error(w, `{"error":"limit must be 1-50"}`, http.StatusBadRequest)
+				return
+			}
+			limit = n
+		}
+
+		report, err := gs.DTCReport(r.Context(), code.Code, vehicle, limit)
+		if err != nil {
+			logger.Error("dtc report", "code", code.Code, "vehicle", vehicle, "err", err)
+			http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+
+		resp := DTCResponse{DTC: report.DTC, Vehicle: vehicle, Causes: report.Causes, Documents: report.Documents}
+		if !report.Found || resp.Description == "" {
+			resp.DTC = graph.DTC(code)
+		}
+		if resp.Causes == nil {
+			resp.Causes = []graph.DTCCause{}
+		}
+		if resp.Documents == nil {
+			resp.Documents = []graph.DTCDocument{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// seedDTCs loads the bundled generic J2012 descriptions into the graph.
+func seedDTCs(ctx context.Context, gs *graph.GraphStore, logger *slog.Logger) {
+	codes := dtc.All()
+	defs := make([]graph.DTC, len(codes))
+	for i, c := range codes {
+		defs[i] = graph.DTC(c)
+	}
+	if err := gs.SeedDTCs(ctx, defs); err != nil {
+		logger.Warn("dtc seed failed", "err", err)
+		return
+	}
+	logger.Info("dtc seed complete", "codes", len(defs))
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/WessleyAI/wessley-mvp/engine/graph"
+)
+
+func TestHandleDTC(t *testing.T) {
+	sess := &mockCypherSessionFunc{runFn: func(_ context.Context, cypher string, params map[string]any) (graph.CypherResult, error) {
+		if params["code"] != "P0420" || params["myID"] != nil && params["myID"] != "ford-f-150-2018" {
+			t.Errorf("unexpected params %v", params)
+		}
+		switch {
+		case strings.Contains(cypher, "CAUSED_BY"):
+			return &mockCypherResult{records: []mockRecord{
+				{keys: []string{"component", "evidence"}, values: []any{"Oxygen sensor", int64(3)}},
+			}}, nil
+		case strings.Contains(cypher, "MENTIONS"):
+			return &mockCypherResult{records: []mockRecord{
+				{keys: []string{"id", "title", "source", "url", "quality"}, values: []any{"reddit:abc", "P0420 fixed", "reddit", "https://reddit.com/abc", 0.9}},
+			}}, nil
+		}
+		return &mockCypherResult{}, nil // no DTC node yet
+	}}
+	gs := graph.NewWithOpener(&mockOpener{session: sess})
+
+	req := httptest.NewRequest("GET", "/api/v1/dtc/p0420?vehicle=Ford-F-150-2018", nil)
+	req.SetPathValue("code", "p0420")
+	rec := httptest.NewRecorder()
+	handleDTC(gs, slog.Default())(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var resp DTCResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Code != "P0420" || resp.Description == "" || resp.System != "Exhaust" || resp.Vehicle != "ford-f-150-2018" {
+		t.Errorf("unexpected code fields %+v", resp)
+	}
+	if len(resp.Causes) != 1 || resp.Causes[0].Evidence != 3 || len(resp.Documents) != 1 || resp.Documents[0].ID != "reddit:abc" {
+		t.Errorf("unexpected causes/documents %+v", resp)
+	}
+}
+
+func TestHandleDTC_BadRequest(t *testing.T) {
+	gs := graph.NewWithOpener(&mockOpener{session: &mockCypherSession{}})
+	for _, target := range []string{"/api/v1/dtc/P04", "/api/v1/dtc/P0420?limit=0"} {
+		req := httptest.NewRequest("GET", target, nil)
+		req.SetPathValue("code", strings.TrimPrefix(strings.SplitN(target, "?", 2)[0], "/api/v1/dtc/"))
+		rec := httptest.NewRecorder()
+		handleDTC(gs, slog.Default())(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", target, rec.Code)
+		}
+	}
+}
+
+func TestHandleDTC_Error(t *testing.T) {
+	gs := graph.NewWithOpener(&mockOpener{session: &mockCypherSession{err: errors.New("down")}})
+	req := httptest.NewRequest("GET", "/api/v1/dtc/P0300", nil)
+	req.SetPathValue("code", "P0300")
+	rec := httptest.NewRecorder()
+	handleDTC(gs, slog.Default())(rec, req)
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,416 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/WessleyAI/wessley-mvp/engine/ingest"
+	"github.com/WessleyAI/wessley-mvp/engine/manual"
+	"github.com/WessleyAI/wessley-mvp/engine/scraper"
+	"github.com/WessleyAI/wessley-mvp/pkg/fn"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxIngestUpload bounds the request body of POST /api/v1/ingest.
+	maxIngestUpload = 64 << 20
+	// ingestQueueSize is the number of jobs that may wait for the worker.
+	ingestQueueSize = 32
+	// maxIngestJobs is how many jobs are remembered; the oldest finished ones are dropped.
+	maxIngestJobs = 500
+	// uploadIDPrefix namespaces the source IDs of uploaded documents, so an
+	// upload never overwrites a scraped document's vectors or graph nodes.
+	uploadIDPrefix = "upload-"
+)
+
+// Ingest job and document states.
+const (
+	jobQueued  = "queued"
+	jobRunning = "running"
+	jobDone    = "done"
+	jobFailed  = "failed" // every document failed
+
+	docPending  = "pending"
+	docIngested = "ingested"
+	docDropped  = "dropped" // below the quality threshold, or retracted
+	docFailed   = "failed"
+)
+
+// IngestJob is the JSON response for GET /api/v1/ingest/jobs/{id}.
+type IngestJob struct {
+	ID         string            `json:"id"`
+	Status     string            `json:"status"`
+	CreatedAt  time.Time         `json:"created_at"`
+	StartedAt  *time.Time        `json:"started_at,omitempty"`
+	FinishedAt *time.Time        `json:"finished_at,omitempty"`
+	Total      int               `json:"total"`
+	Ingested   int               `json:"ingested"`
+	Dropped    int               `json:"dropped"`
+	Failed     int               `json:"failed"`
+	Documents  []IngestDocStatus `json:"documents"`
+
+	posts []scraper.ScrapedPost
+}
+
+// IngestDocStatus reports one document of a job.
+type IngestDocStatus struct {
+	DocID  string `json:"doc_id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+	Stage  string `json:"stage,omitempty"` // pipeline stage reached, see ingest.NewPipeline
+	Chunks int    `json:"chunks"`
+	Error  string `json:"error,omitempty"`
+}
+
+// IngestAccepted is the JSON response for POST /api/v1/ingest.
+type IngestAccepted struct {
+	JobID     string `json:"job_id"`
+	Status    string `json:"status"`
+	Documents int    `json:"documents"`
+}
+
+var errQueueFull = errors.New("ingest queue full")
+
+// ingestJobs runs uploaded documents through the ingestion pipeline one job
+// at a time and keeps their status in memory.
+type ingestJobs struct {
+	pipeline fn.Stage[scraper.ScrapedPost, string]
+	queue    chan *IngestJob
+	logger   *slog.Logger
+
+	mu    sync.Mutex
+	jobs  map[string]*IngestJob
+	order []string // job IDs, oldest first
+}
+
+func newIngestJobs(pipeline fn.Stage[scraper.ScrapedPost, string], logger *slog.Logger) *ingestJobs {
+	return &ingestJobs{
+		pipeline: pipeline,
+		queue:    make(chan *IngestJob, ingestQueueSize),
+		logger:   logger,
+		jobs:     make(map[string]*IngestJob),
+	}
+}
+
+// enqueue registers a job for posts and hands it to the worker.
+func (j *ingestJobs) enqueue(posts []scraper.ScrapedPost) (*IngestJob, error) {
+	job := &IngestJob{
+		ID:        uuid.NewString(),
+		Status:    jobQueued,
+		CreatedAt: time.Now().UTC(),
+		Total:     len(posts),
+		Documents: make([]IngestDocStatus, len(posts)),
+		posts:     posts,
+	}
+	for i, p := range posts {
+		job.Documents[i] = IngestDocStatus{DocID: p.Source + ":" + p.SourceID, Title: p.Title, Status: docPending}
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	select {
+	case j.queue <- job:
+	default:
+		return nil, errQueueFull
+	}
+	j.jobs[job.ID] = job
+	j.order = append(j.order, job.ID)
+	j.evict()
+	return job, nil
+}
+
+// evict drops the oldest finished jobs beyond maxIngestJobs. Callers hold mu.
+func (j *ingestJobs) evict() {
+	for i := 0; len(j.jobs) > maxIngestJobs && i < len(j.order); {
+		if job := j.jobs[j.order[i]]; job.FinishedAt != nil {
+			delete(j.jobs, job.ID)
+			j.order = append(j.order[:i], j.order[i+1:]...)
+			continue
+		}
+		i++
+	}
+}
+
+// get returns a copy of the job with the given ID.
+func (j *ingestJobs) get(id string) (IngestJob, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	job, ok := j.jobs[id]
+	if !ok {
+		return IngestJob{}, false
+	}
+	out := *job
+	out.Documents = append([]IngestDocStatus(nil), job.Documents...)
+	out.posts = nil
+	return out, true
+}
+
+// run processes queued jobs until ctx is cancelled.
+func (j *ingestJobs) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-j.queue:
+			j.process(ctx, job)
+		}
+	}
+}
+
+func (j *ingestJobs) process(ctx context.Context, job *IngestJob) {
+	j.mu.Lock()
+	now := time.Now().UTC()
+	job.Status = jobRunning
+	job.StartedAt = &now
+	j.mu.Unlock()
+
+	for i, post := range job.posts {
+		rep := ingest.RunReported(ctx, j.pipeline, post)
+
+		j.mu.Lock()
+		doc := &job.Documents[i]
+		doc.DocID = rep.DocID
+		doc.Stage = rep.Stage
+		doc.Chunks = rep.Chunks
+		switch {
+		case errors.Is(rep.Err, ingest.ErrLowQuality), errors.Is(rep.Err, ingest.ErrRetracted):
+			doc.Status = docDropped
+			doc.Error = rep.Err.Error()
+			job.Dropped++
+		case rep.Err != nil:
+			doc.Status = docFailed
+			doc.Error = rep.Err.Error()
+			job.Failed++
+		default:
+			doc.Status = docIngested
+			job.Ingested++
+		}
+		j.mu.Unlock()
+
+		if doc.Status == docFailed {
+			j.logger.Warn("ingest document failed", "job", job.ID, "doc_id", rep.DocID, "stage", rep.Stage, "err", rep.Err)
+		}
+	}
+
+	j.mu.Lock()
+	done := time.Now().UTC()
+	job.FinishedAt = &done
+	job.Status = jobDone
+	if job.Failed == job.Total {
+		job.Status = jobFailed
+	}
+	job.posts = nil
+	j.mu.Unlock()
+	j.logger.Info("ingest job finished", "job", job.ID, "ingested", job.Ingested, "dropped", job.Dropped, "failed", job.Failed)
+}
+
+// --- Ingest Handlers ---
+
+// handleIngest accepts ScrapedPost JSON (a single object, an array or JSONL)
+// or PDF manuals, either as the raw body or as multipart "file" parts. PDFs
+// are split into section posts by manual.PostsFromPDF; the make, model and
+// year query or form fields override the vehicle tagged from the file.
+//
+// Only documents of the given sources are accepted ("forum" allows every
+// "forum:<name>"), and their source IDs are prefixed with "upload-".
+func handleIngest(jobs *ingestJobs, sources []string, logger *slog.Logger) http.HandlerFunc {
+	allowed := make(map[string]bool, len(sources))
+	for _, src := range sources {
+		if src = strings.TrimSpace(src); src != "" {
+			allowed[src] = true
+		}
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxIngestUpload)
+
+		mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		var (
+			posts []scraper.ScrapedPost
+			err   error
+		)
+		switch mediaType {
+		case "application/json", "application/x-ndjson", "application/jsonl":
+			posts, err = decodePosts(r.Body)
+		case "application/pdf":
+			posts, err = readPDF(r.Body, r.URL.Query().Get("filename"), vehicleFromForm(r.URL.Query().Get))
+		case "multipart/form-data":
+			posts, err = readMultipart(multipart.NewReader(r.Body, params["boundary"]), r.URL.Query().Get)
+		default:
+			http.Error(w, `{"error":"unsupported content type"}`, http.StatusUnsupportedMediaType)
+			return
+		}
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, `{"error":"upload too large"}`, http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, fmt.Sprintf(`{"error":%q}`, err.Error()), http.StatusBadRequest)
+			return
+		}
+		if len(posts) == 0 {
+			http.Error(w, `{"error":"no documents"}`, http.StatusBadRequest)
+			return
+		}
+		for i := range posts {
+			base, _, _ := strings.Cut(posts[i].Source, ":")
+			if !allowed[posts[i].Source] && !allowed[base] {
+				http.Error(w, fmt.Sprintf(`{"error":%q}`, "source not allowed: "+posts[i].Source), http.StatusForbidden)
+				return
+			}
+			if id := posts[i].SourceID; id != "" && !strings.HasPrefix(id, uploadIDPrefix) {
+				posts[i].SourceID = uploadIDPrefix + posts[i].SourceID
+			}
+		}
+
+		job, err := jobs.enqueue(posts)
+		if err != nil {
+			logger.Warn("ingest enqueue", "err", err)
+			http.Error(w, `{"error":"ingest queue full"}`, http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/api/v1/ingest/jobs/"+job.ID)
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(IngestAccepted{JobID: job.ID, Status: jobQueued, Documents: len(posts)})
+	}
+}
+
+func handleIngestJob(jobs *ingestJobs) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		job, ok := jobs.get(r.PathValue("id"))
+		if !ok {
+			http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(job)
+	}
+}
+
+// decodePosts reads a stream of JSON values, each a ScrapedPost or an array of them.
+func decodePosts(r io.Reader) ([]scraper.ScrapedPost, error) {
+	var posts []scraper.ScrapedPost
+	dec := json.NewDecoder(r)
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err == io.EOF {
+			return posts, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid json: %w", err)
+		}
+		if strings.HasPrefix(strings.TrimSpace(string(raw)), "[") {
+			var batch []scraper.ScrapedPost
+			if err := json.Unmarshal(raw, &batch); err != nil {
+				return nil, fmt.Errorf("invalid json: %w", err)
+			}
+			posts = append(posts, batch...)
+			continue
+		}
+		var post scraper.ScrapedPost
+		if err := json.Unmarshal(raw, &post); err != nil {
+			return nil, fmt.Errorf("invalid json: %w", err)
+		}
+		posts = append(posts, post)
+	}
+}
+
+func readPDF(r io.Reader, filename string, vi *scraper.VehicleInfo) ([]scraper.ScrapedPost, error) {
+	if filename == "" {
+		filename = "upload.pdf"
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	posts, err := manual.PostsFromPDF(data, filepath.Base(filename), "upload://"+filepath.Base(filename), vi)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return posts, nil
+}
+
+// readMultipart collects the "file" parts of a multipart upload. Form fields
+// may come before or after the files, so PDFs are parsed once all parts are read.
+func readMultipart(mr *multipart.Reader, query func(string) string) ([]scraper.ScrapedPost, error) {
+	type pdfPart struct {
+		name string
+		data []byte
+	}
+	var (
+		posts []scraper.ScrapedPost
+		pdfs  []pdfPart
+	)
+	fields := map[string]string{}
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() != "file" {
+			v, err := io.ReadAll(part)
+			if err != nil {
+				return nil, err
+			}
+			fields[part.FormName()] = string(v)
+			continue
+		}
+		ct, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if ct == "application/pdf" || strings.EqualFold(filepath.Ext(part.FileName()), ".pdf") {
+			data, err := io.ReadAll(part)
+			if err != nil {
+				return nil, err
+			}
+			pdfs = append(pdfs, pdfPart{name: part.FileName(), data: data})
+			continue
+		}
+		batch, err := decodePosts(part)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", part.FileName(), err)
+		}
+		posts = append(posts, batch...)
+	}
+
+	vi := vehicleFromForm(func(k string) string {
+		if v := fields[k]; v != "" {
+			return v
+		}
+		return query(k)
+	})
+	for _, p := range pdfs {
+		batch, err := readPDF(bytes.NewReader(p.data), p.name, vi)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, batch...)
+	}
+	return posts, nil
+}
+
+// vehicleFromForm returns the vehicle given by the make, model and year
+// fields, or nil to let manual.PostsFromPDF tag it.
+func vehicleFromForm(get func(string) string) *scraper.VehicleInfo {
+	mk := get("make")
+	if mk == "" {
+		return nil
+	}
+	year, _ := strconv.Atoi(get("year"))
+	return &scraper.VehicleInfo{Make: mk, Model: get("model"), Year: year}
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/WessleyAI/wessley-mvp/engine/ingest"
+	"github.com/WessleyAI/wessley-mvp/engine/scraper"
+	"github.com/WessleyAI/wessley-mvp/pkg/fn"
+)
+
+// fakeIngestPipeline validates like ingest.NewPipeline and fails at the embed
+// stage for posts whose title contains "fail".
+func fakeIngestPipeline() fn.Stage[scraper.ScrapedPost, string] {
+	log := slog.Default()
+	validated := fn.Then(ingest.LoggedTap[scraper.ScrapedPost]("validate", log), ingest.Validate)
+	parsed := fn.Then(validated, ingest.Parse)
+	chunked := fn.Then(parsed, fn.Then(ingest.LoggedTap[ingest.ParsedDoc]("chunk", log), ingest.ChunkDoc))
+	return fn.Then(chunked, fn.Then(ingest.LoggedTap[ingest.ChunkedDoc]("embed", log),
+		func(_ context.Context, doc ingest.ChunkedDoc) fn.Result[string] {
+			if strings.Contains(doc.Title, "fail") {
+				return fn.Err[string](errors.New("embed: ml-worker unavailable"))
+			}
+			return fn.Ok(doc.ID)
+		}))
+}
+
+// testIngestSources lets "bogus" past the allowlist so validation rejects it.
+var testIngestSources = []string{"reddit", "ifixit", "manual", "forum", "bogus"}
+
+func postIngest(t *testing.T, h http.HandlerFunc, contentType string, body []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/api/v1/ingest", bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	h(rec, req)
+	return rec
+}
+
+// waitJob polls the jobs endpoint until the job has finished.
+func waitJob(t *testing.T, jobs *ingestJobs, id string) IngestJob {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/ingest/jobs/{id}", handleIngestJob(jobs))
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/ingest/jobs/"+id, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rec.Code)
+		}
+		var job IngestJob
+		if err := json.NewDecoder(rec.Body).Decode(&job); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if job.FinishedAt != nil {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", id)
+	return IngestJob{}
+}
+
+func startIngestJobs(t *testing.T) *ingestJobs {
+	t.Helper()
+	jobs := newIngestJobs(fakeIngestPipeline(), slog.Default())
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go jobs.run(ctx)
+	return jobs
+}
+
+func TestIngest_JSONLReportsPerDocument(t *testing.T) {
+	jobs := startIngestJobs(t)
+	body := `{"source":"reddit","source_id":"a1","title":"Battery drain","content":"The battery dies overnight. The alternator tested fine."}
+{"source":"reddit","source_id":"a2","title":"should fail","content":"Rough idle after rain."}
+{"source":"bogus","source_id":"a3","title":"Unknown source","content":"text"}
+`
+	rec := postIngest(t, handleIngest(jobs, testIngestSources, slog.Default()), "application/x-ndjson", []byte(body))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body)
+	}
+	var accepted IngestAccepted
+	json.NewDecoder(rec.Body).Decode(&accepted)
+	if accepted.JobID == "" || accepted.Documents != 3 {
+		t.Fatalf("unexpected response %+v", accepted)
+	}
+
+	job := waitJob(t, jobs, accepted.JobID)
+	if job.Status != jobDone || job.Ingested != 1 || job.Failed != 2 {
+		t.Fatalf("unexpected job %+v", job)
+	}
+	ok, embedFail, invalid := job.Documents[0], job.Documents[1], job.Documents[2]
+	if ok.Status != docIngested || ok.Stage != ingest.StageDone || ok.Chunks == 0 || ok.DocID != "reddit:upload-a1" {
+		t.Errorf("unexpected ingested doc %+v", ok)
+	}
+	if embedFail.Status != docFailed || embedFail.Stage != "embed" || embedFail.Chunks == 0 || embedFail.Error == "" {
+		t.Errorf("unexpected embed failure %+v", embedFail)
+	}
+	if invalid.Status != docFailed || invalid.Stage != "validate" || !strings.Contains(invalid.Error, "unknown source") {
+		t.Errorf("unexpected validation failure %+v", invalid)
+	}
+}
+
+func TestIngest_JSONArray(t *testing.T) {
+	jobs := startIngestJobs(t)
+	body := `[{"source":"ifixit","source_id":"g1","title":"Replace fuse","content":"Pull the 15A fuse."},
+	{"source":"ifixit","source_id":"g2","title":"Replace relay","content":"Swap the starter relay."}]`
+	rec := postIngest(t, handleIngest(jobs, testIngestSources, slog.Default()), "application/json", []byte(body))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", rec.Code)
+	}
+	if !strings.HasPrefix(rec.Header().Get("Location"), "/api/v1/ingest/jobs/") {
+		t.Fatalf("missing Location header")
+	}
+	var accepted IngestAccepted
+	json.NewDecoder(rec.Body).Decode(&accepted)
+	if job := waitJob(t, jobs, accepted.JobID); job.Ingested != 2 {
+		t.Fatalf("unexpected job %+v", job)
+	}
+}
+
+func TestIngest_PDFUpload(t *testing.T) {
+	jobs := startIngestJobs(t)
+	pdf := []byte("%PDF-1.4\nBT (2020 Toyota Camry Manual Content) ET\n%%EOF")
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fw, _ := mw.CreateFormFile("file", "2020_Toyota_Camry.pdf")
+	fw.Write(pdf)
+	mw.WriteField("make", "Toyota")
+	mw.WriteField("model", "Camry")
+	mw.WriteField("year", "2021")
+	mw.Close()
+
+	rec := postIngest(t, handleIngest(jobs, testIngestSources, slog.Default()), mw.FormDataContentType(), buf.Bytes())
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body)
+	}
+	var accepted IngestAccepted
+	json.NewDecoder(rec.Body).Decode(&accepted)
+	job := waitJob(t, jobs, accepted.JobID)
+	if job.Ingested != 1 || !strings.HasPrefix(job.Documents[0].DocID, "manual:upload-2020_Toyota_Camry.pdf") {
+		t.Fatalf("unexpected job %+v", job)
+	}
+
+	rec = postIngest(t, handleIngest(jobs, testIngestSources, slog.Default()), "application/pdf", pdf)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202 for raw pdf, got %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestIngest_BadRequests(t *testing.T) {
+	jobs := newIngestJobs(fakeIngestPipeline(), slog.Default())
+	h := handleIngest(jobs, testIngestSources, slog.Default())
+
+	tests := []struct {
+		name, contentType, body string
+		want                    int
+	}{
+		{"unsupported type", "text/plain", "hello", http.StatusUnsupportedMediaType},
+		{"invalid json", "application/json", "{not json", http.StatusBadRequest},
+		{"empty body", "application/json", "", http.StatusBadRequest},
+		{"pdf without text", "application/pdf", "%PDF-1.4\n%%EOF", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := postIngest(t, h, tt.contentType, []byte(tt.body)); rec.Code != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, rec.Code)
+			}
+		})
+	}
+}
+
+func TestIngest_SourceAllowlist(t *testing.T) {
+	jobs := newIngestJobs(fakeIngestPipeline(), slog.Default())
+	h := handleIngest(jobs, []string{"manual", "forum"}, slog.Default())
+
+	body := `{"source":"forum:BITOG","source_id":"t1","title":"t","content":"c"}
+{"source":"nhtsa","source_id":"nhtsa-1","title":"t","content":"c"}`
+	if rec := postIngest(t, h, "application/x-ndjson", []byte(body)); rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), "nhtsa") {
+		t.Fatalf("expected 403 for a source outside the allowlist, got %d: %s", rec.Code, rec.Body)
+	}
+
+	rec := postIngest(t, h, "application/json", []byte(`{"source":"forum:BITOG","source_id":"upload-t1","title":"t","content":"c"}`))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body)
+	}
+	job, _ := jobs.get(decodeAccepted(t, rec).JobID)
+	if job.Documents[0].DocID != "forum:BITOG:upload-t1" {
+		t.Errorf("source ID should be namespaced once, got %q", job.Documents[0].DocID)
+	}
+}
+
+func decodeAccepted(t *testing.T, rec *httptest.ResponseRecorder) IngestAccepted {
+	t.Helper()
+	var accepted IngestAccepted
+	if err := json.NewDecoder(rec.Body).Decode(&accepted); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return accepted
+}
+
+func TestIngest_QueueFull(t *testing.T) {
+	jobs := newIngestJobs(fakeIngestPipeline(), slog.Default()) // no worker running
+	h := handleIngest(jobs, testIngestSources, slog.Default())
+	body := []byte(`{"source":"reddit","source_id":"q","title":"t","content":"c"}`)
+	for i := 0; i < ingestQueueSize; i++ {
+		if rec := postIngest(t, h, "application/json", body); rec.Code != http.StatusAccepted {
+			t.Fatalf("job %d: expected 202, got %d", i, rec.Code)
+		}
+	}
+	if rec := postIngest(t, h, "application/json", body); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rec.Code)
+	}
+}
+
+func TestIngestJob_NotFound(t *testing.T) {
+	jobs := newIngestJobs(fakeIngestPipeline(), slog.Default())
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/v1/ingest/jobs/missing", nil)
+	req.SetPathValue("id", "missing")
+	handleIngestJob(jobs)(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestHandleRetract_Validation(t *testing.T) {
+	r := ingest.NewRetractor(nil, nil, slog.Default())
+	h := handleRetract(r, slog.Default())
+	for _, body := range []string{`{`, `{"reason":"spam"}`, `{"doc_ids":["reddit:x"]}`} {
+		rec := httptest.NewRecorder()
+		h(rec, httptest.NewRequest("POST", "/api/v1/admin/retract", strings.NewReader(body)))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("body %s: expected 400, got %d", body, rec.Code)
+		}
+	}
+}
+
+func TestHandleRetract_DryRun(t *testing.T) {
+	r := ingest.NewRetractor(nil, nil, slog.Default())
+	rec := httptest.NewRecorder()
+	body := `{"doc_ids":["reddit:x","nhtsa:1"],"reason":"spam","dry_run":true}`
+	handleRetract(r, slog.Default())(rec, httptest.NewRequest("POST", "/api/v1/admin/retract", strings.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var resp RetractResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if !resp.DryRun || resp.Retracted != 0 || len(resp.Documents) != 2 {
+		t.Fatalf("unexpected dry run response %+v", resp)
+	}
+}
@@ -4,20 +4,27 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/WessleyAI/wessley-mvp/engine/graph"
+	"github.com/WessleyAI/wessley-mvp/engine/ingest"
 	"github.com/WessleyAI/wessley-mvp/engine/rag"
+	"github.com/WessleyAI/wessley-mvp/engine/scraper"
 	"github.com/WessleyAI/wessley-mvp/engine/semantic"
+	mlpb "github.com/WessleyAI/wessley-mvp/ml/proto/wessley/ml/v1"
+	"github.com/WessleyAI/wessley-mvp/pkg/blob"
 	"github.com/WessleyAI/wessley-mvp/pkg/mid"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"google.golang.org/grpc"
@@ -37,6 +44,14 @@ type Config struct {
 	CORSOrigin    string
 	DataDir       string
 	StateFile     string
+	BlobStore     string // manual PDF store, see blob.Open
+	EmbedModel    string // must have built the QDRANT_COLLECTION target; recorded on points written by POST /api/v1/ingest
+	EmbedVersion  int
+	EmbedDims     int
+	MinQuality    float64  // uploads scoring below are dropped, see ingest.NewScore
+	AdminToken    string   // bearer token for /api/v1/admin; empty disables those routes
+	IngestToken   string   // bearer token for /api/v1/ingest; empty disables those routes
+	IngestSources []string // sources POST /api/v1/ingest may write, see handleIngest
 }
 
 func loadConfig() Config {
@@ -52,6 +67,14 @@ func loadConfig() Config {
 		CORSOrigin:    envOr("CORS_ORIGIN", "*"),
 		DataDir:       envOr("DATA_DIR", "/tmp/wessley-data"),
 		StateFile:     envOr("INGEST_STATE_FILE", "/tmp/wessley-data/.ingest-state.json"),
+		BlobStore:     envOr("BLOB_STORE", "/tmp/wessley-data/manuals/blobs"),
+		EmbedModel:    envOr("EMBED_MODEL", "nomic-embed-text"),
+		EmbedVersion:  envInt("EMBED_VERSION", 1),
+		EmbedDims:     envInt("EMBED_DIMS", 768),
+		MinQuality:    envFloat("INGEST_MIN_QUALITY", ingest.DefaultQualityThreshold),
+		AdminToken:    os.Getenv("ADMIN_TOKEN"),
+		IngestToken:   os.Getenv("INGEST_TOKEN"),
+		IngestSources: strings.Split(envOr("INGEST_SOURCES", "manual"), ","),
 	}
 }
 
@@ -62,6 +85,20 @@ func envOr(key, fallback string) string {
 	return fallback
 }
 
+func envInt(key string, fallback int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return v
+	}
+	return fallback
+}
+
+func envFloat(key string, fallback float64) float64 {
+	if v, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return v
+	}
+	return fallback
+}
+
 func main() {
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
 	slog.SetDefault(logger)
@@ -93,6 +130,7 @@ func run(cfg Config, logger *slog.Logger) error {
 	defer neo4jDriver.Close(ctx)
 
 	graphStore := graph.New(neo4jDriver)
+	go seedDTCs(ctx, graphStore, logger)
 
 	// --- Connect to Qdrant ---
 	vectorStore, err := semantic.New(cfg.QdrantURL, cfg.Collection)
@@ -100,6 +138,25 @@ func run(cfg Config, logger *slog.Logger) error {
 		return fmt.Errorf("qdrant connect: %w", err)
 	}
 	defer vectorStore.Close()
+	// QDRANT_COLLECTION is normally an alias; cmd/reembed switches its target.
+	// Queries must be embedded with the model that built the target.
+	model := semantic.EmbeddingModel{Name: cfg.EmbedModel, Version: cfg.EmbedVersion, Dims: cfg.EmbedDims}
+	if target, err := vectorStore.ResolveAlias(ctx, cfg.Collection); err != nil {
+		logger.Warn("qdrant alias lookup failed", "alias", cfg.Collection, "err", err)
+	} else if target != "" {
+		if !model.Matches(target) {
+			return fmt.Errorf("qdrant alias %s points at %s, not built by %s v%d: %w",
+				cfg.Collection, target, cfg.EmbedModel, cfg.EmbedVersion, semantic.ErrModelMismatch)
+		}
+		logger.Info("qdrant alias resolved", "alias", cfg.Collection, "collection", target)
+	}
+
+	// --- Manual PDF store ---
+	blobs, err := blob.Open(cfg.BlobStore)
+	if err != nil {
+		logger.Warn("blob store unavailable, manual downloads redirect to the source", "store", cfg.BlobStore, "err", err)
+		blobs = nil
+	}
 
 	// --- Build RAG service ---
 	ragSvc := rag.New(
@@ -110,14 +167,43 @@ func run(cfg Config, logger *slog.Logger) error {
 		logger,
 	)
 
+	// --- Ingestion jobs ---
+	ingestJobs := newIngestJobs(ingest.NewPipeline(ingest.Deps{
+		Embedder:    mlpb.NewEmbedServiceClient(mlConn),
+		VectorStore: vectorStore,
+		GraphStore:  graphStore,
+		Logger:      logger,
+		Model:       model,
+		Quality:     ingest.QualityOptions{Threshold: cfg.MinQuality},
+		Tombstoned:  graphStore.IsTombstoned,
+		CheckModel:  func(ctx context.Context) error { return vectorStore.CheckModel(ctx, model) },
+	}), logger)
+	retractor := ingest.NewRetractor(vectorStore, graphStore, logger)
+	go ingestJobs.run(ctx)
+
 	// --- Build HTTP server ---
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /api/health", handleHealth)
 	mux.HandleFunc("POST /api/chat", handleChat(ragSvc, logger))
 	mux.HandleFunc("GET /api/v1/manuals", handleManuals(graphStore, logger))
-	mux.HandleFunc("GET /api/v1/manuals/{id}/download", handleManualDownload(graphStore, logger))
+	mux.HandleFunc("GET /api/v1/manuals/{id}/download", handleManualDownload(graphStore, blobs, logger))
+	mux.HandleFunc("GET /api/v1/manuals/{id}/pages/{n}", handleManualPage(graphStore, logger))
+	mux.HandleFunc("GET /api/v1/vehicles/{id}/fuses", handleVehicleFuses(graphStore, logger))
+	mux.HandleFunc("GET /api/v1/vehicles/{id}/maintenance", handleVehicleMaintenance(graphStore, logger))
+	mux.HandleFunc("GET /api/v1/vehicles/{id}/procedure", handleVehicleProcedure(graphStore, logger))
+	mux.HandleFunc("GET /api/v1/vehicles/{id}/recalls", handleVehicleRecalls(graphStore, logger))
+	mux.HandleFunc("GET /api/v1/vehicles/{id}/complaints/stats", handleVehicleComplaintStats(graphStore, logger))
+	mux.HandleFunc("GET /api/v1/dtc/{code}", handleDTC(graphStore, logger))
 	mux.HandleFunc("GET /api/v1/metrics/snapshot", handleMetricsSnapshot(graphStore, cfg, logger))
 
+	uploads := mid.BearerToken(cfg.IngestToken)
+	mux.Handle("POST /api/v1/ingest", uploads(handleIngest(ingestJobs, cfg.IngestSources, logger)))
+	mux.Handle("GET /api/v1/ingest/jobs/{id}", uploads(handleIngestJob(ingestJobs)))
+
+	admin := mid.BearerToken(cfg.AdminToken)
+	mux.Handle("POST /api/v1/admin/retract", admin(handleRetract(retractor, logger)))
+	mux.Handle("GET /api/v1/admin/tombstones", admin(handleTombstones(graphStore, logger)))
+
 	handler := mid.Chain(mux,
 		mid.Recover(logger),
 		mid.Logger(logger),
@@ -168,10 +254,11 @@ type ChatRequest struct {
 
 // ChatResponse is the JSON response for POST /api/chat.
 type ChatResponse struct {
-	Answer  string       `json:"answer"`
-	Sources []rag.Source `json:"sources"`
-	Model   string       `json:"model"`
-	Tokens  int32        `json:"tokens_used"`
+	Answer    string           `json:"answer"`
+	Sources   []rag.Source     `json:"sources"`
+	Model     string           `json:"model"`
+	Tokens    int32            `json:"tokens_used"`
+	Procedure *graph.Procedure `json:"procedure,omitempty"` // for "how do I replace X" questions
 }
 
 func handleChat(ragSvc *rag.Service, logger *slog.Logger) http.HandlerFunc {
@@ -193,12 +280,14 @@ func handleChat(ragSvc *rag.Service, logger *slog.Logger) http.HandlerFunc {
 			return
 		}
 
+		linkCitations(answer.Sources)
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(ChatResponse{
-			Answer:  answer.Text,
-			Sources: answer.Sources,
-			Model:   answer.Model,
-			Tokens:  answer.TokensUsed,
+			Answer:    answer.Text,
+			Sources:   answer.Sources,
+			Model:     answer.Model,
+			Tokens:    answer.TokensUsed,
+			Procedure: answer.Procedure,
 		})
 	}
 }
@@ -229,7 +318,7 @@ func handleManuals(gs *graph.GraphStore, logger *slog.Logger) http.HandlerFunc {
 	}
 }
 
-func handleManualDownload(gs *graph.GraphStore, logger *slog.Logger) http.HandlerFunc {
+func handleManualDownload(gs *graph.GraphStore, blobs blob.BlobStore, logger *slog.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := r.PathValue("id")
 		if id == "" {
@@ -237,29 +326,99 @@ func handleManualDownload(gs *graph.GraphStore, logger *slog.Logger) http.Handle
 			return
 		}
 
-		entries, err := gs.FindManuals(r.Context(), graph.ManualFilter{})
+		e, err := gs.GetManual(r.Context(), id)
 		if err != nil {
 			logger.Error("find manual for download", "err", err)
 			http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
 			return
 		}
+		if e == nil {
+			http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+			return
+		}
 
-		for _, e := range entries {
-			if e.ID == id {
-				if e.LocalPath != "" {
-					http.ServeFile(w, r, e.LocalPath)
-					return
-				}
-				// Redirect to source URL
-				http.Redirect(w, r, e.URL, http.StatusTemporaryRedirect)
-				return
-			}
+		if e.Blob != "" && blobs != nil && serveBlob(w, r, blobs, e.Blob, logger) {
+			return
+		}
+		if e.LocalPath != "" {
+			http.ServeFile(w, r, e.LocalPath)
+			return
+		}
+		// Redirect to source URL
+		http.Redirect(w, r, e.URL, http.StatusTemporaryRedirect)
+	}
+}
+
+// handleManualPage redirects to the manual's PDF opened at page n, through
+// the #page=N fragment PDF viewers honor. Stored copies are preferred over
+// the source URL, as for downloads.
+func handleManualPage(gs *graph.GraphStore, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		page, err := strconv.Atoi(r.PathValue("n"))
+		if id == "" || err != nil || page < 1 {
+			http.Error(w, `{"error":"id and a page number from 1 required"}`, http.StatusBadRequest)
+			return
 		}
 
-		http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+		e, err := gs.GetManual(r.Context(), id)
+		if err != nil {
+			logger.Error("find manual for page", "err", err)
+			http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+		if e == nil {
+			http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+			return
+		}
+
+		target := e.URL
+		if e.Blob != "" || e.LocalPath != "" {
+			target = "/api/v1/manuals/" + url.PathEscape(e.ID) + "/download"
+		}
+		if i := strings.IndexByte(target, '#'); i >= 0 {
+			target = target[:i]
+		}
+		http.Redirect(w, r, fmt.Sprintf("%s#page=%d", target, page), http.StatusFound)
+	}
+}
+
+// linkCitations points manual sources at the cited page.
+func linkCitations(sources []rag.Source) {
+	for i, s := range sources {
+		if s.ManualID != "" && s.Page > 0 {
+			sources[i].Link = fmt.Sprintf("/api/v1/manuals/%s/pages/%d", url.PathEscape(s.ManualID), s.Page)
+		}
 	}
 }
 
+// serveBlob writes the stored PDF, reporting false when the store does
+// not have it so the caller can fall back to the source.
+func serveBlob(w http.ResponseWriter, r *http.Request, blobs blob.BlobStore, sum string, logger *slog.Logger) bool {
+	rc, err := blobs.Get(r.Context(), sum)
+	if err != nil {
+		if !errors.Is(err, blob.ErrNotFound) {
+			logger.Warn("get manual blob", "blob", sum, "err", err)
+		}
+		return false
+	}
+	defer rc.Close()
+
+	// Content never changes under a digest, so it is its own ETag.
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("ETag", `"`+sum+`"`)
+	if rs, ok := rc.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, sum+".pdf", time.Time{}, rs)
+		return true
+	}
+	if r.Header.Get("If-None-Match") == `"`+sum+`"` {
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+	io.Copy(w, rc)
+	return true
+}
+
 // --- Snapshot Types ---
 
 type MetricsSnapshot struct {
@@ -449,11 +608,11 @@ func queryIngestion(dataDir, stateFile string, logger *slog.Logger) IngestionSna
 			count++
 		}
 		// Determine source from filename
-		source := strings.TrimSuffix(e.Name(), ".json")
-		source = strings.TrimSuffix(source, "-posts")
-		source = strings.TrimSuffix(source, "-complaints")
-		source = strings.TrimSuffix(source, "-guides")
-		snap.DocsBySource[source] = count
+		source, ok := scraper.SourceForFile(e.Name())
+		if !ok {
+			source = strings.TrimSuffix(e.Name(), ".json")
+		}
+		snap.DocsBySource[source] += count
 		snap.TotalDocsIngested += count
 	}
 	snap.LastIngestion = time.Now().UTC().Format(time.RFC3339)
@@ -527,3 +686,38 @@ func (a *graphAdapter) FindRelatedComponents(ctx context.Context, keywords []str
 
 	return allComponents, edges, nil
 }
+
+// FindProcedure implements rag.ProcedureFinder. vehicle is a ModelYear ID
+// such as "ford-f-150-2018" or a name such as "2018 Ford F-150".
+func (a *graphAdapter) FindProcedure(ctx context.Context, vehicle, component, operation string) (*graph.Procedure, error) {
+	id := vehicleModelYearID(vehicle)
+	if id == "" {
+		return nil, nil
+	}
+	return a.store.FindProcedure(ctx, id, component, operation)
+}
+
+// vehicleModelYearID turns "2018 Ford F-150" into the ModelYear ID
+// "ford-f-150-2018"; IDs pass through lowercased. It returns "" without a
+// year.
+func vehicleModelYearID(vehicle string) string {
+	words := strings.Fields(strings.ToLower(vehicle))
+	if len(words) == 1 {
+		return words[0]
+	}
+	year := ""
+	var rest []string
+	for _, w := range words {
+		if len(w) == 4 && year == "" {
+			if _, err := strconv.Atoi(w); err == nil {
+				year = w
+				continue
+			}
+		}
+		rest = append(rest, w)
+	}
+	if year == "" || len(rest) == 0 {
+		return ""
+	}
+	return strings.Join(rest, "-") + "-" + year
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/WessleyAI/wessley-mvp/engine/graph"
+	"github.com/WessleyAI/wessley-mvp/engine/rag"
+	"github.com/WessleyAI/wessley-mvp/pkg/blob"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+func TestHandleManualDownload_Blob(t *testing.T) {
+	blobs, err := blob.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := blobs.Put(context.Background(), strings.NewReader("%PDF-1.7 manual"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	manual := func(props map[string]any) *graph.GraphStore {
+		return graph.NewWithOpener(&mockOpener{session: &mockCypherSession{records: []mockRecord{
+			{keys: []string{"n"}, values: []any{dbtype.Node{Props: props}}},
+		}}})
+	}
+
+	gs := manual(map[string]any{"id": "m1", "url": "https://oem.example/m.pdf", "blob": info.SHA256})
+	req := httptest.NewRequest("GET", "/api/v1/manuals/m1/download", nil)
+	req.SetPathValue("id", "m1")
+	rec := httptest.NewRecorder()
+	handleManualDownload(gs, blobs, slog.Default())(rec, req)
+	if rec.Code != http.StatusOK || rec.Body.String() != "%PDF-1.7 manual" {
+		t.Fatalf("expected the stored PDF, got %d: %q", rec.Code, rec.Body)
+	}
+	if rec.Header().Get("Content-Type") != "application/pdf" || rec.Header().Get("ETag") != `"`+info.SHA256+`"` {
+		t.Errorf("unexpected headers %v", rec.Header())
+	}
+
+	// An evicted blob falls back to the source.
+	blobs.Delete(context.Background(), info.SHA256)
+	rec = httptest.NewRecorder()
+	handleManualDownload(gs, blobs, slog.Default())(rec, req)
+	if rec.Code != http.StatusTemporaryRedirect || rec.Header().Get("Location") != "https://oem.example/m.pdf" {
+		t.Errorf("expected a redirect to the source, got %d %v", rec.Code, rec.Header())
+	}
+}
+
+func TestHandleManualPage(t *testing.T) {
+	gs := graph.NewWithOpener(&mockOpener{session: &mockCypherSession{records: []mockRecord{
+		{keys: []string{"n"}, values: []any{dbtype.Node{Props: map[string]any{"id": "m1", "url": "https://oem.example/m.pdf", "blob": "abc"}}}},
+	}}})
+	for _, tc := range []struct {
+		page, want string
+		code       int
+	}{
+		{"241", "/api/v1/manuals/m1/download#page=241", http.StatusFound},
+		{"0", "", http.StatusBadRequest},
+		{"x", "", http.StatusBadRequest},
+	} {
+		req := httptest.NewRequest("GET", "/api/v1/manuals/m1/pages/"+tc.page, nil)
+		req.SetPathValue("id", "m1")
+		req.SetPathValue("n", tc.page)
+		rec := httptest.NewRecorder()
+		handleManualPage(gs, slog.Default())(rec, req)
+		if rec.Code != tc.code || rec.Header().Get("Location") != tc.want {
+			t.Errorf("page %s: got %d %q", tc.page, rec.Code, rec.Header().Get("Location"))
+		}
+	}
+
+	// Without a stored copy the source URL is opened at the page.
+	gs = graph.NewWithOpener(&mockOpener{session: &mockCypherSession{records: []mockRecord{
+		{keys: []string{"n"}, values: []any{dbtype.Node{Props: map[string]any{"id": "m2", "url": "https://oem.example/m.pdf"}}}},
+	}}})
+	req := httptest.NewRequest("GET", "/api/v1/manuals/m2/pages/7", nil)
+	req.SetPathValue("id", "m2")
+	req.SetPathValue("n", "7")
+	rec := httptest.NewRecorder()
+	handleManualPage(gs, slog.Default())(rec, req)
+	if rec.Header().Get("Location") != "https://oem.example/m.pdf#page=7" {
+		t.Errorf("expected the source deep link, got %q", rec.Header().Get("Location"))
+	}
+
+	gs = graph.NewWithOpener(&mockOpener{session: &mockCypherSession{}})
+	rec = httptest.NewRecorder()
+	handleManualPage(gs, slog.Default())(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestLinkCitations(t *testing.T) {
+	sources := []rag.Source{{ID: "a", ManualID: "m1", Page: 241}, {ID: "b", Source: "reddit"}}
+	linkCitations(sources)
+	if sources[0].Link != "/api/v1/manuals/m1/pages/241" || sources[1].Link != "" {
+		t.Errorf("unexpected links %+v", sources)
+	}
+}
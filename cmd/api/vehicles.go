@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/WessleyAI/wessley-mvp/engine/graph"
+)
+
+// FuseLookupResponse is the JSON response for GET /api/v1/vehicles/{id}/fuses.
+type FuseLookupResponse struct {
+	Vehicle string            `json:"vehicle"`
+	Circuit string            `json:"circuit,omitempty"`
+	Fuses   []graph.FuseEntry `json:"fuses"`
+}
+
+// handleVehicleFuses answers "which fuse protects the power outlet on a 2018
+// F-150" as GET /api/v1/vehicles/ford-f-150-2018/fuses?circuit=power+outlet.
+// Without circuit it returns the vehicle's whole fuse and relay chart.
+func handleVehicleFuses(gs *graph.GraphStore, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := strings.ToLower(r.PathValue("id"))
+		if id == "" {
+			http.Error(w, `{"error":"id required"}`, http.StatusBadRequest)
+			return
+		}
+		circuit := r.URL.Query().Get("circuit")
+
+		fuses, err := gs.FindFuses(r.Context(), id, circuit)
+		if err != nil {
+			logger.Error("find fuses", "vehicle", id, "circuit", circuit, "err", err)
+			http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+		if fuses == nil {
+			fuses = []graph.FuseEntry{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(FuseLookupResponse{Vehicle: id, Circuit: circuit, Fuses: fuses})
+	}
+}
+
+// MaintenanceResponse is the JSON response for GET
+// /api/v1/vehicles/{id}/maintenance.
+type MaintenanceResponse struct {
+	Vehicle  string                   `json:"vehicle"`
+	Mileage  int                      `json:"mileage"`
+	Months   int                      `json:"months,omitempty"`
+	Severe   bool                     `json:"severe,omitempty"`
+	Overdue  []graph.ScheduledService `json:"overdue"`
+	Due      []graph.ScheduledService `json:"due"`
+	Upcoming []graph.ScheduledService `json:"upcoming"`
+}
+
+// handleVehicleMaintenance answers "what service does my 2018 F-150 need at
+// 30,000 miles" as GET /api/v1/vehicles/ford-f-150-2018/maintenance?mileage=30000.
+// Optional months gives the vehicle's age for time-based intervals, and
+// severe=true selects the severe-service schedule.
+func handleVehicleMaintenance(gs *graph.GraphStore, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := strings.ToLower(r.PathValue("id"))
+		if id == "" {
+			http.Error(w, `{"error":"id required"}`, http.StatusBadRequest)
+			return
+		}
+		q := r.URL.Query()
+		mileage, err := strconv.Atoi(q.Get("mileage"))
+		if err != nil || mileage < 0 {
+			http.Error(w, `{"error":"mileage must be a non-negative integer"}`, http.StatusBadRequest)
+			return
+		}
+		months := 0
+		if v := q.Get("months"); v != "" {
+			if months, err = strconv.Atoi(v); err != nil || months < 0 {
+				http.Error(w, `{"error":"months must be a non-negative integer"}`, http.StatusBadRequest)
+				return
+			}
+		}
+		severe, _ := strconv.ParseBool(q.Get("severe"))
+
+		items, err := gs.MaintenanceSchedule(r.Context(), id)
+		if err != nil {
+			logger.Error("maintenance schedule", "vehicle", id, "err", err)
+			http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+
+		resp := MaintenanceResponse{
+			Vehicle: id, Mileage: mileage, Months: months, Severe: severe,
+			Overdue: []graph.ScheduledService{}, Due: []graph.ScheduledService{}, Upcoming: []graph.ScheduledService{},
+		}
+		for _, s := range graph.ScheduleAt(items, mileage, months, severe, graph.DefaultMaintenanceWindow) {
+			switch s.Status {
+			case graph.MaintenanceOverdue:
+				resp.Overdue = append(resp.Overdue, s)
+			case graph.MaintenanceDue:
+				resp.Due = append(resp.Due, s)
+			case graph.MaintenanceUpcoming:
+				resp.Upcoming = append(resp.Upcoming, s)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// handleVehicleProcedure returns the step-by-step procedure for an operation
+// on a component, e.g. GET
+// /api/v1/vehicles/ford-f-150-2018/procedure?component=alternator&operation=replace.
+// operation may be left out to take any procedure on the component.
+func handleVehicleProcedure(gs *graph.GraphStore, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := strings.ToLower(r.PathValue("id"))
+		component := r.URL.Query().Get("component")
+		if id == "" || component == "" {
+			http.Error(w, `{"error":"id and component required"}`, http.StatusBadRequest)
+			return
+		}
+		operation := r.URL.Query().Get("operation")
+
+		p, err := gs.FindProcedure(r.Context(), id, component, operation)
+		if err != nil {
+			logger.Error("find procedure", "vehicle", id, "component", component, "operation", operation, "err", err)
+			http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+		if p == nil {
+			http.Error(w, `{"error":"procedure not found"}`, http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(p)
+	}
+}
+
+// RecallsResponse is the JSON response for GET /api/v1/vehicles/{id}/recalls.
+type RecallsResponse struct {
+	Vehicle string         `json:"vehicle"`
+	Recalls []graph.Recall `json:"recalls"`
+}
+
+// handleVehicleRecalls answers "is there a recall for this?" as GET
+// /api/v1/vehicles/ford-f-150-2018/recalls, newest first. kind=recall or
+// kind=investigation keeps only that kind.
+func handleVehicleRecalls(gs *graph.GraphStore, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := strings.ToLower(r.PathValue("id"))
+		if id == "" {
+			http.Error(w, `{"error":"id required"}`, http.StatusBadRequest)
+			return
+		}
+		kind := r.URL.Query().Get("kind")
+		if kind != "" && kind != graph.RecallKindRecall && kind != graph.RecallKindInvestigation {
+			http.Error(w, `{"error":"kind must be recall or investigation"}`, http.StatusBadRequest)
+			return
+		}
+
+		recalls, err := gs.Recalls(r.Context(), id)
+		if err != nil {
+			logger.Error("recalls", "vehicle", id, "err", err)
+			http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+
+		resp := RecallsResponse{Vehicle: id, Recalls: []graph.Recall{}}
+		for _, rc := range recalls {
+			if kind == "" || rc.Kind == kind {
+				resp.Recalls = append(resp.Recalls, rc)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// ComplaintStatsResponse is the JSON response for GET
+// /api/v1/vehicles/{id}/complaints/stats.
+type ComplaintStatsResponse struct {
+	Vehicle        string                 `json:"vehicle"`
+	Complaints     int                    `json:"complaints"` // summed over components; a complaint can name several
+	Components     []graph.ComponentStats `json:"components"`
+	SafetyCritical []string               `json:"safety_critical"` // components flagged for crashes, fires or deaths
+}
+
+// handleVehicleComplaintStats returns complaint counts, crash and fire rates
+// and monthly incident trends by component, e.g. GET
+// /api/v1/vehicles/ford-f-150-2018/complaints/stats.
+func handleVehicleComplaintStats(gs *graph.GraphStore, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := strings.ToLower(r.PathValue("id"))
+		if id == "" {
+			http.Error(w, `{"error":"id required"}`, http.StatusBadRequest)
+			return
+		}
+
+		stats, err := gs.ComplaintStats(r.Context(), id)
+		if err != nil {
+			logger.Error("complaint stats", "vehicle", id, "err", err)
+			http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+
+		resp := ComplaintStatsResponse{Vehicle: id, Components: []graph.ComponentStats{}, SafetyCritical: []string{}}
+		for _, s := range stats {
+			resp.Complaints += s.Complaints
+			resp.Components = append(resp.Components, s)
+			if s.SafetyCritical {
+				resp.SafetyCritical = append(resp.SafetyCritical, s.Component)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/WessleyAI/wessley-mvp/engine/graph"
+)
+
+func TestHandleVehicleFuses(t *testing.T) {
+	keys := []string{"kind", "position", "box", "amperage", "type", "page", "circuits"}
+	sess := &mockCypherSessionFunc{runFn: func(_ context.Context, _ string, params map[string]any) (graph.CypherResult, error) {
+		if params["myID"] != "ford-f-150-2018" {
+			t.Errorf("unexpected model year %v", params["myID"])
+		}
+		return &mockCypherResult{records: []mockRecord{
+			{keys: keys, values: []any{"fuse", "F12", "cabin", 20.0, "mini", int64(241), []any{"Power point 1"}}},
+			{keys: keys, values: []any{"fuse", "F13", "cabin", 10.0, "mini", int64(241), []any{"Radio"}}},
+		}}, nil
+	}}
+	gs := graph.NewWithOpener(&mockOpener{session: sess})
+
+	req := httptest.NewRequest("GET", "/api/v1/vehicles/Ford-F-150-2018/fuses?circuit=power+outlet", nil)
+	req.SetPathValue("id", "Ford-F-150-2018")
+	rec := httptest.NewRecorder()
+	handleVehicleFuses(gs, slog.Default())(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var resp FuseLookupResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Fuses) != 1 || resp.Fuses[0].Position != "F12" || resp.Fuses[0].Amperage != 20 {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+}
+
+func TestHandleVehicleFuses_Error(t *testing.T) {
+	gs := graph.NewWithOpener(&mockOpener{session: &mockCypherSession{err: errors.New("down")}})
+	req := httptest.NewRequest("GET", "/api/v1/vehicles/ford-f-150-2018/fuses", nil)
+	req.SetPathValue("id", "ford-f-150-2018")
+	rec := httptest.NewRecorder()
+	handleVehicleFuses(gs, slog.Default())(rec, req)
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", rec.Code)
+	}
+}
+
+func TestHandleVehicleMaintenance(t *testing.T) {
+	keys := []string{"operation", "component", "miles", "km", "months", "severe", "page"}
+	sess := &mockCypherSessionFunc{runFn: func(_ context.Context, _ string, params map[string]any) (graph.CypherResult, error) {
+		if params["myID"] != "ford-f-150-2018" {
+			t.Errorf("unexpected model year %v", params["myID"])
+		}
+		return &mockCypherResult{records: []mockRecord{
+			{keys: keys, values: []any{"Replace engine oil", "Engine oil", int64(7500), nil, int64(12), false, int64(410)}},
+			{keys: keys, values: []any{"Replace spark plugs", "Spark plugs", int64(25000), nil, nil, false, nil}},
+			{keys: keys, values: []any{"Replace timing belt", nil, int64(32000), nil, nil, false, nil}},
+		}}, nil
+	}}
+	gs := graph.NewWithOpener(&mockOpener{session: sess})
+
+	req := httptest.NewRequest("GET", "/api/v1/vehicles/Ford-F-150-2018/maintenance?mileage=30200", nil)
+	req.SetPathValue("id", "Ford-F-150-2018")
+	rec := httptest.NewRecorder()
+	handleVehicleMaintenance(gs, slog.Default())(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var resp MaintenanceResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Due) != 1 || resp.Due[0].Operation != "Replace engine oil" || resp.Due[0].AtMiles != 30000 {
+		t.Errorf("unexpected due %+v", resp.Due)
+	}
+	if len(resp.Overdue) != 1 || resp.Overdue[0].OverdueBy != 5200 {
+		t.Errorf("unexpected overdue %+v", resp.Overdue)
+	}
+	if len(resp.Upcoming) != 1 || resp.Upcoming[0].DueInMiles != 1800 {
+		t.Errorf("unexpected upcoming %+v", resp.Upcoming)
+	}
+}
+
+func TestHandleVehicleMaintenance_BadMileage(t *testing.T) {
+	gs := graph.NewWithOpener(&mockOpener{session: &mockCypherSession{}})
+	for _, q := range []string{"", "?mileage=abc", "?mileage=-5", "?mileage=100&months=x"} {
+		req := httptest.NewRequest("GET", "/api/v1/vehicles/ford-f-150-2018/maintenance"+q, nil)
+		req.SetPathValue("id", "ford-f-150-2018")
+		rec := httptest.NewRecorder()
+		handleVehicleMaintenance(gs, slog.Default())(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%q: expected 400, got %d", q, rec.Code)
+		}
+	}
+}
+
+func TestHandleVehicleProcedure(t *testing.T) {
+	keys := []string{"title", "operation", "tools", "warnings", "source", "url", "components", "steps"}
+	sess := &mockCypherSession{records: []mockRecord{{keys: keys, values: []any{
+		"Alternator Replacement", "replace", []any{"13 mm socket"}, nil, "ifixit", "https://ifixit.com/Guide/77", []any{"Alternator"},
+		[]any{
+			map[string]any{"order": int64(1), "title": "Battery", "text": "Disconnect the negative cable."},
+			map[string]any{"order": int64(2), "title": nil, "text": "Release the belt tensioner."},
+		},
+	}}}}
+	gs := graph.NewWithOpener(&mockOpener{session: sess})
+
+	req := httptest.NewRequest("GET", "/api/v1/vehicles/ford-f-150-2018/procedure?component=alternator&operation=replace", nil)
+	req.SetPathValue("id", "ford-f-150-2018")
+	rec := httptest.NewRecorder()
+	handleVehicleProcedure(gs, slog.Default())(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var p graph.Procedure
+	if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Title != "Alternator Replacement" || len(p.Steps) != 2 || p.Steps[0].Title != "Battery" || p.Tools[0] != "13 mm socket" {
+		t.Errorf("unexpected procedure %+v", p)
+	}
+
+	for _, q := range []string{"?component=alternator&operation=remove", "?component=starter"} {
+		req = httptest.NewRequest("GET", "/api/v1/vehicles/ford-f-150-2018/procedure"+q, nil)
+		req.SetPathValue("id", "ford-f-150-2018")
+		rec = httptest.NewRecorder()
+		handleVehicleProcedure(gs, slog.Default())(rec, req)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%s: expected 404, got %d", q, rec.Code)
+		}
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/vehicles/ford-f-150-2018/procedure", nil)
+	req.SetPathValue("id", "ford-f-150-2018")
+	rec = httptest.NewRecorder()
+	handleVehicleProcedure(gs, slog.Default())(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 without component, got %d", rec.Code)
+	}
+}
+
+func TestHandleVehicleRecalls(t *testing.T) {
+	keys := []string{"campaign", "kind", "component", "summary", "consequence", "remedy", "manufacturer", "date", "status", "units", "url", "affects"}
+	sess := &mockCypherSession{records: []mockRecord{
+		{keys: keys, values: []any{
+			"18V123000", "recall", "FUEL SYSTEM, GASOLINE", "The fuel pump may fail.", "Stall.", "Replace the pump.", "Ford Motor Company",
+			"2018-03-01", nil, int64(1200), "https://www.nhtsa.gov/recalls?nhtsaId=18V123000", []any{"Fuel Pump"},
+		}},
+		{keys: keys, values: []any{
+			"PE18004", "investigation", "SERVICE BRAKES", "Brake fluid leak.", nil, nil, nil, "2018-01-10", "open", nil, nil, []any{"Brakes"},
+		}},
+	}}
+	gs := graph.NewWithOpener(&mockOpener{session: sess})
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/v1/vehicles/Ford-F-150-2018/recalls"+query, nil)
+		req.SetPathValue("id", "Ford-F-150-2018")
+		rec := httptest.NewRecorder()
+		handleVehicleRecalls(gs, slog.Default())(rec, req)
+		return rec
+	}
+
+	rec := get("")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var resp RecallsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Vehicle != "ford-f-150-2018" || len(resp.Recalls) != 2 || resp.Recalls[0].Remedy != "Replace the pump." {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+
+	rec = get("?kind=investigation")
+	resp = RecallsResponse{}
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if len(resp.Recalls) != 1 || resp.Recalls[0].Campaign != "PE18004" {
+		t.Errorf("kind should filter, got %+v", resp.Recalls)
+	}
+
+	if rec := get("?kind=tsb"); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown kind, got %d", rec.Code)
+	}
+}
+
+func TestHandleVehicleComplaintStats(t *testing.T) {
+	keys := []string{"component", "system", "complaints", "crashes", "fires", "injuries", "deaths", "months"}
+	sess := &mockCypherSession{records: []mockRecord{
+		{keys: keys, values: []any{"ENGINE", "Engine", int64(10), int64(2), int64(0), int64(1), int64(0), []any{"2023-05", "2023-05"}}},
+		{keys: keys, values: []any{"STEERING", "Steering", int64(4), int64(0), int64(0), int64(0), int64(0), []any{}}},
+	}}
+	gs := graph.NewWithOpener(&mockOpener{session: sess})
+
+	req := httptest.NewRequest("GET", "/api/v1/vehicles/Ford-F-150-2018/complaints/stats", nil)
+	req.SetPathValue("id", "Ford-F-150-2018")
+	rec := httptest.NewRecorder()
+	handleVehicleComplaintStats(gs, slog.Default())(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var resp ComplaintStatsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Vehicle != "ford-f-150-2018" || resp.Complaints != 14 || len(resp.Components) != 2 {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	if c := resp.Components[0]; c.CrashRate != 0.2 || len(c.Trend) != 1 || c.Trend[0].Count != 2 {
+		t.Errorf("unexpected engine stats %+v", c)
+	}
+	if len(resp.SafetyCritical) != 1 || resp.SafetyCritical[0] != "ENGINE" {
+		t.Errorf("expected ENGINE flagged, got %v", resp.SafetyCritical)
+	}
+
+	gs = graph.NewWithOpener(&mockOpener{session: &mockCypherSession{err: errors.New("down")}})
+	rec = httptest.NewRecorder()
+	handleVehicleComplaintStats(gs, slog.Default())(rec, req)
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d", rec.Code)
+	}
+}
+
+func TestVehicleModelYearID(t *testing.T) {
+	for in, want := range map[string]string{
+		"2018 Ford F-150":  "ford-f-150-2018",
+		"Ford-F-150-2018":  "ford-f-150-2018",
+		"Honda Civic 2015": "honda-civic-2015",
+		"Honda Civic":      "",
+		"":                 "",
+	} {
+		if got := vehicleModelYearID(in); got != want {
+			t.Errorf("vehicleModelYearID(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
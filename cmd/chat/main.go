@@ -13,6 +13,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -42,6 +43,7 @@ func main() {
 	qdrantAddr := envOr("QDRANT_URL", "localhost:6334")
 	collection := envOr("QDRANT_COLLECTION", "wessley")
 	embedModel := envOr("EMBED_MODEL", "nomic-embed-text")
+	embedVersion, _ := strconv.Atoi(envOr("EMBED_VERSION", "1"))
 	chatModel := envOr("CHAT_MODEL", "llama3.1:8b")
 	port := envOr("PORT", "8090")
 
@@ -53,6 +55,19 @@ func main() {
 	}
 	defer store.Close()
 
+	// QDRANT_COLLECTION is normally an alias; cmd/reembed switches its target.
+	// Questions must be embedded with the model that built the target.
+	model := semantic.EmbeddingModel{Name: embedModel, Version: embedVersion}
+	if target, err := store.ResolveAlias(context.Background(), collection); err != nil {
+		logger.Warn("qdrant alias lookup failed", "alias", collection, "err", err)
+	} else if target != "" && !model.Matches(target) {
+		logger.Error("EMBED_MODEL does not match the collection behind the alias",
+			"alias", collection, "collection", target, "model", embedModel, "version", embedVersion)
+		os.Exit(1)
+	} else if target != "" {
+		logger.Info("qdrant alias resolved", "alias", collection, "collection", target)
+	}
+
 	// Ollama embed client
 	embedClient := ollama.NewEmbedClient(ollamaURL, embedModel)
 
@@ -3,8 +3,10 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
@@ -13,17 +15,19 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/WessleyAI/wessley-mvp/engine/graph"
 	"github.com/WessleyAI/wessley-mvp/engine/ingest"
 	"github.com/WessleyAI/wessley-mvp/engine/scraper"
 	"github.com/WessleyAI/wessley-mvp/engine/semantic"
-	"github.com/WessleyAI/wessley-mvp/pkg/fn"
 	"github.com/WessleyAI/wessley-mvp/pkg/vehiclenlp"
 	"github.com/WessleyAI/wessley-mvp/pkg/metrics"
 	"github.com/WessleyAI/wessley-mvp/pkg/ollama"
+	mlpb "github.com/WessleyAI/wessley-mvp/ml/proto/wessley/ml/v1"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"google.golang.org/grpc"
 )
 
 var met = metrics.New()
@@ -33,6 +37,8 @@ var (
 	mDocsTotal       = func(source string) *metrics.Counter { return met.Counter(metrics.WithLabels("wessley_ingest_docs_total", "source", source), "Total documents ingested") }
 	mErrorsTotal     = func(stage string) *metrics.Counter { return met.Counter(metrics.WithLabels("wessley_ingest_errors_total", "stage", stage), "Total ingestion errors") }
 	mDocsSkipped     = met.Counter("wessley_ingest_docs_skipped_total", "Documents skipped by dedup")
+	mDocsLowQuality  = met.Counter("wessley_ingest_docs_low_quality_total", "Documents dropped below the quality threshold")
+	mDocsRetracted   = met.Counter("wessley_ingest_docs_retracted_total", "Documents skipped because they were retracted")
 	mChunksTotal     = met.Counter("wessley_ingest_chunks_total", "Total chunks created")
 	mEmbeddingsTotal = met.Counter("wessley_ingest_embeddings_total", "Total embeddings generated")
 	mNeo4jWrites     = met.Counter("wessley_ingest_neo4j_writes_total", "Graph store writes")
@@ -56,20 +62,29 @@ var (
 	mQueueDepth              = met.Gauge("wessley_ingest_queue_depth", "Files waiting to process")
 )
 
-const vectorDims = 768 // nomic-embed-text
-
 func main() {
 	var (
 		dataDir    = flag.String("dir", "/tmp/wessley-data", "directory to watch for JSON files")
 		ollamaURL  = flag.String("ollama", "http://localhost:11434", "Ollama base URL")
 		ollamaModel = flag.String("model", "nomic-embed-text", "Ollama embedding model")
+		embedVer    = flag.Int("embed-version", 1, "embedding model revision recorded on each point")
+		vectorDims  = flag.Int("dims", 768, "embedding dimensions of -model")
 		neo4jURL   = flag.String("neo4j", "neo4j://localhost:7687", "Neo4j bolt URL")
 		neo4jUser  = flag.String("neo4j-user", "neo4j", "Neo4j username")
 		neo4jPass  = flag.String("neo4j-pass", "wessley123", "Neo4j password")
 		qdrantAddr = flag.String("qdrant", "localhost:6334", "Qdrant gRPC address")
-		collection = flag.String("collection", "wessley", "Qdrant collection name")
+		collection = flag.String("collection", "wessley", "Qdrant collection alias (see cmd/reembed)")
 		interval   = flag.Duration("interval", 30*time.Second, "scan interval")
 		stateFile  = flag.String("state", "/tmp/wessley-data/.ingest-state.json", "processed files state")
+
+		prepareWorkers = flag.Int("workers", 0, "validate/parse/chunk workers (0 = NumCPU)")
+		embedWorkers   = flag.Int("embed-workers", ingest.DefaultEmbedWorkers, "concurrent embedding requests")
+		storeWorkers   = flag.Int("store-workers", ingest.DefaultStoreWorkers, "concurrent graph writers")
+		embedBatch     = flag.Int("embed-batch", ingest.EmbedBatchSize, "chunks per embedding request, across documents")
+		upsertBatch    = flag.Int("upsert-batch", ingest.DefaultUpsertBatchSize, "points per Qdrant upsert, across documents")
+		scrubFile      = flag.String("scrub-policy", "", "JSON file of per-source PII redaction policies, overriding the defaults")
+		minQuality     = flag.Float64("min-quality", ingest.DefaultQualityThreshold, "drop documents scoring below this quality (0-1)")
+		tagLowQuality  = flag.Bool("tag-low-quality", false, "keep documents below -min-quality and mark them low_quality instead of dropping")
 	)
 	flag.Parse()
 
@@ -101,19 +116,35 @@ func main() {
 		os.Exit(1)
 	}
 	defer vs.Close()
-	if err := vs.EnsureCollection(ctx, vectorDims); err != nil {
+	model := semantic.EmbeddingModel{Name: *ollamaModel, Version: *embedVer, Dims: *vectorDims}
+	target, err := vs.EnsureAlias(ctx, *collection, model)
+	if err != nil {
 		log.Error("qdrant ensure collection failed", "error", err)
 		os.Exit(1)
 	}
-	log.Info("connected to Qdrant", "collection", *collection, "dims", vectorDims)
+	// Writing one model's vectors into another model's collection makes them unsearchable.
+	if err := vs.CheckModel(ctx, model); err != nil {
+		log.Error("qdrant model check failed", "alias", *collection, "target", target, "model", *ollamaModel, "version", *embedVer, "error", err)
+		os.Exit(1)
+	}
+	log.Info("connected to Qdrant", "collection", *collection, "target", target, "dims", *vectorDims)
 
 	// Ollama embedder
-	embedder := ollama.NewEmbedClient(*ollamaURL, *ollamaModel)
+	embedder := meteredEmbedder{ollama.NewEmbedClient(*ollamaURL, *ollamaModel)}
 	log.Info("using Ollama embeddings", "model", *ollamaModel)
 
+	scrub, err := loadScrubPolicies(*scrubFile)
+	if err != nil {
+		log.Error("load scrub policy failed", "error", err)
+		os.Exit(1)
+	}
+
 	// Graph store
 	gs := graph.New(driver)
 
+	// Set once CheckModel sees the alias move; the process then exits non-zero.
+	var aliasMoved atomic.Bool
+
 	// Dedup map
 	var mu sync.Mutex
 	seen := make(map[string]bool)
@@ -131,10 +162,30 @@ func main() {
 			seen[docID] = true
 			return false, nil
 		},
-		Logger: log,
+		Tombstoned: gs.IsTombstoned,
+		// cmd/reembed may switch the alias while we run; stop rather than
+		// keep writing into the other model's collection.
+		CheckModel: func(ctx context.Context) error {
+			err := vs.CheckModel(ctx, model)
+			if errors.Is(err, semantic.ErrModelMismatch) && aliasMoved.CompareAndSwap(false, true) {
+				log.Error("alias moved to a collection built by a different embedding model, shutting down", "error", err)
+				stop()
+			}
+			return err
+		},
+		Logger:     log,
+		Model:      model,
+		Scrub:      scrub,
+		Quality:    ingest.QualityOptions{Threshold: *minQuality, TagOnly: *tagLowQuality},
 	}
 
-	pipeline := ingest.NewPipeline(deps)
+	pipeline := ingest.NewBatchPipeline(deps, ingest.BatchOptions{
+		PrepareWorkers:  *prepareWorkers,
+		EmbedWorkers:    *embedWorkers,
+		StoreWorkers:    *storeWorkers,
+		EmbedBatchSize:  *embedBatch,
+		UpsertBatchSize: *upsertBatch,
+	})
 
 	// Load state
 	processed := loadState(*stateFile)
@@ -200,6 +251,9 @@ func main() {
 		select {
 		case <-ctx.Done():
 			log.Info("shutting down")
+			if aliasMoved.Load() {
+				os.Exit(1)
+			}
 			return
 		case <-ticker.C:
 			scan()
@@ -207,148 +261,23 @@ func main() {
 	}
 }
 
-// rawPost is a union of possible scraped formats (Reddit, NHTSA, iFixit, etc.)
-type rawPost struct {
-	// ScrapedPost fields
-	Source      string    `json:"source"`
-	SourceID    string    `json:"source_id"`
-	Content     string    `json:"content"`
-	
-	// Reddit raw fields
-	ID          string    `json:"id"`
-	Subreddit   string    `json:"subreddit"`
-	Title       string    `json:"title"`
-	Author      string    `json:"author"`
-	SelfText    string    `json:"self_text"`
-	URL         string    `json:"url"`
-	Permalink   string    `json:"permalink"`
-	CreatedUTC  time.Time `json:"created_utc"`
-	ScrapedAt   time.Time `json:"scraped_at"`
-	
-	// NHTSA fields
-	ODINumber   string    `json:"odi_number"`
-	Make        string    `json:"make"`
-	Model       string    `json:"model"`
-	Year        int       `json:"year"`
-	Summary     string    `json:"summary"`
-	Complaint   string    `json:"complaint"`
-	DateAdded   string    `json:"date_added"`
-	
-	// iFixit fields
-	GuideID     int       `json:"guide_id"`
-	GuideName   string    `json:"guide_name"`
-	Category    string    `json:"category"`
-	Summary2    string    `json:"summary"` // iFixit uses summary too
-}
-
-func (r rawPost) toScrapedPost() scraper.ScrapedPost {
-	// Already in ScrapedPost format
-	if r.Source != "" && r.SourceID != "" {
-		return scraper.ScrapedPost{
-			Source:    r.Source,
-			SourceID:  r.SourceID,
-			Title:     r.Title,
-			Content:   r.Content,
-			Author:    r.Author,
-			URL:       r.URL,
-			ScrapedAt: r.ScrapedAt,
-		}
-	}
-	
-	// Reddit format
-	if r.Subreddit != "" {
-		content := r.SelfText
-		if content == "" {
-			content = r.Title
-		}
-		return scraper.ScrapedPost{
-			Source:      "reddit:" + r.Subreddit,
-			SourceID:    r.ID,
-			Title:       r.Title,
-			Content:     content,
-			Author:      r.Author,
-			URL:         "https://reddit.com" + r.Permalink,
-			PublishedAt: r.CreatedUTC,
-			ScrapedAt:   r.ScrapedAt,
-		}
-	}
-	
-	// NHTSA format
-	if r.ODINumber != "" {
-		vehicle := fmt.Sprintf("%d %s %s", r.Year, r.Make, r.Model)
-		content := r.Complaint
-		if content == "" {
-			content = r.Summary
-		}
-		var vi *scraper.VehicleInfo
-		if r.Make != "" && r.Model != "" && r.Year > 0 {
-			vi = &scraper.VehicleInfo{
-				Make:  r.Make,
-				Model: r.Model,
-				Year:  r.Year,
-			}
-		}
-		return scraper.ScrapedPost{
-			Source:   "nhtsa",
-			SourceID: r.ODINumber,
-			Title:    vehicle + " - NHTSA Complaint",
-			Content:  content,
-			URL:      "https://www.nhtsa.gov/",
-			Metadata: scraper.Metadata{
-				Vehicle:     vehicle,
-				VehicleInfo: vi,
-				Components:  r.Summary,
-			},
-		}
-	}
-	
-	// iFixit format
-	if r.GuideID != 0 || r.GuideName != "" {
-		return scraper.ScrapedPost{
-			Source:   "ifixit",
-			SourceID: fmt.Sprintf("%d", r.GuideID),
-			Title:    r.GuideName,
-			Content:  r.Summary2,
-		}
-	}
-	
-	return scraper.ScrapedPost{}
-}
-
-func processFile(ctx context.Context, path string, pipeline fn.Stage[scraper.ScrapedPost, string]) (int, int) {
+func processFile(ctx context.Context, path string, pipeline *ingest.BatchPipeline) (int, int) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return 0, 1
 	}
 
+	// Each record is either a ScrapedPost or a source's native format
+	// (e.g. raw Reddit output); the source adapters decode the latter.
 	var posts []scraper.ScrapedPost
-
-	// Try decoding as ScrapedPost first (NHTSA, sources scrapers output this format)
-	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec := json.NewDecoder(bytes.NewReader(data))
 	for {
-		var post scraper.ScrapedPost
-		if err := dec.Decode(&post); err != nil {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
 			break
 		}
-		// If it decoded as ScrapedPost with content, use it directly
-		if post.SourceID != "" && post.Content != "" {
+		if post, ok := scraper.DecodeRecord(raw); ok {
 			posts = append(posts, post)
-			continue
-		}
-	}
-
-	// If no posts found, try raw format (Reddit raw output)
-	if len(posts) == 0 {
-		dec2 := json.NewDecoder(strings.NewReader(string(data)))
-		for {
-			var raw rawPost
-			if err := dec2.Decode(&raw); err != nil {
-				break
-			}
-			post := raw.toScrapedPost()
-			if post.SourceID != "" && post.Content != "" {
-				posts = append(posts, post)
-			}
 		}
 	}
 
@@ -368,32 +297,81 @@ func processFile(ctx context.Context, path string, pipeline fn.Stage[scraper.Scr
 
 	count, errs := 0, 0
 	log := slog.Default()
-	for _, p := range posts {
-		if ctx.Err() != nil {
-			break
+	mActiveDocs.Set(int64(len(posts)))
+	start := time.Now()
+	results := pipeline.Ingest(ctx, posts)
+	mActiveDocs.Set(0)
+	if len(posts) > 0 {
+		mPipelineDur.Observe(time.Since(start).Seconds() / float64(len(posts)))
+	}
+	for i, r := range results {
+		if errors.Is(r.Err, ingest.ErrLowQuality) {
+			log.Debug("dropped low-quality document", "source_id", r.SourceID, "reason", r.Err)
+			mDocsLowQuality.Inc()
+			continue
+		}
+		if errors.Is(r.Err, ingest.ErrRetracted) {
+			log.Debug("skipped retracted document", "source_id", r.SourceID)
+			mDocsRetracted.Inc()
+			continue
 		}
-		mActiveDocs.Inc()
-		docStart := time.Now()
-		result := pipeline(ctx, p)
-		mPipelineDur.Since(docStart)
-		mActiveDocs.Dec()
-		if result.IsErr() {
-			_, err := result.Unwrap()
-			log.Error("pipeline error", "source_id", p.SourceID, "error", err)
+		if r.Err != nil {
+			log.Error("pipeline error", "source_id", r.SourceID, "error", r.Err)
 			mErrorsTotal("pipeline").Inc()
 			errs++
-		} else {
-			source := p.Source
-			if idx := strings.IndexByte(source, ':'); idx > 0 {
-				source = source[:idx]
-			}
-			mDocsTotal(source).Inc()
-			count++
+			continue
 		}
+		src, _ := scraper.LookupSource(posts[i].Source)
+		mDocsTotal(src.Name).Inc()
+		mChunksTotal.Add(int64(r.Chunks))
+		mQdrantWrites.Add(int64(r.Chunks))
+		count++
 	}
 	return count, errs
 }
 
+// meteredEmbedder records batch size and latency of every embedding call.
+type meteredEmbedder struct {
+	mlpb.EmbedServiceClient
+}
+
+func (m meteredEmbedder) EmbedBatch(ctx context.Context, in *mlpb.EmbedBatchRequest, opts ...grpc.CallOption) (*mlpb.EmbedBatchResponse, error) {
+	mEmbedBatchSize.Observe(float64(len(in.GetTexts())))
+	start := time.Now()
+	resp, err := m.EmbedServiceClient.EmbedBatch(ctx, in, opts...)
+	mEmbedDur.Since(start)
+	if err != nil {
+		mErrorsTotal("embed").Inc()
+	} else {
+		mEmbeddingsTotal.Add(int64(len(resp.GetEmbeddings())))
+	}
+	return resp, err
+}
+
+// loadScrubPolicies overlays the policies in path (if any) on the defaults, e.g.
+// {"forum": {"usernames": true, "emails": true}, "*": {"emails": true}}.
+func loadScrubPolicies(path string) (ingest.ScrubPolicies, error) {
+	policies := make(ingest.ScrubPolicies, len(ingest.DefaultScrubPolicies))
+	for k, v := range ingest.DefaultScrubPolicies {
+		policies[k] = v
+	}
+	if path == "" {
+		return policies, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var overrides ingest.ScrubPolicies
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	for k, v := range overrides {
+		policies[k] = v
+	}
+	return policies, nil
+}
+
 func loadState(path string) map[string]bool {
 	m := make(map[string]bool)
 	data, err := os.ReadFile(path)
@@ -0,0 +1,106 @@
+// Command reembed rebuilds the vector collection behind a Qdrant alias with a
+// different embedding model, using the chunk text already stored in Qdrant.
+//
+// The new vectors go into a versioned collection ("<alias>_<model>_v<N>");
+// readers keep using the alias until the copy is complete, then the alias is
+// switched atomically. Points written, updated or deleted in the source while
+// the copy ran are carried over before the switch. The previous collection is kept for -rollback until it
+// is removed with -drop-previous.
+//
+// Stop cmd/ingest before switching and restart it with the new -model and
+// -embed-version so new documents land in the new collection.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+
+	"github.com/WessleyAI/wessley-mvp/engine/semantic"
+	"github.com/WessleyAI/wessley-mvp/pkg/fn"
+	"github.com/WessleyAI/wessley-mvp/pkg/ollama"
+)
+
+func main() {
+	var (
+		qdrantAddr = flag.String("qdrant", "localhost:6334", "Qdrant gRPC address")
+		alias      = flag.String("alias", "wessley", "collection alias read by cmd/api and cmd/chat")
+		from       = flag.String("from", "", "source collection (default: the alias's current target)")
+		ollamaURL  = flag.String("ollama", "http://localhost:11434", "Ollama base URL")
+		model      = flag.String("model", "nomic-embed-text", "new embedding model")
+		version    = flag.Int("embed-version", 1, "revision of -model; bump to rebuild with the same model")
+		dims       = flag.Int("dims", 768, "embedding dimensions of -model")
+		stateFile  = flag.String("state", "/tmp/wessley-data/.reembed-state.json", "progress file for resuming")
+		batch      = flag.Int("batch", 256, "points read per scroll page")
+		workers    = flag.Int("workers", 4, "concurrent embedding requests")
+		doSwitch   = flag.Bool("switch", true, "switch the alias once the new collection is complete")
+		rollback   = flag.Bool("rollback", false, "point the alias back at the collection it served before the last switch")
+		dropPrev   = flag.Bool("drop-previous", false, "delete the collection replaced by the last switch")
+		status     = flag.Bool("status", false, "print the saved progress and exit")
+	)
+	flag.Parse()
+
+	log := slog.Default()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if *status {
+		st, err := loadRunState(*stateFile)
+		if err != nil {
+			fatal(log, "load state", err)
+		}
+		if st == nil {
+			fmt.Println("no re-embedding run recorded")
+			return
+		}
+		out, _ := json.MarshalIndent(st, "", "  ")
+		fmt.Println(string(out))
+		return
+	}
+
+	vs, err := semantic.New(*qdrantAddr, *alias)
+	if err != nil {
+		fatal(log, "qdrant connect", err)
+	}
+	defer vs.Close()
+
+	r := &reembedder{
+		store:     vs,
+		embedder:  ollama.NewEmbedClient(*ollamaURL, *model),
+		model:     semantic.EmbeddingModel{Name: *model, Version: *version, Dims: *dims},
+		statePath: *stateFile,
+		batch:     *batch,
+		workers:   max(*workers, 1),
+		retry:     fn.DefaultRetry,
+		log:       log,
+	}
+
+	switch {
+	case *rollback:
+		if err := r.rollback(ctx); err != nil {
+			fatal(log, "rollback", err)
+		}
+	case *dropPrev:
+		if err := r.dropPrevious(ctx); err != nil {
+			fatal(log, "drop previous", err)
+		}
+	default:
+		st, err := r.start(ctx, *alias, *from)
+		if err != nil {
+			fatal(log, "start", err)
+		}
+		if err := r.run(ctx, st, *doSwitch); err != nil {
+			fatal(log, "re-embed (rerun to resume)", err)
+		}
+		log.Info("re-embedding done", "phase", st.Phase, "target", st.Target, "copied", st.Copied, "skipped", st.Skipped, "deleted", st.Deleted)
+	}
+}
+
+func fatal(log *slog.Logger, msg string, err error) {
+	log.Error(msg+" failed", "error", err)
+	os.Exit(1)
+}
@@ -0,0 +1,368 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/WessleyAI/wessley-mvp/engine/semantic"
+	mlpb "github.com/WessleyAI/wessley-mvp/ml/proto/wessley/ml/v1"
+	"github.com/WessleyAI/wessley-mvp/pkg/fn"
+)
+
+// Phases of a re-embedding run, in order.
+const (
+	phaseCopy       = "copy"        // scrolling the source and writing re-embedded points
+	phaseCatchUp    = "catchup"     // re-scanning the source for points written or updated during the copy
+	phaseReconcile  = "reconcile"   // scanning the target for points deleted from the source during the copy
+	phaseReady      = "ready"       // target complete; alias not yet switched
+	phaseSwitched   = "switched"    // alias points at the target
+	phaseRolledBack = "rolled-back" // alias pointed back at the previous collection
+)
+
+// runState is persisted after every batch so an interrupted run resumes where it stopped.
+type runState struct {
+	Alias     string    `json:"alias"`
+	Source    string    `json:"source"`
+	Target    string    `json:"target"`
+	Model     string    `json:"model"`
+	Version   int       `json:"version"`
+	Phase     string    `json:"phase"`
+	Offset    string    `json:"offset,omitempty"` // next scroll offset in Source
+	Total     uint64    `json:"total"`            // Source point count when the run started
+	Copied    int64     `json:"copied"`
+	Skipped   int64     `json:"skipped"` // points without stored content
+	Deleted   int64     `json:"deleted"` // target points whose source point was deleted during the copy
+	Previous  string    `json:"previous,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// finished reports whether the run needs no further work.
+func (s *runState) finished() bool {
+	return s.Phase == phaseSwitched || s.Phase == phaseRolledBack
+}
+
+func loadRunState(path string) (*runState, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read state: %w", err)
+	}
+	var s runState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse state %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// save writes the state atomically so a crash never leaves a torn file.
+func (s *runState) save(path string) error {
+	s.UpdatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write state: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// reembedder copies stored chunk text from one collection into a new one,
+// embedding it with a different model.
+type reembedder struct {
+	store     *semantic.VectorStore // connection; collections are selected with WithCollection
+	embedder  mlpb.EmbedServiceClient
+	model     semantic.EmbeddingModel
+	statePath string
+	batch     int // points per scroll page
+	workers   int // concurrent embedding requests per page
+	retry     fn.RetryOpts
+	log       *slog.Logger
+}
+
+// start returns the state for a run into model's collection behind alias,
+// resuming a matching unfinished run when one exists.
+func (r *reembedder) start(ctx context.Context, alias, from string) (*runState, error) {
+	target := r.model.CollectionName(alias)
+
+	prev, err := loadRunState(r.statePath)
+	if err != nil {
+		return nil, err
+	}
+	if prev != nil && !prev.finished() {
+		if prev.Target != target {
+			return nil, fmt.Errorf("unfinished run into %s (phase %s); finish or roll it back first", prev.Target, prev.Phase)
+		}
+		r.log.Info("resuming re-embedding", "target", target, "phase", prev.Phase, "copied", prev.Copied, "total", prev.Total)
+		return prev, nil
+	}
+
+	source := from
+	if source == "" {
+		if source, err = r.store.ResolveAlias(ctx, alias); err != nil {
+			return nil, err
+		}
+		if source == "" {
+			return nil, fmt.Errorf("%s is not an alias; pass -from <collection> and serve the result under a new alias", alias)
+		}
+	}
+	if source == target {
+		return nil, fmt.Errorf("%s already holds %s v%d vectors; bump -embed-version", target, r.model.Name, r.model.Version)
+	}
+
+	total, err := r.store.WithCollection(source).Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.store.WithCollection(target).EnsureCollection(ctx, r.model.Dims); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	st := &runState{
+		Alias:     alias,
+		Source:    source,
+		Target:    target,
+		Model:     r.model.Name,
+		Version:   r.model.Version,
+		Phase:     phaseCopy,
+		Total:     total,
+		StartedAt: now,
+	}
+	r.log.Info("starting re-embedding", "alias", alias, "source", source, "target", target, "points", total)
+	return st, st.save(r.statePath)
+}
+
+// run drives st through the copy, catch-up and reconcile phases. With
+// doSwitch it then points the alias at the target.
+func (r *reembedder) run(ctx context.Context, st *runState, doSwitch bool) error {
+	src := r.store.WithCollection(st.Source)
+	dst := r.store.WithCollection(st.Target)
+
+	for st.Phase == phaseCopy || st.Phase == phaseCatchUp {
+		page, next, err := src.Scroll(ctx, st.Offset, r.batch)
+		if err != nil {
+			return err
+		}
+		if st.Phase == phaseCatchUp {
+			if page, err = r.stale(ctx, dst, page); err != nil {
+				return err
+			}
+		}
+		if err := r.copyPage(ctx, dst, page, st); err != nil {
+			return err
+		}
+
+		st.Offset = next
+		if next == "" {
+			if st.Phase == phaseCopy {
+				st.Phase = phaseCatchUp
+			} else {
+				st.Phase = phaseReconcile
+			}
+		}
+		if err := st.save(r.statePath); err != nil {
+			return err
+		}
+		r.progress(st)
+	}
+
+	// Retractions during the copy deleted points from the source only; drop
+	// them from the target too so they do not come back with the switch.
+	for st.Phase == phaseReconcile {
+		page, next, err := dst.Scroll(ctx, st.Offset, r.batch)
+		if err != nil {
+			return err
+		}
+		ids := fn.Map(page, func(rec semantic.VectorRecord) string { return rec.ID })
+		have, err := src.Existing(ctx, ids)
+		if err != nil {
+			return err
+		}
+		gone := fn.Filter(ids, func(id string) bool { return !have[id] })
+		if err := dst.Delete(ctx, gone); err != nil {
+			return err
+		}
+		st.Deleted += int64(len(gone))
+
+		st.Offset = next
+		if next == "" {
+			st.Phase = phaseReady
+		}
+		if err := st.save(r.statePath); err != nil {
+			return err
+		}
+		r.progress(st)
+	}
+
+	if st.Phase == phaseReady && doSwitch {
+		return r.switchTo(ctx, st)
+	}
+	return nil
+}
+
+// stale filters page down to points the target does not have yet or holds
+// with a different payload, as after a re-ingest during the copy.
+func (r *reembedder) stale(ctx context.Context, dst *semantic.VectorStore, page []semantic.VectorRecord) ([]semantic.VectorRecord, error) {
+	ids := fn.Map(page, func(rec semantic.VectorRecord) string { return rec.ID })
+	have, err := dst.Get(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	copied := make(map[string]map[string]any, len(have))
+	for _, rec := range have {
+		copied[rec.ID] = rec.Payload
+	}
+	return fn.Filter(page, func(rec semantic.VectorRecord) bool {
+		payload, ok := copied[rec.ID]
+		return !ok || !r.samePayload(rec.Payload, payload)
+	}), nil
+}
+
+// samePayload compares a source payload with its copy, ignoring the
+// embedding model fields the copy replaced.
+func (r *reembedder) samePayload(src, dst map[string]any) bool {
+	model := r.model.Payload()
+	strip := func(p map[string]any) map[string]any {
+		out := make(map[string]any, len(p))
+		for k, v := range p {
+			if _, ok := model[k]; !ok {
+				out[k] = v
+			}
+		}
+		return out
+	}
+	return reflect.DeepEqual(strip(src), strip(dst))
+}
+
+// copyPage re-embeds the stored content of page and writes it to dst under the same IDs.
+func (r *reembedder) copyPage(ctx context.Context, dst *semantic.VectorStore, page []semantic.VectorRecord, st *runState) error {
+	usable := fn.Filter(page, func(rec semantic.VectorRecord) bool {
+		content, _ := rec.Payload["content"].(string)
+		return content != ""
+	})
+	if st.Phase == phaseCopy {
+		// Catch-up sees the same content-less points again; count them once.
+		st.Skipped += int64(len(page) - len(usable))
+	}
+	if len(usable) == 0 {
+		return nil
+	}
+
+	size := (len(usable) + r.workers - 1) / r.workers
+	results := fn.ParMapResult(fn.Chunk(usable, size), r.workers, func(part []semantic.VectorRecord) fn.Result[[]semantic.VectorRecord] {
+		return r.embed(ctx, part)
+	})
+	embedded, err := fn.Collect(results).Unwrap()
+	if err != nil {
+		return err
+	}
+	records := fn.FlatMap(embedded, func(part []semantic.VectorRecord) []semantic.VectorRecord { return part })
+	if err := dst.Upsert(ctx, records); err != nil {
+		return err
+	}
+	st.Copied += int64(len(records))
+	return nil
+}
+
+func (r *reembedder) embed(ctx context.Context, part []semantic.VectorRecord) fn.Result[[]semantic.VectorRecord] {
+	texts := fn.Map(part, func(rec semantic.VectorRecord) string { return rec.Payload["content"].(string) })
+	resp, err := fn.Retry(ctx, r.retry, func(ctx context.Context) fn.Result[*mlpb.EmbedBatchResponse] {
+		return fn.FromPair(r.embedder.EmbedBatch(ctx, &mlpb.EmbedBatchRequest{Texts: texts}))
+	}).Unwrap()
+	if err != nil {
+		return fn.Err[[]semantic.VectorRecord](fmt.Errorf("embed: %w", err))
+	}
+	if len(resp.GetEmbeddings()) != len(part) {
+		return fn.Errf[[]semantic.VectorRecord]("embed: got %d embeddings for %d texts", len(resp.GetEmbeddings()), len(part))
+	}
+	out := make([]semantic.VectorRecord, len(part))
+	for i, rec := range part {
+		payload := make(map[string]any, len(rec.Payload)+2)
+		for k, v := range rec.Payload {
+			payload[k] = v
+		}
+		for k, v := range r.model.Payload() {
+			payload[k] = v
+		}
+		out[i] = semantic.VectorRecord{ID: rec.ID, Embedding: resp.GetEmbeddings()[i].GetValues(), Payload: payload}
+	}
+	return fn.Ok(out)
+}
+
+func (r *reembedder) progress(st *runState) {
+	elapsed := time.Since(st.StartedAt)
+	attrs := []any{"phase", st.Phase, "copied", st.Copied, "total", st.Total, "skipped", st.Skipped, "deleted", st.Deleted, "elapsed", elapsed.Round(time.Second)}
+	if st.Total > 0 && st.Copied > 0 && st.Phase == phaseCopy {
+		pct := float64(st.Copied) / float64(st.Total)
+		eta := time.Duration(float64(elapsed) * (1 - pct) / pct)
+		attrs = append(attrs, "percent", fmt.Sprintf("%.1f", pct*100), "eta", eta.Round(time.Second))
+	}
+	r.log.Info("re-embedding progress", attrs...)
+}
+
+// switchTo points the alias at the target, remembering the old collection for rollback.
+func (r *reembedder) switchTo(ctx context.Context, st *runState) error {
+	current, err := r.store.ResolveAlias(ctx, st.Alias)
+	if err != nil {
+		return err
+	}
+	if err := r.store.SwitchAlias(ctx, st.Alias, st.Target); err != nil {
+		return err
+	}
+	st.Previous = current
+	if st.Previous == "" {
+		st.Previous = st.Source
+	}
+	st.Phase = phaseSwitched
+	r.log.Info("alias switched", "alias", st.Alias, "from", st.Previous, "to", st.Target)
+	return st.save(r.statePath)
+}
+
+// rollback points the alias back at the collection it served before the switch.
+func (r *reembedder) rollback(ctx context.Context) error {
+	st, err := loadRunState(r.statePath)
+	if err != nil {
+		return err
+	}
+	if st == nil || st.Phase != phaseSwitched || st.Previous == "" {
+		return fmt.Errorf("nothing to roll back in %s", r.statePath)
+	}
+	if err := r.store.SwitchAlias(ctx, st.Alias, st.Previous); err != nil {
+		return err
+	}
+	st.Phase = phaseRolledBack
+	r.log.Info("alias rolled back", "alias", st.Alias, "to", st.Previous, "kept", st.Target)
+	return st.save(r.statePath)
+}
+
+// dropPrevious deletes the collection replaced by the last switch. After this
+// the switch can no longer be rolled back.
+func (r *reembedder) dropPrevious(ctx context.Context) error {
+	st, err := loadRunState(r.statePath)
+	if err != nil {
+		return err
+	}
+	if st == nil || st.Phase != phaseSwitched || st.Previous == "" {
+		return fmt.Errorf("no switched run with a previous collection in %s", r.statePath)
+	}
+	if current, err := r.store.ResolveAlias(ctx, st.Alias); err != nil {
+		return err
+	} else if current == st.Previous {
+		return fmt.Errorf("alias %s still points at %s", st.Alias, st.Previous)
+	}
+	if err := r.store.WithCollection(st.Previous).DeleteCollection(ctx); err != nil {
+		return err
+	}
+	r.log.Info("previous collection dropped", "collection", st.Previous)
+	st.Previous = ""
+	return st.save(r.statePath)
+}
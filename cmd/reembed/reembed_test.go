@@ -0,0 +1,388 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/WessleyAI/wessley-mvp/engine/semantic"
+	mlpb "github.com/WessleyAI/wessley-mvp/ml/proto/wessley/ml/v1"
+	"github.com/WessleyAI/wessley-mvp/pkg/fn"
+	pb "github.com/qdrant/go-client/qdrant"
+	"google.golang.org/grpc"
+)
+
+// fakeQdrant is an in-memory Qdrant with collections, aliases and scrolling.
+type fakeQdrant struct {
+	collections map[string]map[string]*pb.PointStruct
+	aliases     map[string]string
+	failScroll  int // fail the scroll call with this 1-based index
+	scrolls     int
+}
+
+func newFakeQdrant() *fakeQdrant {
+	return &fakeQdrant{collections: map[string]map[string]*pb.PointStruct{}, aliases: map[string]string{}}
+}
+
+func (f *fakeQdrant) resolve(name string) string {
+	if c, ok := f.aliases[name]; ok {
+		return c
+	}
+	return name
+}
+
+func (f *fakeQdrant) Upsert(_ context.Context, in *pb.UpsertPoints, _ ...grpc.CallOption) (*pb.PointsOperationResponse, error) {
+	col := f.collections[f.resolve(in.CollectionName)]
+	if col == nil {
+		return nil, fmt.Errorf("no collection %s", in.CollectionName)
+	}
+	for _, p := range in.Points {
+		col[p.Id.GetUuid()] = p
+	}
+	return &pb.PointsOperationResponse{}, nil
+}
+
+func (f *fakeQdrant) Delete(_ context.Context, in *pb.DeletePoints, _ ...grpc.CallOption) (*pb.PointsOperationResponse, error) {
+	col := f.collections[f.resolve(in.CollectionName)]
+	for _, id := range in.GetPoints().GetPoints().GetIds() {
+		delete(col, id.GetUuid())
+	}
+	return &pb.PointsOperationResponse{}, nil
+}
+
+func (f *fakeQdrant) Search(_ context.Context, _ *pb.SearchPoints, _ ...grpc.CallOption) (*pb.SearchResponse, error) {
+	return &pb.SearchResponse{}, nil
+}
+
+func (f *fakeQdrant) sortedIDs(name string) []string {
+	var ids []string
+	for id := range f.collections[f.resolve(name)] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (f *fakeQdrant) Scroll(_ context.Context, in *pb.ScrollPoints, _ ...grpc.CallOption) (*pb.ScrollResponse, error) {
+	f.scrolls++
+	if f.scrolls == f.failScroll {
+		return nil, fmt.Errorf("connection reset")
+	}
+	ids := f.sortedIDs(in.CollectionName)
+	start := 0
+	if in.Offset != nil {
+		start = sort.SearchStrings(ids, in.Offset.GetUuid())
+	}
+	end := min(start+int(in.GetLimit()), len(ids))
+	resp := &pb.ScrollResponse{}
+	for _, id := range ids[start:end] {
+		p := f.collections[f.resolve(in.CollectionName)][id]
+		resp.Result = append(resp.Result, &pb.RetrievedPoint{Id: p.Id, Payload: p.Payload})
+	}
+	if end < len(ids) {
+		resp.NextPageOffset = &pb.PointId{PointIdOptions: &pb.PointId_Uuid{Uuid: ids[end]}}
+	}
+	return resp, nil
+}
+
+func (f *fakeQdrant) Count(_ context.Context, in *pb.CountPoints, _ ...grpc.CallOption) (*pb.CountResponse, error) {
+	return &pb.CountResponse{Result: &pb.CountResult{Count: uint64(len(f.collections[f.resolve(in.CollectionName)]))}}, nil
+}
+
+func (f *fakeQdrant) Get(_ context.Context, in *pb.GetPoints, _ ...grpc.CallOption) (*pb.GetResponse, error) {
+	resp := &pb.GetResponse{}
+	col := f.collections[f.resolve(in.CollectionName)]
+	for _, id := range in.Ids {
+		if p, ok := col[id.GetUuid()]; ok {
+			rp := &pb.RetrievedPoint{Id: p.Id}
+			if in.GetWithPayload().GetEnable() {
+				rp.Payload = p.Payload
+			}
+			resp.Result = append(resp.Result, rp)
+		}
+	}
+	return resp, nil
+}
+
+func (f *fakeQdrant) List(_ context.Context, _ *pb.ListCollectionsRequest, _ ...grpc.CallOption) (*pb.ListCollectionsResponse, error) {
+	resp := &pb.ListCollectionsResponse{}
+	for name := range f.collections {
+		resp.Collections = append(resp.Collections, &pb.CollectionDescription{Name: name})
+	}
+	return resp, nil
+}
+
+func (f *fakeQdrant) Create(_ context.Context, in *pb.CreateCollection, _ ...grpc.CallOption) (*pb.CollectionOperationResponse, error) {
+	f.collections[in.CollectionName] = map[string]*pb.PointStruct{}
+	return &pb.CollectionOperationResponse{Result: true}, nil
+}
+
+func (f *fakeQdrant) dropCollection(name string) { delete(f.collections, name) }
+
+func (f *fakeQdrant) UpdateAliases(_ context.Context, in *pb.ChangeAliases, _ ...grpc.CallOption) (*pb.CollectionOperationResponse, error) {
+	for _, a := range in.Actions {
+		switch op := a.Action.(type) {
+		case *pb.AliasOperations_DeleteAlias:
+			delete(f.aliases, op.DeleteAlias.AliasName)
+		case *pb.AliasOperations_CreateAlias:
+			f.aliases[op.CreateAlias.AliasName] = op.CreateAlias.CollectionName
+		}
+	}
+	return &pb.CollectionOperationResponse{Result: true}, nil
+}
+
+func (f *fakeQdrant) ListAliases(_ context.Context, _ *pb.ListAliasesRequest, _ ...grpc.CallOption) (*pb.ListAliasesResponse, error) {
+	resp := &pb.ListAliasesResponse{}
+	for a, c := range f.aliases {
+		resp.Aliases = append(resp.Aliases, &pb.AliasDescription{AliasName: a, CollectionName: c})
+	}
+	return resp, nil
+}
+
+// collectionsAPI adapts the fake's collection Delete, whose name clashes with points Delete.
+type collectionsAPI struct{ *fakeQdrant }
+
+func (c collectionsAPI) Delete(_ context.Context, in *pb.DeleteCollection, _ ...grpc.CallOption) (*pb.CollectionOperationResponse, error) {
+	c.dropCollection(in.CollectionName)
+	return &pb.CollectionOperationResponse{Result: true}, nil
+}
+
+// fakeEmbedder counts batch calls; copyPage embeds in parallel.
+type fakeEmbedder struct{ calls atomic.Int64 }
+
+func (e *fakeEmbedder) Embed(_ context.Context, _ *mlpb.EmbedRequest, _ ...grpc.CallOption) (*mlpb.EmbedResponse, error) {
+	return nil, nil
+}
+
+func (e *fakeEmbedder) EmbedBatch(_ context.Context, in *mlpb.EmbedBatchRequest, _ ...grpc.CallOption) (*mlpb.EmbedBatchResponse, error) {
+	e.calls.Add(1)
+	resp := &mlpb.EmbedBatchResponse{}
+	for range in.Texts {
+		resp.Embeddings = append(resp.Embeddings, &mlpb.EmbedResponse{Values: []float32{0, 1}})
+	}
+	return resp, nil
+}
+
+func seed(f *fakeQdrant, collection string, n int) {
+	f.collections[collection] = map[string]*pb.PointStruct{}
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("00000000-0000-0000-0000-%012d", i)
+		content := fmt.Sprintf("chunk %d", i)
+		if i == 3 {
+			content = "" // legacy point without stored text
+		}
+		f.collections[collection][id] = &pb.PointStruct{
+			Id: &pb.PointId{PointIdOptions: &pb.PointId_Uuid{Uuid: id}},
+			Payload: map[string]*pb.Value{
+				"content": {Kind: &pb.Value_StringValue{StringValue: content}},
+				"doc_id":  {Kind: &pb.Value_StringValue{StringValue: "reddit:x"}},
+			},
+		}
+	}
+}
+
+func newTestReembedder(t *testing.T, f *fakeQdrant) (*reembedder, *fakeEmbedder) {
+	t.Helper()
+	emb := &fakeEmbedder{}
+	return &reembedder{
+		store:     semantic.NewWithClients(f, collectionsAPI{f}, "wessley"),
+		embedder:  emb,
+		model:     semantic.EmbeddingModel{Name: "mxbai-embed-large", Version: 1, Dims: 2},
+		statePath: filepath.Join(t.TempDir(), "state.json"),
+		batch:     4,
+		workers:   2,
+		retry:     fn.RetryOpts{MaxAttempts: 1},
+		log:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}, emb
+}
+
+func TestReembed_CopiesAndSwitchesAlias(t *testing.T) {
+	f := newFakeQdrant()
+	seed(f, "wessley_nomic-embed-text_v1", 10)
+	f.aliases["wessley"] = "wessley_nomic-embed-text_v1"
+
+	r, _ := newTestReembedder(t, f)
+	ctx := context.Background()
+	st, err := r.start(ctx, "wessley", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.run(ctx, st, true); err != nil {
+		t.Fatal(err)
+	}
+
+	target := "wessley_mxbai-embed-large_v1"
+	if f.aliases["wessley"] != target {
+		t.Fatalf("alias not switched: %v", f.aliases)
+	}
+	if got := len(f.collections[target]); got != 9 {
+		t.Fatalf("expected 9 re-embedded points (1 without content), got %d", got)
+	}
+	p := f.collections[target]["00000000-0000-0000-0000-000000000000"]
+	if p.Payload["embedding_model"].GetStringValue() != "mxbai-embed-large" || p.Payload["embedding_version"].GetIntegerValue() != 1 {
+		t.Fatalf("model not recorded: %v", p.Payload)
+	}
+	if p.Payload["doc_id"].GetStringValue() != "reddit:x" {
+		t.Fatal("original payload not carried over")
+	}
+	if st.Phase != phaseSwitched || st.Previous != "wessley_nomic-embed-text_v1" || st.Skipped != 1 {
+		t.Fatalf("unexpected final state %+v", st)
+	}
+
+	// Rollback restores the old target and keeps the new collection.
+	if err := r.rollback(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if f.aliases["wessley"] != "wessley_nomic-embed-text_v1" || f.collections[target] == nil {
+		t.Fatalf("rollback failed: %v", f.aliases)
+	}
+}
+
+func TestReembed_ResumesAfterFailure(t *testing.T) {
+	f := newFakeQdrant()
+	seed(f, "old", 10)
+	f.aliases["wessley"] = "old"
+	f.failScroll = 2
+
+	r, emb := newTestReembedder(t, f)
+	ctx := context.Background()
+	st, err := r.start(ctx, "wessley", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.run(ctx, st, true); err == nil {
+		t.Fatal("expected scroll failure")
+	}
+	if f.aliases["wessley"] != "old" {
+		t.Fatal("alias must not switch on failure")
+	}
+	callsBefore := emb.calls.Load()
+
+	// A fresh process picks up the saved offset.
+	st2, err := r.start(ctx, "wessley", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st2.Offset == "" || st2.Copied != 3 {
+		t.Fatalf("expected resume after first page, got %+v", st2)
+	}
+	if err := r.run(ctx, st2, true); err != nil {
+		t.Fatal(err)
+	}
+	if st2.Copied != 9 {
+		t.Fatalf("pages were re-copied: copied=%d", st2.Copied)
+	}
+	if emb.calls.Load()-callsBefore > 4 {
+		t.Fatalf("too many embed calls after resume: %d", emb.calls.Load()-callsBefore)
+	}
+	if f.aliases["wessley"] != "wessley_mxbai-embed-large_v1" {
+		t.Fatal("alias not switched after resume")
+	}
+}
+
+func TestReembed_CatchUpPicksUpLateWrites(t *testing.T) {
+	f := newFakeQdrant()
+	seed(f, "old", 4)
+	f.aliases["wessley"] = "old"
+
+	r, _ := newTestReembedder(t, f)
+	ctx := context.Background()
+	st, err := r.start(ctx, "wessley", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.run(ctx, st, false); err != nil {
+		t.Fatal(err)
+	}
+	if st.Phase != phaseReady || f.aliases["wessley"] != "old" {
+		t.Fatalf("expected ready without switch, got %s", st.Phase)
+	}
+
+	// A point ingested behind the copy cursor is found by the catch-up pass.
+	st.Phase = phaseCatchUp
+	f.collections["old"]["00000000-0000-0000-0000-000000000000a"] = &pb.PointStruct{
+		Id:      &pb.PointId{PointIdOptions: &pb.PointId_Uuid{Uuid: "00000000-0000-0000-0000-000000000000a"}},
+		Payload: map[string]*pb.Value{"content": {Kind: &pb.Value_StringValue{StringValue: "late"}}},
+	}
+	if err := r.run(ctx, st, true); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.collections["wessley_mxbai-embed-large_v1"]) != 4 {
+		t.Fatalf("late write not copied: %d", len(f.collections["wessley_mxbai-embed-large_v1"]))
+	}
+}
+
+func TestReembed_ReconcilesChangesDuringCopy(t *testing.T) {
+	f := newFakeQdrant()
+	seed(f, "old", 6)
+	f.aliases["wessley"] = "old"
+
+	r, _ := newTestReembedder(t, f)
+	ctx := context.Background()
+	st, err := r.start(ctx, "wessley", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.run(ctx, st, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Behind the copy, a document is retracted and another re-ingested.
+	st.Phase, st.Offset = phaseCatchUp, ""
+	retracted, updated := "00000000-0000-0000-0000-000000000001", "00000000-0000-0000-0000-000000000002"
+	delete(f.collections["old"], retracted)
+	f.collections["old"][updated].Payload["content"] = &pb.Value{Kind: &pb.Value_StringValue{StringValue: "corrected"}}
+	copied := st.Copied
+	if err := r.run(ctx, st, true); err != nil {
+		t.Fatal(err)
+	}
+
+	target := f.collections["wessley_mxbai-embed-large_v1"]
+	if _, ok := target[retracted]; ok {
+		t.Error("a point retracted during the copy survived the switch")
+	}
+	if got := target[updated].Payload["content"].GetStringValue(); got != "corrected" {
+		t.Errorf("updated point not re-copied: %q", got)
+	}
+	if st.Copied-copied != 1 || st.Deleted != 1 || f.aliases["wessley"] != "wessley_mxbai-embed-large_v1" {
+		t.Errorf("expected 1 re-copied and 1 deleted point, got %+v", st)
+	}
+}
+
+func TestReembed_Guards(t *testing.T) {
+	f := newFakeQdrant()
+	seed(f, "wessley", 2) // legacy concrete collection, no alias
+
+	r, _ := newTestReembedder(t, f)
+	ctx := context.Background()
+	if _, err := r.start(ctx, "wessley", ""); err == nil || !strings.Contains(err.Error(), "not an alias") {
+		t.Fatalf("expected legacy collection error, got %v", err)
+	}
+	if err := r.rollback(ctx); err == nil {
+		t.Fatal("expected nothing to roll back")
+	}
+
+	// Migrating a legacy collection under a new alias works with -from.
+	st, err := r.start(ctx, "wessley-live", "wessley")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.run(ctx, st, true); err != nil {
+		t.Fatal(err)
+	}
+	if f.aliases["wessley-live"] != "wessley-live_mxbai-embed-large_v1" {
+		t.Fatalf("unexpected aliases %v", f.aliases)
+	}
+	if err := r.dropPrevious(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := f.collections["wessley"]; ok {
+		t.Fatal("previous collection not dropped")
+	}
+}
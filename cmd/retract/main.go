@@ -0,0 +1,142 @@
+// Command retract removes documents from Qdrant and Neo4j and tombstones
+// them so later scrapes are skipped by the ingestion pipeline.
+//
+// Select documents by ID (-doc, repeatable or comma-separated), by source
+// (-source reddit) or by URL glob (-url 'https://example.com/forum/*').
+// A -source or -url retraction also tombstones the filter, so matching
+// documents scraped later are skipped too. Use -dry-run to list the matches
+// first. -list prints the tombstones and -restore lifts one, by document ID
+// or by the "filter:..." ID of a source or URL tombstone.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/WessleyAI/wessley-mvp/engine/graph"
+	"github.com/WessleyAI/wessley-mvp/engine/ingest"
+	"github.com/WessleyAI/wessley-mvp/engine/semantic"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// docIDs collects repeated or comma-separated -doc flags.
+type docIDs []string
+
+func (d *docIDs) String() string { return strings.Join(*d, ",") }
+
+func (d *docIDs) Set(v string) error {
+	for _, id := range strings.Split(v, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			*d = append(*d, id)
+		}
+	}
+	return nil
+}
+
+func main() {
+	var docs docIDs
+	flag.Var(&docs, "doc", "document ID to retract, e.g. reddit:MechanicAdvice:abc123 (repeatable)")
+	var (
+		source     = flag.String("source", "", "retract every document from this source (\"reddit\" includes \"reddit:<sub>\")")
+		urlPattern = flag.String("url", "", "retract every document whose URL matches this glob")
+		reason     = flag.String("reason", "", "why the documents are retracted, recorded on the tombstone")
+		dryRun     = flag.Bool("dry-run", false, "list the matching documents without retracting them")
+		list       = flag.Bool("list", false, "print the tombstones and exit")
+		restore    = flag.String("restore", "", "lift the tombstone with this document or filter ID and exit")
+		neo4jURL   = flag.String("neo4j", "neo4j://localhost:7687", "Neo4j bolt URL")
+		neo4jUser  = flag.String("neo4j-user", "neo4j", "Neo4j username")
+		neo4jPass  = flag.String("neo4j-pass", "wessley123", "Neo4j password")
+		qdrantAddr = flag.String("qdrant", "localhost:6334", "Qdrant gRPC address")
+		collection = flag.String("collection", "wessley", "Qdrant collection alias")
+	)
+	flag.Parse()
+
+	log := slog.Default()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	driver, err := neo4j.NewDriverWithContext(*neo4jURL, neo4j.BasicAuth(*neo4jUser, *neo4jPass, ""))
+	if err != nil {
+		fatal(log, "neo4j connect", err)
+	}
+	defer driver.Close(ctx)
+	gs := graph.New(driver)
+
+	switch {
+	case *list:
+		tombstones, err := gs.Tombstones(ctx)
+		if err != nil {
+			fatal(log, "list tombstones", err)
+		}
+		printJSON(tombstones)
+		return
+	case *restore != "":
+		if err := gs.DeleteTombstone(ctx, *restore); err != nil {
+			fatal(log, "restore", err)
+		}
+		log.Info("tombstone lifted; the document is ingested again on its next scrape", "doc_id", *restore)
+		return
+	}
+
+	if len(docs) == 0 && *source == "" && *urlPattern == "" {
+		fmt.Fprintln(os.Stderr, "retract: one of -doc, -source or -url is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *reason == "" && !*dryRun {
+		fmt.Fprintln(os.Stderr, "retract: -reason is required")
+		os.Exit(2)
+	}
+
+	vs, err := semantic.New(*qdrantAddr, *collection)
+	if err != nil {
+		fatal(log, "qdrant connect", err)
+	}
+	defer vs.Close()
+	r := ingest.NewRetractor(vs, gs, log)
+
+	var results []ingest.RetractResult
+	for _, id := range docs {
+		if *dryRun {
+			results = append(results, ingest.RetractResult{DocID: id})
+			continue
+		}
+		results = append(results, r.Retract(ctx, id, *reason))
+	}
+	if *source != "" || *urlPattern != "" {
+		matched, err := r.RetractWhere(ctx, graph.DocumentFilter{Source: *source, URLPattern: *urlPattern}, *reason, *dryRun)
+		results = append(results, matched...)
+		if err != nil {
+			printJSON(results)
+			fatal(log, "bulk retract", err)
+		}
+	}
+	printJSON(results)
+
+	failed := 0
+	for _, res := range results {
+		if res.Error != "" {
+			failed++
+		}
+	}
+	log.Info("retract done", "documents", len(results), "failed", failed, "dry_run", *dryRun)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func printJSON(v any) {
+	out, _ := json.MarshalIndent(v, "", "  ")
+	fmt.Println(string(out))
+}
+
+func fatal(log *slog.Logger, msg string, err error) {
+	log.Error(msg+" failed", "error", err)
+	os.Exit(1)
+}
@@ -0,0 +1,101 @@
+package reddit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/WessleyAI/wessley-mvp/engine/scraper"
+)
+
+// BackfillWindows are Reddit's time windows for top and search listings,
+// from narrowest to widest.
+var BackfillWindows = []string{"hour", "day", "week", "month", "year", "all"}
+
+// BackfillOpts configures a backfill.
+type BackfillOpts struct {
+	Listing  string   // "top" or "search"
+	Query    string   // search terms; required for "search"
+	Windows  []string // time windows to walk (default: day, week, month, year, all)
+	MaxPages int      // pages per subreddit and window (default 10)
+}
+
+// Backfill walks each subreddit's top or search listing over the time
+// windows in opts, following "after" tokens, to collect older posts the
+// incremental scrape never saw. Each post is returned once, with comments.
+func (s *Scraper) Backfill(ctx context.Context, opts BackfillOpts) ([]Post, error) {
+	switch opts.Listing {
+	case "top":
+	case "search":
+		if opts.Query == "" {
+			return nil, errors.New("reddit: search backfill needs a query")
+		}
+	default:
+		return nil, fmt.Errorf("reddit: unknown backfill listing %q", opts.Listing)
+	}
+	windows := opts.Windows
+	if len(windows) == 0 {
+		windows = BackfillWindows[1:]
+	}
+	maxPages := opts.MaxPages
+	if maxPages <= 0 {
+		maxPages = defaultMaxPages
+	}
+	limit := s.cfg.PostsPerSub
+	if limit <= 0 || limit > maxPageSize {
+		limit = maxPageSize
+	}
+	limiter := scraper.RateLimiter(ctx, s.cfg.RateLimit)
+
+	seen := map[string]bool{}
+	var all []Post
+	for _, sub := range s.cfg.Subreddits {
+		for _, window := range windows {
+			q := url.Values{"t": {window}, "limit": {fmt.Sprint(limit)}, "raw_json": {"1"}}
+			path := fmt.Sprintf("%s/r/%s/top.json", baseURL, sub)
+			if opts.Listing == "search" {
+				path = fmt.Sprintf("%s/r/%s/search.json", baseURL, sub)
+				q.Set("q", opts.Query)
+				q.Set("restrict_sr", "1")
+				q.Set("sort", "top")
+			}
+			for page := 0; page < maxPages; page++ {
+				if err := ctx.Err(); err != nil {
+					return all, err
+				}
+				resp, err := s.fetchListing(ctx, path+"?"+q.Encode(), limiter)
+				if err != nil {
+					log.Printf("warning: backfill r/%s %s/%s: %v", sub, opts.Listing, window, err)
+					break
+				}
+				for _, child := range resp.Data.Children {
+					if child.Kind != "t3" || seen[child.Data.ID] {
+						continue
+					}
+					seen[child.Data.ID] = true
+					all = append(all, s.withComments(ctx, newPost(child.Data), limiter))
+				}
+				if resp.Data.After == "" {
+					break
+				}
+				q.Set("after", resp.Data.After)
+			}
+		}
+	}
+	return all, nil
+}
+
+// BackfillSource runs Backfill under the scraper runtime. It has no cursor;
+// run it once.
+func (s *Scraper) BackfillSource(opts BackfillOpts) scraper.Source {
+	return scraper.FetchAllSource(s.Name(), func(ctx context.Context) ([]scraper.ScrapedPost, error) {
+		posts, err := s.Backfill(ctx, opts)
+		out, convErr := toScrapedPosts(posts)
+		if convErr != nil {
+			return nil, convErr
+		}
+		return out, err
+	})
+}
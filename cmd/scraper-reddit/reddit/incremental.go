@@ -0,0 +1,221 @@
+package reddit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/WessleyAI/wessley-mvp/engine/scraper"
+)
+
+// Defaults for the incremental scrape.
+const (
+	defaultMaxPages      = 10
+	defaultCommentGrowth = 10
+	defaultTrackFor      = 48 * time.Hour
+	maxPageSize          = 100 // Reddit's cap on limit=
+	maxByID              = 100 // fullnames per /by_id request
+)
+
+// State is where an incremental scrape stopped: a high-water mark per
+// subreddit. It is persisted as the runtime cursor.
+type State struct {
+	Subreddits map[string]Mark `json:"subreddits"`
+}
+
+// Mark is a subreddit's high-water mark.
+type Mark struct {
+	Newest  string                 `json:"newest"`            // fullname of the newest post seen, e.g. "t3_1abcde"
+	Created int64                  `json:"created"`           // its created_utc
+	Tracked map[string]TrackedPost `json:"tracked,omitempty"` // recent posts by ID, watched for new comments
+}
+
+// TrackedPost is a recent post's comment count when it was last scraped.
+type TrackedPost struct {
+	Comments int   `json:"comments"`
+	Created  int64 `json:"created"`
+}
+
+// Name implements scraper.Source.
+func (s *Scraper) Name() string { return "reddit" }
+
+// Fetch implements scraper.Source. The cursor is the JSON State of the
+// previous fetch; see FetchSince.
+func (s *Scraper) Fetch(ctx context.Context, cursor string) ([]scraper.ScrapedPost, string, error) {
+	var state State
+	if cursor != "" {
+		if err := json.Unmarshal([]byte(cursor), &state); err != nil {
+			log.Printf("reddit: ignoring unreadable cursor: %v", err)
+		}
+	}
+	posts, state, err := s.FetchSince(ctx, state)
+	out, convErr := toScrapedPosts(posts)
+	if convErr != nil {
+		return nil, "", convErr
+	}
+	next, _ := json.Marshal(state)
+	return out, string(next), err
+}
+
+// FetchSince fetches the posts submitted to each subreddit since its mark
+// in state, following "after" tokens page by page until it reaches the mark
+// (at most MaxPages pages). A subreddit without a mark gets a single page.
+// Posts seen within TrackFor are also re-checked, and come back again with
+// fresh comments once they gained CommentGrowth comments. It returns the
+// posts and the updated state; a subreddit that fails keeps its old mark.
+func (s *Scraper) FetchSince(ctx context.Context, state State) ([]Post, State, error) {
+	limiter := scraper.RateLimiter(ctx, s.cfg.RateLimit)
+	next := State{Subreddits: map[string]Mark{}}
+	for sub, m := range state.Subreddits {
+		next.Subreddits[sub] = m
+	}
+
+	var all []Post
+	for _, sub := range s.cfg.Subreddits {
+		if err := ctx.Err(); err != nil {
+			return all, next, err
+		}
+		posts, mark, err := s.fetchNew(ctx, sub, state.Subreddits[sub], limiter)
+		if err != nil {
+			log.Printf("warning: failed to fetch r/%s: %v", sub, err)
+			continue
+		}
+		updated := s.refreshTracked(ctx, &mark, posts, limiter)
+		all = append(all, posts...)
+		all = append(all, updated...)
+		next.Subreddits[sub] = mark
+	}
+	return all, next, nil
+}
+
+// fetchNew pages through r/sub/new back to mark and returns the new posts,
+// with comments, and the moved mark.
+func (s *Scraper) fetchNew(ctx context.Context, sub string, mark Mark, limiter *rate.Limiter) ([]Post, Mark, error) {
+	maxPages := s.cfg.MaxPages
+	if maxPages <= 0 {
+		maxPages = defaultMaxPages
+	}
+	limit := s.cfg.PostsPerSub
+	if limit <= 0 || limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	fresh := mark.Created == 0
+	newest := mark
+	var (
+		posts []Post
+		after string
+	)
+	for page := 0; page < maxPages; page++ {
+		url := fmt.Sprintf("%s/r/%s/new.json?limit=%d&raw_json=1", baseURL, sub, limit)
+		if after != "" {
+			url += "&after=" + after
+		}
+		resp, err := s.fetchListing(ctx, url, limiter)
+		if err != nil {
+			if page == 0 {
+				return nil, mark, fmt.Errorf("r/%s listing: %w", sub, err)
+			}
+			// Keep the old mark so the next fetch pages through the gap
+			// again; the posts already fetched come back once more.
+			log.Printf("warning: r/%s page %d: %v", sub, page+1, err)
+			return posts, mark, nil
+		}
+
+		reached := false
+		for _, child := range resp.Data.Children {
+			d := child.Data
+			name, created := child.Kind+"_"+d.ID, int64(d.CreatedUTC)
+			if !fresh && (name == mark.Newest || created < mark.Created) {
+				reached = true
+				break
+			}
+			if created > newest.Created {
+				newest.Newest, newest.Created = name, created
+			}
+			posts = append(posts, s.withComments(ctx, newPost(d), limiter))
+		}
+		if reached || fresh || resp.Data.After == "" {
+			break
+		}
+		if page == maxPages-1 {
+			log.Printf("warning: r/%s: %d pages without reaching the last post seen; older posts skipped", sub, maxPages)
+		}
+		after = resp.Data.After
+	}
+	return posts, newest, nil
+}
+
+// refreshTracked records the new posts in mark.Tracked, drops posts older
+// than TrackFor and re-checks the rest. Posts that gained CommentGrowth
+// comments since they were last scraped are returned with fresh comments.
+func (s *Scraper) refreshTracked(ctx context.Context, mark *Mark, fresh []Post, limiter *rate.Limiter) []Post {
+	growth := s.cfg.CommentGrowth
+	if growth <= 0 {
+		growth = defaultCommentGrowth
+	}
+	trackFor := s.cfg.TrackFor
+	if trackFor <= 0 {
+		trackFor = defaultTrackFor
+	}
+	cutoff := time.Now().Add(-trackFor).Unix()
+
+	tracked := map[string]TrackedPost{}
+	var recheck []string
+	for id, t := range mark.Tracked {
+		if t.Created >= cutoff {
+			tracked[id] = t
+			recheck = append(recheck, "t3_"+id)
+		}
+	}
+	for _, p := range fresh {
+		if p.CreatedUTC.Unix() >= cutoff {
+			tracked[p.ID] = TrackedPost{Comments: p.NumComments, Created: p.CreatedUTC.Unix()}
+		}
+	}
+	mark.Tracked = tracked
+
+	var updated []Post
+	for len(recheck) > 0 {
+		batch := recheck[:min(len(recheck), maxByID)]
+		recheck = recheck[len(batch):]
+		url := fmt.Sprintf("%s/by_id/%s.json?raw_json=1", baseURL, strings.Join(batch, ","))
+		resp, err := s.fetchListing(ctx, url, limiter)
+		if err != nil {
+			log.Printf("warning: re-checking %d posts: %v", len(batch), err)
+			continue
+		}
+		for _, child := range resp.Data.Children {
+			d := child.Data
+			t, ok := tracked[d.ID]
+			if !ok || d.NumComments-t.Comments < growth {
+				continue
+			}
+			updated = append(updated, s.withComments(ctx, newPost(d), limiter))
+			t.Comments = d.NumComments
+			tracked[d.ID] = t
+		}
+	}
+	return updated
+}
+
+// toScrapedPosts converts posts with the registered reddit adapter, which
+// threads the comments into answers.
+func toScrapedPosts(posts []Post) ([]scraper.ScrapedPost, error) {
+	out := make([]scraper.ScrapedPost, 0, len(posts))
+	for _, p := range posts {
+		raw, err := json.Marshal(p)
+		if err != nil {
+			return nil, err
+		}
+		if sp, ok := scraper.DecodeRecord(raw); ok {
+			out = append(out, sp)
+		}
+	}
+	return out, nil
+}
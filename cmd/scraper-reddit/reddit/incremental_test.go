@@ -0,0 +1,241 @@
+package reddit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSubreddit serves new.json, top.json, by_id and comments for posts
+// p1 (oldest) .. pN (newest), newest first, with "after" paging.
+type fakeSubreddit struct {
+	mu       sync.Mutex
+	posts    []listingData // newest first
+	requests []string
+}
+
+func newFakeSubreddit(n int) *fakeSubreddit {
+	f := &fakeSubreddit{}
+	base := time.Now().Add(-time.Hour).Unix()
+	for i := n; i >= 1; i-- {
+		f.posts = append(f.posts, f.post(i, base))
+	}
+	return f
+}
+
+func (f *fakeSubreddit) post(i int, base int64) listingData {
+	id := fmt.Sprintf("p%d", i)
+	return listingData{
+		ID: id, Subreddit: "cars", Title: "Post " + id, SelfText: "body",
+		Permalink: "/r/cars/comments/" + id + "/t/", CreatedUTC: float64(base + int64(i)*60),
+	}
+}
+
+// add submits n newer posts.
+func (f *fakeSubreddit) add(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	last, _ := strconv.Atoi(strings.TrimPrefix(f.posts[0].ID, "p"))
+	base := time.Now().Add(-time.Hour).Unix()
+	for i := last + 1; i <= last+n; i++ {
+		f.posts = append([]listingData{f.post(i, base)}, f.posts...)
+	}
+}
+
+func (f *fakeSubreddit) setComments(id string, n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := range f.posts {
+		if f.posts[i].ID == id {
+			f.posts[i].NumComments = n
+		}
+	}
+}
+
+func (f *fakeSubreddit) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r.URL.Path+"?"+r.URL.RawQuery)
+
+	listing := func(children []listingData, after string) listingResponse {
+		var resp listingResponse
+		for _, d := range children {
+			resp.Data.Children = append(resp.Data.Children, listingChild{Kind: "t3", Data: d})
+		}
+		resp.Data.After = after
+		return resp
+	}
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/by_id/"):
+		ids := strings.Split(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/by_id/"), ".json"), ",")
+		var found []listingData
+		for _, d := range f.posts {
+			for _, id := range ids {
+				if "t3_"+d.ID == id {
+					found = append(found, d)
+				}
+			}
+		}
+		json.NewEncoder(w).Encode(listing(found, ""))
+	case strings.HasSuffix(r.URL.Path, "/new.json"), strings.HasSuffix(r.URL.Path, "/top.json"):
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		start := 0
+		if after := r.URL.Query().Get("after"); after != "" {
+			for i, d := range f.posts {
+				if "t3_"+d.ID == after {
+					start = i + 1
+				}
+			}
+		}
+		end := min(start+limit, len(f.posts))
+		next := ""
+		if end < len(f.posts) {
+			next = "t3_" + f.posts[end-1].ID
+		}
+		json.NewEncoder(w).Encode(listing(f.posts[start:end], next))
+	default: // comments
+		var comments listingResponse
+		comments.Data.Children = []listingChild{{Kind: "t1", Data: listingData{ID: "c1", Author: "mech", Body: "Check the fuse.", Score: 3, ParentID: "t3_x"}}}
+		json.NewEncoder(w).Encode([]listingResponse{{}, comments})
+	}
+}
+
+func (f *fakeSubreddit) count(prefix string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, r := range f.requests {
+		if strings.HasPrefix(r, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+func ids(posts []Post) string {
+	var out []string
+	for _, p := range posts {
+		out = append(out, p.ID)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestFetchSince_Paginates(t *testing.T) {
+	sub := newFakeSubreddit(5)
+	s, srv := newTestScraper(sub, Config{Subreddits: []string{"cars"}, PostsPerSub: 3, RateLimit: time.Millisecond})
+	defer srv.Close()
+	ctx := context.Background()
+
+	// Without a mark only the first page is read.
+	posts, state, err := s.FetchSince(ctx, State{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids(posts) != "p5,p4,p3" {
+		t.Fatalf("first fetch: got %s", ids(posts))
+	}
+	if m := state.Subreddits["cars"]; m.Newest != "t3_p5" || len(m.Tracked) != 3 {
+		t.Fatalf("unexpected mark %+v", m)
+	}
+	if len(posts[0].Comments) != 1 {
+		t.Errorf("new posts should come with comments: %+v", posts[0])
+	}
+
+	// Seven new posts span three pages; paging stops at the mark.
+	sub.add(7)
+	posts, state, err = s.FetchSince(ctx, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids(posts) != "p12,p11,p10,p9,p8,p7,p6" {
+		t.Fatalf("second fetch: got %s", ids(posts))
+	}
+	if state.Subreddits["cars"].Newest != "t3_p12" {
+		t.Errorf("mark should move to the newest post, got %+v", state.Subreddits["cars"])
+	}
+	if n := sub.count("/r/cars/new.json"); n != 4 {
+		t.Errorf("expected 4 listing requests, got %d", n)
+	}
+
+	// Nothing new: nothing returned, mark unchanged.
+	posts, state, err = s.FetchSince(ctx, state)
+	if err != nil || len(posts) != 0 || state.Subreddits["cars"].Newest != "t3_p12" {
+		t.Errorf("third fetch: posts %s, state %+v, err %v", ids(posts), state, err)
+	}
+}
+
+func TestFetchSince_CommentGrowth(t *testing.T) {
+	sub := newFakeSubreddit(3)
+	s, srv := newTestScraper(sub, Config{Subreddits: []string{"cars"}, PostsPerSub: 10, RateLimit: time.Millisecond, CommentGrowth: 5})
+	defer srv.Close()
+	ctx := context.Background()
+
+	_, state, err := s.FetchSince(ctx, State{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub.setComments("p2", 8)
+	sub.setComments("p1", 3)
+	posts, state, err := s.FetchSince(ctx, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids(posts) != "p2" || posts[0].NumComments != 8 || len(posts[0].Comments) != 1 {
+		t.Fatalf("only p2 gained enough comments, got %+v", posts)
+	}
+	if state.Subreddits["cars"].Tracked["p2"].Comments != 8 {
+		t.Errorf("tracked count should be updated: %+v", state.Subreddits["cars"].Tracked)
+	}
+
+	// Posts past TrackFor are no longer watched.
+	s.cfg.TrackFor = time.Minute
+	_, state, _ = s.FetchSince(ctx, state)
+	if len(state.Subreddits["cars"].Tracked) != 0 {
+		t.Errorf("old posts should be dropped: %+v", state.Subreddits["cars"].Tracked)
+	}
+}
+
+func TestFetch_Cursor(t *testing.T) {
+	sub := newFakeSubreddit(2)
+	s, srv := newTestScraper(sub, Config{Subreddits: []string{"cars"}, PostsPerSub: 10, RateLimit: time.Millisecond})
+	defer srv.Close()
+
+	posts, cursor, err := s.Fetch(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != 2 || posts[0].Source != "reddit:cars" || len(posts[0].Metadata.Answers) != 1 {
+		t.Fatalf("unexpected posts %+v", posts)
+	}
+	posts, _, err = s.Fetch(context.Background(), cursor)
+	if err != nil || len(posts) != 0 {
+		t.Errorf("resuming from the cursor should find nothing new, got %d posts, %v", len(posts), err)
+	}
+}
+
+func TestBackfill(t *testing.T) {
+	sub := newFakeSubreddit(5)
+	s, srv := newTestScraper(sub, Config{Subreddits: []string{"cars"}, PostsPerSub: 2, RateLimit: time.Millisecond})
+	defer srv.Close()
+
+	posts, err := s.Backfill(context.Background(), BackfillOpts{Listing: "top", Windows: []string{"week", "all"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids(posts) != "p5,p4,p3,p2,p1" {
+		t.Errorf("each post should come back once, got %s", ids(posts))
+	}
+	if n := sub.count("/r/cars/top.json?"); n != 6 {
+		t.Errorf("expected 3 pages per window, got %d requests", n)
+	}
+
+	if _, err := s.Backfill(context.Background(), BackfillOpts{Listing: "search"}); err == nil {
+		t.Error("search backfill without a query should fail")
+	}
+}
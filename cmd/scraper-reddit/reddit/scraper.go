@@ -7,8 +7,12 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
+	"golang.org/x/time/rate"
+
+	"github.com/WessleyAI/wessley-mvp/engine/scraper"
 	"github.com/WessleyAI/wessley-mvp/pkg/fn"
 )
 
@@ -33,8 +37,7 @@ func NewScraper(cfg Config) *Scraper {
 // FetchAll scrapes all configured subreddits and returns posts with comments.
 func (s *Scraper) FetchAll(ctx context.Context) ([]Post, error) {
 	var allPosts []Post
-	limiter := time.NewTicker(s.cfg.RateLimit)
-	defer limiter.Stop()
+	limiter := scraper.RateLimiter(ctx, s.cfg.RateLimit)
 
 	for _, sub := range s.cfg.Subreddits {
 		select {
@@ -53,59 +56,66 @@ func (s *Scraper) FetchAll(ctx context.Context) ([]Post, error) {
 	return allPosts, nil
 }
 
-func (s *Scraper) fetchSubreddit(ctx context.Context, sub string, limiter *time.Ticker) ([]Post, error) {
+func (s *Scraper) fetchSubreddit(ctx context.Context, sub string, limiter *rate.Limiter) ([]Post, error) {
 	url := fmt.Sprintf("%s/r/%s/new.json?limit=%d&raw_json=1", baseURL, sub, s.cfg.PostsPerSub)
+	resp, err := s.fetchListing(ctx, url, limiter)
+	if err != nil {
+		return nil, fmt.Errorf("r/%s listing: %w", sub, err)
+	}
+
+	posts := make([]Post, 0, len(resp.Data.Children))
+	for _, child := range resp.Data.Children {
+		posts = append(posts, s.withComments(ctx, newPost(child.Data), limiter))
+	}
+	return posts, nil
+}
 
+// fetchListing fetches one page of a listing with rate limiting + retry.
+func (s *Scraper) fetchListing(ctx context.Context, url string, limiter *rate.Limiter) (*listingResponse, error) {
 	result := fn.Retry(ctx, fn.RetryOpts{
 		MaxAttempts: 3,
 		InitialWait: 5 * time.Second,
 		MaxWait:     30 * time.Second,
 		Jitter:      true,
 	}, func(ctx context.Context) fn.Result[*listingResponse] {
-		<-limiter.C
+		if err := limiter.Wait(ctx); err != nil {
+			return fn.Err[*listingResponse](err)
+		}
 		return s.doGet(ctx, url)
 	})
+	return result.Unwrap()
+}
 
-	resp, err := result.Unwrap()
-	if err != nil {
-		return nil, fmt.Errorf("r/%s listing: %w", sub, err)
+func newPost(d listingData) Post {
+	return Post{
+		ID:          d.ID,
+		Subreddit:   d.Subreddit,
+		Title:       d.Title,
+		Author:      d.Author,
+		SelfText:    d.SelfText,
+		URL:         d.URL,
+		Permalink:   "https://www.reddit.com" + d.Permalink,
+		Score:       d.Score,
+		NumComments: d.NumComments,
+		CreatedUTC:  time.Unix(int64(d.CreatedUTC), 0).UTC(),
+		Flair:       d.LinkFlairText,
+		ScrapedAt:   time.Now().UTC(),
 	}
+}
 
-	now := time.Now().UTC()
-	posts := make([]Post, 0, len(resp.Data.Children))
-
-	for _, child := range resp.Data.Children {
-		d := child.Data
-		post := Post{
-			ID:          d.ID,
-			Subreddit:   d.Subreddit,
-			Title:       d.Title,
-			Author:      d.Author,
-			SelfText:    d.SelfText,
-			URL:         d.URL,
-			Permalink:   "https://www.reddit.com" + d.Permalink,
-			Score:       d.Score,
-			NumComments: d.NumComments,
-			CreatedUTC:  time.Unix(int64(d.CreatedUTC), 0).UTC(),
-			Flair:       d.LinkFlairText,
-			ScrapedAt:   now,
-		}
-
-		// Fetch comments with rate limiting + retry
-		comments, err := s.fetchComments(ctx, d.Permalink, limiter)
-		if err != nil {
-			log.Printf("warning: comments for %s: %v", d.ID, err)
-		} else {
-			post.Comments = comments
-		}
-
-		posts = append(posts, post)
+// withComments fetches the post's comments; on failure the post is
+// returned without them.
+func (s *Scraper) withComments(ctx context.Context, post Post, limiter *rate.Limiter) Post {
+	comments, err := s.fetchComments(ctx, strings.TrimPrefix(post.Permalink, "https://www.reddit.com"), limiter)
+	if err != nil {
+		log.Printf("warning: comments for %s: %v", post.ID, err)
+	} else {
+		post.Comments = comments
 	}
-
-	return posts, nil
+	return post
 }
 
-func (s *Scraper) fetchComments(ctx context.Context, permalink string, limiter *time.Ticker) ([]Comment, error) {
+func (s *Scraper) fetchComments(ctx context.Context, permalink string, limiter *rate.Limiter) ([]Comment, error) {
 	url := fmt.Sprintf("%s%s.json?limit=%d&raw_json=1&sort=top", baseURL, permalink, s.cfg.CommentsPerPost)
 
 	result := fn.Retry(ctx, fn.RetryOpts{
@@ -114,7 +124,9 @@ func (s *Scraper) fetchComments(ctx context.Context, permalink string, limiter *
 		MaxWait:     15 * time.Second,
 		Jitter:      true,
 	}, func(ctx context.Context) fn.Result[[]Comment] {
-		<-limiter.C
+		if err := limiter.Wait(ctx); err != nil {
+			return fn.Err[[]Comment](err)
+		}
 		return s.doGetComments(ctx, url)
 	})
 
@@ -10,6 +10,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"golang.org/x/time/rate"
 )
 
 // redirectTransport intercepts HTTP requests and redirects them to a test server.
@@ -534,8 +536,7 @@ func TestFetchSubreddit_Directly(t *testing.T) {
 	s := NewScraper(Config{PostsPerSub: 10, CommentsPerPost: 10, RateLimit: 1 * time.Millisecond})
 	s.client = &http.Client{Transport: &redirectTransport{server: srv}, Timeout: 5 * time.Second}
 
-	limiter := time.NewTicker(1 * time.Millisecond)
-	defer limiter.Stop()
+	limiter := rate.NewLimiter(rate.Every(time.Millisecond), 1)
 
 	posts, err := s.fetchSubreddit(context.Background(), "TestSub", limiter)
 	if err != nil {
@@ -564,8 +565,7 @@ func TestFetchComments_Directly(t *testing.T) {
 	s := NewScraper(Config{CommentsPerPost: 10, RateLimit: 1 * time.Millisecond})
 	s.client = &http.Client{Transport: &redirectTransport{server: srv}, Timeout: 5 * time.Second}
 
-	limiter := time.NewTicker(1 * time.Millisecond)
-	defer limiter.Stop()
+	limiter := rate.NewLimiter(rate.Every(time.Millisecond), 1)
 
 	comments, err := s.fetchComments(context.Background(), "/r/sub/comments/p1/t/", limiter)
 	if err != nil {
@@ -37,4 +37,9 @@ type Config struct {
 	PostsPerSub     int
 	CommentsPerPost int
 	RateLimit       time.Duration
+
+	// Incremental scraping (FetchSince); zero values use the defaults.
+	MaxPages      int           // new.json pages per subreddit per fetch (default 10)
+	CommentGrowth int           // new comments that make a seen post worth re-scraping (default 10)
+	TrackFor      time.Duration // how long seen posts are watched for new comments (default 48h)
 }
@@ -0,0 +1,303 @@
+package forums
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Forum engines with a thread parser.
+const (
+	EngineXenForo   = "xenforo"
+	EngineVBulletin = "vbulletin"
+	EngineDiscourse = "discourse"
+)
+
+// threadPage is one page of a thread. The first page starts with the
+// opening post.
+type threadPage struct {
+	Title string
+	Posts []Reply
+	Total int // posts in the thread, when the engine reports it
+}
+
+// These patterns only locate elements; elementAt finds where they end, so
+// nested divs and quotes don't trip them up.
+var (
+	tagRe          = regexp.MustCompile(`<[^>]*>`)
+	blockEndRe     = regexp.MustCompile(`(?i)<br\s*/?>|</(?:p|div|li|h[1-6]|tr|blockquote|pre)>`)
+	blankLinesRe   = regexp.MustCompile(`\n{3,}`)
+	signatureRe    = regexp.MustCompile(`(?m)^(?:_{5,}|-- ?)$`)
+	nextPageRe     = regexp.MustCompile(`(?i)<(?:link|a)\b[^>]*\brel="next"[^>]*>`)
+	dateFallbackRe = regexp.MustCompile(`\b(\d{2}-\d{2}-\d{4})\b`)
+
+	// Quotes of earlier posts and signatures, per engine.
+	xfQuoteRe     = regexp.MustCompile(`(?i)<blockquote\b[^>]*class="[^"]*bbCodeBlock--quote[^"]*"[^>]*>`)
+	xfSignatureRe = regexp.MustCompile(`(?i)<aside\b[^>]*class="[^"]*message-signature[^"]*"[^>]*>`)
+	vbQuoteRe     = regexp.MustCompile(`(?i)<div\b[^>]*class="[^"]*bbcode_container[^"]*"[^>]*>|<div\b[^>]*>\s*<div class="smallfont"[^>]*>\s*Quote:`)
+	vbSignatureRe = regexp.MustCompile(`(?i)<div\b[^>]*class="[^"]*signaturecontainer[^"]*"[^>]*>`)
+	dcQuoteRe     = regexp.MustCompile(`(?i)<aside\b[^>]*class="[^"]*\bquote\b[^"]*"[^>]*>`)
+
+	xfTitleRe   = regexp.MustCompile(`(?is)<h1\b[^>]*class="[^"]*p-title-value[^"]*"[^>]*>(.*?)</h1>`)
+	xfPostRe    = regexp.MustCompile(`(?i)<article\b[^>]*class="[^"]*\bmessage--post\b[^"]*"[^>]*>`)
+	xfBodyRe    = regexp.MustCompile(`(?i)<div\b[^>]*class="[^"]*\bbbWrapper\b[^"]*"[^>]*>`)
+	xfTimeRe    = regexp.MustCompile(`(?i)<time\b[^>]*\bdatetime="([^"]+)"`)
+	vbTitleRe   = regexp.MustCompile(`(?is)<(?:span|h1|h2)\b[^>]*class="[^"]*threadtitle[^"]*"[^>]*>(.*?)</(?:span|h1|h2)>|<title>(.*?)</title>`)
+	vbPostRe    = regexp.MustCompile(`(?i)<div\b[^>]*\bid="post_message_(\d+)"[^>]*>`)
+	vbAuthorRe  = regexp.MustCompile(`(?is)<a\b[^>]*class="(?:bigusername|username[^"]*)"[^>]*>(.*?)</a>`)
+	vbPageSufRe = regexp.MustCompile(`\s+-\s+Page\s+\d+(?:\s+-\s.*)?$`)
+)
+
+// detectEngine guesses the forum software from a thread page.
+func detectEngine(body string) string {
+	switch {
+	case strings.Contains(body, "bbWrapper") && strings.Contains(body, "message--post"):
+		return EngineXenForo
+	case strings.Contains(body, `id="post_message_`):
+		return EngineVBulletin
+	case strings.Contains(body, `"post_stream"`), strings.Contains(body, `content="Discourse`):
+		return EngineDiscourse
+	}
+	return ""
+}
+
+// parsePage parses a thread page with the given engine's parser.
+func parsePage(engine, body string) (threadPage, error) {
+	switch engine {
+	case EngineXenForo:
+		return parseXenForo(body), nil
+	case EngineVBulletin:
+		return parseVBulletin(body), nil
+	case EngineDiscourse:
+		return parseDiscourse(body)
+	}
+	return threadPage{}, fmt.Errorf("unknown forum engine %q", engine)
+}
+
+// parseXenForo parses a XenForo 2 thread page. Question threads mark the
+// accepted answer with an is-solution or message--solution class.
+func parseXenForo(body string) threadPage {
+	var page threadPage
+	if m := xfTitleRe.FindStringSubmatch(body); m != nil {
+		page.Title = htmlToText(m[1])
+	}
+	for _, loc := range xfPostRe.FindAllStringIndex(body, -1) {
+		tag := body[loc[0]:loc[1]]
+		inner, _ := elementAt(body, loc[0])
+		b := xfBodyRe.FindStringIndex(inner)
+		if b == nil {
+			continue
+		}
+		content, _ := elementAt(inner, b[0])
+		content = removeElements(content, xfQuoteRe, xfSignatureRe)
+
+		r := Reply{
+			ID:      strings.TrimPrefix(attr(tag, "data-content"), "post-"),
+			Author:  html.UnescapeString(attr(tag, "data-author")),
+			Content: cleanPost(content),
+		}
+		class := " " + attr(tag, "class") + " "
+		r.Solution = strings.Contains(class, " is-solution ") || strings.Contains(class, " message--solution ")
+		if t := xfTimeRe.FindStringSubmatch(inner); t != nil {
+			r.PostedAt = parseTime(t[1])
+		}
+		page.Posts = append(page.Posts, r)
+	}
+	return page
+}
+
+// parseVBulletin parses a vBulletin 3 or 4 thread page. The author and date
+// of a post come before its post_message div.
+func parseVBulletin(body string) threadPage {
+	var page threadPage
+	if m := vbTitleRe.FindStringSubmatch(body); m != nil {
+		title := m[1]
+		if title == "" {
+			title = m[2]
+		}
+		page.Title = vbPageSufRe.ReplaceAllString(htmlToText(title), "")
+	}
+	prev := 0
+	for _, loc := range vbPostRe.FindAllStringSubmatchIndex(body, -1) {
+		header := body[prev:loc[0]]
+		inner, end := elementAt(body, loc[0])
+		prev = end
+		content := removeElements(inner, vbQuoteRe, vbSignatureRe)
+
+		r := Reply{ID: body[loc[2]:loc[3]], Content: cleanPost(content)}
+		if a := vbAuthorRe.FindAllStringSubmatch(header, -1); len(a) > 0 {
+			r.Author = htmlToText(a[len(a)-1][1])
+		}
+		if d := dateFallbackRe.FindAllStringSubmatch(header, -1); len(d) > 0 {
+			r.PostedAt, _ = time.Parse("01-02-2006", d[len(d)-1][1])
+		}
+		page.Posts = append(page.Posts, r)
+	}
+	return page
+}
+
+// discourseTopic is the subset of a Discourse topic's JSON (/t/{slug}/{id}.json)
+// the scraper reads.
+type discourseTopic struct {
+	Title          string `json:"title"`
+	PostsCount     int    `json:"posts_count"`
+	AcceptedAnswer *struct {
+		PostNumber int `json:"post_number"`
+	} `json:"accepted_answer"`
+	PostStream struct {
+		Posts []discoursePost `json:"posts"`
+	} `json:"post_stream"`
+}
+
+type discoursePost struct {
+	ID             int       `json:"id"`
+	PostNumber     int       `json:"post_number"`
+	Username       string    `json:"username"`
+	Cooked         string    `json:"cooked"`
+	CreatedAt      time.Time `json:"created_at"`
+	AcceptedAnswer bool      `json:"accepted_answer"`
+}
+
+// parseDiscourse parses one page of a Discourse topic's JSON. Answers
+// accepted with the solved plugin are marked as the solution.
+func parseDiscourse(body string) (threadPage, error) {
+	var topic discourseTopic
+	if err := json.Unmarshal([]byte(body), &topic); err != nil {
+		return threadPage{}, fmt.Errorf("discourse topic: %w", err)
+	}
+	page := threadPage{Title: topic.Title, Total: topic.PostsCount}
+	for _, p := range topic.PostStream.Posts {
+		page.Posts = append(page.Posts, Reply{
+			ID:       fmt.Sprint(p.ID),
+			Author:   p.Username,
+			Content:  cleanPost(removeElements(p.Cooked, dcQuoteRe)),
+			PostedAt: p.CreatedAt.UTC(),
+			Solution: p.AcceptedAnswer || (topic.AcceptedAnswer != nil && topic.AcceptedAnswer.PostNumber == p.PostNumber),
+		})
+	}
+	return page, nil
+}
+
+// nextPageURL returns the href of the page's rel="next" link or anchor.
+func nextPageURL(body string) string {
+	tag := nextPageRe.FindString(body)
+	if tag == "" {
+		return ""
+	}
+	return html.UnescapeString(attr(tag, "href"))
+}
+
+// elementAt returns the inner HTML of the element whose start tag begins at
+// s[start], and the index just past its end tag. Nested elements of the same
+// name are balanced; an unclosed element runs to the end of s.
+func elementAt(s string, start int) (string, int) {
+	open := strings.IndexByte(s[start:], '>')
+	if open < 0 {
+		return "", len(s)
+	}
+	open += start + 1
+	fields := strings.FieldsFunc(s[start+1:open-1], func(r rune) bool {
+		return r == ' ' || r == '/' || r == '\t' || r == '\n' || r == '\r'
+	})
+	if len(fields) == 0 {
+		return "", open
+	}
+	re := tagPattern(strings.ToLower(fields[0]))
+
+	depth := 1
+	for _, m := range re.FindAllStringSubmatchIndex(s[open:], -1) {
+		if m[3] > m[2] {
+			depth--
+		} else {
+			depth++
+		}
+		if depth == 0 {
+			return s[open : open+m[0]], open + m[1]
+		}
+	}
+	return s[open:], len(s)
+}
+
+var tagPatterns sync.Map // element name -> *regexp.Regexp matching its start and end tags
+
+// tagPattern returns a pattern matching <name ...> and </name>, capturing the
+// slash of end tags.
+func tagPattern(name string) *regexp.Regexp {
+	if re, ok := tagPatterns.Load(name); ok {
+		return re.(*regexp.Regexp)
+	}
+	re := regexp.MustCompile(`(?i)<(/?)` + regexp.QuoteMeta(name) + `\b[^>]*>`)
+	tagPatterns.Store(name, re)
+	return re
+}
+
+// removeElements deletes every element whose start tag matches one of res,
+// with everything inside it.
+func removeElements(s string, res ...*regexp.Regexp) string {
+	for _, re := range res {
+		for {
+			loc := re.FindStringIndex(s)
+			if loc == nil {
+				break
+			}
+			_, end := elementAt(s, loc[0])
+			s = s[:loc[0]] + s[end:]
+		}
+	}
+	return s
+}
+
+// attr returns the value of a double-quoted attribute in a start tag.
+func attr(tag, name string) string {
+	for i := 0; ; {
+		j := strings.Index(tag[i:], name+`="`)
+		if j < 0 {
+			return ""
+		}
+		i += j
+		if i > 0 && strings.ContainsRune(" \t\r\n", rune(tag[i-1])) {
+			v := tag[i+len(name)+2:]
+			if k := strings.IndexByte(v, '"'); k >= 0 {
+				return v[:k]
+			}
+			return ""
+		}
+		i += len(name)
+	}
+}
+
+// htmlToText strips tags and entities, keeping block breaks as newlines.
+func htmlToText(s string) string {
+	s = blockEndRe.ReplaceAllString(s, "\n")
+	s = html.UnescapeString(tagRe.ReplaceAllString(s, ""))
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = strings.Join(strings.Fields(l), " ")
+	}
+	return strings.TrimSpace(blankLinesRe.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// cleanPost converts a post body to text and cuts a signature typed into
+// the post itself ("__________" or "-- " on a line of its own).
+func cleanPost(s string) string {
+	text := htmlToText(s)
+	if loc := signatureRe.FindStringIndex(text); loc != nil {
+		text = strings.TrimSpace(text[:loc[0]])
+	}
+	return text
+}
+
+// parseTime parses the timestamp formats forum engines put in datetime
+// attributes.
+func parseTime(s string) time.Time {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05-0700"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
@@ -11,6 +11,8 @@ import (
 	"strings"
 	"time"
 
+	"golang.org/x/time/rate"
+
 	"github.com/WessleyAI/wessley-mvp/engine/scraper"
 	"github.com/WessleyAI/wessley-mvp/pkg/fn"
 )
@@ -34,17 +36,19 @@ func NewScraper(cfg Config) *Scraper {
 // DefaultForums returns the standard list of automotive forums to scrape.
 func DefaultForums() []ForumConfig {
 	return []ForumConfig{
-		{Name: "BobIsTheOilGuy", BaseURL: "https://bobistheoilguy.com/forums", SearchPath: "/search/?q=%s&o=date"},
+		{Name: "BobIsTheOilGuy", BaseURL: "https://bobistheoilguy.com/forums", SearchPath: "/search/?q=%s&o=date", Engine: EngineXenForo},
 		{Name: "AutoRepairForum", BaseURL: "https://www.autorepairforum.com", SearchPath: "/search/?q=%s"},
 		{Name: "MechanicsForum", BaseURL: "https://www.mechanicsforum.com", SearchPath: "/search/?q=%s"},
 	}
 }
 
-// FetchAll scrapes all configured forums and returns ScrapedPosts.
+// FetchAll scrapes all configured forums and returns ScrapedPosts, one per
+// thread found, with the thread's replies as answers. A thread that can't be
+// fetched keeps its search-result title only.
 func (s *Scraper) FetchAll(ctx context.Context) ([]scraper.ScrapedPost, error) {
 	var allPosts []scraper.ScrapedPost
-	limiter := time.NewTicker(s.cfg.RateLimit)
-	defer limiter.Stop()
+	limiter := scraper.RateLimiter(ctx, s.cfg.RateLimit)
+	threads := map[string]*ForumThread{} // by URL; nil if the fetch failed
 
 	for _, forum := range s.cfg.Forums {
 		for _, query := range s.cfg.Queries {
@@ -54,7 +58,7 @@ func (s *Scraper) FetchAll(ctx context.Context) ([]scraper.ScrapedPost, error) {
 			default:
 			}
 
-			posts, err := s.fetchForum(ctx, forum, query, limiter)
+			posts, err := s.fetchForum(ctx, forum, query, limiter, threads)
 			if err != nil {
 				log.Printf("warning: failed to fetch %s for %q: %v", forum.Name, query, err)
 				continue
@@ -65,20 +69,10 @@ func (s *Scraper) FetchAll(ctx context.Context) ([]scraper.ScrapedPost, error) {
 	return allPosts, nil
 }
 
-func (s *Scraper) fetchForum(ctx context.Context, forum ForumConfig, query string, limiter *time.Ticker) ([]scraper.ScrapedPost, error) {
+func (s *Scraper) fetchForum(ctx context.Context, forum ForumConfig, query string, limiter *rate.Limiter, threads map[string]*ForumThread) ([]scraper.ScrapedPost, error) {
 	searchURL := forum.BaseURL + fmt.Sprintf(forum.SearchPath, url.QueryEscape(query))
 
-	result := fn.Retry(ctx, fn.RetryOpts{
-		MaxAttempts: 3,
-		InitialWait: 5 * time.Second,
-		MaxWait:     30 * time.Second,
-		Jitter:      true,
-	}, func(ctx context.Context) fn.Result[string] {
-		<-limiter.C
-		return s.doGet(ctx, searchURL)
-	})
-
-	html, err := result.Unwrap()
+	html, err := s.get(ctx, searchURL, limiter)
 	if err != nil {
 		return nil, fmt.Errorf("%s search %q: %w", forum.Name, query, err)
 	}
@@ -87,6 +81,21 @@ func (s *Scraper) fetchForum(ctx context.Context, forum ForumConfig, query strin
 	if s.cfg.MaxPerForum > 0 && len(posts) > s.cfg.MaxPerForum {
 		posts = posts[:s.cfg.MaxPerForum]
 	}
+	for i, p := range posts {
+		t, ok := threads[p.URL]
+		if !ok {
+			if t, err = s.fetchThread(ctx, forum, p.URL, limiter); err != nil {
+				if ctx.Err() != nil {
+					return posts, nil
+				}
+				log.Printf("warning: %s thread %s: %v", forum.Name, p.URL, err)
+			}
+			threads[p.URL] = t
+		}
+		if t != nil {
+			posts[i] = threadPost(t, p)
+		}
+	}
 	return posts, nil
 }
 
@@ -146,7 +155,7 @@ func parseSearchResults(html string, forum ForumConfig, query string) []scraper.
 			Source:      "forum:" + forum.Name,
 			SourceID:    fmt.Sprintf("forum-%s-%s", forum.Name, href),
 			Title:       title,
-			Content:     "", // content fetched in a follow-up pass if needed
+			Content:     "", // filled from the thread by threadPost
 			Author:      "",
 			URL:         url,
 			PublishedAt: time.Time{},
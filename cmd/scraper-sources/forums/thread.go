@@ -0,0 +1,231 @@
+package forums
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/WessleyAI/wessley-mvp/engine/scraper"
+	"github.com/WessleyAI/wessley-mvp/pkg/fn"
+)
+
+const (
+	// defaultMaxPages is the number of thread pages fetched when MaxPages is unset.
+	defaultMaxPages = 5
+	// maxAnswers is the number of replies kept as answers per thread.
+	maxAnswers = 20
+	// maxFixLen caps the length of a solution recorded in Metadata.Fixes.
+	maxFixLen = 300
+)
+
+// discourseTopicRe matches the topic part of a Discourse URL, /t/{slug}/{id}.
+var discourseTopicRe = regexp.MustCompile(`/t/[^/]+/\d+`)
+
+var (
+	errUnknownEngine = errors.New("unrecognized forum engine")
+	errNoPosts       = errors.New("no posts found")
+)
+
+// FetchThread fetches a thread and its replies, following the thread's
+// pages up to MaxPages. Quotes and signatures are stripped from every post
+// and the reply that solved the thread, if any, is marked.
+func (s *Scraper) FetchThread(ctx context.Context, forum ForumConfig, threadURL string) (*ForumThread, error) {
+	return s.fetchThread(ctx, forum, threadURL, scraper.RateLimiter(ctx, s.cfg.RateLimit))
+}
+
+func (s *Scraper) fetchThread(ctx context.Context, forum ForumConfig, threadURL string, limiter *rate.Limiter) (*ForumThread, error) {
+	maxPages := s.cfg.MaxPages
+	if maxPages <= 0 {
+		maxPages = defaultMaxPages
+	}
+	t := &ForumThread{ForumName: forum.Name, URL: threadURL, Engine: forum.Engine}
+	if t.Engine == "" && discourseTopicRe.MatchString(threadURL) {
+		t.Engine = EngineDiscourse
+	}
+	pageURL := threadURL
+	if t.Engine == EngineDiscourse {
+		pageURL = discourseJSONURL(threadURL, 1)
+	}
+
+	var (
+		posts   []Reply
+		seen    = map[string]bool{}
+		visited = map[string]bool{}
+	)
+	for page := 1; page <= maxPages && pageURL != "" && !visited[pageURL]; page++ {
+		visited[pageURL] = true
+		body, err := s.get(ctx, pageURL, limiter)
+		if err != nil {
+			if page == 1 {
+				return nil, err
+			}
+			// Keep the pages already read.
+			log.Printf("warning: %s thread %s page %d: %v", forum.Name, threadURL, page, err)
+			break
+		}
+		if t.Engine == "" {
+			if t.Engine = detectEngine(body); t.Engine == "" {
+				return nil, errUnknownEngine
+			}
+			if t.Engine == EngineDiscourse {
+				// An HTML topic page; its JSON has the posts.
+				pageURL, page = discourseJSONURL(threadURL, 1), 0
+				continue
+			}
+		}
+
+		p, err := parsePage(t.Engine, body)
+		if err != nil {
+			if page == 1 {
+				return nil, err
+			}
+			log.Printf("warning: %s thread %s page %d: %v", forum.Name, threadURL, page, err)
+			break
+		}
+		if t.Title == "" {
+			t.Title = p.Title
+		}
+		added := 0
+		for _, r := range p.Posts {
+			if r.ID == "" || seen[r.ID] || r.Content == "" {
+				continue
+			}
+			seen[r.ID] = true
+			posts = append(posts, r)
+			added++
+		}
+		if added == 0 || (p.Total > 0 && len(posts) >= p.Total) {
+			break
+		}
+
+		if t.Engine == EngineDiscourse {
+			pageURL = discourseJSONURL(threadURL, page+1)
+		} else {
+			pageURL = resolveURL(pageURL, nextPageURL(body))
+		}
+	}
+	if len(posts) == 0 {
+		return nil, errNoPosts
+	}
+
+	op := posts[0]
+	t.ID, t.Author, t.Content, t.PostedAt = op.ID, op.Author, op.Content, op.PostedAt
+	t.Replies = posts[1:]
+	markSolution(t)
+	return t, nil
+}
+
+// get fetches url with retries, waiting on limiter before each attempt.
+func (s *Scraper) get(ctx context.Context, url string, limiter *rate.Limiter) (string, error) {
+	return fn.Retry(ctx, fn.RetryOpts{
+		MaxAttempts: 3,
+		InitialWait: 5 * time.Second,
+		MaxWait:     30 * time.Second,
+		Jitter:      true,
+	}, func(ctx context.Context) fn.Result[string] {
+		if err := limiter.Wait(ctx); err != nil {
+			return fn.Err[string](err)
+		}
+		return s.doGet(ctx, url)
+	}).Unwrap()
+}
+
+// markSolution marks the reply that solved the thread when the engine did
+// not: if the original poster confirms a fix, the last reply from someone
+// else before the confirmation is the solution, or the confirmation itself
+// when the poster explains the fix alone.
+func markSolution(t *ForumThread) {
+	for _, r := range t.Replies {
+		if r.Solution {
+			return
+		}
+	}
+	if t.Author == "" {
+		return
+	}
+	for i, r := range t.Replies {
+		if r.Author != t.Author || !scraper.IsResolution(r.Content) {
+			continue
+		}
+		for j := i - 1; j >= 0; j-- {
+			if t.Replies[j].Author != t.Author {
+				t.Replies[j].Solution = true
+				return
+			}
+		}
+		t.Replies[i].Solution = true
+		return
+	}
+}
+
+// threadPost fills a search result's post with the thread: the opening post
+// as content and the replies as answers, solution first.
+func threadPost(t *ForumThread, post scraper.ScrapedPost) scraper.ScrapedPost {
+	if t.Title != "" {
+		post.Title = t.Title
+	}
+	post.Content = t.Content
+	post.Author = t.Author
+	post.PublishedAt = t.PostedAt
+	post.Metadata.Comments = len(t.Replies)
+	post.Metadata.Answers = nil
+
+	replies := append([]Reply(nil), t.Replies...)
+	sort.SliceStable(replies, func(i, j int) bool { return replies[i].Solution && !replies[j].Solution })
+	for _, r := range replies {
+		if len(post.Metadata.Answers) == maxAnswers {
+			break
+		}
+		text := r.Content
+		if r.Author == t.Author {
+			text = "OP: " + text
+		}
+		post.Metadata.Answers = append(post.Metadata.Answers, scraper.Answer{Text: text, Resolved: r.Solution})
+		if r.Solution {
+			post.Metadata.Fixes = append(post.Metadata.Fixes, scraper.Clip(r.Content, maxFixLen))
+		}
+	}
+	return post
+}
+
+// discourseJSONURL returns the JSON URL of a page of a Discourse topic.
+func discourseJSONURL(topicURL string, page int) string {
+	u, err := url.Parse(topicURL)
+	if err != nil {
+		return ""
+	}
+	path := discourseTopicRe.FindString(u.Path)
+	if path == "" {
+		return ""
+	}
+	u.Path = u.Path[:strings.Index(u.Path, path)] + path + ".json"
+	u.RawQuery, u.Fragment = "", ""
+	if page > 1 {
+		u.RawQuery = fmt.Sprintf("page=%d", page)
+	}
+	return u.String()
+}
+
+// resolveURL resolves a possibly relative href against the page it was on.
+func resolveURL(base, href string) string {
+	if href == "" {
+		return ""
+	}
+	b, err := url.Parse(base)
+	if err != nil {
+		return ""
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	return b.ResolveReference(ref).String()
+}
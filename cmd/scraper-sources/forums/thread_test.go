@@ -0,0 +1,241 @@
+package forums
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixtureServer serves testdata files by request path and query.
+func fixtureServer(t *testing.T, files map[string]string) (*httptest.Server, *[]string) {
+	t.Helper()
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RequestURI())
+		name, ok := files[r.URL.RequestURI()]
+		if !ok {
+			// An empty page rather than an error status, which would be retried.
+			w.Write([]byte("<html></html>"))
+			return
+		}
+		b, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Errorf("fixture: %v", err)
+		}
+		w.Write(b)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func fixtureScraper(srv *httptest.Server, forums ...ForumConfig) *Scraper {
+	s := NewScraper(Config{Forums: forums, Queries: []string{"idle"}, RateLimit: time.Millisecond})
+	s.client = &http.Client{Transport: &redirectTransport{server: srv}, Timeout: 5 * time.Second}
+	return s
+}
+
+func TestFetchThread_XenForo(t *testing.T) {
+	srv, requests := fixtureServer(t, map[string]string{
+		"/threads/rough-idle.5501/":       "xenforo_page1.html",
+		"/threads/rough-idle.5501/page-2": "xenforo_page2.html",
+	})
+	s := fixtureScraper(srv)
+
+	// No engine configured: detected from the page.
+	th, err := s.FetchThread(context.Background(), ForumConfig{Name: "BITOG"}, "https://example.com/threads/rough-idle.5501/")
+	if err != nil {
+		t.Fatalf("FetchThread: %v", err)
+	}
+	if th.Engine != EngineXenForo || th.Title != "2018 F-150 rough idle when cold" {
+		t.Errorf("unexpected thread %q (%s)", th.Title, th.Engine)
+	}
+	if th.ID != "1001" || th.Author != "tdriver" || !strings.HasPrefix(th.Content, "My 2018 F-150") {
+		t.Errorf("unexpected opening post %s %s %q", th.ID, th.Author, th.Content)
+	}
+	if strings.Contains(th.Content, "Accord") {
+		t.Errorf("signature should be stripped: %q", th.Content)
+	}
+	if want := time.Date(2023, 4, 2, 13, 15, 0, 0, time.UTC); !th.PostedAt.Equal(want) {
+		t.Errorf("PostedAt = %v, want %v", th.PostedAt, want)
+	}
+	if len(th.Replies) != 3 {
+		t.Fatalf("expected 3 replies over 2 pages, got %+v (requests %v)", th.Replies, *requests)
+	}
+	if r := th.Replies[0]; r.Content != "Check the purge valve. They stick open on these and flood the intake with fuel vapor at startup." {
+		t.Errorf("quote should be stripped: %q", r.Content)
+	}
+	sol := th.Replies[1]
+	if !sol.Solution || th.Replies[2].Solution {
+		t.Errorf("the marked solution should win over the OP's confirmation: %+v", th.Replies)
+	}
+	if strings.Contains(sol.Content, "Sent from") || strings.Contains(sol.Content, "___") {
+		t.Errorf("typed signature should be cut: %q", sol.Content)
+	}
+}
+
+func TestFetchThread_VBulletin(t *testing.T) {
+	srv, _ := fixtureServer(t, map[string]string{"/showthread.php?t=7788": "vbulletin4.html"})
+	s := fixtureScraper(srv)
+
+	th, err := s.FetchThread(context.Background(), ForumConfig{Name: "CamryClub", Engine: EngineVBulletin}, "https://example.com/showthread.php?t=7788")
+	if err != nil {
+		t.Fatalf("FetchThread: %v", err)
+	}
+	if th.Title != "Brake pedal goes to the floor - 2012 Camry" || th.Author != "camryowner" {
+		t.Errorf("unexpected thread %q by %q", th.Title, th.Author)
+	}
+	if want := time.Date(2022, 3, 14, 0, 0, 0, 0, time.UTC); !th.PostedAt.Equal(want) {
+		t.Errorf("PostedAt = %v, want %v", th.PostedAt, want)
+	}
+	if len(th.Replies) != 2 {
+		t.Fatalf("expected 2 replies, got %+v", th.Replies)
+	}
+	fix := th.Replies[0]
+	if fix.Author != "mastertech" || strings.Contains(fix.Content, "Originally Posted") || !strings.HasPrefix(fix.Content, "No external leak") {
+		t.Errorf("unexpected reply %+v", fix)
+	}
+	// The OP's "that fixed it" marks the reply before it.
+	if !fix.Solution || th.Replies[1].Solution {
+		t.Errorf("expected the mechanic's reply to be the solution: %+v", th.Replies)
+	}
+}
+
+func TestParseVBulletin3(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("testdata", "vbulletin3.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := string(b)
+	if got := detectEngine(body); got != EngineVBulletin {
+		t.Fatalf("detectEngine = %q", got)
+	}
+	page := parseVBulletin(body)
+	if page.Title != "Civic overheating in traffic" {
+		t.Errorf("page and site suffix should be stripped: %q", page.Title)
+	}
+	if len(page.Posts) != 2 {
+		t.Fatalf("expected 2 posts, got %+v", page.Posts)
+	}
+	if p := page.Posts[0]; p.Author != "civicfan" || strings.Contains(p.Content, "99 Civic") {
+		t.Errorf("unexpected first post %+v", p)
+	}
+	p := page.Posts[1]
+	if p.ID != "5002" || p.Author != "hondaguru" || p.PostedAt.Day() != 2 {
+		t.Errorf("unexpected second post %+v", p)
+	}
+	if p.Content != "Fine at speed but hot at idle is the radiator fan. Check the fan relay and the fan switch on the radiator." {
+		t.Errorf("quote should be stripped: %q", p.Content)
+	}
+}
+
+func TestFetchThread_Discourse(t *testing.T) {
+	srv, requests := fixtureServer(t, map[string]string{
+		"/t/p0420-after-new-cat/4410.json":        "discourse_page1.json",
+		"/t/p0420-after-new-cat/4410.json?page=2": "discourse_page2.json",
+	})
+	s := fixtureScraper(srv)
+
+	th, err := s.FetchThread(context.Background(), ForumConfig{Name: "PriusChat"}, "https://example.com/t/p0420-after-new-cat/4410/2")
+	if err != nil {
+		t.Fatalf("FetchThread: %v", err)
+	}
+	if th.Engine != EngineDiscourse || th.Author != "prius_pat" || th.Content != "Replaced the catalytic converter on my 2010 Prius last month and P0420 is back.\n\nAny ideas?" {
+		t.Errorf("unexpected thread %+v", th)
+	}
+	if len(th.Replies) != 2 {
+		t.Fatalf("expected 2 replies, got %+v (requests %v)", th.Replies, *requests)
+	}
+	if r := th.Replies[0]; strings.Contains(r.Content, "prius_pat") || !strings.HasPrefix(r.Content, "Aftermarket converters") {
+		t.Errorf("quote should be stripped: %q", r.Content)
+	}
+	if !th.Replies[1].Solution || th.Replies[0].Solution {
+		t.Errorf("expected the accepted answer to be the solution: %+v", th.Replies)
+	}
+	// posts_count ends the thread without asking for page 3.
+	if n := len(*requests); n != 2 {
+		t.Errorf("expected 2 requests, got %v", *requests)
+	}
+}
+
+func TestFetchThread_Unrecognized(t *testing.T) {
+	srv, _ := fixtureServer(t, nil)
+	s := fixtureScraper(srv)
+	if _, err := s.FetchThread(context.Background(), ForumConfig{Name: "X"}, "https://example.com/threads/x.1/"); err == nil {
+		t.Error("expected an error for a page no parser recognizes")
+	}
+}
+
+func TestMarkSolution(t *testing.T) {
+	th := &ForumThread{Author: "op", Replies: []Reply{
+		{Author: "a", Content: "Try a new battery."},
+		{Author: "op", Content: "Still not fixed."},
+		{Author: "b", Content: "Clean the ground strap."},
+		{Author: "op", Content: "Thanks"},
+		{Author: "op", Content: "Update: fixed, it was the strap."},
+	}}
+	markSolution(th)
+	for i, r := range th.Replies {
+		if r.Solution != (i == 2) {
+			t.Errorf("reply %d Solution = %v", i, r.Solution)
+		}
+	}
+
+	// The OP answering their own thread.
+	th = &ForumThread{Author: "op", Replies: []Reply{{Author: "op", Content: "Solved: it was a blown fuse."}}}
+	markSolution(th)
+	if !th.Replies[0].Solution {
+		t.Error("expected the OP's own fix to be the solution")
+	}
+}
+
+func TestFetchAll_Threads(t *testing.T) {
+	srv, requests := fixtureServer(t, map[string]string{
+		"/search/?q=idle":                 "search.html",
+		"/threads/rough-idle.5501/":       "xenforo_page1.html",
+		"/threads/rough-idle.5501/page-2": "xenforo_page2.html",
+	})
+	s := fixtureScraper(srv, ForumConfig{Name: "BITOG", BaseURL: "https://example.com", SearchPath: "/search/?q=%s", Engine: EngineXenForo})
+	s.cfg.Queries = []string{"idle", "idle"}
+
+	posts, err := s.FetchAll(context.Background())
+	if err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+	if len(posts) != 4 {
+		t.Fatalf("expected 2 posts per query, got %d", len(posts))
+	}
+	threadFetches := 0
+	for _, r := range *requests {
+		if strings.HasPrefix(r, "/threads/") {
+			threadFetches++
+		}
+	}
+	if threadFetches != 3 {
+		t.Errorf("threads should be fetched once, got requests %v", *requests)
+	}
+
+	p := posts[0]
+	if p.Author != "tdriver" || !strings.HasPrefix(p.Content, "My 2018 F-150") || p.Metadata.Comments != 3 {
+		t.Errorf("unexpected threaded post %+v", p)
+	}
+	ans := p.Metadata.Answers
+	if len(ans) != 3 || !ans[0].Resolved || !strings.HasPrefix(ans[0].Text, "It is the canister purge valve") {
+		t.Fatalf("expected the solution first, got %+v", ans)
+	}
+	if !strings.HasPrefix(ans[2].Text, "OP: ") {
+		t.Errorf("OP replies should be labelled: %q", ans[2].Text)
+	}
+	if len(p.Metadata.Fixes) != 1 || !strings.Contains(p.Metadata.Fixes[0], "purge valve") {
+		t.Errorf("unexpected fixes %v", p.Metadata.Fixes)
+	}
+
+	// The thread the forum failed to serve keeps its search title.
+	if q := posts[1]; q.Title != "Missing thread" || q.Content != "" {
+		t.Errorf("unexpected fallback post %+v", q)
+	}
+}
@@ -7,6 +7,7 @@ import "time"
 type ForumThread struct {
 	ID        string    `json:"id"`
 	ForumName string    `json:"forum_name"`
+	Engine    string    `json:"engine"`
 	Title     string    `json:"title"`
 	Author    string    `json:"author"`
 	Content   string    `json:"content"`
@@ -17,9 +18,11 @@ type ForumThread struct {
 
 // Reply represents a reply in a forum thread.
 type Reply struct {
-	Author  string    `json:"author"`
-	Content string    `json:"content"`
+	ID       string    `json:"id"`
+	Author   string    `json:"author"`
+	Content  string    `json:"content"`
 	PostedAt time.Time `json:"posted_at"`
+	Solution bool      `json:"solution,omitempty"` // marked or confirmed as the fix
 }
 
 // ForumConfig describes a single forum to scrape.
@@ -28,6 +31,9 @@ type ForumConfig struct {
 	BaseURL string
 	// SearchPath is the URL path template for search (uses %s for query).
 	SearchPath string
+	// Engine is the forum software: "xenforo", "vbulletin" or "discourse".
+	// Empty detects it from each thread page.
+	Engine string
 }
 
 // Config controls forum scraper behavior.
@@ -35,5 +41,6 @@ type Config struct {
 	Forums    []ForumConfig
 	Queries   []string
 	MaxPerForum int
+	MaxPages  int // reply pages fetched per thread (default 5)
 	RateLimit time.Duration
 }
@@ -8,9 +8,13 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	"golang.org/x/time/rate"
+
 	"github.com/WessleyAI/wessley-mvp/engine/scraper"
 	"github.com/WessleyAI/wessley-mvp/pkg/fn"
 )
@@ -36,8 +40,7 @@ func NewScraper(cfg Config) *Scraper {
 // FetchAll scrapes iFixit guides using search queries and returns ScrapedPosts.
 func (s *Scraper) FetchAll(ctx context.Context) ([]scraper.ScrapedPost, error) {
 	var allPosts []scraper.ScrapedPost
-	limiter := time.NewTicker(s.cfg.RateLimit)
-	defer limiter.Stop()
+	limiter := scraper.RateLimiter(ctx, s.cfg.RateLimit)
 
 	// Use search queries based on categories to find guides
 	queries := []string{
@@ -68,7 +71,7 @@ func (s *Scraper) FetchAll(ctx context.Context) ([]scraper.ScrapedPost, error) {
 		for _, p := range posts {
 			if !seen[p.SourceID] {
 				seen[p.SourceID] = true
-				allPosts = append(allPosts, p)
+				allPosts = append(allPosts, s.withGuide(ctx, p, limiter))
 			}
 		}
 
@@ -97,7 +100,7 @@ type searchResult struct {
 	ModifiedDate int64  `json:"modified_date"`
 }
 
-func (s *Scraper) searchGuides(ctx context.Context, query string, limiter *time.Ticker) ([]scraper.ScrapedPost, error) {
+func (s *Scraper) searchGuides(ctx context.Context, query string, limiter *rate.Limiter) ([]scraper.ScrapedPost, error) {
 	searchURL := fmt.Sprintf("%s/search/%s?limit=%d", baseURL, url.PathEscape(query), s.cfg.MaxGuides)
 
 	result := fn.Retry(ctx, fn.RetryOpts{
@@ -106,7 +109,9 @@ func (s *Scraper) searchGuides(ctx context.Context, query string, limiter *time.
 		MaxWait:     30 * time.Second,
 		Jitter:      true,
 	}, func(ctx context.Context) fn.Result[*searchResponse] {
-		<-limiter.C
+		if err := limiter.Wait(ctx); err != nil {
+			return fn.Err[*searchResponse](err)
+		}
 		return s.doSearch(ctx, searchURL)
 	})
 
@@ -156,35 +161,130 @@ func (s *Scraper) searchGuides(ctx context.Context, query string, limiter *time.
 }
 
 func (s *Scraper) doSearch(ctx context.Context, searchURL string) fn.Result[*searchResponse] {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, searchURL, nil)
-	if err != nil {
+	var sr searchResponse
+	if err := s.getJSON(ctx, searchURL, &sr); err != nil {
 		return fn.Err[*searchResponse](err)
 	}
+	return fn.Ok(&sr)
+}
+
+// getJSON fetches apiURL and decodes the JSON body into v.
+func (s *Scraper) getJSON(ctx context.Context, apiURL string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return err
+	}
 	req.Header.Set("User-Agent", "wessley-scraper/1.0 (automotive repair data collection)")
 
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return fn.Err[*searchResponse](err)
+		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
-		return fn.Err[*searchResponse](fmt.Errorf("http %d from %s", resp.StatusCode, searchURL))
+		return fmt.Errorf("http %d from %s", resp.StatusCode, apiURL)
 	}
 	if resp.StatusCode != http.StatusOK {
-		return fn.Err[*searchResponse](fmt.Errorf("unexpected status %d from %s", resp.StatusCode, searchURL))
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, apiURL)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fn.Err[*searchResponse](fmt.Errorf("read body: %w", err))
+		return fmt.Errorf("read body: %w", err)
 	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+	return nil
+}
 
-	var sr searchResponse
-	if err := json.Unmarshal(body, &sr); err != nil {
-		return fn.Err[*searchResponse](fmt.Errorf("decode: %w", err))
+// withGuide fetches the full guide behind a search result and, when it has
+// steps, replaces the post's summary with the step text and attaches the
+// steps, tools and cautions as a procedure. The post is returned unchanged
+// if the guide can't be fetched.
+func (s *Scraper) withGuide(ctx context.Context, p scraper.ScrapedPost, limiter *rate.Limiter) scraper.ScrapedPost {
+	id, err := strconv.Atoi(strings.TrimPrefix(p.SourceID, "ifixit-"))
+	if err != nil {
+		return p
 	}
-	return fn.Ok(&sr)
+	if err := limiter.Wait(ctx); err != nil {
+		return p
+	}
+	var g Guide
+	if err := s.getJSON(ctx, fmt.Sprintf("%s/guides/%d", baseURL, id), &g); err != nil {
+		log.Printf("warning: failed to fetch iFixit guide %d: %v", id, err)
+		return p
+	}
+	if len(g.Steps) == 0 {
+		return p
+	}
+
+	if g.Summary == "" {
+		g.Summary = p.Content
+	}
+	p.Content = buildGuideContent(g)
+	p.Metadata.Fixes = extractFixes(p.Content)
+	p.Metadata.Procedure = guideProcedure(g, p.Title, p.URL)
+	if g.Subject != "" {
+		p.Metadata.Components = g.Subject
+	}
+	if vi := vehicleFromCategory(g.Category); vi != nil {
+		p.Metadata.VehicleInfo = vi
+		p.Metadata.Vehicle = fmt.Sprintf("%d %s %s", vi.Year, vi.Make, vi.Model)
+	}
+	return p
+}
+
+// guideProcedure converts a guide's steps, tools and caution lines into a
+// graph procedure. The guide's subject, e.g. "Alternator", is the component.
+func guideProcedure(g Guide, title, guideURL string) *scraper.Procedure {
+	proc := &scraper.Procedure{Title: title, Source: "ifixit", URL: guideURL}
+	if g.Subject != "" {
+		proc.Components = []string{g.Subject}
+	}
+	for _, t := range g.Tools {
+		if t.Text != "" {
+			proc.Tools = append(proc.Tools, t.Text)
+		}
+	}
+	for i, step := range g.Steps {
+		var lines []string
+		for _, line := range step.Lines {
+			if line.Text == "" {
+				continue
+			}
+			if line.Bullet == "icon_caution" {
+				proc.Warnings = append(proc.Warnings, line.Text)
+			}
+			lines = append(lines, line.Text)
+		}
+		order := step.OrderBy
+		if order == 0 {
+			order = i + 1
+		}
+		proc.Steps = append(proc.Steps, scraper.ProcedureStep{Order: order, Title: step.Title, Text: strings.Join(lines, "\n")})
+	}
+	return proc
+}
+
+// categoryYears matches a model year or range such as "2009-2014".
+var categoryYears = regexp.MustCompile(`\(?\b((?:19|20)\d\d)(?:\s*-\s*(?:19|20)?\d\d)?\b\)?`)
+
+// vehicleFromCategory reads make, model and year from a device category
+// like "Ford F-150 2009-2014" or "2007-2011 Toyota Camry". A range is tagged
+// with its first year. It returns nil unless the category names all three.
+func vehicleFromCategory(category string) *scraper.VehicleInfo {
+	m := categoryYears.FindStringSubmatchIndex(category)
+	if m == nil {
+		return nil
+	}
+	year, _ := strconv.Atoi(category[m[2]:m[3]])
+	words := strings.Fields(category[:m[0]] + " " + category[m[1]:])
+	if len(words) < 2 {
+		return nil
+	}
+	return &scraper.VehicleInfo{Make: words[0], Model: strings.Join(words[1:], " "), Year: year}
 }
 
 // buildGuideContent builds a text representation of a Guide for ingestion.
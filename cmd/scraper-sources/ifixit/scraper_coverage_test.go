@@ -185,3 +185,81 @@ func TestExtractFixes_Coverage(t *testing.T) {
 		}
 	}
 }
+
+func TestFetchAll_GuideSteps(t *testing.T) {
+	search := searchResponse{Results: []searchResult{
+		{DataType: "guide", GuideID: 77, Title: "Ford F-150 (2009-2014) Alternator Replacement", Summary: "Swap the alternator", URL: "https://ifixit.com/Guide/77"},
+	}}
+	guide := Guide{
+		GuideID:  77,
+		Category: "Ford F-150 2009-2014",
+		Subject:  "Alternator",
+		Steps: []Step{
+			{OrderBy: 1, Title: "Battery", Lines: []Line{
+				{Text: "Disconnect the negative battery cable.", Bullet: "black"},
+				{Text: "Never work on a live charging circuit.", Bullet: "icon_caution"},
+			}},
+			{OrderBy: 2, Title: "Belt", Lines: []Line{{Text: "Release the belt tensioner."}}},
+		},
+		Tools: []Tool{{Text: "13 mm socket"}},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/2.0/guides/") {
+			json.NewEncoder(w).Encode(guide)
+			return
+		}
+		json.NewEncoder(w).Encode(search)
+	}))
+	defer srv.Close()
+
+	s := NewScraper(Config{MaxGuides: 1, RateLimit: time.Millisecond})
+	s.client = &http.Client{Transport: &redirectTransport{server: srv}, Timeout: 5 * time.Second}
+
+	posts, err := s.FetchAll(context.Background())
+	if err != nil || len(posts) != 1 {
+		t.Fatalf("FetchAll: %v, %d posts", err, len(posts))
+	}
+	p := posts[0]
+	if !strings.Contains(p.Content, "Step 2: Belt") || !strings.Contains(p.Content, "Swap the alternator") {
+		t.Errorf("expected step text in content, got %q", p.Content)
+	}
+	vi := p.Metadata.VehicleInfo
+	if vi == nil || vi.Make != "Ford" || vi.Model != "F-150" || vi.Year != 2009 {
+		t.Errorf("unexpected vehicle %+v", vi)
+	}
+	proc := p.Metadata.Procedure
+	if proc == nil || len(proc.Steps) != 2 || proc.Steps[0].Title != "Battery" || proc.URL != "https://ifixit.com/Guide/77" {
+		t.Fatalf("unexpected procedure %+v", proc)
+	}
+	if len(proc.Components) != 1 || proc.Components[0] != "Alternator" || len(proc.Tools) != 1 {
+		t.Errorf("unexpected components/tools %+v", proc)
+	}
+	if len(proc.Warnings) != 1 || proc.Warnings[0] != "Never work on a live charging circuit." {
+		t.Errorf("expected the caution line as a warning, got %v", proc.Warnings)
+	}
+}
+
+func TestVehicleFromCategory(t *testing.T) {
+	tests := []struct {
+		category, make, model string
+		year                  int
+	}{
+		{"Ford F-150 2009-2014", "Ford", "F-150", 2009},
+		{"2007-2011 Toyota Camry", "Toyota", "Camry", 2007},
+		{"Honda Civic (2006)", "Honda", "Civic", 2006},
+		{"Car and Truck", "", "", 0},
+		{"2005 Ford", "", "", 0},
+	}
+	for _, tt := range tests {
+		vi := vehicleFromCategory(tt.category)
+		if tt.year == 0 {
+			if vi != nil {
+				t.Errorf("%q: expected nil, got %+v", tt.category, vi)
+			}
+			continue
+		}
+		if vi == nil || vi.Make != tt.make || vi.Model != tt.model || vi.Year != tt.year {
+			t.Errorf("%q: got %+v", tt.category, vi)
+		}
+	}
+}
@@ -18,9 +18,15 @@ type Guide struct {
 		Username string `json:"username"`
 	} `json:"author"`
 	Steps []Step `json:"steps"`
+	Tools []Tool `json:"tools"`
 	ModifiedDate int64 `json:"modified_date"`
 }
 
+// Tool is a tool a guide lists as required.
+type Tool struct {
+	Text string `json:"text"`
+}
+
 // Step represents a single step in a guide.
 type Step struct {
 	OrderBy int    `json:"orderby"`
@@ -30,8 +36,9 @@ type Step struct {
 
 // Line represents a text line in a step.
 type Line struct {
-	Text  string `json:"text_raw"`
-	Level int    `json:"level"`
+	Text   string `json:"text_raw"`
+	Level  int    `json:"level"`
+	Bullet string `json:"bullet"` // colour, or icon_caution, icon_note, icon_reminder
 }
 
 // Config controls iFixit scraper behavior.
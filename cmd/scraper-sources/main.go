@@ -1,16 +1,17 @@
-// Command scraper-sources scrapes automotive data from NHTSA complaints,
-// iFixit repair guides, and automotive forums, outputting structured JSON
-// to stdout or publishing to NATS.
+// Command scraper-sources scrapes automotive data from NHTSA complaints and
+// recalls, iFixit repair guides, and automotive forums, outputting structured
+// JSON to stdout or publishing to NATS.
 package main
 
 import (
 	"context"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -24,7 +25,7 @@ import (
 	"github.com/WessleyAI/wessley-mvp/cmd/scraper-sources/nhtsa"
 	"github.com/WessleyAI/wessley-mvp/engine/graph"
 	"github.com/WessleyAI/wessley-mvp/engine/scraper"
-	"github.com/WessleyAI/wessley-mvp/pkg/natsutil"
+	"github.com/WessleyAI/wessley-mvp/pkg/blob"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 )
 
@@ -32,56 +33,39 @@ func main() {
 	natsURL := flag.String("nats", "", "NATS URL (if empty, output JSON to stdout)")
 	subject := flag.String("subject", "wessley.scraper.sources.posts", "NATS subject to publish to")
 	outputDir := flag.String("output-dir", "", "directory to write JSON files for ingest pipeline (e.g. /tmp/wessley-data)")
+	outputMaxBytes := flag.Int64("output-max-bytes", 0, "rotate output-dir files at this size (0 = one file per fetch)")
+	outputMaxAge := flag.Duration("output-max-age", 0, "rotate output-dir files at this age (0 = one file per fetch)")
+	cursorsPath := flag.String("cursors", "", "file to persist fetch cursors in (default: in memory)")
 	interval := flag.Duration("interval", 30*time.Minute, "polling interval (0 = one-shot)")
-	sources := flag.String("sources", "nhtsa,ifixit,forums", "comma-separated sources to scrape")
+	sources := flag.String("sources", "nhtsa,recalls,ifixit,forums", "comma-separated sources to scrape")
 	nhtsaMakes := flag.String("nhtsa-makes", "TOYOTA,HONDA,FORD,CHEVROLET,BMW,NISSAN", "comma-separated vehicle makes for NHTSA")
 	nhtsaYear := flag.Int("nhtsa-year", 2024, "model year for NHTSA queries (single year; overridden by -nhtsa-year-start/-nhtsa-year-end)")
 	nhtsaYearStart := flag.Int("nhtsa-year-start", 0, "start of model year range for NHTSA (inclusive)")
 	nhtsaYearEnd := flag.Int("nhtsa-year-end", 0, "end of model year range for NHTSA (inclusive)")
+	nhtsaInvestigations := flag.Bool("nhtsa-investigations", true, "also scrape ODI defect investigations with the recalls source")
+	forumMaxPages := flag.Int("forum-max-pages", 5, "reply pages fetched per forum thread")
 	manualsDir := flag.String("manuals-dir", "", "directory containing PDF vehicle manuals (legacy) / output dir for crawler")
 	manualsMax := flag.Int("manuals-max", 0, "max manual files to process (0 = unlimited)")
 	manualsDiscover := flag.Bool("manuals-discover", false, "crawl sources and build manual index only")
 	manualsDownload := flag.Bool("manuals-download", false, "download pending manuals from index")
 	manualsProcess := flag.Bool("manuals-process", false, "full pipeline: discover + download + ingest")
 	manualsStatus := flag.Bool("manuals-status", false, "print manual registry stats")
+	manualsReparse := flag.Bool("manuals-reparse", false, "re-run extraction on manuals kept in the blob store")
 	manualsMakes := flag.String("manuals-makes", "", "comma-separated makes to target")
 	manualsYears := flag.String("manuals-years", "2015-2026", "year range (e.g. 2015-2026)")
 	manualsSources := flag.String("manuals-sources", "toyota,honda,ford,chevrolet,gmc,ram,jeep,dodge,chrysler,nissan,hyundai,kia,subaru,mazda,volkswagen,bmw,mercedes,audi,tesla,volvo,lexus,acura,infiniti,genesis,porsche,mitsubishi,lincoln,buick,cadillac,archive,nhtsa,search", "comma-separated sources")
+	manualsCatalog := flag.String("manuals-catalog", "", "JSON catalog of template manual sources (default: built-in)")
+	blobStore := flag.String("blob-store", "", "manual PDF store: a directory or s3://bucket/prefix (default: <manuals-dir>/blobs)")
+	blobMaxBytes := flag.Int64("blob-max-bytes", 0, "evict the oldest stored PDFs beyond this many bytes (0 = unlimited)")
+	blobMaxAge := flag.Duration("blob-max-age", 0, "evict stored PDFs older than this (0 = keep)")
 	neo4jURL := flag.String("neo4j-url", "", "Neo4j URL for manual registry")
 	neo4jUser := flag.String("neo4j-user", "neo4j", "Neo4j username")
 	neo4jPass := flag.String("neo4j-pass", "password", "Neo4j password")
 	flag.Parse()
 
 	met := metrics.New()
-	mDocsTotal := func(source string) *metrics.Counter {
-		return met.Counter(metrics.WithLabels("wessley_scraper_sources_docs_total", "source", source), "Docs scraped by source")
-	}
-	mErrorsTotal := func(source string) *metrics.Counter {
-		return met.Counter(metrics.WithLabels("wessley_scraper_sources_errors_total", "source", source), "Scraper errors by source")
-	}
-	mScrapeDur := func(source string) *metrics.Histogram {
-		return met.Histogram(metrics.WithLabels("wessley_scraper_sources_scrape_duration_seconds", "source", source), "Scrape duration by source", nil)
-	}
-	mLastScrape := met.Gauge("wessley_scraper_sources_last_scrape_timestamp", "Epoch of last scrape")
 	mManualsDiscovered := met.Counter("wessley_scraper_manuals_discovered_total", "Manuals discovered")
 	mManualsDownloaded := met.Counter("wessley_scraper_manuals_downloaded_total", "Manuals downloaded")
-	mManualsFailed := met.Counter("wessley_scraper_manuals_failed_total", "Manuals failed")
-	mBytesTotal := func(source string) *metrics.Counter {
-		return met.Counter(metrics.WithLabels("wessley_scraper_sources_bytes_total", "source", source), "Bytes scraped by source")
-	}
-	mManualsDownloadBytes := met.Counter("wessley_scraper_manuals_download_bytes_total", "Total manual download bytes")
-	mManualsDownloadDur := met.Histogram("wessley_scraper_manuals_download_duration_seconds", "Manual download duration", nil)
-	mManualsPdfPages := met.Counter("wessley_scraper_manuals_pdf_pages_total", "Total PDF pages processed")
-
-	// Suppress unused warnings for manual-mode metrics
-	_ = mManualsDiscovered
-	_ = mManualsDownloaded
-	_ = mManualsFailed
-	_ = mBytesTotal
-	_ = mManualsDownloadBytes
-	_ = mManualsDownloadDur
-	_ = mManualsPdfPages
-
 	met.CollectRuntime("wessley_scraper_sources", 15*time.Second)
 	met.ServeAsync(9092)
 
@@ -89,7 +73,7 @@ func main() {
 	defer stop()
 
 	// --- Manual crawler mode ---
-	if *manualsDiscover || *manualsDownload || *manualsProcess || *manualsStatus {
+	if *manualsDiscover || *manualsDownload || *manualsProcess || *manualsStatus || *manualsReparse {
 		if *neo4jURL == "" {
 			*neo4jURL = os.Getenv("NEO4J_URL")
 			if *neo4jURL == "" {
@@ -122,6 +106,15 @@ func main() {
 			makes = strings.Split(*manualsMakes, ",")
 		}
 
+		blobSpec := *blobStore
+		if blobSpec == "" {
+			blobSpec = filepath.Join(outputDir, "blobs")
+		}
+		blobs, err := blob.Open(blobSpec)
+		if err != nil {
+			log.Fatalf("blob store: %v", err)
+		}
+
 		crawlerCfg := manuals.CrawlerConfig{
 			OutputDir:    outputDir,
 			MaxPerSource: 100,
@@ -131,9 +124,15 @@ func main() {
 			Concurrency:  3,
 			Makes:        makes,
 			YearRange:    yearRange,
+			Blobs:        blobs,
+			Retention:    blob.Retention{MaxAge: *blobMaxAge, MaxBytes: *blobMaxBytes},
 		}
 
-		srcs := buildManualSources(*manualsSources)
+		crawlerCfg.Fetcher = manuals.NewFetcher(&http.Client{Timeout: 60 * time.Second}, manuals.FetcherOpts{UserAgent: crawlerCfg.UserAgent})
+		srcs, err := buildManualSources(*manualsSources, *manualsCatalog, crawlerCfg.Fetcher)
+		if err != nil {
+			log.Fatalf("manual sources: %v", err)
+		}
 		crawler := manuals.NewCrawler(graphStore, crawlerCfg, srcs...)
 
 		switch {
@@ -156,12 +155,14 @@ func main() {
 			if err != nil {
 				log.Fatalf("discover: %v", err)
 			}
+			mManualsDiscovered.Add(int64(n))
 			fmt.Printf("Discovered %d manuals\n", n)
 		case *manualsDownload:
 			n, err := crawler.Download(ctx, 0)
 			if err != nil {
 				log.Fatalf("download: %v", err)
 			}
+			mManualsDownloaded.Add(int64(n))
 			fmt.Printf("Downloaded %d manuals\n", n)
 			// Auto-ingest downloaded PDFs into JSON
 			ni, err := crawler.Ingest(ctx, outputDir, 0)
@@ -175,6 +176,12 @@ func main() {
 				log.Fatalf("process: %v", err)
 			}
 			fmt.Println("Manual processing complete")
+		case *manualsReparse:
+			n, err := crawler.Reparse(ctx, outputDir, *manualsMax)
+			if err != nil {
+				log.Fatalf("reparse: %v", err)
+			}
+			fmt.Printf("Reparsed %d manuals\n", n)
 		}
 		return
 	}
@@ -184,15 +191,13 @@ func main() {
 		enabledSources[strings.TrimSpace(s)] = true
 	}
 
-	// Initialize scrapers
-	var nhtsaScraper *nhtsa.Scraper
-	if enabledSources["nhtsa"] {
+	var jobs []scraper.Job
+	if enabledSources["nhtsa"] || enabledSources["recalls"] {
 		makes := strings.Split(*nhtsaMakes, ",")
 		cfg := nhtsa.Config{
 			Makes:      makes,
 			ModelYear:  *nhtsaYear,
 			MaxPerMake: 50,
-			RateLimit:  2 * time.Second,
 		}
 		// If year range flags are set, build ModelYears list
 		if *nhtsaYearStart > 0 && *nhtsaYearEnd > 0 {
@@ -201,33 +206,51 @@ func main() {
 			}
 			log.Printf("NHTSA year range: %d-%d (%d years)", *nhtsaYearStart, *nhtsaYearEnd, len(cfg.ModelYears))
 		}
-		nhtsaScraper = nhtsa.NewScraper(cfg)
+		if enabledSources["nhtsa"] {
+			jobs = append(jobs, scraper.Job{
+				Source:   scraper.FetchAllSource("nhtsa", nhtsa.NewScraper(cfg).FetchAll),
+				Interval: *interval,
+				Rate:     2 * time.Second,
+			})
+		}
+		if enabledSources["recalls"] {
+			jobs = append(jobs, scraper.Job{
+				Source:   scraper.FetchAllSource("nhtsa-recalls", nhtsa.NewRecallScraper(cfg, *nhtsaInvestigations).FetchAll),
+				Interval: *interval,
+				Rate:     2 * time.Second,
+			})
+		}
 	}
 
-	var ifixitScraper *ifixit.Scraper
 	if enabledSources["ifixit"] {
-		ifixitScraper = ifixit.NewScraper(ifixit.Config{
+		ifixitScraper := ifixit.NewScraper(ifixit.Config{
 			Categories: []string{
 				"Car and Truck",
 				"Car",
 			},
 			MaxGuides: 50,
-			RateLimit: 1 * time.Second,
+		})
+		jobs = append(jobs, scraper.Job{
+			Source:   scraper.FetchAllSource("ifixit", ifixitScraper.FetchAll),
+			Interval: *interval,
+			Rate:     1 * time.Second,
 		})
 	}
 
-	var manualScraper *manuals.Scraper
 	if enabledSources["manuals"] && *manualsDir != "" {
-		manualScraper = manuals.NewScraper(manuals.Config{
+		manualScraper := manuals.NewScraper(manuals.Config{
 			Directory: *manualsDir,
 			MaxFiles:  *manualsMax,
 			RateLimit: 500 * time.Millisecond,
 		})
+		jobs = append(jobs, scraper.Job{
+			Source:   scraper.FetchAllSource("manual", manualScraper.FetchAll),
+			Interval: *interval,
+		})
 	}
 
-	var forumScraper *forums.Scraper
 	if enabledSources["forums"] {
-		forumScraper = forums.NewScraper(forums.Config{
+		forumScraper := forums.NewScraper(forums.Config{
 			Forums: forums.DefaultForums(),
 			Queries: []string{
 				"engine repair",
@@ -237,164 +260,46 @@ func main() {
 				"oil leak",
 			},
 			MaxPerForum: 25,
-			RateLimit:   3 * time.Second,
+			MaxPages:    *forumMaxPages,
+		})
+		jobs = append(jobs, scraper.Job{
+			Source:   scraper.FetchAllSource("forum", forumScraper.FetchAll),
+			Interval: *interval,
+			Rate:     3 * time.Second,
 		})
 	}
 
-	var nc *nats.Conn
+	var sinks []scraper.Sink
 	if *natsURL != "" {
-		var err error
-		nc, err = nats.Connect(*natsURL)
+		nc, err := nats.Connect(*natsURL)
 		if err != nil {
 			log.Fatalf("nats connect: %v", err)
 		}
 		defer nc.Close()
 		log.Printf("publishing to NATS subject %s", *subject)
+		sinks = append(sinks, scraper.NewNATSSink(nc, *subject))
+	} else {
+		sinks = append(sinks, scraper.NewWriterSink(os.Stdout))
 	}
-
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-
-	// Ensure output dir exists if specified
 	if *outputDir != "" {
-		os.MkdirAll(*outputDir, 0o755)
-		log.Printf("writing JSON files to %s", *outputDir)
-	}
-
-	emit := func(posts []scraper.ScrapedPost) error {
-		for _, p := range posts {
-			if nc != nil {
-				if err := natsutil.Publish(ctx, nc, *subject, p); err != nil {
-					log.Printf("nats publish error: %v", err)
-				}
-			} else {
-				if err := enc.Encode(p); err != nil {
-					return fmt.Errorf("encode: %w", err)
-				}
-			}
-		}
-		// Write posts to output dir as JSON files for ingest pipeline
-		if *outputDir != "" && len(posts) > 0 {
-			source := "unknown"
-			if len(posts) > 0 {
-				source = posts[0].Source
-			}
-			// Replace colons in source (e.g. "reddit:MechanicAdvice" → "reddit-MechanicAdvice")
-			source = strings.ReplaceAll(source, ":", "-")
-			filename := fmt.Sprintf("%s/%s-%d.json", *outputDir, source, time.Now().UnixNano())
-			f, err := os.Create(filename)
-			if err != nil {
-				log.Printf("output-dir write error: %v", err)
-			} else {
-				fenc := json.NewEncoder(f)
-				for _, p := range posts {
-					fenc.Encode(p)
-				}
-				f.Close()
-				log.Printf("wrote %d posts to %s", len(posts), filename)
-			}
-		}
-		return nil
-	}
-
-	run := func() error {
-		var total int
-
-		if nhtsaScraper != nil {
-			start := time.Now()
-			posts, err := nhtsaScraper.FetchAll(ctx)
-			if err != nil {
-				mErrorsTotal("nhtsa").Inc()
-				log.Printf("nhtsa error: %v", err)
-			} else {
-				mScrapeDur("nhtsa").Since(start)
-				mDocsTotal("nhtsa").Add(int64(len(posts)))
-				log.Printf("nhtsa: fetched %d posts", len(posts))
-				total += len(posts)
-				if err := emit(posts); err != nil {
-					return err
-				}
-			}
-		}
-
-		if ifixitScraper != nil {
-			start := time.Now()
-			posts, err := ifixitScraper.FetchAll(ctx)
-			if err != nil {
-				mErrorsTotal("ifixit").Inc()
-				log.Printf("ifixit error: %v", err)
-			} else {
-				mScrapeDur("ifixit").Since(start)
-				mDocsTotal("ifixit").Add(int64(len(posts)))
-				log.Printf("ifixit: fetched %d posts", len(posts))
-				total += len(posts)
-				if err := emit(posts); err != nil {
-					return err
-				}
-			}
-		}
-
-		if manualScraper != nil {
-			start := time.Now()
-			posts, err := manualScraper.FetchAll(ctx)
-			if err != nil {
-				mErrorsTotal("manuals").Inc()
-				log.Printf("manuals error: %v", err)
-			} else {
-				mScrapeDur("manuals").Since(start)
-				mDocsTotal("manuals").Add(int64(len(posts)))
-				log.Printf("manuals: fetched %d posts", len(posts))
-				total += len(posts)
-				if err := emit(posts); err != nil {
-					return err
-				}
-			}
-		}
-
-		if forumScraper != nil {
-			start := time.Now()
-			posts, err := forumScraper.FetchAll(ctx)
-			if err != nil {
-				mErrorsTotal("forums").Inc()
-				log.Printf("forums error: %v", err)
-			} else {
-				mScrapeDur("forums").Since(start)
-				mDocsTotal("forums").Add(int64(len(posts)))
-				log.Printf("forums: fetched %d posts", len(posts))
-				total += len(posts)
-				if err := emit(posts); err != nil {
-					return err
-				}
-			}
+		dir, err := scraper.NewDirSink(*outputDir, scraper.DirSinkOpts{MaxBytes: *outputMaxBytes, MaxAge: *outputMaxAge})
+		if err != nil {
+			log.Fatalf("output dir: %v", err)
 		}
-
-		mLastScrape.Set(time.Now().Unix())
-		log.Printf("total: %d posts scraped", total)
-		return nil
+		log.Printf("writing JSON files to %s", *outputDir)
+		sinks = append(sinks, dir)
 	}
 
-	// First run
-	if err := run(); err != nil {
-		log.Fatalf("scrape: %v", err)
+	cursors, err := scraper.OpenCursors(*cursorsPath)
+	if err != nil {
+		log.Fatalf("cursors: %v", err)
 	}
 
-	if *interval <= 0 {
-		return
-	}
+	rt := scraper.NewRuntime(scraper.RuntimeConfig{Sinks: sinks, Cursors: cursors, Metrics: met}, jobs...)
+	defer rt.Close()
 
-	// Poll loop
-	ticker := time.NewTicker(*interval)
-	defer ticker.Stop()
-	for {
-		select {
-		case <-ctx.Done():
-			log.Println("shutting down")
-			return
-		case <-ticker.C:
-			if err := run(); err != nil {
-				log.Printf("scrape error: %v", err)
-			}
-		}
+	if err := rt.Run(ctx); err != nil {
+		log.Printf("scrape: %v", err)
 	}
 }
 
@@ -414,7 +319,16 @@ func parseYearRange(s string) [2]int {
 	return yr
 }
 
-func buildManualSources(sourcesList string) []manuals.ManualSource {
+// buildManualSources creates the enabled sources around the shared Fetcher
+// f, which the crawler's downloads also go through, so every request is
+// paced by the same per-host schedule. Makes without a source of their own
+// come from the catalog at catalogPath (the built-in one if empty).
+func buildManualSources(sourcesList, catalogPath string, f *manuals.Fetcher) ([]manuals.ManualSource, error) {
+	catalog, err := manuals.LoadCatalog(catalogPath)
+	if err != nil {
+		return nil, err
+	}
+
 	enabled := make(map[string]bool)
 	for _, s := range strings.Split(sourcesList, ",") {
 		enabled[strings.TrimSpace(s)] = true
@@ -422,100 +336,27 @@ func buildManualSources(sourcesList string) []manuals.ManualSource {
 
 	var srcs []manuals.ManualSource
 	if enabled["toyota"] {
-		srcs = append(srcs, manuals.NewToyotaSource())
+		srcs = append(srcs, manuals.NewToyotaSource(f))
 	}
 	if enabled["honda"] {
-		srcs = append(srcs, manuals.NewHondaSource())
+		srcs = append(srcs, manuals.NewHondaSource(f))
 	}
 	if enabled["ford"] {
-		srcs = append(srcs, manuals.NewFordSource())
+		srcs = append(srcs, manuals.NewFordSource(f))
 	}
 	if enabled["archive"] {
-		srcs = append(srcs, manuals.NewArchiveSource())
+		srcs = append(srcs, manuals.NewArchiveSource(f))
 	}
 	if enabled["nhtsa"] {
-		srcs = append(srcs, manuals.NewNHTSASource())
-	}
-	if enabled["chevrolet"] {
-		srcs = append(srcs, manuals.NewChevroletSource())
-	}
-	if enabled["gmc"] {
-		srcs = append(srcs, manuals.NewGMCSource())
-	}
-	if enabled["ram"] {
-		srcs = append(srcs, manuals.NewRamSource())
-	}
-	if enabled["jeep"] {
-		srcs = append(srcs, manuals.NewJeepSource())
-	}
-	if enabled["dodge"] {
-		srcs = append(srcs, manuals.NewDodgeSource())
-	}
-	if enabled["chrysler"] {
-		srcs = append(srcs, manuals.NewChryslerSource())
+		srcs = append(srcs, manuals.NewNHTSASource(f))
 	}
-	if enabled["nissan"] {
-		srcs = append(srcs, manuals.NewNissanSource())
-	}
-	if enabled["hyundai"] {
-		srcs = append(srcs, manuals.NewHyundaiSource())
-	}
-	if enabled["kia"] {
-		srcs = append(srcs, manuals.NewKiaSource())
-	}
-	if enabled["subaru"] {
-		srcs = append(srcs, manuals.NewSubaruSource())
-	}
-	if enabled["mazda"] {
-		srcs = append(srcs, manuals.NewMazdaSource())
-	}
-	if enabled["volkswagen"] {
-		srcs = append(srcs, manuals.NewVolkswagenSource())
-	}
-	if enabled["bmw"] {
-		srcs = append(srcs, manuals.NewBMWSource())
-	}
-	if enabled["mercedes"] {
-		srcs = append(srcs, manuals.NewMercedesBenzSource())
-	}
-	if enabled["audi"] {
-		srcs = append(srcs, manuals.NewAudiSource())
-	}
-	if enabled["tesla"] {
-		srcs = append(srcs, manuals.NewTeslaSource())
-	}
-	if enabled["volvo"] {
-		srcs = append(srcs, manuals.NewVolvoSource())
-	}
-	if enabled["lexus"] {
-		srcs = append(srcs, manuals.NewLexusSource())
-	}
-	if enabled["acura"] {
-		srcs = append(srcs, manuals.NewAcuraSource())
-	}
-	if enabled["infiniti"] {
-		srcs = append(srcs, manuals.NewInfinitiSource())
-	}
-	if enabled["genesis"] {
-		srcs = append(srcs, manuals.NewGenesisSource())
-	}
-	if enabled["porsche"] {
-		srcs = append(srcs, manuals.NewPorscheSource())
-	}
-	if enabled["mitsubishi"] {
-		srcs = append(srcs, manuals.NewMitsubishiSource())
-	}
-	if enabled["lincoln"] {
-		srcs = append(srcs, manuals.NewLincolnSource())
-	}
-	if enabled["buick"] {
-		srcs = append(srcs, manuals.NewBuickSource())
-	}
-	if enabled["cadillac"] {
-		srcs = append(srcs, manuals.NewCadillacSource())
+	for _, def := range catalog.Sources {
+		if enabled[def.Name] {
+			srcs = append(srcs, manuals.NewTemplateSource(def, f))
+		}
 	}
 	if enabled["search"] {
-		srcs = append(srcs, manuals.NewGenericSearchSource())
+		srcs = append(srcs, manuals.NewGenericSearchSource(f))
 	}
-	return srcs
+	return srcs, nil
 }
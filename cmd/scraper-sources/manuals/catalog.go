@@ -0,0 +1,139 @@
+package manuals
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+//go:embed catalog.json
+var defaultCatalog []byte
+
+// Verification policies for catalog URLs built from templates.
+const (
+	VerifyNone = "none" // emit every templated URL; the downloader finds the dead ones
+	VerifyHead = "head" // emit only URLs that answer a HEAD request
+)
+
+// Catalog lists the manual sources that are plain data: a make, its models
+// and the URL patterns its manuals live at. Sites that need code (Toyota,
+// Archive.org, NHTSA) stay ManualSource implementations of their own.
+type Catalog struct {
+	Sources []SourceDef `json:"sources"`
+}
+
+// SourceDef describes one TemplateSource.
+type SourceDef struct {
+	Name       string `json:"name"`                  // source identifier, as in -manuals-sources
+	Make       string `json:"make"`                  // e.g. "Mercedes-Benz"
+	Site       string `json:"site"`                  // ManualEntry.SourceSite
+	ManualType string `json:"manual_type,omitempty"` // default "owner"
+	Language   string `json:"language,omitempty"`    // default "en"
+	// URLTemplates are expanded per model and year; {year}, {model} (the
+	// slug) and {make} are replaced.
+	URLTemplates []string   `json:"url_templates"`
+	Verify       string     `json:"verify,omitempty"` // VerifyNone (default) or VerifyHead
+	Models       []ModelDef `json:"models"`
+	Index        *IndexDef  `json:"index,omitempty"`
+}
+
+// ModelDef is a model slug as it appears in the make's URLs, with the
+// model years it was sold. Zero From or To leaves that end open.
+type ModelDef struct {
+	Slug string `json:"slug"`
+	Name string `json:"name,omitempty"` // display name; default normModel(Slug)
+	From int    `json:"from,omitempty"`
+	To   int    `json:"to,omitempty"`
+}
+
+// IndexDef is a per-year index page scraped for additional PDF links.
+type IndexDef struct {
+	URL string `json:"url"` // template with {year}
+	// Pattern is a regexp whose first group is a manual URL; default is
+	// any href to a .pdf.
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// LoadCatalog reads the catalog at path, or the built-in one when path is
+// empty.
+func LoadCatalog(path string) (*Catalog, error) {
+	data := defaultCatalog
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("manuals: read catalog: %w", err)
+		}
+	}
+	var c Catalog
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("manuals: parse catalog: %w", err)
+	}
+	seen := make(map[string]bool, len(c.Sources))
+	for i := range c.Sources {
+		def := &c.Sources[i]
+		if err := def.validate(); err != nil {
+			return nil, fmt.Errorf("manuals: catalog source %q: %w", def.Name, err)
+		}
+		if seen[def.Name] {
+			return nil, fmt.Errorf("manuals: catalog source %q defined twice", def.Name)
+		}
+		seen[def.Name] = true
+	}
+	return &c, nil
+}
+
+// validate checks def and fills in defaults.
+func (def *SourceDef) validate() error {
+	switch {
+	case def.Name == "" || def.Make == "" || def.Site == "":
+		return fmt.Errorf("name, make and site are required")
+	case len(def.URLTemplates) == 0 && def.Index == nil:
+		return fmt.Errorf("needs url_templates or an index")
+	}
+	for _, t := range def.URLTemplates {
+		if !strings.Contains(t, "{year}") || !strings.Contains(t, "{model}") {
+			return fmt.Errorf("url template %q needs {year} and {model}", t)
+		}
+	}
+	if def.Index != nil {
+		if def.Index.URL == "" {
+			return fmt.Errorf("index url is required")
+		}
+		if def.Index.Pattern != "" {
+			re, err := regexp.Compile(def.Index.Pattern)
+			if err != nil {
+				return fmt.Errorf("index pattern: %w", err)
+			}
+			if re.NumSubexp() < 1 {
+				return fmt.Errorf("index pattern needs a group capturing the url")
+			}
+		}
+	}
+	switch def.Verify {
+	case "":
+		def.Verify = VerifyNone
+	case VerifyNone, VerifyHead:
+	default:
+		return fmt.Errorf("unknown verify policy %q", def.Verify)
+	}
+	if def.ManualType == "" {
+		def.ManualType = "owner"
+	}
+	if def.Language == "" {
+		def.Language = "en"
+	}
+	return nil
+}
+
+// Source returns the catalog's source named name.
+func (c *Catalog) Source(name string) (SourceDef, bool) {
+	for _, def := range c.Sources {
+		if def.Name == name {
+			return def, true
+		}
+	}
+	return SourceDef{}, false
+}
@@ -0,0 +1,135 @@
+package manuals
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// catalogSource returns the built-in catalog's source called name.
+func catalogSource(t *testing.T, name string) *TemplateSource {
+	t.Helper()
+	c, err := LoadCatalog("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	def, ok := c.Source(name)
+	if !ok {
+		t.Fatalf("no catalog source %q", name)
+	}
+	return NewTemplateSource(def, testFetcher())
+}
+
+func TestLoadCatalogBuiltin(t *testing.T) {
+	c, err := LoadCatalog("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Sources) < 20 {
+		t.Errorf("expected the built-in catalog to cover the OEM sites, got %d sources", len(c.Sources))
+	}
+	def, ok := c.Source("mercedes")
+	if !ok || def.Make != "Mercedes-Benz" || def.Verify != VerifyNone || def.ManualType != "owner" {
+		t.Errorf("unexpected mercedes source %+v", def)
+	}
+}
+
+func TestLoadCatalogInvalid(t *testing.T) {
+	tests := map[string]string{
+		"missing make":   `{"sources":[{"name":"x","site":"x.com","url_templates":["https://x.com/{year}/{model}.pdf"]}]}`,
+		"no placeholder": `{"sources":[{"name":"x","make":"X","site":"x.com","url_templates":["https://x.com/manual.pdf"]}]}`,
+		"bad verify":     `{"sources":[{"name":"x","make":"X","site":"x.com","verify":"get","url_templates":["https://x.com/{year}/{model}.pdf"]}]}`,
+		"bad pattern":    `{"sources":[{"name":"x","make":"X","site":"x.com","index":{"url":"https://x.com/{year}","pattern":"\\.pdf"}}]}`,
+		"duplicate":      `{"sources":[{"name":"x","make":"X","site":"x.com","index":{"url":"https://x.com/{year}"}},{"name":"x","make":"Y","site":"y.com","index":{"url":"https://y.com/{year}"}}]}`,
+		"not json":       `sources: []`,
+	}
+	for name, body := range tests {
+		path := filepath.Join(t.TempDir(), "catalog.json")
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadCatalog(path); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestTemplateSourceDiscover(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		heads []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/robots.txt":
+			http.NotFound(w, r)
+		case r.Method == http.MethodHead:
+			mu.Lock()
+			heads = append(heads, r.URL.Path)
+			mu.Unlock()
+			if strings.Contains(r.URL.Path, "beta") {
+				http.NotFound(w, r)
+			}
+		case strings.HasPrefix(r.URL.Path, "/index/"):
+			fmt.Fprintf(w, `<a data-manual="%s/docs/alpha-guide.pdf">x</a><a data-manual="%s/docs/alpha-guide.pdf">y</a>`, "http://oem.test", "http://oem.test")
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "catalog.json")
+	catalog := fmt.Sprintf(`{"sources":[{
+		"name": "oem", "make": "OEM", "site": "oem.test", "manual_type": "service", "verify": "head",
+		"url_templates": ["%[1]s/{make}/{year}/{model}.pdf"],
+		"models": [{"slug": "alpha"}, {"slug": "beta"}, {"slug": "gamma-ev", "name": "Gamma EV", "from": 2024}],
+		"index": {"url": "%[1]s/index/{year}", "pattern": "data-manual=\"([^\"]+)\""}
+	}]}`, srv.URL)
+	if err := os.WriteFile(path, []byte(catalog), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadCatalog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewTemplateSource(c.Sources[0], testFetcher())
+	if s.Name() != "oem" {
+		t.Errorf("expected name oem, got %q", s.Name())
+	}
+
+	entries, err := s.Discover(context.Background(), []string{"oem"}, []int{2023, 2024})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// gamma-ev is only checked for 2024; beta fails verification.
+	mu.Lock()
+	defer mu.Unlock()
+	if len(heads) != 5 {
+		t.Errorf("expected 5 HEAD checks, got %v", heads)
+	}
+	got := make(map[string]bool)
+	for _, e := range entries {
+		got[fmt.Sprintf("%d %s %s", e.Year, e.Model, e.ManualType)] = true
+		if e.Make != "OEM" || e.SourceSite != "oem.test" {
+			t.Errorf("unexpected entry %+v", e)
+		}
+	}
+	for _, want := range []string{"2023 Alpha service", "2024 Alpha service", "2024 Gamma EV service"} {
+		if !got[want] {
+			t.Errorf("missing entry %q in %v", want, got)
+		}
+	}
+	if got["2023 Beta service"] || got["2023 Gamma EV service"] {
+		t.Errorf("unexpected entries %v", got)
+	}
+	// The index link is listed for both years but kept once.
+	if len(entries) != 4 {
+		t.Errorf("expected 4 entries, got %d", len(entries))
+	}
+}
@@ -1,9 +1,12 @@
 package manuals
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
@@ -13,7 +16,9 @@ import (
 	"time"
 
 	"github.com/WessleyAI/wessley-mvp/engine/graph"
+	"github.com/WessleyAI/wessley-mvp/engine/manual"
 	"github.com/WessleyAI/wessley-mvp/engine/scraper"
+	"github.com/WessleyAI/wessley-mvp/pkg/blob"
 )
 
 // Crawler orchestrates discovery and downloading of vehicle manual PDFs.
@@ -21,7 +26,6 @@ type Crawler struct {
 	sources []ManualSource
 	graph   *graph.GraphStore
 	cfg     CrawlerConfig
-	client  *http.Client
 }
 
 // CrawlerConfig controls crawler behavior.
@@ -34,22 +38,28 @@ type CrawlerConfig struct {
 	Concurrency  int
 	Makes        []string
 	YearRange    [2]int
+	Blobs        blob.BlobStore // where downloaded PDFs are kept; default OutputDir/blobs
+	Retention    blob.Retention // applied to Blobs after each download run
+	Fetcher      *Fetcher       // shared with the sources; default a new one with UserAgent
 }
 
 // NewCrawler creates a new Crawler with the given sources and config.
 func NewCrawler(g *graph.GraphStore, cfg CrawlerConfig, sources ...ManualSource) *Crawler {
+	if cfg.Blobs == nil {
+		s, err := blob.NewLocalStore(filepath.Join(cfg.OutputDir, "blobs"))
+		if err != nil {
+			log.Printf("manuals: blob store: %v (keeping PDFs in %s)", err, cfg.OutputDir)
+		} else {
+			cfg.Blobs = s
+		}
+	}
+	if cfg.Fetcher == nil {
+		cfg.Fetcher = NewFetcher(&http.Client{Timeout: 60 * time.Second}, FetcherOpts{UserAgent: cfg.UserAgent})
+	}
 	return &Crawler{
 		sources: sources,
 		graph:   g,
 		cfg:     cfg,
-		client: &http.Client{
-			Timeout: 60 * time.Second,
-			Transport: &http.Transport{
-				MaxIdleConns:       cfg.Concurrency,
-				IdleConnTimeout:    90 * time.Second,
-				DisableCompression: false,
-			},
-		},
 	}
 }
 
@@ -115,13 +125,14 @@ func (c *Crawler) Download(ctx context.Context, limit int) (int, error) {
 		return 0, fmt.Errorf("get pending downloads: %w", err)
 	}
 
-	dl := NewDownloader(c.client, c.cfg.OutputDir, c.cfg.MaxFileSize, c.cfg.UserAgent)
+	dl := NewDownloader(c.cfg.Fetcher, c.cfg.OutputDir, c.cfg.MaxFileSize)
 
 	var (
 		downloaded int
 		mu         sync.Mutex
 		sem        = make(chan struct{}, c.cfg.Concurrency)
 		wg         sync.WaitGroup
+		byHash     = map[string]graph.ManualEntry{} // this batch's downloads, by content
 	)
 
 	for _, entry := range pending {
@@ -141,7 +152,17 @@ func (c *Crawler) Download(ctx context.Context, limit int) (int, error) {
 				return
 			}
 
-			localPath, err := dl.Download(ctx, e)
+			res, err := dl.Download(ctx, e)
+			if err == nil && res.Path == "" && c.cfg.Blobs != nil {
+				// The server confirmed the stored blob; make sure it is still there.
+				if _, serr := c.cfg.Blobs.Stat(ctx, res.SHA256); errors.Is(serr, blob.ErrNotFound) {
+					e.Blob, e.ETag, e.LastModified = "", "", ""
+					res, err = dl.Download(ctx, e)
+				}
+			}
+			if err == nil && res.Path != "" {
+				err = c.storeBlob(ctx, &e, res.Path)
+			}
 			if err != nil {
 				log.Printf("manuals: download %s failed: %v", e.URL, err)
 				_ = c.graph.UpdateManualStatus(ctx, e.ID, "failed", err.Error())
@@ -149,9 +170,29 @@ func (c *Crawler) Download(ctx context.Context, limit int) (int, error) {
 			}
 
 			now := time.Now()
-			e.LocalPath = localPath
+			e.FileSize = res.Size
+			e.SHA256 = res.SHA256
+			e.ETag, e.LastModified = res.ETag, res.LastModified
 			e.DownloadedAt = &now
 			e.Status = "downloaded"
+
+			mu.Lock()
+			orig, seen := byHash[e.SHA256]
+			if !seen {
+				byHash[e.SHA256] = e
+			}
+			mu.Unlock()
+			if !seen {
+				if o, err := c.graph.FindManualByHash(ctx, e.SHA256, e.ID); err != nil {
+					log.Printf("manuals: hash lookup error: %v", err)
+				} else if o != nil {
+					orig, seen = *o, true
+				}
+			}
+			if seen {
+				markDuplicate(&e, orig)
+			}
+
 			if err := c.graph.SaveManualEntry(ctx, e); err != nil {
 				log.Printf("manuals: save downloaded entry error: %v", err)
 				return
@@ -167,9 +208,74 @@ func (c *Crawler) Download(ctx context.Context, limit int) (int, error) {
 	}
 
 	wg.Wait()
+
+	if removed, err := c.PruneBlobs(ctx); err != nil {
+		log.Printf("manuals: prune blobs: %v", err)
+	} else if removed > 0 {
+		log.Printf("manuals: pruned %d blobs", removed)
+	}
 	return downloaded, nil
 }
 
+// storeBlob moves the downloaded file at path into the blob store and
+// points e at it. Without a store the file stays at e.LocalPath.
+func (c *Crawler) storeBlob(ctx context.Context, e *graph.ManualEntry, path string) error {
+	if c.cfg.Blobs == nil {
+		e.LocalPath = path
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	info, err := c.cfg.Blobs.Put(ctx, f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("store pdf: %w", err)
+	}
+	if err := os.Remove(path); err != nil {
+		log.Printf("manuals: remove %s: %v", path, err)
+	}
+	e.Blob, e.LocalPath = info.SHA256, ""
+	return nil
+}
+
+// PruneBlobs applies the configured retention to the blob store. Blobs of
+// manuals awaiting ingestion are kept; entries whose blob was removed are
+// served from their origin URL again.
+func (c *Crawler) PruneBlobs(ctx context.Context) (int, error) {
+	r := c.cfg.Retention
+	if c.cfg.Blobs == nil || (r.MaxAge <= 0 && r.MaxBytes <= 0) {
+		return 0, nil
+	}
+	pending, err := c.graph.PendingManualBlobs(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("pending blobs: %w", err)
+	}
+	pinned := make(map[string]bool, len(pending))
+	for _, sum := range pending {
+		pinned[sum] = true
+	}
+	removed, err := blob.Prune(ctx, c.cfg.Blobs, r, func(sum string) bool { return pinned[sum] })
+	for _, b := range removed {
+		if cerr := c.graph.ClearManualBlob(ctx, b.SHA256); cerr != nil {
+			log.Printf("manuals: clear blob %s: %v", b.SHA256, cerr)
+		}
+	}
+	return len(removed), err
+}
+
+// markDuplicate records that e holds the same PDF as orig, e.g. one
+// mirrored on several sites. Both share one blob; e is not ingested.
+func markDuplicate(e *graph.ManualEntry, orig graph.ManualEntry) {
+	if orig.Blob != "" {
+		e.Blob = orig.Blob
+	}
+	e.DuplicateOf = orig.ID
+	e.Status = "duplicate"
+	log.Printf("manuals: %s duplicates %s (sha256 %s)", e.URL, orig.URL, e.SHA256)
+}
+
 // Ingest processes downloaded PDFs into JSON files for the ingest pipeline.
 func (c *Crawler) Ingest(ctx context.Context, outputDir string, limit int) (int, error) {
 	if limit <= 0 {
@@ -192,99 +298,246 @@ func (c *Crawler) Ingest(ctx context.Context, outputDir string, limit int) (int,
 		default:
 		}
 
-		if entry.LocalPath == "" {
-			_ = c.graph.UpdateManualStatus(ctx, entry.ID, "failed", "no local path")
-			continue
-		}
-
-		content, err := ExtractTextFromPDF(entry.LocalPath)
+		sections, err := c.ingestEntry(ctx, &entry, outputDir)
 		if err != nil {
-			log.Printf("manuals: extract text from %s: %v", entry.LocalPath, err)
+			log.Printf("manuals: ingest %s: %v", entry.URL, err)
 			_ = c.graph.UpdateManualStatus(ctx, entry.ID, "failed", err.Error())
 			continue
 		}
-		if strings.TrimSpace(content) == "" {
-			_ = c.graph.UpdateManualStatus(ctx, entry.ID, "failed", "no text extracted")
-			continue
-		}
+		ingested++
+		log.Printf("manuals: ingested %s → %d sections", entry.URL, sections)
+	}
+	return ingested, nil
+}
 
-		sections := ParseSections(content)
+// Reparse re-runs extraction on up to limit manuals kept in the blob store,
+// e.g. after the parsers improved. Sections, specs, fuses and wiring are
+// written again; the JSON files for the ingest pipeline are replaced.
+func (c *Crawler) Reparse(ctx context.Context, outputDir string, limit int) (int, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	stored, err := c.graph.GetStoredManuals(ctx, limit)
+	if err != nil {
+		return 0, fmt.Errorf("get stored manuals: %w", err)
+	}
+	if outputDir == "" {
+		outputDir = c.cfg.OutputDir
+	}
 
-		// Write JSON files for ingest pipeline
-		baseName := strings.TrimSuffix(filepath.Base(entry.LocalPath), ".pdf")
-		jsonDir := filepath.Join(outputDir, "json")
-		if err := os.MkdirAll(jsonDir, 0o755); err != nil {
-			log.Printf("manuals: mkdir %s: %v", jsonDir, err)
+	var reparsed int
+	for _, entry := range stored {
+		if err := ctx.Err(); err != nil {
+			return reparsed, err
+		}
+		sections, err := c.ingestEntry(ctx, &entry, outputDir)
+		if err != nil {
+			log.Printf("manuals: reparse %s: %v", entry.URL, err)
 			continue
 		}
+		reparsed++
+		log.Printf("manuals: reparsed %s → %d sections", entry.URL, sections)
+	}
+	return reparsed, nil
+}
 
-		vehicle := ""
-		if entry.Make != "" && entry.Year > 0 {
-			vehicle = fmt.Sprintf("%d-%s-%s", entry.Year, entry.Make, entry.Model)
+// readManual returns the PDF of entry from the blob store, or from
+// entry.LocalPath for manuals downloaded before the store existed; those
+// are moved into the store.
+func (c *Crawler) readManual(ctx context.Context, entry *graph.ManualEntry) ([]byte, error) {
+	if entry.Blob != "" && c.cfg.Blobs != nil {
+		rc, err := c.cfg.Blobs.Get(ctx, entry.Blob)
+		if err != nil {
+			return nil, fmt.Errorf("get blob %s: %w", entry.Blob, err)
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+	if entry.LocalPath == "" {
+		return nil, errors.New("no stored pdf")
+	}
+	data, err := os.ReadFile(entry.LocalPath)
+	if err != nil {
+		return nil, err
+	}
+	if c.cfg.Blobs != nil {
+		if info, err := c.cfg.Blobs.Put(ctx, bytes.NewReader(data)); err != nil {
+			log.Printf("manuals: store %s: %v", entry.LocalPath, err)
+		} else {
+			os.Remove(entry.LocalPath)
+			entry.Blob, entry.LocalPath = info.SHA256, ""
 		}
+	}
+	return data, nil
+}
 
-		for i, sec := range sections {
-			post := scraper.ScrapedPost{
-				Source:    "manual",
-				SourceID:  fmt.Sprintf("%s-sec-%d", baseName, i),
-				Title:     fmt.Sprintf("%s - %s", baseName, sec.Title),
-				Content:   sec.Content,
-				URL:       entry.URL,
-				ScrapedAt: time.Now(),
-				Metadata: scraper.Metadata{
-					Vehicle: vehicle,
-					Keywords: []string{"manual", "owner's manual"},
-				},
-			}
-			if entry.Make != "" {
-				post.Metadata.VehicleInfo = &scraper.VehicleInfo{
-					Make:  entry.Make,
-					Model: entry.Model,
-					Year:  entry.Year,
-				}
-			}
-			if sec.System != "" {
-				post.Metadata.Section = sec.System
-				if sec.Subsystem != "" {
-					post.Metadata.Section = sec.System + "/" + sec.Subsystem
-				}
-				post.Metadata.Keywords = append(post.Metadata.Keywords, strings.ToLower(sec.System))
-			}
+// ingestEntry extracts the manual's text into the graph and into JSON files
+// under outputDir/json, marks it ingested and returns its section count.
+func (c *Crawler) ingestEntry(ctx context.Context, entry *graph.ManualEntry, outputDir string) (int, error) {
+	data, err := c.readManual(ctx, entry)
+	if err != nil {
+		return 0, err
+	}
+	pc, err := manual.ParsePDF(data)
+	if err != nil {
+		return 0, fmt.Errorf("extract text: %w", err)
+	}
+	if strings.TrimSpace(pc.Text) == "" {
+		return 0, errors.New("no text extracted")
+	}
 
-			jsonPath := filepath.Join(jsonDir, fmt.Sprintf("%s-sec-%d.json", baseName, i))
-			data, err := json.Marshal(post)
-			if err != nil {
-				continue
+	sections := manual.ParseSections(pc.Text)
+	specs := MapTables(pc.Tables)
+	attachSpecs(sections, specs)
+	fuses := ExtractFuseChart(pc.Text, pc.Tables)
+	wires := ExtractWiring(pc.Text, pc.Tables)
+	maint := ExtractMaintenance(pc.Text, pc.Tables)
+	if entry.Make != "" && entry.Year > 0 {
+		c.enrichManual(ctx, *entry, sections, specs, fuses, wires, maint)
+	}
+
+	// Write JSON files for ingest pipeline
+	baseName := strings.TrimSuffix(manualFileName(*entry), ".pdf")
+	jsonDir := filepath.Join(outputDir, "json")
+	if err := os.MkdirAll(jsonDir, 0o755); err != nil {
+		return 0, fmt.Errorf("mkdir %s: %w", jsonDir, err)
+	}
+
+	vehicle := ""
+	if entry.Make != "" && entry.Year > 0 {
+		vehicle = fmt.Sprintf("%d-%s-%s", entry.Year, entry.Make, entry.Model)
+	}
+
+	for i, sec := range sections {
+		post := scraper.ScrapedPost{
+			Source:    "manual",
+			SourceID:  sectionSourceID(baseName, i),
+			Title:     fmt.Sprintf("%s - %s", baseName, sec.Title),
+			Content:   sec.Content,
+			URL:       entry.URL,
+			ScrapedAt: time.Now(),
+			Metadata: scraper.Metadata{
+				Vehicle:      vehicle,
+				Keywords:     []string{"manual", "owner's manual"},
+				ManualID:     entry.ID,
+				SectionTitle: sec.Title,
+				PageRange:    sec.PageRange,
+			},
+		}
+		if entry.Make != "" {
+			post.Metadata.VehicleInfo = &scraper.VehicleInfo{
+				Make:  entry.Make,
+				Model: entry.Model,
+				Year:  entry.Year,
 			}
-			if err := os.WriteFile(jsonPath, data, 0o644); err != nil {
-				log.Printf("manuals: write json %s: %v", jsonPath, err)
-				continue
+		}
+		if sec.System != "" {
+			post.Metadata.Section = sec.System
+			if sec.Subsystem != "" {
+				post.Metadata.Section = sec.System + "/" + sec.Subsystem
 			}
+			post.Metadata.Keywords = append(post.Metadata.Keywords, strings.ToLower(sec.System))
 		}
 
-		// Clean up PDF to save disk — we keep the Archive.org URL in Neo4j
-		pdfPath := entry.LocalPath
-		now := time.Now()
-		entry.IngestedAt = &now
-		entry.Status = "ingested"
-		entry.PageCount = len(sections)
-		entry.LocalPath = "" // clear local path — PDF will be deleted
-		if err := c.graph.SaveManualEntry(ctx, entry); err != nil {
-			log.Printf("manuals: save ingested entry error: %v", err)
+		jsonPath := filepath.Join(jsonDir, sectionSourceID(baseName, i)+".json")
+		data, err := json.Marshal(post)
+		if err != nil {
+			continue
+		}
+		if err := os.WriteFile(jsonPath, data, 0o644); err != nil {
+			log.Printf("manuals: write json %s: %v", jsonPath, err)
 			continue
 		}
-		// Delete PDF after successful ingestion + graph save
-		if pdfPath != "" {
-			if err := os.Remove(pdfPath); err != nil && !os.IsNotExist(err) {
-				log.Printf("manuals: cleanup pdf %s: %v", pdfPath, err)
-			} else {
-				log.Printf("manuals: deleted pdf %s (kept URL: %s)", pdfPath, entry.URL)
+	}
+
+	// The PDF stays in the blob store for later reparses until retention
+	// evicts it.
+	now := time.Now()
+	entry.IngestedAt = &now
+	entry.Status = "ingested"
+	entry.PageCount = len(sections)
+	if err := c.graph.SaveManualEntry(ctx, *entry); err != nil {
+		return 0, fmt.Errorf("save ingested entry: %w", err)
+	}
+	return len(sections), nil
+}
+
+// enrichManual writes the manual's sections, spec tables, fuse charts,
+// wiring and maintenance schedule to the vehicle's graph. Each spec, fuse,
+// wire and maintenance item is recorded against the section document whose
+// pages it was read from, so retracting the section removes it. Failures
+// are logged; the text is still ingested.
+func (c *Crawler) enrichManual(ctx context.Context, entry graph.ManualEntry, sections []graph.ManualSection, specs []graph.ManualSpec,
+	fuses []graph.FuseEntry, wires []graph.WireRun, maint []graph.MaintenanceItem) {
+	vi := graph.VehicleInfo{Make: entry.Make, Model: entry.Model, Year: entry.Year}
+	enricher := graph.NewEnricher(c.graph)
+	if err := enricher.EnrichFromManual(ctx, vi, sections); err != nil {
+		log.Printf("manuals: enrich sections of %s: %v", entry.URL, err)
+	}
+
+	baseName := strings.TrimSuffix(manualFileName(entry), ".pdf")
+	for _, g := range bySection(sections, baseName, specs, func(s graph.ManualSpec) int { return s.Page }) {
+		if err := enricher.EnrichFromSpecs(ctx, vi, g.items, g.docID); err != nil {
+			log.Printf("manuals: enrich specs of %s: %v", entry.URL, err)
+		}
+	}
+	for _, g := range bySection(sections, baseName, fuses, func(f graph.FuseEntry) int { return f.Page }) {
+		if err := enricher.EnrichFromFuseChart(ctx, vi, g.items, g.docID); err != nil {
+			log.Printf("manuals: enrich fuse chart of %s: %v", entry.URL, err)
+		}
+	}
+	for _, g := range bySection(sections, baseName, wires, func(w graph.WireRun) int { return w.Page }) {
+		if err := enricher.EnrichFromWiring(ctx, vi, g.items, g.docID); err != nil {
+			log.Printf("manuals: enrich wiring of %s: %v", entry.URL, err)
+		}
+	}
+	for _, g := range bySection(sections, baseName, maint, func(m graph.MaintenanceItem) int { return m.Page }) {
+		if err := enricher.EnrichFromMaintenance(ctx, vi, g.items, g.docID); err != nil {
+			log.Printf("manuals: enrich maintenance schedule of %s: %v", entry.URL, err)
+		}
+	}
+}
+
+// sectionSourceID returns the SourceID of the post for section i of a
+// manual; the section's document ID is "manual:" followed by it.
+func sectionSourceID(baseName string, i int) string {
+	return fmt.Sprintf("%s-sec-%d", baseName, i)
+}
+
+// sectionGroup is the items read from one section document.
+type sectionGroup[T any] struct {
+	docID string
+	items []T
+}
+
+// bySection groups items by the document of the section whose page range
+// covers their page, in order of first appearance. Items with no page, or
+// a page no section covers, go to the first section. With no sections the
+// items form one group without provenance.
+func bySection[T any](sections []graph.ManualSection, baseName string, items []T, page func(T) int) []sectionGroup[T] {
+	var groups []sectionGroup[T]
+	at := map[string]int{}
+	for _, it := range items {
+		docID := ""
+		if len(sections) > 0 {
+			sec := 0
+			for i := range sections {
+				if pageInRange(sections[i].PageRange, page(it)) {
+					sec = i
+					break
+				}
 			}
+			docID = "manual:" + sectionSourceID(baseName, sec)
 		}
-		ingested++
-		log.Printf("manuals: ingested %s → %d sections", entry.URL, len(sections))
+		i, ok := at[docID]
+		if !ok {
+			i = len(groups)
+			at[docID] = i
+			groups = append(groups, sectionGroup[T]{docID: docID})
+		}
+		groups[i].items = append(groups[i].items, it)
 	}
-	return ingested, nil
+	return groups
 }
 
 // Process runs the full pipeline: discover → download → ingest.
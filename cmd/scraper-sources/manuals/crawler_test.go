@@ -25,49 +25,49 @@ func (m *mockSource) Discover(_ context.Context, _ []string, _ []int) ([]graph.M
 }
 
 func TestToyotaSourceName(t *testing.T) {
-	s := NewToyotaSource()
+	s := NewToyotaSource(testFetcher())
 	if s.Name() != "toyota" {
 		t.Errorf("expected 'toyota', got %q", s.Name())
 	}
 }
 
 func TestHondaSourceName(t *testing.T) {
-	s := NewHondaSource()
+	s := NewHondaSource(testFetcher())
 	if s.Name() != "honda" {
 		t.Errorf("expected 'honda', got %q", s.Name())
 	}
 }
 
 func TestFordSourceName(t *testing.T) {
-	s := NewFordSource()
+	s := NewFordSource(testFetcher())
 	if s.Name() != "ford" {
 		t.Errorf("expected 'ford', got %q", s.Name())
 	}
 }
 
 func TestArchiveSourceName(t *testing.T) {
-	s := NewArchiveSource()
+	s := NewArchiveSource(testFetcher())
 	if s.Name() != "archive" {
 		t.Errorf("expected 'archive', got %q", s.Name())
 	}
 }
 
 func TestNHTSASourceName(t *testing.T) {
-	s := NewNHTSASource()
+	s := NewNHTSASource(testFetcher())
 	if s.Name() != "nhtsa" {
 		t.Errorf("expected 'nhtsa', got %q", s.Name())
 	}
 }
 
 func TestGenericSearchSourceName(t *testing.T) {
-	s := NewGenericSearchSource()
+	s := NewGenericSearchSource(testFetcher())
 	if s.Name() != "search" {
 		t.Errorf("expected 'search', got %q", s.Name())
 	}
 }
 
 func TestToyotaSourceSkipsNonToyota(t *testing.T) {
-	s := NewToyotaSource()
+	s := NewToyotaSource(testFetcher())
 	entries, err := s.Discover(context.Background(), []string{"Honda"}, []int{2024})
 	if err != nil {
 		t.Fatal(err)
@@ -78,7 +78,7 @@ func TestToyotaSourceSkipsNonToyota(t *testing.T) {
 }
 
 func TestHondaSourceSkipsNonHonda(t *testing.T) {
-	s := NewHondaSource()
+	s := NewHondaSource(testFetcher())
 	entries, err := s.Discover(context.Background(), []string{"Toyota"}, []int{2024})
 	if err != nil {
 		t.Fatal(err)
@@ -89,7 +89,7 @@ func TestHondaSourceSkipsNonHonda(t *testing.T) {
 }
 
 func TestFordSourceSkipsNonFord(t *testing.T) {
-	s := NewFordSource()
+	s := NewFordSource(testFetcher())
 	entries, err := s.Discover(context.Background(), []string{"Toyota"}, []int{2024})
 	if err != nil {
 		t.Fatal(err)
@@ -222,7 +222,7 @@ func TestDownloaderWithMockServer(t *testing.T) {
 	defer srv.Close()
 
 	dir := t.TempDir()
-	dl := NewDownloader(srv.Client(), dir, 10*1024*1024, "test")
+	dl := NewDownloader(testFetcher(), dir, 10*1024*1024)
 
 	entry := graph.ManualEntry{
 		ID:         "test123456789",
@@ -233,13 +233,13 @@ func TestDownloaderWithMockServer(t *testing.T) {
 		ManualType: "owner",
 	}
 
-	path, err := dl.Download(context.Background(), entry)
+	res, err := dl.Download(context.Background(), entry)
 	if err != nil {
 		t.Fatalf("download failed: %v", err)
 	}
 
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		t.Errorf("file not created at %s", path)
+	if _, err := os.Stat(res.Path); os.IsNotExist(err) {
+		t.Errorf("file not created at %s", res.Path)
 	}
 }
 
@@ -321,3 +321,44 @@ func TestArchiveSourceWithMock(t *testing.T) {
 		t.Errorf("unexpected: make=%q year=%d", make_, year)
 	}
 }
+
+func TestCrawlerKeepsPDFsInBlobStore(t *testing.T) {
+	dir := t.TempDir()
+	c := NewCrawler(nil, CrawlerConfig{OutputDir: dir})
+	if c.cfg.Blobs == nil {
+		t.Fatal("expected a default blob store")
+	}
+	ctx := context.Background()
+
+	// A fresh download moves into the store.
+	path := filepath.Join(dir, "owner_abcdef01.pdf")
+	os.WriteFile(path, []byte("%PDF-1.7 new"), 0o644)
+	var e graph.ManualEntry
+	if err := c.storeBlob(ctx, &e, path); err != nil {
+		t.Fatal(err)
+	}
+	if e.Blob == "" || e.LocalPath != "" {
+		t.Errorf("expected a blob reference, got %+v", e)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("downloaded file kept outside the store")
+	}
+	data, err := c.readManual(ctx, &e)
+	if err != nil || string(data) != "%PDF-1.7 new" {
+		t.Errorf("read from store: %q, %v", data, err)
+	}
+
+	// A manual downloaded before the store existed is moved on first read.
+	legacy := filepath.Join(dir, "owner_01234567.pdf")
+	os.WriteFile(legacy, []byte("%PDF-1.7 old"), 0o644)
+	old := graph.ManualEntry{LocalPath: legacy}
+	if data, err := c.readManual(ctx, &old); err != nil || string(data) != "%PDF-1.7 old" {
+		t.Fatalf("read legacy file: %q, %v", data, err)
+	}
+	if old.Blob == "" || old.LocalPath != "" {
+		t.Errorf("expected the legacy file in the store, got %+v", old)
+	}
+	if _, err := c.cfg.Blobs.Stat(ctx, old.Blob); err != nil {
+		t.Errorf("legacy blob: %v", err)
+	}
+}
@@ -2,11 +2,16 @@ package manuals
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/WessleyAI/wessley-mvp/engine/graph"
@@ -14,114 +19,307 @@ import (
 
 // Downloader handles downloading and saving manual PDFs.
 type Downloader struct {
-	client      *http.Client
+	fetcher     *Fetcher
 	outputDir   string
 	maxFileSize int64
-	userAgent   string
 }
 
-// NewDownloader creates a new Downloader.
-func NewDownloader(client *http.Client, outputDir string, maxFileSize int64, userAgent string) *Downloader {
+// NewDownloader creates a new Downloader. Downloads go through f, so they
+// are paced with the sources' page fetches of the same host.
+func NewDownloader(f *Fetcher, outputDir string, maxFileSize int64) *Downloader {
 	return &Downloader{
-		client:      client,
+		fetcher:     f,
 		outputDir:   outputDir,
 		maxFileSize: maxFileSize,
-		userAgent:   userAgent,
 	}
 }
 
-// Download fetches a manual PDF and saves it to the organized directory structure.
-// Returns the local file path.
-func (d *Downloader) Download(ctx context.Context, entry graph.ManualEntry) (string, error) {
+// DownloadResult describes a manual file on disk after Download. Path is
+// empty when the server confirmed the entry's stored blob instead.
+type DownloadResult struct {
+	Path         string
+	Size         int64
+	SHA256       string // hex digest of the file
+	ETag         string // validators to send on the next download
+	LastModified string
+	NotModified  bool // the server confirmed the local copy is current
+}
+
+// ErrTooLarge is returned for files over the downloader's max file size.
+var ErrTooLarge = errors.New("manuals: file too large")
+
+// errRestart asks Download to start over after the partial file was dropped.
+var errRestart = errors.New("manuals: restart download")
+
+// Download fetches a manual PDF into the organized directory structure.
+//
+// An interrupted download is kept in a ".part" file and resumed with a Range
+// request, guarded by If-Range so a file that changed on the server starts
+// over. A file already on disk, or the entry's blob in the store, is
+// revalidated with the entry's ETag and Last-Modified, or kept as is when
+// the entry has neither. Files whose
+// Content-Length exceeds the max file size are rejected before any body
+// is read.
+func (d *Downloader) Download(ctx context.Context, entry graph.ManualEntry) (DownloadResult, error) {
 	// Build target path: {outputDir}/{make}/{model}/{year}/{manual_type}.pdf
 	make_ := sanitizePath(entry.Make)
 	model := sanitizePath(entry.Model)
 	year := fmt.Sprintf("%d", entry.Year)
+
+	dir := filepath.Join(d.outputDir, make_, model, year)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return DownloadResult{}, fmt.Errorf("mkdir: %w", err)
+	}
+	localPath := filepath.Join(dir, manualFileName(entry))
+
+	// Check if already downloaded
+	var current validators
+	_, err := os.Stat(localPath)
+	onDisk := err == nil
+	if onDisk || entry.Blob != "" {
+		current = validators{ETag: entry.ETag, LastModified: entry.LastModified}
+		if current == (validators{}) {
+			if onDisk {
+				return localResult(localPath, current, false)
+			}
+			return storedResult(entry, current), nil
+		}
+	}
+
+	res, err := d.fetch(ctx, entry.URL, localPath, current)
+	if errors.Is(err, errRestart) {
+		res, err = d.fetch(ctx, entry.URL, localPath, current)
+	}
+	if err == nil && res.NotModified && res.Path == "" {
+		res = storedResult(entry, current)
+	}
+	return res, err
+}
+
+// manualFileName names an entry's PDF by manual type plus an ID prefix,
+// to avoid collisions between manuals of one vehicle.
+func manualFileName(entry graph.ManualEntry) string {
 	manualType := entry.ManualType
 	if manualType == "" {
 		manualType = "owner"
 	}
+	id := entry.ID
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	return fmt.Sprintf("%s_%s.pdf", manualType, id)
+}
 
-	dir := filepath.Join(d.outputDir, make_, model, year)
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return "", fmt.Errorf("mkdir: %w", err)
+// storedResult describes the entry's blob, confirmed current.
+func storedResult(entry graph.ManualEntry, v validators) DownloadResult {
+	return DownloadResult{
+		Size:         entry.FileSize,
+		SHA256:       entry.Blob,
+		ETag:         v.ETag,
+		LastModified: v.LastModified,
+		NotModified:  true,
 	}
+}
 
-	// Use manual type + hash suffix to avoid collisions
-	filename := fmt.Sprintf("%s_%s.pdf", manualType, entry.ID[:8])
-	localPath := filepath.Join(dir, filename)
+// validators are the HTTP cache validators of a download.
+type validators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
 
-	// Check if already downloaded
-	if _, err := os.Stat(localPath); err == nil {
-		return localPath, nil
+// fetch downloads url to localPath through localPath + ".part". The
+// validators of the partial file are kept next to it in ".part.json".
+// current, when set, makes the request conditional on the local copy
+// having changed.
+func (d *Downloader) fetch(ctx context.Context, url, localPath string, current validators) (DownloadResult, error) {
+	partPath := localPath + ".part"
+	metaPath := partPath + ".json"
+	dropPart := func() {
+		os.Remove(partPath)
+		os.Remove(metaPath)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, entry.URL, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return "", fmt.Errorf("create request: %w", err)
+		return DownloadResult{}, fmt.Errorf("create request: %w", err)
+	}
+	if current.ETag != "" {
+		req.Header.Set("If-None-Match", current.ETag)
+	}
+	if current.LastModified != "" {
+		req.Header.Set("If-Modified-Since", current.LastModified)
 	}
-	req.Header.Set("User-Agent", d.userAgent)
 
-	// Support resume via Range header
-	var existingSize int64
-	tmpPath := localPath + ".tmp"
-	if info, err := os.Stat(tmpPath); err == nil {
-		existingSize = info.Size()
-		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", existingSize))
+	// Resume a partial download only when it can be tied to the server's
+	// version of the file.
+	var offset int64
+	var part validators
+	if info, err := os.Stat(partPath); err == nil && info.Size() > 0 {
+		part = readValidators(metaPath)
+		ifRange := part.ETag
+		if ifRange == "" || strings.HasPrefix(ifRange, "W/") {
+			ifRange = part.LastModified
+		}
+		if ifRange != "" {
+			offset = info.Size()
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+			req.Header.Set("If-Range", ifRange)
+		} else {
+			dropPart()
+		}
 	}
 
-	resp, err := d.client.Do(req)
+	resp, err := d.fetcher.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("http get: %w", err)
+		return DownloadResult{}, fmt.Errorf("http get: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
-		return "", fmt.Errorf("http status %d", resp.StatusCode)
+	total := resp.ContentLength
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		if _, err := os.Stat(localPath); err != nil {
+			return DownloadResult{NotModified: true}, nil // the caller holds the copy
+		}
+		return localResult(localPath, current, true)
+	case http.StatusOK:
+		offset = 0
+	case http.StatusPartialContent:
+		start, size, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			dropPart()
+			return DownloadResult{}, errRestart
+		}
+		total = size
+	case http.StatusRequestedRangeNotSatisfiable:
+		dropPart()
+		return DownloadResult{}, errRestart
+	default:
+		return DownloadResult{}, fmt.Errorf("http status %d", resp.StatusCode)
 	}
 
 	// Check content length
-	if d.maxFileSize > 0 && resp.ContentLength > d.maxFileSize {
-		return "", fmt.Errorf("file too large: %d bytes", resp.ContentLength)
+	if d.maxFileSize > 0 && total > d.maxFileSize {
+		dropPart()
+		return DownloadResult{}, fmt.Errorf("%w: %d bytes", ErrTooLarge, total)
+	}
+
+	got := validators{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}
+	if offset > 0 && got == (validators{}) {
+		got = part
+	}
+	if err := writeValidators(metaPath, got); err != nil {
+		return DownloadResult{}, err
 	}
 
 	flags := os.O_CREATE | os.O_WRONLY
-	if resp.StatusCode == http.StatusPartialContent {
+	if offset > 0 {
 		flags |= os.O_APPEND
 	} else {
 		flags |= os.O_TRUNC
 	}
-
-	f, err := os.OpenFile(tmpPath, flags, 0o644)
+	f, err := os.OpenFile(partPath, flags, 0o644)
 	if err != nil {
-		return "", fmt.Errorf("open file: %w", err)
+		return DownloadResult{}, fmt.Errorf("open file: %w", err)
 	}
 
+	// Read one byte past the limit so an oversized body without a
+	// Content-Length is caught rather than truncated.
 	reader := io.Reader(resp.Body)
 	if d.maxFileSize > 0 {
-		reader = io.LimitReader(resp.Body, d.maxFileSize-existingSize)
+		reader = io.LimitReader(resp.Body, d.maxFileSize-offset+1)
 	}
-
 	n, err := io.Copy(f, reader)
 	f.Close()
 	if err != nil {
-		return "", fmt.Errorf("write file: %w", err)
+		// Keep the partial file for the next attempt to resume.
+		return DownloadResult{}, fmt.Errorf("write file: %w", err)
+	}
+
+	size := offset + n
+	if d.maxFileSize > 0 && size > d.maxFileSize {
+		dropPart()
+		return DownloadResult{}, fmt.Errorf("%w: over %d bytes", ErrTooLarge, d.maxFileSize)
+	}
+	if total >= 0 && size != total {
+		return DownloadResult{}, fmt.Errorf("incomplete download: %d of %d bytes", size, total)
 	}
 
 	// Verify it's a PDF (check magic bytes)
-	if err := verifyPDF(tmpPath); err != nil {
-		os.Remove(tmpPath)
-		return "", err
+	if err := verifyPDF(partPath); err != nil {
+		dropPart()
+		return DownloadResult{}, err
+	}
+
+	// Move part to final
+	if err := os.Rename(partPath, localPath); err != nil {
+		return DownloadResult{}, fmt.Errorf("rename: %w", err)
 	}
+	os.Remove(metaPath)
+	return localResult(localPath, got, false)
+}
 
-	_ = n // total bytes written
+// localResult describes the finished file at path.
+func localResult(path string, v validators, notModified bool) (DownloadResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return DownloadResult{}, err
+	}
+	defer f.Close()
 
-	// Move tmp to final
-	if err := os.Rename(tmpPath, localPath); err != nil {
-		return "", fmt.Errorf("rename: %w", err)
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return DownloadResult{}, fmt.Errorf("hash file: %w", err)
 	}
+	return DownloadResult{
+		Path:         path,
+		Size:         n,
+		SHA256:       hex.EncodeToString(h.Sum(nil)),
+		ETag:         v.ETag,
+		LastModified: v.LastModified,
+		NotModified:  notModified,
+	}, nil
+}
 
-	return localPath, nil
+// parseContentRange reads "bytes 100-999/1000", returning a total of -1
+// when the server does not know it ("bytes 100-999/*").
+func parseContentRange(s string) (start, total int64, ok bool) {
+	rng, size, found := strings.Cut(strings.TrimPrefix(s, "bytes "), "/")
+	first, _, found2 := strings.Cut(rng, "-")
+	if !found || !found2 {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	if size == "*" {
+		return start, -1, true
+	}
+	if total, err = strconv.ParseInt(size, 10, 64); err != nil {
+		return 0, 0, false
+	}
+	return start, total, true
+}
+
+func readValidators(path string) validators {
+	var v validators
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &v)
+	}
+	return v
+}
+
+func writeValidators(path string, v validators) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write validators: %w", err)
+	}
+	return nil
 }
 
 // verifyPDF checks that the file starts with %PDF.
@@ -0,0 +1,233 @@
+package manuals
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/WessleyAI/wessley-mvp/engine/graph"
+)
+
+func pdfBytes(size int) []byte {
+	return append([]byte("%PDF-1.7\n"), bytes.Repeat([]byte("x"), size)...)
+}
+
+func testEntry(url string) graph.ManualEntry {
+	return graph.ManualEntry{ID: "abcdef0123456789", URL: url, Make: "Ford", Model: "F-150", Year: 2018}
+}
+
+// manualServer serves h to the Downloader, answering robots.txt itself so
+// that h sees only download requests.
+func manualServer(h http.HandlerFunc) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			fmt.Fprint(w, "User-agent: *\nDisallow: /private/\n")
+			return
+		}
+		h(w, r)
+	}))
+}
+
+func TestDownloadResumesAfterInterruption(t *testing.T) {
+	content := pdfBytes(4000)
+	var ranges []string
+	interrupted := false
+	srv := manualServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		if !interrupted {
+			// Promise the whole file, send half, then drop the connection.
+			interrupted = true
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			w.Write(content[:2000])
+			return
+		}
+		ranges = append(ranges, r.Header.Get("Range")+" "+r.Header.Get("If-Range"))
+		http.ServeContent(w, r, "manual.pdf", time.Time{}, bytes.NewReader(content))
+	})
+	defer srv.Close()
+
+	dl := NewDownloader(testFetcher(), t.TempDir(), 1<<20)
+	entry := testEntry(srv.URL + "/manual.pdf")
+	if _, err := dl.Download(context.Background(), entry); err == nil {
+		t.Fatal("expected the interrupted download to fail")
+	}
+
+	res, err := dl.Download(context.Background(), entry)
+	if err != nil {
+		t.Fatalf("resume failed: %v", err)
+	}
+	if len(ranges) != 1 || ranges[0] != `bytes=2000- "v1"` {
+		t.Errorf("expected a ranged If-Range request, got %q", ranges)
+	}
+	sum := sha256.Sum256(content)
+	if res.SHA256 != hex.EncodeToString(sum[:]) || res.Size != int64(len(content)) || res.ETag != `"v1"` {
+		t.Errorf("unexpected result %+v", res)
+	}
+	if _, err := os.Stat(res.Path + ".part"); !os.IsNotExist(err) {
+		t.Error("part file left behind")
+	}
+}
+
+func TestDownloadRestartsWhenFileChanged(t *testing.T) {
+	content := pdfBytes(3000)
+	srv := manualServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v2"`)
+		http.ServeContent(w, r, "manual.pdf", time.Time{}, bytes.NewReader(content))
+	})
+	defer srv.Close()
+
+	dir := t.TempDir()
+	dl := NewDownloader(testFetcher(), dir, 1<<20)
+	entry := testEntry(srv.URL + "/manual.pdf")
+
+	// A stale part from the previous version of the file.
+	target := dir + "/ford/f-150/2018/owner_abcdef01.pdf"
+	os.MkdirAll(dir+"/ford/f-150/2018", 0o755)
+	os.WriteFile(target+".part", []byte("%PDF-old old old"), 0o644)
+	os.WriteFile(target+".part.json", []byte(`{"etag":"\"v1\""}`), 0o644)
+
+	res, err := dl.Download(context.Background(), entry)
+	if err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	got, _ := os.ReadFile(res.Path)
+	if !bytes.Equal(got, content) || res.Path != target {
+		t.Errorf("stale part was resumed: %d bytes at %s", len(got), res.Path)
+	}
+}
+
+func TestDownloadConditional(t *testing.T) {
+	content := pdfBytes(100)
+	requests := 0
+	srv := manualServer(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(w, r, "manual.pdf", time.Time{}, bytes.NewReader(content))
+	})
+	defer srv.Close()
+
+	dl := NewDownloader(testFetcher(), t.TempDir(), 1<<20)
+	entry := testEntry(srv.URL + "/manual.pdf")
+	first, err := dl.Download(context.Background(), entry)
+	if err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+
+	entry.ETag = first.ETag
+	again, err := dl.Download(context.Background(), entry)
+	if err != nil {
+		t.Fatalf("revalidation failed: %v", err)
+	}
+	if !again.NotModified || again.SHA256 != first.SHA256 || requests != 2 {
+		t.Errorf("expected a 304 revalidation, got %+v after %d requests", again, requests)
+	}
+}
+
+func TestDownloadRevalidatesBlob(t *testing.T) {
+	requests := 0
+	srv := manualServer(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") != `"v1"` {
+			t.Errorf("expected a conditional request, got %v", r.Header)
+		}
+		w.WriteHeader(http.StatusNotModified)
+	})
+	defer srv.Close()
+
+	// The PDF was moved into the blob store, so nothing is on disk.
+	dl := NewDownloader(testFetcher(), t.TempDir(), 1<<20)
+	entry := testEntry(srv.URL + "/manual.pdf")
+	entry.Blob, entry.ETag, entry.FileSize = "abc", `"v1"`, 100
+	res, err := dl.Download(context.Background(), entry)
+	if err != nil {
+		t.Fatalf("revalidation failed: %v", err)
+	}
+	if !res.NotModified || res.Path != "" || res.SHA256 != "abc" || res.Size != 100 || requests != 1 {
+		t.Errorf("expected the blob confirmed, got %+v after %d requests", res, requests)
+	}
+}
+
+func TestDownloadTooLarge(t *testing.T) {
+	content := pdfBytes(5000)
+	srv := manualServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.Write(content)
+	})
+	defer srv.Close()
+
+	dir := t.TempDir()
+	dl := NewDownloader(testFetcher(), dir, 1000)
+	_, err := dl.Download(context.Background(), testEntry(srv.URL+"/big.pdf"))
+	if !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge, got %v", err)
+	}
+	if _, err := os.Stat(dir + "/ford/f-150/2018/owner_abcdef01.pdf.part"); !os.IsNotExist(err) {
+		t.Error("oversized download left a part file")
+	}
+}
+
+func TestDownloadThroughFetcher(t *testing.T) {
+	content := pdfBytes(100)
+	var calls atomic.Int32
+	srv := manualServer(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(content)
+	})
+	defer srv.Close()
+
+	dl := NewDownloader(testFetcher(), t.TempDir(), 1<<20)
+	if _, err := dl.Download(context.Background(), testEntry(srv.URL+"/private/manual.pdf")); !errors.Is(err, ErrDisallowed) {
+		t.Fatalf("expected ErrDisallowed, got %v", err)
+	}
+	if calls.Load() != 0 {
+		t.Fatalf("a disallowed download reached the server")
+	}
+	res, err := dl.Download(context.Background(), testEntry(srv.URL+"/manual.pdf"))
+	if err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	if res.Size != int64(len(content)) || calls.Load() != 2 {
+		t.Errorf("expected the 503 to be retried, got %+v after %d requests", res, calls.Load())
+	}
+}
+
+func TestParseContentRange(t *testing.T) {
+	tests := []struct {
+		in           string
+		start, total int64
+		ok           bool
+	}{
+		{"bytes 100-999/1000", 100, 1000, true},
+		{"bytes 0-99/*", 0, -1, true},
+		{"bytes */1000", 0, 0, false},
+		{"", 0, 0, false},
+	}
+	for _, tt := range tests {
+		start, total, ok := parseContentRange(tt.in)
+		if start != tt.start || total != tt.total || ok != tt.ok {
+			t.Errorf("parseContentRange(%q) = %d, %d, %v", tt.in, start, total, ok)
+		}
+	}
+}
+
+func TestMarkDuplicate(t *testing.T) {
+	e := graph.ManualEntry{ID: "mirror", URL: "https://mirror.example/m.pdf", SHA256: "abc", Blob: "abc", Status: "downloaded"}
+	orig := graph.ManualEntry{ID: "orig", URL: "https://oem.example/m.pdf", SHA256: "abc", Blob: "abc"}
+	markDuplicate(&e, orig)
+	if e.Status != "duplicate" || e.DuplicateOf != "orig" || e.Blob != "abc" {
+		t.Errorf("unexpected entry %+v", e)
+	}
+}
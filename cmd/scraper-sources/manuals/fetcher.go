@@ -0,0 +1,409 @@
+package manuals
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/WessleyAI/wessley-mvp/pkg/fn"
+	"github.com/WessleyAI/wessley-mvp/pkg/resilience"
+)
+
+var (
+	// ErrDisallowed is returned for URLs the host's robots.txt disallows.
+	ErrDisallowed = errors.New("manuals: disallowed by robots.txt")
+	// ErrNotFound is returned for 404 and 410 responses, which are not retried.
+	ErrNotFound = errors.New("manuals: not found")
+)
+
+// FetcherOpts configures a Fetcher. Zero fields take DefaultFetcherOpts.
+type FetcherOpts struct {
+	UserAgent   string
+	Rate        float64            // requests per second per host; a robots.txt Crawl-delay lowers it
+	HostRates   map[string]float64 // per-host overrides of Rate
+	Burst       int
+	MaxBodySize int64
+	MaxBackoff  time.Duration // cap on a Retry-After wait
+	RobotsTTL   time.Duration // how long a host's robots.txt is cached
+	Retry       fn.RetryOpts
+	Breaker     resilience.BreakerOpts
+}
+
+// DefaultFetcherOpts paces each host at one request per second.
+var DefaultFetcherOpts = FetcherOpts{
+	UserAgent: "WessleyBot/1.0",
+	Rate:      1,
+	HostRates: map[string]float64{
+		"html.duckduckgo.com": 1.0 / 3, // search engines throttle hardest
+		"archive.org":         0.5,
+	},
+	Burst:       1,
+	MaxBodySize: 5 * 1024 * 1024,
+	MaxBackoff:  5 * time.Minute,
+	RobotsTTL:   24 * time.Hour,
+	Retry:       fn.RetryOpts{MaxAttempts: 3, InitialWait: time.Second, MaxWait: 30 * time.Second, Jitter: true},
+	Breaker:     resilience.BreakerOpts{FailThreshold: 5, Timeout: time.Minute},
+}
+
+// Fetcher is the HTTP client every ManualSource fetches pages through, so
+// that all sources share one politeness schedule per host: robots.txt is
+// honored (including Crawl-delay), requests are paced by a per-host token
+// bucket, a 429 or 503 with Retry-After holds back the whole host, failing
+// hosts trip a per-host circuit breaker, and transient failures are retried.
+type Fetcher struct {
+	client *http.Client
+	opts   FetcherOpts
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+	now   func() time.Time // for testing
+}
+
+// hostState is the politeness state of one host.
+type hostState struct {
+	limiter *resilience.Limiter
+	breaker *resilience.Breaker
+
+	mu         sync.Mutex // guards the fields below and serializes robots fetches
+	robots     *robotsRules
+	robotsAt   time.Time
+	retryAfter time.Time // no requests before this
+}
+
+// NewFetcher creates a Fetcher. A nil client uses one with a 30s timeout.
+func NewFetcher(client *http.Client, opts FetcherOpts) *Fetcher {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	d := DefaultFetcherOpts
+	if opts.UserAgent == "" {
+		opts.UserAgent = d.UserAgent
+	}
+	if opts.Rate <= 0 {
+		opts.Rate = d.Rate
+	}
+	if opts.HostRates == nil {
+		opts.HostRates = d.HostRates
+	}
+	if opts.Burst <= 0 {
+		opts.Burst = d.Burst
+	}
+	if opts.MaxBodySize <= 0 {
+		opts.MaxBodySize = d.MaxBodySize
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = d.MaxBackoff
+	}
+	if opts.RobotsTTL <= 0 {
+		opts.RobotsTTL = d.RobotsTTL
+	}
+	if opts.Retry.MaxAttempts <= 0 {
+		opts.Retry = d.Retry
+	}
+	if opts.Breaker.FailThreshold <= 0 {
+		opts.Breaker = d.Breaker
+	}
+	return &Fetcher{client: client, opts: opts, hosts: make(map[string]*hostState), now: time.Now}
+}
+
+// host returns the state of host, creating it on first use.
+func (f *Fetcher) host(host string) *hostState {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	hs, ok := f.hosts[host]
+	if !ok {
+		hs = &hostState{
+			limiter: resilience.NewLimiter(resilience.LimiterOpts{Rate: f.rate(host), Burst: f.opts.Burst}),
+			breaker: resilience.NewBreaker(f.opts.Breaker),
+		}
+		f.hosts[host] = hs
+	}
+	return hs
+}
+
+// rate is the configured request rate for host.
+func (f *Fetcher) rate(host string) float64 {
+	if r, ok := f.opts.HostRates[host]; ok && r > 0 {
+		return r
+	}
+	return f.opts.Rate
+}
+
+// fetchError is a failed attempt; retry says whether another attempt may
+// succeed.
+type fetchError struct {
+	err   error
+	retry bool
+}
+
+func (e *fetchError) Error() string { return e.err.Error() }
+func (e *fetchError) Unwrap() error { return e.err }
+
+// Get fetches rawURL and returns its body. It fails with ErrDisallowed
+// without a request when robots.txt disallows the URL, with ErrNotFound
+// for 404/410, and with resilience.ErrCircuitOpen while the host is
+// failing.
+func (f *Fetcher) Get(ctx context.Context, rawURL string) ([]byte, error) {
+	r, err := f.fetch(ctx, http.MethodGet, rawURL)
+	return r.body, err
+}
+
+// Head checks that rawURL exists without downloading it and returns its
+// headers. It fails like Get.
+func (f *Fetcher) Head(ctx context.Context, rawURL string) (http.Header, error) {
+	r, err := f.fetch(ctx, http.MethodHead, rawURL)
+	return r.header, err
+}
+
+type fetchResult struct {
+	body   []byte
+	header http.Header
+	err    error
+}
+
+func (f *Fetcher) fetch(ctx context.Context, method, rawURL string) (fetchResult, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return fetchResult{}, fmt.Errorf("manuals: bad url %q", rawURL)
+	}
+	hs := f.host(u.Host)
+
+	rules, err := f.robots(ctx, hs, u)
+	if err != nil {
+		return fetchResult{}, fmt.Errorf("robots.txt %s: %w", u.Host, err)
+	}
+	if !rules.allowed(u.RequestURI()) {
+		return fetchResult{}, fmt.Errorf("%w: %s", ErrDisallowed, rawURL)
+	}
+
+	// A permanent failure ends the retries as an Ok carrying the error.
+	result := fn.Retry(ctx, f.opts.Retry, func(ctx context.Context) fn.Result[fetchResult] {
+		r, err := f.attempt(ctx, hs, method, rawURL)
+		var fe *fetchError
+		if errors.As(err, &fe) && fe.retry {
+			return fn.Err[fetchResult](err)
+		}
+		r.err = err
+		return fn.Ok(r)
+	})
+	r, err := result.Unwrap()
+	if err != nil {
+		return fetchResult{}, err
+	}
+	return r, r.err
+}
+
+// attempt makes one paced request for rawURL through the host's breaker.
+func (f *Fetcher) attempt(ctx context.Context, hs *hostState, method, rawURL string) (fetchResult, error) {
+	req, err := f.newRequest(ctx, method, rawURL)
+	if err != nil {
+		return fetchResult{}, err
+	}
+	resp, err := f.send(hs, req)
+	if err != nil {
+		return fetchResult{}, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		body, err := io.ReadAll(io.LimitReader(resp.Body, f.opts.MaxBodySize))
+		if err != nil {
+			return fetchResult{}, &fetchError{err: err, retry: true}
+		}
+		return fetchResult{body: body, header: resp.Header}, nil
+	case http.StatusNotFound, http.StatusGone:
+		return fetchResult{}, fmt.Errorf("%w: %s", ErrNotFound, rawURL)
+	default:
+		return fetchResult{}, fmt.Errorf("status %d", resp.StatusCode)
+	}
+}
+
+// send makes one paced request through the host's breaker and returns the
+// response unread. Transport errors, 429 and 5xx count against the host and
+// fail with a retryable error; other statuses are left to the caller, as
+// client errors say nothing about the host's health.
+func (f *Fetcher) send(hs *hostState, req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if err := f.pace(ctx, hs); err != nil {
+		return nil, err
+	}
+
+	var resp *http.Response
+	err := hs.breaker.Call(ctx, func(ctx context.Context) error {
+		r, err := f.client.Do(req)
+		if err != nil {
+			return &fetchError{err: err, retry: true}
+		}
+		if r.StatusCode == http.StatusTooManyRequests || r.StatusCode >= 500 {
+			r.Body.Close()
+			if r.StatusCode == http.StatusTooManyRequests || r.StatusCode == http.StatusServiceUnavailable {
+				f.holdBack(hs, r.Header.Get("Retry-After"))
+			}
+			return &fetchError{err: fmt.Errorf("status %d", r.StatusCode), retry: true}
+		}
+		resp = r
+		return nil
+	})
+	if errors.Is(err, resilience.ErrCircuitOpen) {
+		return nil, fmt.Errorf("%s: %w", req.URL, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Do sends req on the same schedule as Get, with its robots.txt check,
+// pacing, Retry-After holds, breaker and retries, but returns the response
+// unread so a large body can be streamed, whatever its status below 500.
+// The caller closes the body. req must not have a body, as it is resent on
+// retries; a missing User-Agent is set to the Fetcher's.
+func (f *Fetcher) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if req.URL.Host == "" {
+		return nil, fmt.Errorf("manuals: bad url %q", req.URL)
+	}
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", f.opts.UserAgent)
+	}
+	hs := f.host(req.URL.Host)
+
+	rules, err := f.robots(ctx, hs, req.URL)
+	if err != nil {
+		return nil, fmt.Errorf("robots.txt %s: %w", req.URL.Host, err)
+	}
+	if !rules.allowed(req.URL.RequestURI()) {
+		return nil, fmt.Errorf("%w: %s", ErrDisallowed, req.URL)
+	}
+
+	// As in fetch, a permanent failure ends the retries as an Ok.
+	var permanent error
+	resp, err := fn.Retry(ctx, f.opts.Retry, func(ctx context.Context) fn.Result[*http.Response] {
+		resp, err := f.send(hs, req.WithContext(ctx))
+		var fe *fetchError
+		if errors.As(err, &fe) && fe.retry {
+			return fn.Err[*http.Response](err)
+		}
+		permanent = err
+		return fn.Ok(resp)
+	}).Unwrap()
+	if err != nil {
+		return nil, err
+	}
+	return resp, permanent
+}
+
+func (f *Fetcher) do(ctx context.Context, method, rawURL string) (*http.Response, error) {
+	req, err := f.newRequest(ctx, method, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return f.client.Do(req)
+}
+
+// newRequest builds a request carrying the Fetcher's User-Agent.
+func (f *Fetcher) newRequest(ctx context.Context, method, rawURL string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", f.opts.UserAgent)
+	return req, nil
+}
+
+// holdBack stops requests to the host for the Retry-After duration, given
+// in seconds or as an HTTP date, capped at MaxBackoff. Without a usable
+// header the retry backoff alone applies.
+func (f *Fetcher) holdBack(hs *hostState, retryAfter string) {
+	var wait time.Duration
+	if secs, err := strconv.Atoi(retryAfter); err == nil {
+		wait = time.Duration(secs) * time.Second
+	} else if t, err := http.ParseTime(retryAfter); err == nil {
+		wait = t.Sub(f.now())
+	}
+	if wait <= 0 {
+		return
+	}
+	if wait > f.opts.MaxBackoff {
+		wait = f.opts.MaxBackoff
+	}
+	hs.mu.Lock()
+	if until := f.now().Add(wait); until.After(hs.retryAfter) {
+		hs.retryAfter = until
+	}
+	hs.mu.Unlock()
+}
+
+// pace waits until the host may be sent another request: past any
+// Retry-After hold and for a token from its limiter.
+func (f *Fetcher) pace(ctx context.Context, hs *hostState) error {
+	hs.mu.Lock()
+	wait := hs.retryAfter.Sub(f.now())
+	limiter := hs.limiter
+	hs.mu.Unlock()
+	if wait > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+	return limiter.Wait(ctx)
+}
+
+// robots returns the host's robots.txt rules for our user agent, fetching
+// them when missing or older than RobotsTTL. A 4xx robots.txt allows
+// everything; a 5xx or unreachable one fails the fetch, so nothing is
+// crawled until the rules are known. A Crawl-delay slower than the
+// configured rate replaces the host's limiter.
+func (f *Fetcher) robots(ctx context.Context, hs *hostState, u *url.URL) (*robotsRules, error) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	if hs.robots != nil && f.now().Sub(hs.robotsAt) < f.opts.RobotsTTL {
+		return hs.robots, nil
+	}
+
+	if err := hs.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	robotsURL := (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/robots.txt"}).String()
+	var rules *robotsRules
+	err := hs.breaker.Call(ctx, func(ctx context.Context) error {
+		resp, err := f.do(ctx, http.MethodGet, robotsURL)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		switch {
+		case resp.StatusCode == http.StatusOK:
+			body, err := io.ReadAll(io.LimitReader(resp.Body, 512*1024))
+			if err != nil {
+				return err
+			}
+			rules = parseRobots(string(body), f.opts.UserAgent)
+		case resp.StatusCode >= 400 && resp.StatusCode < 500:
+			rules = &robotsRules{}
+		default:
+			return fmt.Errorf("status %d", resp.StatusCode)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if rules.crawlDelay > 0 {
+		if rate := float64(time.Second) / float64(rules.crawlDelay); rate < f.rate(u.Host) {
+			hs.limiter = resilience.NewLimiter(resilience.LimiterOpts{Rate: rate, Burst: 1})
+		}
+	}
+	hs.robots, hs.robotsAt = rules, f.now()
+	return rules, nil
+}
@@ -0,0 +1,219 @@
+package manuals
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/WessleyAI/wessley-mvp/pkg/fn"
+	"github.com/WessleyAI/wessley-mvp/pkg/resilience"
+)
+
+// testFetcher is a Fetcher that does not keep tests waiting.
+func testFetcher() *Fetcher {
+	return NewFetcher(&http.Client{Timeout: 5 * time.Second}, FetcherOpts{
+		Rate:    1000,
+		Burst:   10,
+		Retry:   fn.RetryOpts{MaxAttempts: 3, InitialWait: time.Millisecond, MaxWait: 5 * time.Millisecond},
+		Breaker: resilience.BreakerOpts{FailThreshold: 3, Timeout: time.Minute},
+	})
+}
+
+func TestFetcherGet(t *testing.T) {
+	var robots, pages atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != "WessleyBot/1.0" {
+			t.Errorf("unexpected user agent %q", r.Header.Get("User-Agent"))
+		}
+		switch r.URL.Path {
+		case "/robots.txt":
+			robots.Add(1)
+			fmt.Fprint(w, "User-agent: *\nDisallow: /private/\nAllow: /private/manuals/\n")
+		default:
+			pages.Add(1)
+			fmt.Fprint(w, "ok "+r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	f := testFetcher()
+	ctx := context.Background()
+	for _, path := range []string{"/manuals/2024", "/private/manuals/x.pdf"} {
+		body, err := f.Get(ctx, srv.URL+path)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		if string(body) != "ok "+path {
+			t.Errorf("%s: got %q", path, body)
+		}
+	}
+	if _, err := f.Get(ctx, srv.URL+"/private/secret"); !errors.Is(err, ErrDisallowed) {
+		t.Errorf("expected ErrDisallowed, got %v", err)
+	}
+	if robots.Load() != 1 || pages.Load() != 2 {
+		t.Errorf("expected 1 robots.txt and 2 page requests, got %d and %d", robots.Load(), pages.Load())
+	}
+}
+
+func TestFetcherRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r) // no robots.txt allows everything
+			return
+		}
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	start := time.Now()
+	body, err := testFetcher().Get(context.Background(), srv.URL+"/page")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "ok" || calls.Load() != 2 {
+		t.Errorf("got %q after %d calls", body, calls.Load())
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected to wait out Retry-After, took %v", elapsed)
+	}
+}
+
+func TestFetcherNotFoundNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/robots.txt" {
+			calls.Add(1)
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	f := testFetcher()
+	for i := 0; i < 5; i++ {
+		if _, err := f.Get(context.Background(), srv.URL+"/missing"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+	}
+	// Each 404 is tried once and does not trip the breaker.
+	if calls.Load() != 5 {
+		t.Errorf("expected 5 requests, got %d", calls.Load())
+	}
+}
+
+func TestFetcherBreakerOpens(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			return
+		}
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	f := testFetcher()
+	if _, err := f.Get(context.Background(), srv.URL+"/a"); err == nil {
+		t.Fatal("expected error")
+	}
+	_, err := f.Get(context.Background(), srv.URL+"/b")
+	if !errors.Is(err, resilience.ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected the breaker to stop requests after 3, got %d", calls.Load())
+	}
+}
+
+func TestFetcherCrawlDelay(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			fmt.Fprint(w, "User-agent: WessleyBot\nCrawl-delay: 0.2\n")
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	f := testFetcher()
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := f.Get(context.Background(), srv.URL+"/page"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The first page takes the new limiter's only token; the next two wait.
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Errorf("expected Crawl-delay pacing, took %v", elapsed)
+	}
+}
+
+func TestFetcherRobotsUnavailable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		t.Errorf("fetched %s without robots.txt", r.URL.Path)
+	}))
+	defer srv.Close()
+
+	if _, err := testFetcher().Get(context.Background(), srv.URL+"/page"); err == nil {
+		t.Fatal("expected error while robots.txt is unavailable")
+	}
+}
+
+func TestParseRobots(t *testing.T) {
+	body := `# example
+User-agent: Googlebot
+Disallow: /
+
+User-agent: *
+Disallow: /search
+Disallow: /*.php$
+Allow: /search/manuals
+Crawl-delay: 5
+
+User-agent: WessleyBot
+User-agent: OtherBot
+Disallow: /owners/
+Crawl-delay: 2
+`
+	own := parseRobots(body, "WessleyBot/1.0")
+	if own.crawlDelay != 2*time.Second {
+		t.Errorf("expected own group crawl delay 2s, got %v", own.crawlDelay)
+	}
+	if own.allowed("/owners/manuals") || !own.allowed("/search") {
+		t.Error("expected the WessleyBot group to apply instead of *")
+	}
+
+	star := parseRobots(body, "SomeBot/2.0")
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/", true},
+		{"/search?q=x", false},
+		{"/search/manuals/2024", true},
+		{"/index.php", false},
+		{"/index.php?x=1", true},
+	}
+	for _, tt := range tests {
+		if got := star.allowed(tt.path); got != tt.want {
+			t.Errorf("allowed(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+	if star.crawlDelay != 5*time.Second {
+		t.Errorf("expected crawl delay 5s, got %v", star.crawlDelay)
+	}
+}
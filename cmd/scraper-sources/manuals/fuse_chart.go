@@ -0,0 +1,263 @@
+package manuals
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/WessleyAI/wessley-mvp/engine/graph"
+	"github.com/WessleyAI/wessley-mvp/engine/manual"
+)
+
+var (
+	ampsPattern      = regexp.MustCompile(`(?i)\b(\d{1,3}(?:\.\d)?)\s*a(?:mps?)?\b`)
+	bareAmpsPattern  = regexp.MustCompile(`^\d{1,3}(?:\.\d)?$`)
+	relayIDPattern   = regexp.MustCompile(`(?i)^(?:relay\s*)?[rk]\s?\d{1,3}[a-z]?$`)
+	positionPattern  = regexp.MustCompile(`(?i)^(?:fuse\s*|#\s*)?([a-z]{0,2}\d{1,3}[a-z]?)$`)
+	circuitSeparator = regexp.MustCompile(`\s*(?:[;,•]|\s/\s)\s*`)
+
+	// Fuse chart lines in page text: "F12 20A Mini Power point" and
+	// "R3 Blower motor relay".
+	fuseLinePattern  = regexp.MustCompile(`(?i)^(?:fuse\s+)?#?([a-z]{0,2}\d{1,3}[a-z]?)\s+(\d{1,3}(?:\.\d)?)\s*a\b\s*(.*)$`)
+	relayLinePattern = regexp.MustCompile(`(?i)^(?:relay\s+)?([rk]\s?\d{1,3}[a-z]?)\s+(\D.*)$`)
+)
+
+// fuseTypes maps the words manuals use for blade and cartridge fuses to the
+// FuseEntry types.
+var fuseTypes = []struct {
+	pattern *regexp.Regexp
+	typ     string
+}{
+	{regexp.MustCompile(`(?i)\bmicro\b`), "micro"},
+	{regexp.MustCompile(`(?i)\bmini\b|\blow[\s-]profile\b`), "mini"},
+	{regexp.MustCompile(`(?i)\bmaxi\b`), "maxi"},
+	{regexp.MustCompile(`(?i)\b(?:cartridge|[jm][\s-]?case|pal|fusible link)\b`), "cartridge"},
+	{regexp.MustCompile(`(?i)\b(?:ato|atc|standard)\b`), "standard"},
+}
+
+func fuseType(s string) string {
+	for _, ft := range fuseTypes {
+		if ft.pattern.MatchString(s) {
+			return ft.typ
+		}
+	}
+	return ""
+}
+
+// fuseBoxes maps the names manuals give fuse box locations to the FuseEntry
+// boxes.
+var fuseBoxes = []struct {
+	pattern *regexp.Regexp
+	box     string
+}{
+	{regexp.MustCompile(`(?i)engine\s+(?:compartment|bay|room)|under[\s-]?hood|power\s+distribution\s+box|battery\s+junction\s+box`), "engine bay"},
+	{regexp.MustCompile(`(?i)passenger\s+compartment|(?:instrument\s+panel|cabin|interior|dash(?:board)?|kick\s+panel)\s+(?:fuse|junction|relay)|under\s+the\s+dash`), "cabin"},
+	{regexp.MustCompile(`(?i)\b(?:trunk|luggage|cargo)\s+(?:compartment|area)\b|rear\s+fuse\s+(?:box|panel)`), "trunk"},
+}
+
+func fuseBox(s string) string {
+	for _, b := range fuseBoxes {
+		if b.pattern.MatchString(s) {
+			return b.box
+		}
+	}
+	return ""
+}
+
+// pageFuseBox returns the fuse box a page is about when it names exactly one.
+func pageFuseBox(text string) string {
+	found := ""
+	for _, b := range fuseBoxes {
+		if b.pattern.MatchString(text) {
+			if found != "" {
+				return ""
+			}
+			found = b.box
+		}
+	}
+	return found
+}
+
+// fuseColumns locates the position, rating, type and circuit columns of a
+// fuse or relay chart, reporting whether headers look like one.
+type fuseColumns struct {
+	position, amps, typ, circuit int
+	relays                       bool // a relay-only chart
+}
+
+func matchFuseTable(headers []string) (fuseColumns, bool) {
+	cols := fuseColumns{position: 0, amps: -1, typ: -1, circuit: -1}
+	for i, h := range headers {
+		lh := strings.ToLower(strings.TrimSpace(h))
+		switch {
+		case strings.Contains(lh, "amp") || strings.Contains(lh, "rating") || lh == "a" || lh == "(a)" || strings.Contains(lh, "current"):
+			cols.amps = i
+		case lh == "type" || strings.Contains(lh, "fuse type"):
+			cols.typ = i
+		case strings.Contains(lh, "circuit") || strings.Contains(lh, "protect") || strings.Contains(lh, "description") ||
+			strings.Contains(lh, "component") || strings.Contains(lh, "function") || strings.Contains(lh, "name") ||
+			strings.Contains(lh, "system") || strings.Contains(lh, "controlled"):
+			cols.circuit = i
+		case i == 0 && strings.Contains(lh, "relay"):
+			cols.relays = true
+		}
+	}
+	first := strings.ToLower(headers[0])
+	isChart := strings.Contains(first, "fuse") || strings.Contains(first, "relay") || strings.Contains(first, "no") ||
+		strings.Contains(first, "#") || strings.Contains(first, "position") || strings.Contains(first, "cavity") ||
+		strings.Contains(first, "location")
+	if !isChart || cols.circuit <= 0 || (cols.amps < 0 && !cols.relays) {
+		return cols, false
+	}
+	return cols, true
+}
+
+// ExtractFuseChart reads fuse and relay box charts from a manual's tables,
+// and from fuse chart lines on pages mentioning fuses that have no such
+// table. text holds the page texts separated by form feeds. The fuse box a
+// chart belongs to comes from the table caption, else from the page when it
+// names a single box.
+func ExtractFuseChart(text string, tables []manual.Table) []graph.FuseEntry {
+	pages := strings.Split(text, "\f")
+	pageText := func(n int) string {
+		if n > 0 && n <= len(pages) {
+			return pages[n-1]
+		}
+		return ""
+	}
+
+	var entries []graph.FuseEntry
+	seen := map[string]bool{}
+	add := func(f graph.FuseEntry) {
+		key := f.Kind + "|" + f.Box + "|" + strings.ToLower(f.Position)
+		if f.Position == "" || len(f.Circuits) == 0 || seen[key] {
+			return
+		}
+		seen[key] = true
+		entries = append(entries, f)
+	}
+
+	charted := map[int]bool{}
+	for _, t := range tables {
+		if len(t.Headers) < 2 {
+			continue
+		}
+		cols, ok := matchFuseTable(t.Headers)
+		if !ok {
+			continue
+		}
+		charted[t.Page] = true
+		box := fuseBox(t.Caption)
+		if box == "" {
+			box = pageFuseBox(pageText(t.Page))
+		}
+		for _, row := range t.Rows {
+			if f, ok := fuseRow(row, cols); ok {
+				f.Box, f.Page = box, t.Page
+				add(f)
+			}
+		}
+	}
+
+	for i, page := range pages {
+		if charted[i+1] || !strings.Contains(strings.ToLower(page), "fuse") {
+			continue
+		}
+		box := ""
+		for _, line := range strings.Split(page, "\n") {
+			line = strings.TrimSpace(line)
+			f, ok := fuseLine(line)
+			if !ok {
+				if b := fuseBox(line); b != "" {
+					box = b
+				}
+				continue
+			}
+			f.Box, f.Page = box, i+1
+			add(f)
+		}
+	}
+	return entries
+}
+
+// fuseRow reads one row of a fuse chart table.
+func fuseRow(row []string, cols fuseColumns) (graph.FuseEntry, bool) {
+	cell := func(i int) string {
+		if i >= 0 && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+	pos, circuit := cell(cols.position), cell(cols.circuit)
+	if pos == "" || circuit == "" {
+		return graph.FuseEntry{}, false
+	}
+
+	f := graph.FuseEntry{Kind: graph.FuseKindFuse}
+	amps := cell(cols.amps)
+	if m := ampsPattern.FindStringSubmatch(amps); m != nil {
+		f.Amperage, _ = strconv.ParseFloat(m[1], 64)
+	} else if bareAmpsPattern.MatchString(amps) {
+		f.Amperage, _ = strconv.ParseFloat(amps, 64)
+	}
+	f.Type = fuseType(cell(cols.typ) + " " + amps)
+
+	switch {
+	case f.Amperage > 0:
+	case cols.relays, relayIDPattern.MatchString(pos), strings.Contains(strings.ToLower(circuit), "relay"):
+		f.Kind = graph.FuseKindRelay
+	}
+	if m := positionPattern.FindStringSubmatch(pos); m != nil && f.Kind == graph.FuseKindFuse {
+		pos = m[1]
+	}
+	f.Position = strings.ToUpper(strings.ReplaceAll(strings.TrimPrefix(strings.ToLower(pos), "relay"), " ", ""))
+	f.Circuits = splitCircuits(circuit, f.Kind)
+	return f, true
+}
+
+// fuseLine reads a fuse chart line of page text.
+func fuseLine(line string) (graph.FuseEntry, bool) {
+	if m := fuseLinePattern.FindStringSubmatch(line); m != nil {
+		amps, _ := strconv.ParseFloat(m[2], 64)
+		rest := m[3]
+		typ := fuseType(firstWord(rest))
+		if typ != "" {
+			rest = strings.TrimSpace(rest[len(firstWord(rest)):])
+		}
+		f := graph.FuseEntry{Kind: graph.FuseKindFuse, Position: strings.ToUpper(m[1]), Amperage: amps, Type: typ}
+		f.Circuits = splitCircuits(rest, f.Kind)
+		return f, amps > 0
+	}
+	if m := relayLinePattern.FindStringSubmatch(line); m != nil {
+		f := graph.FuseEntry{Kind: graph.FuseKindRelay, Position: strings.ToUpper(strings.ReplaceAll(m[1], " ", ""))}
+		f.Circuits = splitCircuits(m[2], f.Kind)
+		return f, true
+	}
+	return graph.FuseEntry{}, false
+}
+
+func firstWord(s string) string {
+	if i := strings.IndexAny(s, " \t"); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// splitCircuits splits a chart's protected circuits cell into circuit
+// names, dropping unused positions. Relays are named after the circuit they
+// switch, so a trailing "relay" is removed.
+func splitCircuits(s, kind string) []string {
+	var out []string
+	for _, c := range circuitSeparator.Split(s, -1) {
+		c = strings.Trim(strings.TrimSpace(c), ".")
+		lc := strings.ToLower(c)
+		if c == "" || c == "-" || c == "—" || strings.Contains(lc, "not used") || lc == "spare" || lc == "empty" {
+			continue
+		}
+		if kind == graph.FuseKindRelay && strings.HasSuffix(lc, " relay") {
+			c = strings.TrimSpace(c[:len(c)-len(" relay")])
+		}
+		out = append(out, c)
+	}
+	return out
+}
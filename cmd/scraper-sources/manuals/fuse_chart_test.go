@@ -0,0 +1,67 @@
+package manuals
+
+import (
+	"testing"
+
+	"github.com/WessleyAI/wessley-mvp/engine/graph"
+	"github.com/WessleyAI/wessley-mvp/engine/manual"
+	"github.com/WessleyAI/wessley-mvp/pkg/pdf"
+)
+
+func fusePanelPage() pdf.Page {
+	var runs []pdf.TextRun
+	runs = append(runs, row(700, map[float64]string{72: "Passenger Compartment Fuse Panel"})...)
+	runs = append(runs, row(670, map[float64]string{72: "Fuse", 140: "Amp Rating", 240: "Protected Circuits"})...)
+	runs = append(runs, row(656, map[float64]string{72: "F12", 140: "20A Mini", 240: "Power point 1, Rear console"})...)
+	runs = append(runs, row(642, map[float64]string{72: "F13", 140: "7.5A", 240: "Not used"})...)
+	runs = append(runs, row(628, map[float64]string{72: "R3", 240: "Blower motor relay"})...)
+	return pdf.Page{Number: 241, Runs: runs}
+}
+
+func TestExtractFuseChart_Table(t *testing.T) {
+	p := fusePanelPage()
+	tables := manual.DetectTables(p)
+	if len(tables) != 1 || tables[0].Caption != "Passenger Compartment Fuse Panel" {
+		t.Fatalf("unexpected tables %+v", tables)
+	}
+	if specs := MapTables(tables); len(specs) != 0 {
+		t.Errorf("fuse chart mapped as spec table: %+v", specs)
+	}
+
+	fuses := ExtractFuseChart("", tables)
+	if len(fuses) != 2 {
+		t.Fatalf("expected fuse and relay, got %+v", fuses)
+	}
+	f := fuses[0]
+	if f.Kind != graph.FuseKindFuse || f.Position != "F12" || f.Box != "cabin" || f.Amperage != 20 || f.Type != "mini" || f.Page != 241 {
+		t.Errorf("unexpected fuse %+v", f)
+	}
+	if len(f.Circuits) != 2 || f.Circuits[0] != "Power point 1" || f.Circuits[1] != "Rear console" {
+		t.Errorf("unexpected circuits %v", f.Circuits)
+	}
+	if r := fuses[1]; r.Kind != graph.FuseKindRelay || r.Position != "R3" || len(r.Circuits) != 1 || r.Circuits[0] != "Blower motor" {
+		t.Errorf("unexpected relay %+v", r)
+	}
+}
+
+func TestExtractFuseChart_Text(t *testing.T) {
+	text := "Introduction\n\f\n" +
+		"Engine Compartment Fuse Box\n" +
+		"Fuse 5 30A J-case Power windows\n" +
+		"F40 7.5A Cigar lighter; Accessory socket\n" +
+		"R1 Starter relay\n" +
+		"Check fuse 7 if the horn stops working."
+	fuses := ExtractFuseChart(text, nil)
+	if len(fuses) != 3 {
+		t.Fatalf("expected 3 entries, got %+v", fuses)
+	}
+	if f := fuses[0]; f.Position != "5" || f.Amperage != 30 || f.Type != "cartridge" || f.Box != "engine bay" || f.Page != 2 || f.Circuits[0] != "Power windows" {
+		t.Errorf("unexpected fuse %+v", f)
+	}
+	if f := fuses[1]; f.Amperage != 7.5 || len(f.Circuits) != 2 || !f.Serves("power outlet") {
+		t.Errorf("unexpected fuse %+v", f)
+	}
+	if r := fuses[2]; r.Kind != graph.FuseKindRelay || r.Circuits[0] != "Starter" {
+		t.Errorf("unexpected relay %+v", r)
+	}
+}
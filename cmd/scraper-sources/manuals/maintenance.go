@@ -0,0 +1,322 @@
+package manuals
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/WessleyAI/wessley-mvp/engine/graph"
+	"github.com/WessleyAI/wessley-mvp/engine/manual"
+)
+
+var (
+	// intervalPattern reads "7,500 miles", "12,000 km", "6 months", "2 years"
+	// and "7.5k mi".
+	intervalPattern = regexp.MustCompile(`(?i)\b(\d{1,3}(?:,\d{3})+|\d+(?:\.\d+)?)\s*(k\b)?\s*(miles?|mi\b\.?|km|kilomet(?:er|re)s?|months?|mos?\b\.?|years?|yrs?\b\.?)`)
+	// everyPattern marks where an interval clause starts in an operation line.
+	everyPattern = regexp.MustCompile(`(?i)\s*[,(—–-]?\s*\b(?:every|at\s+(?:the\s+)?first\s+of|each)\b`)
+	// operationPattern requires a service verb at the start of an operation.
+	operationPattern = regexp.MustCompile(`(?i)^(?:rotate|replace|change|inspect|check|clean|adjust|lubricate|flush|drain|top\s+off|renew|service|tighten|test)\b`)
+	// severePattern marks severe-service schedules and their headings.
+	severePattern = regexp.MustCompile(`(?i)\bsevere\b|special\s+operating\s+conditions|extreme\s+(?:driving\s+)?conditions`)
+	normalPattern = regexp.MustCompile(`(?i)\b(?:normal|standard)\s+(?:service|maintenance|schedule|driving|operating)`)
+	// gridMarkPattern is a cell ticked in a schedule grid.
+	gridMarkPattern = regexp.MustCompile(`^(?i:[x✓✔●•*]|[ir]|ir)$`)
+	// intervalNumber is a bare number in an interval column.
+	intervalNumber = regexp.MustCompile(`^\d{1,3}(?:,\d{3})+$|^\d+(?:\.\d+)?k?$`)
+	bulletTrim     = "•·*-–—▪◦ \t"
+)
+
+// maintenanceComponents names what an operation services; longer names
+// come first so "cabin air filter" wins over "air filter".
+var maintenanceComponents = []string{
+	"cabin air filter", "engine air filter", "air filter", "oil filter", "fuel filter",
+	"engine oil", "transmission fluid", "brake fluid", "power steering fluid", "differential fluid",
+	"transfer case fluid", "coolant", "spark plugs", "timing belt", "drive belt", "serpentine belt",
+	"wiper blades", "brake pads", "brakes", "tires", "battery", "valve clearance", "exhaust system",
+	"suspension", "steering", "cv boots", "hoses",
+}
+
+// maintenanceComponent returns the component an operation services.
+func maintenanceComponent(op string) string {
+	lo := strings.ToLower(op)
+	for _, c := range maintenanceComponents {
+		if strings.Contains(lo, c) {
+			return strings.ToUpper(c[:1]) + c[1:]
+		}
+	}
+	return ""
+}
+
+// interval holds the intervals read from a clause; whichever comes first.
+type interval struct {
+	miles, km, months int
+}
+
+func (iv interval) empty() bool { return iv.miles == 0 && iv.km == 0 && iv.months == 0 }
+
+// parseInterval reads every distance and time in s.
+func parseInterval(s string) interval {
+	var iv interval
+	for _, m := range intervalPattern.FindAllStringSubmatch(s, -1) {
+		n, err := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", ""), 64)
+		if err != nil {
+			continue
+		}
+		if m[2] != "" {
+			n *= 1000
+		}
+		unit := strings.ToLower(m[3])
+		switch {
+		case strings.HasPrefix(unit, "mi"):
+			iv.miles = int(n)
+		case strings.HasPrefix(unit, "k"):
+			iv.km = int(n)
+		case strings.HasPrefix(unit, "mo"):
+			iv.months = int(n)
+		case strings.HasPrefix(unit, "y"):
+			iv.months = int(n * 12)
+		}
+	}
+	return iv
+}
+
+// cleanOperation trims bullets and punctuation from an operation and
+// capitalizes it.
+func cleanOperation(s string) string {
+	s = strings.Trim(strings.TrimSpace(s), bulletTrim+".:;,")
+	if s == "" {
+		return ""
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// ExtractMaintenance reads a manual's maintenance schedule from schedule
+// tables and from the text of pages about maintenance. text holds the page
+// texts separated by form feeds. Lines may carry their own interval
+// ("Rotate tires every 7,500 miles or 6 months", "Every 5,000 miles: rotate
+// tires") or follow an interval heading ("Every 30,000 miles or 24 months"
+// then a list of operations). Operations under a severe-service heading, or
+// in a table captioned as one, are the severe variant.
+func ExtractMaintenance(text string, tables []manual.Table) []graph.MaintenanceItem {
+	var items []graph.MaintenanceItem
+	seen := map[string]bool{}
+	add := func(op string, iv interval, severe bool, page int) {
+		op = cleanOperation(op)
+		if op == "" || iv.empty() || !operationPattern.MatchString(op) {
+			return
+		}
+		key := strings.ToLower(op) + "|" + strconv.FormatBool(severe)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		items = append(items, graph.MaintenanceItem{
+			Operation: op, Component: maintenanceComponent(op),
+			Miles: iv.miles, Km: iv.km, Months: iv.months, Severe: severe, Page: page,
+		})
+	}
+
+	scheduled := map[int]bool{}
+	for _, t := range tables {
+		if len(t.Headers) < 2 {
+			continue
+		}
+		severe := severePattern.MatchString(t.Caption)
+		if rows := maintenanceTable(t); rows != nil {
+			scheduled[t.Page] = true
+			for _, r := range rows {
+				add(r.op, r.iv, severe || r.severe, t.Page)
+			}
+		}
+	}
+
+	for i, page := range strings.Split(text, "\f") {
+		lp := strings.ToLower(page)
+		if scheduled[i+1] || !strings.Contains(lp, "maintenance") && !strings.Contains(lp, "service schedule") {
+			continue
+		}
+		var heading interval
+		severe := false
+		for _, line := range strings.Split(page, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			iv := parseInterval(line)
+			isOp := operationPattern.MatchString(strings.TrimLeft(line, bulletTrim))
+			switch {
+			case !isOp && severePattern.MatchString(line):
+				severe = true
+			case !isOp && normalPattern.MatchString(line):
+				severe = false
+			}
+			lineSevere := severe || isOp && severePattern.MatchString(line)
+
+			if iv.empty() {
+				if isOp && !heading.empty() {
+					for _, op := range strings.Split(line, ";") {
+						add(op, heading, lineSevere, i+1)
+					}
+				}
+				continue
+			}
+			// "Every 5,000 miles or 6 months: rotate tires; inspect brakes"
+			if before, after, ok := strings.Cut(line, ":"); ok && !parseInterval(before).empty() && strings.TrimSpace(after) != "" {
+				for _, op := range strings.Split(after, ";") {
+					add(op, iv, lineSevere, i+1)
+				}
+				continue
+			}
+			if !isOp {
+				heading = iv // an interval heading for the lines below
+				continue
+			}
+			// "Rotate tires every 7,500 miles (12,000 km) or 6 months"
+			op := line
+			if loc := everyPattern.FindStringIndex(line); loc != nil {
+				op = line[:loc[0]]
+			} else if loc := intervalPattern.FindStringIndex(line); loc != nil {
+				op = line[:loc[0]]
+			}
+			add(op, iv, lineSevere, i+1)
+		}
+	}
+	return items
+}
+
+type maintenanceRow struct {
+	op     string
+	iv     interval
+	severe bool
+}
+
+// maintenanceTable reads a schedule table, either with interval columns
+// ("Operation | Miles | km | Months") or as a grid of service points
+// ("Item | 7,500 | 15,000 | 22,500" with ticked cells). It returns nil for
+// other tables.
+func maintenanceTable(t manual.Table) []maintenanceRow {
+	if rows := intervalColumns(t); rows != nil {
+		return rows
+	}
+	return scheduleGrid(t)
+}
+
+func intervalColumns(t manual.Table) []maintenanceRow {
+	units := make([]string, len(t.Headers))
+	found, severeCol := false, -1
+	for i, h := range t.Headers[1:] {
+		lh := strings.ToLower(h)
+		switch {
+		case strings.Contains(lh, "severe"):
+			severeCol = i + 1
+		case strings.Contains(lh, "mile") || lh == "mi" || strings.Contains(lh, "(mi"):
+			units[i+1] = "miles"
+		case strings.Contains(lh, "km") || strings.Contains(lh, "kilomet"):
+			units[i+1] = "km"
+		case strings.Contains(lh, "month"):
+			units[i+1] = "months"
+		case strings.Contains(lh, "year"):
+			units[i+1] = "years"
+		case strings.Contains(lh, "interval"):
+			units[i+1] = "any"
+		default:
+			continue
+		}
+		found = true
+	}
+	if !found {
+		return nil
+	}
+
+	var rows []maintenanceRow
+	for _, row := range t.Rows {
+		if len(row) == 0 {
+			continue
+		}
+		var iv, severeIv interval
+		for i := 1; i < len(row) && i < len(t.Headers); i++ {
+			cell := strings.TrimSpace(row[i])
+			if i == severeCol {
+				severeIv = parseInterval(cell)
+				continue
+			}
+			if units[i] == "" || cell == "" {
+				continue
+			}
+			if units[i] != "any" && intervalNumber.MatchString(cell) {
+				cell += " " + units[i]
+			}
+			got := parseInterval(cell)
+			if got.miles > 0 {
+				iv.miles = got.miles
+			}
+			if got.km > 0 {
+				iv.km = got.km
+			}
+			if got.months > 0 {
+				iv.months = got.months
+			}
+		}
+		op := row[0]
+		rows = append(rows, maintenanceRow{op: op, iv: iv})
+		if !severeIv.empty() {
+			rows = append(rows, maintenanceRow{op: op, iv: severeIv, severe: true})
+		}
+	}
+	return rows
+}
+
+// scheduleGrid reads a grid whose headers are service points. An item's
+// interval is the spacing of its ticks, or its first tick when it has one.
+func scheduleGrid(t manual.Table) []maintenanceRow {
+	unit := "miles"
+	if strings.Contains(strings.ToLower(t.Caption+" "+t.Headers[0]), "km") {
+		unit = "km"
+	}
+	scale := 1
+	if strings.Contains(t.Caption+" "+t.Headers[0], "1000") || strings.Contains(t.Caption+" "+t.Headers[0], "1,000") {
+		scale = 1000
+	}
+	points := make([]int, len(t.Headers))
+	count := 0
+	for i, h := range t.Headers[1:] {
+		n, err := strconv.ParseFloat(strings.ReplaceAll(strings.Trim(strings.TrimSpace(h), "kK"), ",", ""), 64)
+		if err != nil || n <= 0 {
+			continue
+		}
+		if strings.HasSuffix(strings.ToLower(strings.TrimSpace(h)), "k") {
+			n *= 1000
+		}
+		points[i+1] = int(n) * scale
+		count++
+	}
+	if count < 2 {
+		return nil
+	}
+
+	var rows []maintenanceRow
+	for _, row := range t.Rows {
+		var ticks []int
+		for i := 1; i < len(row) && i < len(points); i++ {
+			if points[i] > 0 && gridMarkPattern.MatchString(strings.TrimSpace(row[i])) {
+				ticks = append(ticks, points[i])
+			}
+		}
+		if len(ticks) == 0 {
+			continue
+		}
+		sort.Ints(ticks)
+		step := ticks[0]
+		if len(ticks) > 1 {
+			step = ticks[1] - ticks[0]
+		}
+		iv := interval{miles: step}
+		if unit == "km" {
+			iv = interval{km: step}
+		}
+		rows = append(rows, maintenanceRow{op: row[0], iv: iv})
+	}
+	return rows
+}
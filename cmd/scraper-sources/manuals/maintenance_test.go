@@ -0,0 +1,106 @@
+package manuals
+
+import (
+	"testing"
+
+	"github.com/WessleyAI/wessley-mvp/engine/graph"
+	"github.com/WessleyAI/wessley-mvp/engine/manual"
+	"github.com/WessleyAI/wessley-mvp/pkg/pdf"
+)
+
+func maintenanceByOp(items []graph.MaintenanceItem, severe bool) map[string]graph.MaintenanceItem {
+	m := map[string]graph.MaintenanceItem{}
+	for _, it := range items {
+		if it.Severe == severe {
+			m[it.Operation] = it
+		}
+	}
+	return m
+}
+
+func TestExtractMaintenance_Text(t *testing.T) {
+	text := "Introduction\nReplace the wiper blades when they streak.\n\f\n" +
+		"Scheduled Maintenance\n" +
+		"Rotate tires every 7,500 miles (12,000 km) or 6 months\n" +
+		"Every 30,000 miles or 24 months\n" +
+		"• Replace engine air filter\n" +
+		"• Inspect brake pads; Check coolant level\n" +
+		"Every 10,000 km: Replace cabin air filter\n" +
+		"Severe Service\n" +
+		"Replace engine oil every 3,750 miles or 3 months\n" +
+		"Normal Service\n" +
+		"Replace engine oil every 7.5k miles or 1 year"
+	items := ExtractMaintenance(text, nil)
+	normal := maintenanceByOp(items, false)
+	severe := maintenanceByOp(items, true)
+	if len(normal) != 6 || len(severe) != 1 {
+		t.Fatalf("unexpected items %+v", items)
+	}
+	if m := normal["Rotate tires"]; m.Miles != 7500 || m.Km != 12000 || m.Months != 6 || m.Component != "Tires" || m.Page != 2 {
+		t.Errorf("tires: %+v", m)
+	}
+	if m := normal["Replace engine air filter"]; m.Miles != 30000 || m.Months != 24 || m.Component != "Engine air filter" {
+		t.Errorf("air filter under a heading: %+v", m)
+	}
+	if m := normal["Check coolant level"]; m.Miles != 30000 || m.Component != "Coolant" {
+		t.Errorf("second operation on a line: %+v", m)
+	}
+	if m := normal["Replace cabin air filter"]; m.Km != 10000 || m.Miles != 0 || m.Component != "Cabin air filter" {
+		t.Errorf("cabin filter: %+v", m)
+	}
+	if m := severe["Replace engine oil"]; m.Miles != 3750 || m.Months != 3 {
+		t.Errorf("severe oil: %+v", m)
+	}
+	if m := normal["Replace engine oil"]; m.Miles != 7500 || m.Months != 12 {
+		t.Errorf("normal oil: %+v", m)
+	}
+	if _, ok := normal["Replace the wiper blades when they streak"]; ok {
+		t.Error("pages without a schedule should be ignored")
+	}
+}
+
+func TestExtractMaintenance_IntervalTable(t *testing.T) {
+	var runs []pdf.TextRun
+	runs = append(runs, row(700, map[float64]string{72: "Maintenance Schedule"})...)
+	runs = append(runs, row(670, map[float64]string{72: "Operation", 240: "Miles", 320: "Months", 400: "Severe Service"})...)
+	runs = append(runs, row(656, map[float64]string{72: "Replace engine oil and filter", 240: "10,000", 320: "12", 400: "5,000 miles"})...)
+	runs = append(runs, row(642, map[float64]string{72: "Replace spark plugs", 240: "100,000", 320: "120"})...)
+	tables := manual.DetectTables(pdf.Page{Number: 410, Runs: runs})
+
+	items := ExtractMaintenance("", tables)
+	if len(items) != 3 {
+		t.Fatalf("expected 3 items, got %+v", items)
+	}
+	if m := items[0]; m.Operation != "Replace engine oil and filter" || m.Miles != 10000 || m.Months != 12 || m.Severe || m.Page != 410 || m.Component != "Engine oil" {
+		t.Errorf("oil: %+v", m)
+	}
+	if m := items[1]; !m.Severe || m.Miles != 5000 || m.Operation != "Replace engine oil and filter" {
+		t.Errorf("severe column: %+v", m)
+	}
+	if m := items[2]; m.Miles != 100000 || m.Months != 120 {
+		t.Errorf("spark plugs: %+v", m)
+	}
+}
+
+func TestExtractMaintenance_Grid(t *testing.T) {
+	tables := []manual.Table{{
+		Caption: "Severe Service Schedule",
+		Headers: []string{"Item", "5,000", "10,000", "15,000", "20,000"},
+		Rows: [][]string{
+			{"Rotate tires", "X", "X", "X", "X"},
+			{"Replace engine air filter", "", "", "", "X"},
+			{"Owner notes", "", "", "", ""},
+		},
+		Page: 12,
+	}}
+	items := ExtractMaintenance("", tables)
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %+v", items)
+	}
+	if m := items[0]; m.Miles != 5000 || !m.Severe {
+		t.Errorf("tires: %+v", m)
+	}
+	if m := items[1]; m.Miles != 20000 {
+		t.Errorf("air filter: %+v", m)
+	}
+}
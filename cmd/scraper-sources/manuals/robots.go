@@ -0,0 +1,131 @@
+package manuals
+
+import (
+	"bufio"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// robotsRules are the robots.txt rules that apply to one user agent.
+type robotsRules struct {
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+type robotsRule struct {
+	allow   bool
+	length  int // pattern length; the longest matching rule wins
+	pattern *regexp.Regexp
+}
+
+// allowed reports whether path (with its query) may be fetched. Per RFC
+// 9309 the longest matching rule wins and Allow wins ties; no match allows.
+func (r *robotsRules) allowed(path string) bool {
+	if r == nil {
+		return true
+	}
+	best, allow := -1, true
+	for _, rule := range r.rules {
+		if rule.length < best || !rule.pattern.MatchString(path) {
+			continue
+		}
+		if rule.length > best || rule.allow {
+			allow = rule.allow
+		}
+		best = rule.length
+	}
+	return allow
+}
+
+// parseRobots reads the group of robots.txt that applies to userAgent: the
+// group naming its product token ("wessleybot" for "WessleyBot/1.0"),
+// otherwise the "*" group.
+func parseRobots(body, userAgent string) *robotsRules {
+	token := strings.ToLower(userAgent)
+	if i := strings.IndexAny(token, "/ "); i >= 0 {
+		token = token[:i]
+	}
+
+	var (
+		own, star          robotsRules
+		ownFound, inRules  bool
+		applyOwn, applyAll bool
+		agents             []string
+	)
+	sc := bufio.NewScanner(strings.NewReader(body))
+	for sc.Scan() {
+		line := sc.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		if key == "user-agent" {
+			if inRules {
+				agents, inRules = nil, false
+			}
+			agents = append(agents, strings.ToLower(value))
+			applyOwn, applyAll = false, false
+			for _, a := range agents {
+				switch a {
+				case "*":
+					applyAll = true
+				case token:
+					applyOwn, ownFound = true, true
+				}
+			}
+			continue
+		}
+		inRules = true
+		for _, target := range []struct {
+			apply bool
+			rules *robotsRules
+		}{{applyOwn, &own}, {applyAll, &star}} {
+			if !target.apply {
+				continue
+			}
+			switch key {
+			case "allow", "disallow":
+				if value == "" {
+					continue // "Disallow:" allows everything
+				}
+				target.rules.rules = append(target.rules.rules, robotsRule{
+					allow:   key == "allow",
+					length:  len(value),
+					pattern: robotsPattern(value),
+				})
+			case "crawl-delay":
+				if secs, err := strconv.ParseFloat(value, 64); err == nil && secs > 0 {
+					target.rules.crawlDelay = time.Duration(secs * float64(time.Second))
+				}
+			}
+		}
+	}
+	if ownFound {
+		return &own
+	}
+	return &star
+}
+
+// robotsPattern compiles a robots.txt path pattern, where * matches any
+// run of characters and a trailing $ anchors the end.
+func robotsPattern(p string) *regexp.Regexp {
+	anchored := strings.HasSuffix(p, "$")
+	p = strings.TrimSuffix(p, "$")
+	parts := strings.Split(p, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	expr := "^" + strings.Join(parts, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/WessleyAI/wessley-mvp/engine/manual"
 	"github.com/WessleyAI/wessley-mvp/engine/scraper"
 )
 
@@ -70,7 +71,7 @@ func (s *Scraper) FetchAll(ctx context.Context) ([]scraper.ScrapedPost, error) {
 }
 
 func (s *Scraper) processFile(path, filename string) (scraper.ScrapedPost, error) {
-	content, err := ExtractTextFromPDF(path)
+	content, err := manual.ExtractTextFromPDF(path)
 	if err != nil {
 		return scraper.ScrapedPost{}, err
 	}
@@ -78,7 +79,7 @@ func (s *Scraper) processFile(path, filename string) (scraper.ScrapedPost, error
 		return scraper.ScrapedPost{}, fmt.Errorf("no text extracted")
 	}
 
-	make, model, year := TagVehicleInfo(filename, content)
+	make, model, year := manual.TagVehicleInfo(filename, content)
 
 	var vi *scraper.VehicleInfo
 	if make != "" {
@@ -111,7 +112,7 @@ func (s *Scraper) processFile(path, filename string) (scraper.ScrapedPost, error
 
 // processFileMulti processes a PDF file and returns one ScrapedPost per detected section.
 func (s *Scraper) processFileMulti(path, filename string) ([]scraper.ScrapedPost, error) {
-	content, err := ExtractTextFromPDF(path)
+	content, err := manual.ExtractTextFromPDF(path)
 	if err != nil {
 		return nil, err
 	}
@@ -119,24 +120,8 @@ func (s *Scraper) processFileMulti(path, filename string) ([]scraper.ScrapedPost
 		return nil, fmt.Errorf("no text extracted")
 	}
 
-	mk, mdl, year := TagVehicleInfo(filename, content)
-
-	var vi *scraper.VehicleInfo
-	if mk != "" {
-		vi = &scraper.VehicleInfo{
-			Make:  mk,
-			Model: mdl,
-			Year:  year,
-		}
-	}
-
-	vehicle := ""
-	if mk != "" && year > 0 {
-		vehicle = fmt.Sprintf("%d-%s-%s", year, mk, mdl)
-	}
-
-	sections := ParseSections(content)
-	if len(sections) == 0 {
+	posts := manual.SectionPosts(content, filename, "file://"+path, nil)
+	if len(posts) == 0 {
 		// Fallback to single post.
 		post, err := s.processFile(path, filename)
 		if err != nil {
@@ -144,43 +129,6 @@ func (s *Scraper) processFileMulti(path, filename string) ([]scraper.ScrapedPost
 		}
 		return []scraper.ScrapedPost{post}, nil
 	}
-
-	now := time.Now()
-	baseName := strings.TrimSuffix(filename, filepath.Ext(filename))
-	var posts []scraper.ScrapedPost
-
-	for i, sec := range sections {
-		keywords := []string{"manual", "owner's manual"}
-		if sec.System != "" {
-			keywords = append(keywords, strings.ToLower(sec.System))
-		}
-		if sec.Subsystem != "" {
-			keywords = append(keywords, strings.ToLower(sec.Subsystem))
-		}
-
-		meta := scraper.Metadata{
-			Vehicle:     vehicle,
-			VehicleInfo: vi,
-			Keywords:    keywords,
-		}
-		if sec.System != "" {
-			meta.Section = sec.System
-			if sec.Subsystem != "" {
-				meta.Section = sec.System + "/" + sec.Subsystem
-			}
-		}
-
-		posts = append(posts, scraper.ScrapedPost{
-			Source:    "manual",
-			SourceID:  fmt.Sprintf("%s-sec-%d", filename, i),
-			Title:     fmt.Sprintf("%s - %s", baseName, sec.Title),
-			Content:   sec.Content,
-			URL:       "file://" + path,
-			ScrapedAt: now,
-			Metadata:  meta,
-		})
-	}
-
 	return posts, nil
 }
 
@@ -4,8 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
 	"regexp"
 	"strconv"
@@ -13,16 +11,15 @@ import (
 	"time"
 
 	"github.com/WessleyAI/wessley-mvp/engine/graph"
-	"github.com/WessleyAI/wessley-mvp/pkg/fn"
 )
 
 // ArchiveSource discovers vehicle manuals from Archive.org.
 type ArchiveSource struct {
-	client *http.Client
+	fetcher *Fetcher
 }
 
-func NewArchiveSource() *ArchiveSource {
-	return &ArchiveSource{client: &http.Client{Timeout: 60 * time.Second}}
+func NewArchiveSource(f *Fetcher) *ArchiveSource {
+	return &ArchiveSource{fetcher: f}
 }
 
 func (s *ArchiveSource) Name() string { return "archive" }
@@ -52,7 +49,6 @@ func (s *ArchiveSource) Discover(ctx context.Context, makes []string, years []in
 			continue
 		}
 		entries = append(entries, found...)
-		time.Sleep(2 * time.Second) // Rate limit
 	}
 
 	return dedup(entries), nil
@@ -92,32 +88,7 @@ func (s *ArchiveSource) searchArchive(ctx context.Context, query string, makes [
 		"sort[]":    {"downloads desc"},
 	}.Encode()
 
-	result := fn.Retry(ctx, fn.RetryOpts{
-		MaxAttempts: 2,
-		InitialWait: 2 * time.Second,
-		MaxWait:     10 * time.Second,
-	}, func(ctx context.Context) fn.Result[[]byte] {
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
-		if err != nil {
-			return fn.Err[[]byte](err)
-		}
-		req.Header.Set("User-Agent", "WessleyBot/1.0")
-		resp, err := s.client.Do(req)
-		if err != nil {
-			return fn.Err[[]byte](err)
-		}
-		defer resp.Body.Close()
-		if resp.StatusCode != http.StatusOK {
-			return fn.Errf[[]byte]("status %d", resp.StatusCode)
-		}
-		body, err := io.ReadAll(io.LimitReader(resp.Body, 5*1024*1024))
-		if err != nil {
-			return fn.Err[[]byte](err)
-		}
-		return fn.Ok(body)
-	})
-
-	body, err := result.Unwrap()
+	body, err := s.fetcher.Get(ctx, u)
 	if err != nil {
 		return nil, err
 	}
@@ -157,8 +128,6 @@ func (s *ArchiveSource) searchArchive(ctx context.Context, query string, makes [
 				DiscoveredAt: time.Now(),
 			})
 		}
-
-		time.Sleep(1 * time.Second) // Rate limit metadata requests
 	}
 	return entries, nil
 }
@@ -167,23 +136,7 @@ func (s *ArchiveSource) searchArchive(ctx context.Context, query string, makes [
 func (s *ArchiveSource) findPDFsInItem(ctx context.Context, identifier string) ([]string, error) {
 	u := fmt.Sprintf("https://archive.org/metadata/%s/files", identifier)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("User-Agent", "WessleyBot/1.0")
-
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("metadata status %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(io.LimitReader(resp.Body, 2*1024*1024))
+	body, err := s.fetcher.Get(ctx, u)
 	if err != nil {
 		return nil, err
 	}
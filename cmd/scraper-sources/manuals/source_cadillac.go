@@ -1,113 +0,0 @@
-package manuals
-
-import (
-	"context"
-	"fmt"
-	"io"
-	"log"
-	"net/http"
-	"time"
-
-	"github.com/WessleyAI/wessley-mvp/engine/graph"
-	"github.com/WessleyAI/wessley-mvp/pkg/fn"
-)
-
-// CadillacSource discovers owner manuals from Cadillac's website.
-type CadillacSource struct {
-	client *http.Client
-}
-
-// NewCadillacSource creates a new CadillacSource.
-func NewCadillacSource() *CadillacSource {
-	return &CadillacSource{
-		client: &http.Client{Timeout: 30 * time.Second},
-	}
-}
-
-func (s *CadillacSource) Name() string { return "cadillac" }
-
-func (s *CadillacSource) Discover(ctx context.Context, makes []string, years []int) ([]graph.ManualEntry, error) {
-	if !containsIgnoreCase(makes, "Cadillac") {
-		return nil, nil
-	}
-
-	var entries []graph.ManualEntry
-
-	models := []string{
-		"escalade", "ct4", "ct5", "xt4", "xt5", "xt6", "lyriq",
-	}
-
-	for _, year := range years {
-		for _, model := range models {
-			select {
-			case <-ctx.Done():
-				return entries, ctx.Err()
-			default:
-			}
-
-			url := fmt.Sprintf("https://www.cadillac.com/content/dam/cadillac/us/manuals/%d/%s-owners-manual.pdf", year, model)
-			entry := graph.ManualEntry{
-				ID:           graph.ManualEntryID(url),
-				URL:          url,
-				SourceSite:   "www.cadillac.com",
-				Make:         "Cadillac",
-				Model:        normModel(model),
-				Year:         year,
-				ManualType:   "owner",
-				Language:     "en",
-				Status:       "discovered",
-				DiscoveredAt: time.Now(),
-			}
-			entries = append(entries, entry)
-		}
-
-		// Crawl the owners manual page for additional PDF links
-		pageURL := fmt.Sprintf("https://www.cadillac.com/owners/manuals/%d", year)
-		found, err := s.discoverFromPage(ctx, pageURL, year)
-		if err != nil {
-			log.Printf("cadillac: page crawl %d: %v", year, err)
-		} else {
-			entries = append(entries, found...)
-		}
-
-		time.Sleep(time.Second) // Rate limit between years
-	}
-
-	return entries, nil
-}
-
-var cadillacPDFRegex = pdfLinkRegex
-
-func (s *CadillacSource) discoverFromPage(ctx context.Context, pageURL string, year int) ([]graph.ManualEntry, error) {
-	result := fn.Retry(ctx, fn.RetryOpts{
-		MaxAttempts: 2,
-		InitialWait: time.Second,
-		MaxWait:     5 * time.Second,
-	}, func(ctx context.Context) fn.Result[[]byte] {
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
-		if err != nil {
-			return fn.Err[[]byte](err)
-		}
-		req.Header.Set("User-Agent", "WessleyBot/1.0")
-		resp, err := s.client.Do(req)
-		if err != nil {
-			return fn.Err[[]byte](err)
-		}
-		defer resp.Body.Close()
-		if resp.StatusCode != http.StatusOK {
-			return fn.Errf[[]byte]("status %d", resp.StatusCode)
-		}
-		body, err := io.ReadAll(io.LimitReader(resp.Body, 2*1024*1024))
-		if err != nil {
-			return fn.Err[[]byte](err)
-		}
-		return fn.Ok(body)
-	})
-
-	body, err := result.Unwrap()
-	if err != nil {
-		return nil, err
-	}
-
-	return extractPDFLinks(string(body), "www.cadillac.com", "Cadillac", year), nil
-}
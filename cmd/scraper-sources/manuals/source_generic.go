@@ -3,23 +3,20 @@ package manuals
 import (
 	"context"
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
 	"regexp"
 	"time"
 
 	"github.com/WessleyAI/wessley-mvp/engine/graph"
-	"github.com/WessleyAI/wessley-mvp/pkg/fn"
 )
 
 // GenericSearchSource discovers manuals via web search for PDF links.
 type GenericSearchSource struct {
-	client *http.Client
+	fetcher *Fetcher
 }
 
-func NewGenericSearchSource() *GenericSearchSource {
-	return &GenericSearchSource{client: &http.Client{Timeout: 30 * time.Second}}
+func NewGenericSearchSource(f *Fetcher) *GenericSearchSource {
+	return &GenericSearchSource{fetcher: f}
 }
 
 func (s *GenericSearchSource) Name() string { return "search" }
@@ -42,7 +39,6 @@ func (s *GenericSearchSource) Discover(ctx context.Context, makes []string, year
 				continue
 			}
 			entries = append(entries, found...)
-			time.Sleep(3 * time.Second) // Aggressive rate limiting for search
 		}
 	}
 
@@ -58,32 +54,7 @@ func (s *GenericSearchSource) searchForPDFs(ctx context.Context, query, make_ st
 		"q": {query},
 	}.Encode()
 
-	result := fn.Retry(ctx, fn.RetryOpts{
-		MaxAttempts: 2,
-		InitialWait: 2 * time.Second,
-		MaxWait:     10 * time.Second,
-	}, func(ctx context.Context) fn.Result[[]byte] {
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, searchURL, nil)
-		if err != nil {
-			return fn.Err[[]byte](err)
-		}
-		req.Header.Set("User-Agent", "WessleyBot/1.0")
-		resp, err := s.client.Do(req)
-		if err != nil {
-			return fn.Err[[]byte](err)
-		}
-		defer resp.Body.Close()
-		if resp.StatusCode != http.StatusOK {
-			return fn.Errf[[]byte]("status %d", resp.StatusCode)
-		}
-		body, err := io.ReadAll(io.LimitReader(resp.Body, 2*1024*1024))
-		if err != nil {
-			return fn.Err[[]byte](err)
-		}
-		return fn.Ok(body)
-	})
-
-	body, err := result.Unwrap()
+	body, err := s.fetcher.Get(ctx, searchURL)
 	if err != nil {
 		return nil, err
 	}
@@ -10,7 +10,13 @@ import (
 var pdfLinkRegex = regexp.MustCompile(`href="(https?://[^"]*\.pdf)"`)
 
 func extractPDFLinks(html, sourceSite, make_ string, year int) []graph.ManualEntry {
-	matches := pdfLinkRegex.FindAllStringSubmatch(html, -1)
+	return extractLinks(pdfLinkRegex, html, sourceSite, make_, year)
+}
+
+// extractLinks returns an entry for each distinct URL captured by the first
+// group of re in html.
+func extractLinks(re *regexp.Regexp, html, sourceSite, make_ string, year int) []graph.ManualEntry {
+	matches := re.FindAllStringSubmatch(html, -1)
 	var entries []graph.ManualEntry
 	seen := make(map[string]bool)
 
@@ -3,23 +3,20 @@ package manuals
 import (
 	"context"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"strings"
 	"time"
 
 	"github.com/WessleyAI/wessley-mvp/engine/graph"
-	"github.com/WessleyAI/wessley-mvp/pkg/fn"
 )
 
 // HondaSource discovers owner manuals from Honda's website.
 type HondaSource struct {
-	client *http.Client
+	fetcher *Fetcher
 }
 
-func NewHondaSource() *HondaSource {
-	return &HondaSource{client: &http.Client{Timeout: 30 * time.Second}}
+func NewHondaSource(f *Fetcher) *HondaSource {
+	return &HondaSource{fetcher: f}
 }
 
 func (s *HondaSource) Name() string { return "honda" }
@@ -68,40 +65,13 @@ func (s *HondaSource) Discover(ctx context.Context, makes []string, years []int)
 		} else {
 			entries = append(entries, found...)
 		}
-
-		time.Sleep(time.Second)
 	}
 
 	return entries, nil
 }
 
 func (s *HondaSource) discoverFromPage(ctx context.Context, pageURL string, year int) ([]graph.ManualEntry, error) {
-	result := fn.Retry(ctx, fn.RetryOpts{
-		MaxAttempts: 2,
-		InitialWait: time.Second,
-		MaxWait:     5 * time.Second,
-	}, func(ctx context.Context) fn.Result[[]byte] {
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
-		if err != nil {
-			return fn.Err[[]byte](err)
-		}
-		req.Header.Set("User-Agent", "WessleyBot/1.0")
-		resp, err := s.client.Do(req)
-		if err != nil {
-			return fn.Err[[]byte](err)
-		}
-		defer resp.Body.Close()
-		if resp.StatusCode != http.StatusOK {
-			return fn.Errf[[]byte]("status %d", resp.StatusCode)
-		}
-		body, err := io.ReadAll(io.LimitReader(resp.Body, 2*1024*1024))
-		if err != nil {
-			return fn.Err[[]byte](err)
-		}
-		return fn.Ok(body)
-	})
-
-	body, err := result.Unwrap()
+	body, err := s.fetcher.Get(ctx, pageURL)
 	if err != nil {
 		return nil, err
 	}
@@ -14,7 +14,7 @@ func TestChevroletSourceDiscover(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	s := NewChevroletSource()
+	s := catalogSource(t, "chevrolet")
 	entries, err := s.Discover(context.Background(), []string{"Chevrolet"}, []int{2024})
 	if err != nil {
 		t.Fatal(err)
@@ -35,7 +35,7 @@ func TestChevroletSourceDiscover(t *testing.T) {
 }
 
 func TestChevroletSourceSkipsOtherMakes(t *testing.T) {
-	s := NewChevroletSource()
+	s := catalogSource(t, "chevrolet")
 	entries, err := s.Discover(context.Background(), []string{"Toyota"}, []int{2024})
 	if err != nil {
 		t.Fatal(err)
@@ -46,7 +46,7 @@ func TestChevroletSourceSkipsOtherMakes(t *testing.T) {
 }
 
 func TestTeslaSourceDiscover(t *testing.T) {
-	s := NewTeslaSource()
+	s := catalogSource(t, "tesla")
 	entries, err := s.Discover(context.Background(), []string{"Tesla"}, []int{2024})
 	if err != nil {
 		t.Fatal(err)
@@ -65,7 +65,7 @@ func TestTeslaSourceDiscover(t *testing.T) {
 }
 
 func TestNissanSourceDiscover(t *testing.T) {
-	s := NewNissanSource()
+	s := catalogSource(t, "nissan")
 	entries, err := s.Discover(context.Background(), []string{"Nissan"}, []int{2024})
 	if err != nil {
 		t.Fatal(err)
@@ -4,22 +4,19 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"strings"
 	"time"
 
 	"github.com/WessleyAI/wessley-mvp/engine/graph"
-	"github.com/WessleyAI/wessley-mvp/pkg/fn"
 )
 
 // NHTSASource discovers technical documents from NHTSA APIs.
 type NHTSASource struct {
-	client *http.Client
+	fetcher *Fetcher
 }
 
-func NewNHTSASource() *NHTSASource {
-	return &NHTSASource{client: &http.Client{Timeout: 30 * time.Second}}
+func NewNHTSASource(f *Fetcher) *NHTSASource {
+	return &NHTSASource{fetcher: f}
 }
 
 func (s *NHTSASource) Name() string { return "nhtsa" }
@@ -40,7 +37,6 @@ func (s *NHTSASource) Discover(ctx context.Context, makes []string, years []int)
 				continue
 			}
 			entries = append(entries, found...)
-			time.Sleep(time.Second)
 		}
 	}
 
@@ -63,32 +59,7 @@ type nhtsaRecall struct {
 func (s *NHTSASource) fetchRecalls(ctx context.Context, make_ string, year int) ([]graph.ManualEntry, error) {
 	u := fmt.Sprintf("https://api.nhtsa.gov/recalls/recallsByVehicle?make=%s&modelYear=%d", make_, year)
 
-	result := fn.Retry(ctx, fn.RetryOpts{
-		MaxAttempts: 2,
-		InitialWait: time.Second,
-		MaxWait:     5 * time.Second,
-	}, func(ctx context.Context) fn.Result[[]byte] {
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
-		if err != nil {
-			return fn.Err[[]byte](err)
-		}
-		req.Header.Set("User-Agent", "WessleyBot/1.0")
-		resp, err := s.client.Do(req)
-		if err != nil {
-			return fn.Err[[]byte](err)
-		}
-		defer resp.Body.Close()
-		if resp.StatusCode != http.StatusOK {
-			return fn.Errf[[]byte]("status %d", resp.StatusCode)
-		}
-		body, err := io.ReadAll(io.LimitReader(resp.Body, 5*1024*1024))
-		if err != nil {
-			return fn.Err[[]byte](err)
-		}
-		return fn.Ok(body)
-	})
-
-	body, err := result.Unwrap()
+	body, err := s.fetcher.Get(ctx, u)
 	if err != nil {
 		return nil, err
 	}
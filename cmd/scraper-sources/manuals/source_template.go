@@ -0,0 +1,125 @@
+package manuals
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/WessleyAI/wessley-mvp/engine/graph"
+)
+
+// TemplateSource discovers manuals for one make from a catalog SourceDef:
+// every model's URL templates for each year it was sold, plus the PDF links
+// on the make's per-year index page.
+type TemplateSource struct {
+	def     SourceDef
+	fetcher *Fetcher
+	links   *regexp.Regexp
+}
+
+// NewTemplateSource creates a TemplateSource. def must come from
+// LoadCatalog, which validates it.
+func NewTemplateSource(def SourceDef, f *Fetcher) *TemplateSource {
+	s := &TemplateSource{def: def, fetcher: f, links: pdfLinkRegex}
+	if def.Index != nil && def.Index.Pattern != "" {
+		s.links = regexp.MustCompile(def.Index.Pattern)
+	}
+	return s
+}
+
+func (s *TemplateSource) Name() string { return s.def.Name }
+
+func (s *TemplateSource) Discover(ctx context.Context, makes []string, years []int) ([]graph.ManualEntry, error) {
+	if !containsIgnoreCase(makes, s.def.Make) {
+		return nil, nil
+	}
+
+	var entries []graph.ManualEntry
+	for _, year := range years {
+		for _, model := range s.def.Models {
+			if (model.From != 0 && year < model.From) || (model.To != 0 && year > model.To) {
+				continue
+			}
+			for _, tmpl := range s.def.URLTemplates {
+				select {
+				case <-ctx.Done():
+					return entries, ctx.Err()
+				default:
+				}
+
+				url := s.expand(tmpl, year, model.Slug)
+				if s.def.Verify == VerifyHead && !s.verify(ctx, url) {
+					continue
+				}
+				name := model.Name
+				if name == "" {
+					name = normModel(model.Slug)
+				}
+				entries = append(entries, s.entry(url, name, year))
+			}
+		}
+
+		if s.def.Index == nil {
+			continue
+		}
+		pageURL := s.expand(s.def.Index.URL, year, "")
+		found, err := s.discoverFromPage(ctx, pageURL, year)
+		if err != nil {
+			log.Printf("%s: page crawl %d: %v", s.def.Name, year, err)
+		} else {
+			entries = append(entries, found...)
+		}
+	}
+
+	return dedup(entries), nil
+}
+
+func (s *TemplateSource) expand(tmpl string, year int, model string) string {
+	return strings.NewReplacer(
+		"{year}", strconv.Itoa(year),
+		"{model}", model,
+		"{make}", strings.ToLower(s.def.Make),
+	).Replace(tmpl)
+}
+
+// verify reports whether url answers a HEAD request. Failures other than
+// a plain 404 are logged, since they hide whether the manual exists.
+func (s *TemplateSource) verify(ctx context.Context, url string) bool {
+	_, err := s.fetcher.Head(ctx, url)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		log.Printf("%s: verify %s: %v", s.def.Name, url, err)
+	}
+	return err == nil
+}
+
+func (s *TemplateSource) entry(url, model string, year int) graph.ManualEntry {
+	return graph.ManualEntry{
+		ID:           graph.ManualEntryID(url),
+		URL:          url,
+		SourceSite:   s.def.Site,
+		Make:         s.def.Make,
+		Model:        model,
+		Year:         year,
+		ManualType:   s.def.ManualType,
+		Language:     s.def.Language,
+		Status:       "discovered",
+		DiscoveredAt: time.Now(),
+	}
+}
+
+func (s *TemplateSource) discoverFromPage(ctx context.Context, pageURL string, year int) ([]graph.ManualEntry, error) {
+	body, err := s.fetcher.Get(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := extractLinks(s.links, string(body), s.def.Site, s.def.Make, year)
+	for i := range entries {
+		entries[i].Language = s.def.Language
+	}
+	return entries, nil
+}
@@ -3,27 +3,22 @@ package manuals
 import (
 	"context"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/WessleyAI/wessley-mvp/engine/graph"
-	"github.com/WessleyAI/wessley-mvp/pkg/fn"
 )
 
 // ToyotaSource discovers owner manuals from Toyota's website.
 type ToyotaSource struct {
-	client *http.Client
+	fetcher *Fetcher
 }
 
 // NewToyotaSource creates a new ToyotaSource.
-func NewToyotaSource() *ToyotaSource {
-	return &ToyotaSource{
-		client: &http.Client{Timeout: 30 * time.Second},
-	}
+func NewToyotaSource(f *Fetcher) *ToyotaSource {
+	return &ToyotaSource{fetcher: f}
 }
 
 func (s *ToyotaSource) Name() string { return "toyota" }
@@ -77,40 +72,13 @@ func (s *ToyotaSource) Discover(ctx context.Context, makes []string, years []int
 		} else {
 			entries = append(entries, found...)
 		}
-
-		time.Sleep(time.Second) // Rate limit between years
 	}
 
 	return entries, nil
 }
 
 func (s *ToyotaSource) discoverFromPage(ctx context.Context, pageURL string, year int) ([]graph.ManualEntry, error) {
-	result := fn.Retry(ctx, fn.RetryOpts{
-		MaxAttempts: 2,
-		InitialWait: time.Second,
-		MaxWait:     5 * time.Second,
-	}, func(ctx context.Context) fn.Result[[]byte] {
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
-		if err != nil {
-			return fn.Err[[]byte](err)
-		}
-		req.Header.Set("User-Agent", "WessleyBot/1.0")
-		resp, err := s.client.Do(req)
-		if err != nil {
-			return fn.Err[[]byte](err)
-		}
-		defer resp.Body.Close()
-		if resp.StatusCode != http.StatusOK {
-			return fn.Errf[[]byte]("status %d", resp.StatusCode)
-		}
-		body, err := io.ReadAll(io.LimitReader(resp.Body, 2*1024*1024))
-		if err != nil {
-			return fn.Err[[]byte](err)
-		}
-		return fn.Ok(body)
-	})
-
-	body, err := result.Unwrap()
+	body, err := s.fetcher.Get(ctx, pageURL)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,217 @@
+package manuals
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/WessleyAI/wessley-mvp/engine/graph"
+	"github.com/WessleyAI/wessley-mvp/engine/manual"
+)
+
+// tableMapper turns one recognized table shape into spec rows. The first
+// column names the item; column maps every other header to a spec key and
+// the unit its bare numbers are in, or "" to drop the column.
+type tableMapper struct {
+	kind   string
+	match  func(headers []string) bool
+	column func(header string) (key, unit string)
+}
+
+// tableMappers are tried in order; the first that matches a table wins.
+var tableMappers = []tableMapper{
+	{kind: "torque", match: matchTorque, column: torqueColumn},
+	{kind: "fluid_capacity", match: matchCapacity, column: capacityColumn},
+	{kind: "bulb", match: matchBulb, column: bulbColumn},
+}
+
+var (
+	torqueUnitPattern = regexp.MustCompile(`(?i)\b(?:n[·.\-\s]?m|lbf?[·.\-\s]?ft|ft[·.\-\s]?lbs?f?|lbf?[·.\-\s]?in|in[·.\-\s]?lbs?|kgf?[·.\-\s]?m)\b`)
+	volumeUnitPattern = regexp.MustCompile(`(?i)\b(?:l|liters?|litres?|qts?|quarts?|us\s*qt|imp\s*qt|gal|gallons?|ml|oz|fl\.?\s*oz)\b`)
+	headerUnitPattern = regexp.MustCompile(`\(([^)]+)\)`)
+	numericCell       = regexp.MustCompile(`^[\d\s.,~\-–/]+$`)
+)
+
+func anyHeader(headers []string, pred func(h string) bool) bool {
+	for _, h := range headers {
+		if pred(strings.ToLower(h)) {
+			return true
+		}
+	}
+	return false
+}
+
+// headerUnit returns the unit a header declares, "Capacity (US qt)" or "N·m".
+func headerUnit(h string, unit *regexp.Regexp) string {
+	if m := headerUnitPattern.FindStringSubmatch(h); len(m) > 1 && unit.MatchString(m[1]) {
+		return strings.TrimSpace(m[1])
+	}
+	if unit.FindString(h) == strings.TrimSpace(h) {
+		return strings.TrimSpace(h)
+	}
+	return ""
+}
+
+func matchTorque(headers []string) bool {
+	return anyHeader(headers, func(h string) bool {
+		return strings.Contains(h, "torque") || headerUnit(h, torqueUnitPattern) != ""
+	})
+}
+
+func torqueColumn(h string) (string, string) {
+	lh := strings.ToLower(h)
+	switch {
+	case strings.Contains(lh, "torque") || headerUnit(h, torqueUnitPattern) != "":
+		return "torque", headerUnit(h, torqueUnitPattern)
+	case strings.Contains(lh, "note") || strings.Contains(lh, "remark"):
+		return "note", ""
+	}
+	return "", ""
+}
+
+func matchCapacity(headers []string) bool {
+	return anyHeader(headers, func(h string) bool {
+		return strings.Contains(h, "capacit") || headerUnit(h, volumeUnitPattern) != ""
+	})
+}
+
+func capacityColumn(h string) (string, string) {
+	lh := strings.ToLower(h)
+	switch {
+	case strings.Contains(lh, "capacit") || headerUnit(h, volumeUnitPattern) != "":
+		return "capacity", headerUnit(h, volumeUnitPattern)
+	case strings.Contains(lh, "type"), strings.Contains(lh, "specification"), strings.Contains(lh, "grade"),
+		strings.Contains(lh, "recommended"), strings.Contains(lh, "viscosity"), strings.Contains(lh, "fluid"):
+		return "fluid_type", ""
+	}
+	return "", ""
+}
+
+func matchBulb(headers []string) bool {
+	return anyHeader(headers, func(h string) bool {
+		return strings.Contains(h, "bulb") || strings.Contains(h, "watt") || strings.Contains(h, "trade")
+	})
+}
+
+func bulbColumn(h string) (string, string) {
+	lh := strings.ToLower(strings.TrimSpace(h))
+	switch {
+	case strings.Contains(lh, "watt") || lh == "w":
+		return "wattage", "W"
+	case strings.Contains(lh, "qty") || strings.Contains(lh, "quantity"):
+		return "quantity", ""
+	case strings.Contains(lh, "bulb") || strings.Contains(lh, "trade") || strings.Contains(lh, "type") || strings.Contains(lh, "no."):
+		return "bulb", ""
+	}
+	return "", ""
+}
+
+// MapTables turns the tables a mapper recognizes into spec rows.
+func MapTables(tables []manual.Table) []graph.ManualSpec {
+	var specs []graph.ManualSpec
+	for _, t := range tables {
+		if len(t.Headers) < 2 {
+			continue
+		}
+		for _, m := range tableMappers {
+			if !m.match(t.Headers[1:]) {
+				continue
+			}
+			specs = append(specs, m.apply(t)...)
+			break
+		}
+	}
+	return specs
+}
+
+func (m tableMapper) apply(t manual.Table) []graph.ManualSpec {
+	keys := make([]string, len(t.Headers))
+	units := make([]string, len(t.Headers))
+	for i := 1; i < len(t.Headers); i++ {
+		keys[i], units[i] = m.column(t.Headers[i])
+	}
+
+	var specs []graph.ManualSpec
+	for _, row := range t.Rows {
+		item := strings.TrimSpace(row[0])
+		if item == "" {
+			continue
+		}
+		values := map[string]string{}
+		for i := 1; i < len(row) && i < len(keys); i++ {
+			v := strings.TrimSpace(row[i])
+			if keys[i] == "" || v == "" || v == "-" || v == "—" {
+				continue
+			}
+			if units[i] != "" && numericCell.MatchString(v) {
+				v += " " + units[i]
+			}
+			if prev, ok := values[keys[i]]; ok {
+				v = prev + " / " + v
+			}
+			values[keys[i]] = v
+		}
+		if len(values) == 0 {
+			continue
+		}
+		specs = append(specs, graph.ManualSpec{Kind: m.kind, Item: item, Values: values, Page: t.Page})
+	}
+	return specs
+}
+
+// attachSpecs adds each spec row as a component with specs to the section
+// whose page range covers the row's page, merging with a component of the
+// same name the text extractor already found.
+func attachSpecs(sections []graph.ManualSection, specs []graph.ManualSpec) {
+	for _, spec := range specs {
+		for i := range sections {
+			if !pageInRange(sections[i].PageRange, spec.Page) {
+				continue
+			}
+			sec := &sections[i]
+			name := manual.NormalizeComponentName(spec.Item)
+			merged := false
+			for j := range sec.Components {
+				c := &sec.Components[j]
+				if !strings.EqualFold(c.Name, name) {
+					continue
+				}
+				if c.Specs == nil {
+					c.Specs = map[string]string{}
+				}
+				for k, v := range spec.Values {
+					c.Specs[k] = v
+				}
+				merged = true
+				break
+			}
+			if !merged {
+				vals := make(map[string]string, len(spec.Values))
+				for k, v := range spec.Values {
+					vals[k] = v
+				}
+				sec.Components = append(sec.Components, graph.ExtractedComponent{Name: name, Type: spec.Kind, Specs: vals})
+			}
+			break
+		}
+	}
+}
+
+// pageInRange reports whether page falls within a "5" or "5-7" page range.
+func pageInRange(r string, page int) bool {
+	if r == "" || page == 0 {
+		return false
+	}
+	first, last, found := strings.Cut(r, "-")
+	lo, err := strconv.Atoi(first)
+	if err != nil {
+		return false
+	}
+	hi := lo
+	if found {
+		if hi, err = strconv.Atoi(last); err != nil {
+			return false
+		}
+	}
+	return page >= lo && page <= hi
+}
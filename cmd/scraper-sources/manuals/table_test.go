@@ -0,0 +1,84 @@
+package manuals
+
+import (
+	"testing"
+
+	"github.com/WessleyAI/wessley-mvp/engine/graph"
+	"github.com/WessleyAI/wessley-mvp/engine/manual"
+	"github.com/WessleyAI/wessley-mvp/pkg/pdf"
+)
+
+// row lays out cells at the given x positions on baseline y, 10pt text
+// whose width is 5pt per character.
+func row(y float64, cells map[float64]string) []pdf.TextRun {
+	var runs []pdf.TextRun
+	for x, text := range cells {
+		runs = append(runs, pdf.TextRun{Text: text, X: x, Y: y, W: 5 * float64(len(text)), Size: 10})
+	}
+	return runs
+}
+
+func TestMapTables(t *testing.T) {
+	tables := []manual.Table{
+		{Headers: []string{"Item", "N·m", "lbf·ft"}, Rows: [][]string{{"Wheel lug nuts", "108", "80"}}, Page: 7},
+		{Headers: []string{"Item", "Capacity (US qt)", "Specification"}, Rows: [][]string{{"Engine oil with filter", "4.4", "0W-20"}, {"", "", ""}}, Page: 8},
+		{Headers: []string{"Light", "Bulb No.", "Wattage"}, Rows: [][]string{{"Headlight low beam", "H11", "55"}}, Page: 9},
+		{Headers: []string{"Symptom", "Cause"}, Rows: [][]string{{"No start", "Dead battery"}}, Page: 10},
+	}
+	specs := MapTables(tables)
+	if len(specs) != 3 {
+		t.Fatalf("expected 3 specs, got %+v", specs)
+	}
+	want := []struct {
+		kind, key, value string
+	}{
+		{"torque", "torque", "108 N·m / 80 lbf·ft"},
+		{"fluid_capacity", "capacity", "4.4 US qt"},
+		{"bulb", "wattage", "55 W"},
+	}
+	for i, w := range want {
+		if specs[i].Kind != w.kind || specs[i].Values[w.key] != w.value {
+			t.Errorf("spec %d = %+v, want %s %s=%q", i, specs[i], w.kind, w.key, w.value)
+		}
+	}
+	if specs[1].Values["fluid_type"] != "0W-20" || specs[2].Values["bulb"] != "H11" {
+		t.Errorf("secondary columns not mapped: %+v %+v", specs[1], specs[2])
+	}
+}
+
+func TestAttachSpecs(t *testing.T) {
+	sections := []graph.ManualSection{
+		{Title: "Engine", PageRange: "1-6", Components: []graph.ExtractedComponent{{Name: "Oil Filter"}}},
+		{Title: "Specifications", PageRange: "7-9"},
+	}
+	attachSpecs(sections, []graph.ManualSpec{
+		{Kind: "torque", Item: "wheel lug nuts", Values: map[string]string{"torque": "108 N·m"}, Page: 7},
+		{Kind: "fluid_capacity", Item: "Oil filter", Values: map[string]string{"capacity": "0.2 L"}, Page: 3},
+		{Kind: "bulb", Item: "Fog light", Values: map[string]string{"bulb": "H8"}, Page: 40},
+	})
+	if c := sections[0].Components; len(c) != 1 || c[0].Specs["capacity"] != "0.2 L" {
+		t.Errorf("spec not merged into existing component: %+v", c)
+	}
+	if c := sections[1].Components; len(c) != 1 || c[0].Name != "Wheel Lug Nuts" || c[0].Type != "torque" {
+		t.Errorf("spec not attached to covering section: %+v", c)
+	}
+}
+
+func TestBySection(t *testing.T) {
+	sections := []graph.ManualSection{{Title: "Engine", PageRange: "1-6"}, {Title: "Specifications", PageRange: "7-9"}}
+	fuses := []graph.FuseEntry{{Position: "F1", Page: 8}, {Position: "F2", Page: 2}, {Position: "F3", Page: 9}, {Position: "F4"}}
+	groups := bySection(sections, "2020_Toyota_Camry", fuses, func(f graph.FuseEntry) int { return f.Page })
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 section groups, got %+v", groups)
+	}
+	if g := groups[0]; g.docID != "manual:2020_Toyota_Camry-sec-1" || len(g.items) != 2 || g.items[1].Position != "F3" {
+		t.Errorf("unexpected first group %+v", g)
+	}
+	if g := groups[1]; g.docID != "manual:2020_Toyota_Camry-sec-0" || len(g.items) != 2 || g.items[1].Position != "F4" {
+		t.Errorf("items without a covering section should go to the first: %+v", g)
+	}
+
+	if groups := bySection(nil, "x", fuses, func(f graph.FuseEntry) int { return f.Page }); len(groups) != 1 || groups[0].docID != "" {
+		t.Errorf("without sections the items should form one group: %+v", groups)
+	}
+}
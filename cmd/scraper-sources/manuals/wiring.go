@@ -0,0 +1,244 @@
+package manuals
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/WessleyAI/wessley-mvp/engine/graph"
+	"github.com/WessleyAI/wessley-mvp/engine/manual"
+)
+
+// Wire color codes as printed in wiring diagrams: "BK", "BLK/WHT", "LG-BK",
+// "W-B". Single letters are only accepted in pairs.
+const (
+	colorList  = `BLK|BK|WHT|WH|RED|RD|GRN|GN|BLU|BU|BL|YEL|YE|YL|BRN|BN|BR|GRY|GY|GR|ORN|OG|OR|PNK|PK|VIO|VT|VI|PPL|PU|LG|DG|LB|DB|TAN|TN|NCA|NA|SR`
+	colorCode  = `(?:` + colorList + `)`
+	colorShort = `(?:[BWRGLYOPV]|` + colorList + `)`
+	colorWord  = `(?:(?:lt|dk|light|dark)\s*)?(?:black|white|red|green|blue|yellow|brown|gr[ae]y|orange|pink|violet|purple|tan)`
+)
+
+var (
+	colorCodes = regexp.MustCompile(`^(?:` + colorCode + `(?:[/-]` + colorShort + `)?|` + colorShort + `[/-]` + colorShort + `)$`)
+	colorWords = regexp.MustCompile(`(?i)^` + colorWord + `(?:\s*/\s*` + colorWord + `)?$`)
+
+	gaugeWithUnit = regexp.MustCompile(`(?i)\(?\b(\d{1,2}(?:\.\d{1,2})?)\s*(awg|ga\b|gauge|mm²|mm2|sq\.?\s*mm)\)?`)
+	bareGauge     = regexp.MustCompile(`^\(?(\d{1,2}(?:\.\d{1,2})?)\)?$`)
+
+	// wireSeparator splits a wiring diagram line into ends and wires:
+	// "Fuel pump relay pin 87 - GRN/WHT 0.5 - C101 pin 3".
+	wireSeparator = regexp.MustCompile(`(?i)\s+(?:->|→|—|–|-{1,2}|to)\s+`)
+	wireEndPin    = regexp.MustCompile(`(?i)^(.+?)[\s,(]+(?:pin|terminal|term\.|cavity)\s*#?\s*([a-z]?\d{1,3}[a-z]?)\)?$`)
+
+	connectorHeading = regexp.MustCompile(`(?i)^(?:connector\s+)?([cx]\d{1,4}[a-z]?)\b`)
+	connectorCaption = regexp.MustCompile(`(?i)\b(?:connector\s+)?([cx]\d{1,4}[a-z]?)\b`)
+	pinoutLine       = regexp.MustCompile(`(?i)^(?:pin|terminal|cavity)?\s*([a-z]?\d{1,3}[a-z]?)\s+(.+)$`)
+	wiringPage       = regexp.MustCompile(`(?i)wir(?:e|ing)|connector|pinout|terminal`)
+)
+
+// parseWire reads a wire description such as "GRN/WHT 0.5", "18 AWG BK"
+// or "(0.75) Y-G", reporting false unless it holds exactly one color and
+// nothing but a gauge besides.
+func parseWire(s string) (color, gauge string, ok bool) {
+	if m := gaugeWithUnit.FindStringSubmatch(s); m != nil {
+		unit := strings.ToLower(m[2])
+		switch {
+		case unit == "ga" || unit == "gauge" || unit == "awg":
+			unit = "AWG"
+		default:
+			unit = "mm²"
+		}
+		gauge = m[1] + " " + unit
+		s = strings.Replace(s, m[0], " ", 1)
+	}
+	var words []string
+	for _, f := range strings.Fields(s) {
+		if m := bareGauge.FindStringSubmatch(f); m != nil && gauge == "" {
+			gauge = m[1]
+			continue
+		}
+		words = append(words, f)
+	}
+	color = strings.Join(words, " ")
+	switch {
+	case colorCodes.MatchString(color):
+	case colorWords.MatchString(color):
+		color = strings.ToLower(color)
+	default:
+		return "", "", false
+	}
+	return color, gauge, true
+}
+
+// parseWireEnd splits "C101 pin 3" or "Fuel pump (terminal 1)" into the
+// component and pin.
+func parseWireEnd(s string) (name, pin string, ok bool) {
+	s = strings.TrimSpace(s)
+	name = s
+	if m := wireEndPin.FindStringSubmatch(s); m != nil {
+		name, pin = strings.TrimSpace(m[1]), strings.ToUpper(m[2])
+	}
+	if name == "" || len(name) > 60 || !strings.ContainsAny(strings.ToLower(name), "abcdefghijklmnopqrstuvwxyz") {
+		return "", "", false
+	}
+	return name, pin, true
+}
+
+// wiringLine reads a wiring diagram line alternating ends and wires, e.g.
+// "Fuel pump relay pin 87 - GRN/WHT 0.5 - C101 pin 3 - GRN/WHT - Fuel pump".
+func wiringLine(line string) []graph.WireRun {
+	parts := wireSeparator.Split(strings.TrimSpace(line), -1)
+	if len(parts) < 3 || len(parts)%2 == 0 {
+		return nil
+	}
+	var runs []graph.WireRun
+	from, fromPin, ok := parseWireEnd(parts[0])
+	if !ok {
+		return nil
+	}
+	for i := 1; i+1 < len(parts); i += 2 {
+		color, gauge, ok := parseWire(parts[i])
+		if !ok {
+			return nil
+		}
+		to, toPin, ok := parseWireEnd(parts[i+1])
+		if !ok {
+			return nil
+		}
+		runs = append(runs, graph.WireRun{From: from, FromPin: fromPin, To: to, ToPin: toPin, Color: color, Gauge: gauge})
+		from, fromPin = to, toPin
+	}
+	return runs
+}
+
+// pinoutTextLine reads a pin of a connector pinout printed as text, e.g.
+// "3 BLK/WHT 18 AWG Fuel pump ground". The longest leading run of words
+// that reads as a wire is the wire; the rest is the pin's circuit.
+func pinoutTextLine(connector, line string) (graph.WireRun, bool) {
+	m := pinoutLine.FindStringSubmatch(line)
+	if m == nil {
+		return graph.WireRun{}, false
+	}
+	words := strings.Fields(m[2])
+	for n := len(words); n > 0; n-- {
+		color, gauge, ok := parseWire(strings.Join(words[:n], " "))
+		if !ok {
+			continue
+		}
+		return graph.WireRun{
+			From: connector, FromPin: strings.ToUpper(m[1]),
+			Color: color, Gauge: gauge, Circuit: strings.Join(words[n:], " "),
+		}, true
+	}
+	return graph.WireRun{}, false
+}
+
+// pinoutColumns locates the columns of a connector pinout table.
+type pinoutColumns struct {
+	pin, color, gauge, circuit, to int
+}
+
+func matchPinoutTable(headers []string) (pinoutColumns, bool) {
+	cols := pinoutColumns{pin: -1, color: -1, gauge: -1, circuit: -1, to: -1}
+	for i, h := range headers {
+		lh := strings.ToLower(strings.TrimSpace(h))
+		switch {
+		case cols.pin < 0 && (strings.Contains(lh, "pin") || strings.Contains(lh, "terminal") || strings.Contains(lh, "cavity")):
+			cols.pin = i
+		case strings.Contains(lh, "gauge") || strings.Contains(lh, "size") || strings.Contains(lh, "awg") || strings.Contains(lh, "(mm"):
+			cols.gauge = i
+		case strings.Contains(lh, "color") || strings.Contains(lh, "colour") || lh == "wire":
+			cols.color = i
+		case lh == "to" || strings.Contains(lh, "destination") || strings.Contains(lh, "connects to") || strings.Contains(lh, "goes to"):
+			cols.to = i
+		case strings.Contains(lh, "circuit") || strings.Contains(lh, "function") || strings.Contains(lh, "signal") ||
+			strings.Contains(lh, "description"):
+			cols.circuit = i
+		}
+	}
+	return cols, cols.pin >= 0 && (cols.color >= 0 || cols.to >= 0)
+}
+
+// ExtractWiring reads wires from connector pinout tables, whose caption
+// names the connector, and from the wiring diagram lines and text pinouts
+// on pages about wiring. text holds the page texts separated by form feeds.
+func ExtractWiring(text string, tables []manual.Table) []graph.WireRun {
+	var wires []graph.WireRun
+	for _, t := range tables {
+		cols, ok := matchPinoutTable(t.Headers)
+		if !ok {
+			continue
+		}
+		m := connectorCaption.FindStringSubmatch(t.Caption)
+		if m == nil {
+			continue
+		}
+		for _, row := range t.Rows {
+			if w, ok := pinoutRow(strings.ToUpper(m[1]), row, cols); ok {
+				w.Page = t.Page
+				wires = append(wires, w)
+			}
+		}
+	}
+
+	for i, page := range strings.Split(text, "\f") {
+		if !wiringPage.MatchString(page) {
+			continue
+		}
+		connector := ""
+		for _, line := range strings.Split(page, "\n") {
+			line = strings.TrimSpace(line)
+			if runs := wiringLine(line); runs != nil {
+				for _, w := range runs {
+					w.Page = i + 1
+					wires = append(wires, w)
+				}
+				connector = ""
+				continue
+			}
+			if connector != "" {
+				if w, ok := pinoutTextLine(connector, line); ok {
+					w.Page = i + 1
+					wires = append(wires, w)
+					continue
+				}
+			}
+			connector = ""
+			if m := connectorHeading.FindStringSubmatch(line); m != nil {
+				connector = strings.ToUpper(m[1])
+			}
+		}
+	}
+	return wires
+}
+
+// pinoutRow reads one row of a pinout table of connector.
+func pinoutRow(connector string, row []string, cols pinoutColumns) (graph.WireRun, bool) {
+	cell := func(i int) string {
+		if i >= 0 && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+	pin := cell(cols.pin)
+	if pin == "" {
+		return graph.WireRun{}, false
+	}
+	w := graph.WireRun{From: connector, FromPin: strings.ToUpper(pin), Circuit: cell(cols.circuit)}
+	if raw := cell(cols.color); raw != "" {
+		if color, gauge, ok := parseWire(raw); ok {
+			w.Color, w.Gauge = color, gauge
+		} else {
+			w.Color = raw
+		}
+	}
+	if g := cell(cols.gauge); g != "" {
+		w.Gauge = g
+	}
+	if to := cell(cols.to); to != "" && to != "-" && to != "—" {
+		w.To, w.ToPin, _ = parseWireEnd(to)
+	}
+	if w.Color == "" && w.To == "" && w.Circuit == "" {
+		return graph.WireRun{}, false
+	}
+	return w, true
+}
@@ -0,0 +1,77 @@
+package manuals
+
+import (
+	"testing"
+
+	"github.com/WessleyAI/wessley-mvp/engine/manual"
+	"github.com/WessleyAI/wessley-mvp/pkg/pdf"
+)
+
+func TestParseWire(t *testing.T) {
+	tests := []struct {
+		in, color, gauge string
+		ok               bool
+	}{
+		{"GRN/WHT 0.5", "GRN/WHT", "0.5", true},
+		{"18 AWG BK", "BK", "18 AWG", true},
+		{"(0.75) Y-G", "Y-G", "0.75", true},
+		{"Light Green/Black 0.35 mm2", "light green/black", "0.35 mm²", true},
+		{"R", "", "", false},
+		{"Remove the bolt", "", "", false},
+	}
+	for _, tt := range tests {
+		color, gauge, ok := parseWire(tt.in)
+		if color != tt.color || gauge != tt.gauge || ok != tt.ok {
+			t.Errorf("parseWire(%q) = %q, %q, %v; want %q, %q, %v", tt.in, color, gauge, ok, tt.color, tt.gauge, tt.ok)
+		}
+	}
+}
+
+func TestExtractWiring_Text(t *testing.T) {
+	text := "Contents\n\f\n" +
+		"FUEL PUMP WIRING DIAGRAM\n" +
+		"Fuel pump relay pin 87 - GRN/WHT 0.5 - C101 pin 3 - GRN/WHT 0.5 - Fuel pump (terminal 1)\n" +
+		"Connector C220\n" +
+		"Pin 1 BLK 18 AWG Ground\n" +
+		"2 YEL/RED Radio power\n" +
+		"Route the harness to the bracket."
+	wires := ExtractWiring(text, nil)
+	if len(wires) != 4 {
+		t.Fatalf("expected 4 wires, got %+v", wires)
+	}
+	w := wires[0]
+	if w.From != "Fuel pump relay" || w.FromPin != "87" || w.To != "C101" || w.ToPin != "3" || w.Color != "GRN/WHT" || w.Gauge != "0.5" || w.Page != 2 {
+		t.Errorf("unexpected first wire %+v", w)
+	}
+	if w := wires[1]; w.From != "C101" || w.FromPin != "3" || w.To != "Fuel pump" || w.ToPin != "1" {
+		t.Errorf("unexpected second wire %+v", w)
+	}
+	if w := wires[2]; w.From != "C220" || w.FromPin != "1" || w.Color != "BLK" || w.Gauge != "18 AWG" || w.Circuit != "Ground" || w.To != "" {
+		t.Errorf("unexpected pinout wire %+v", w)
+	}
+	if w := wires[3]; w.FromPin != "2" || w.Color != "YEL/RED" || w.Circuit != "Radio power" {
+		t.Errorf("unexpected pinout wire %+v", w)
+	}
+}
+
+func TestExtractWiring_PinoutTable(t *testing.T) {
+	var runs []pdf.TextRun
+	runs = append(runs, row(700, map[float64]string{72: "Connector C101 (Engine harness to body harness)"})...)
+	runs = append(runs, row(670, map[float64]string{72: "Pin", 120: "Wire Color", 220: "Gauge", 300: "Circuit", 420: "To"})...)
+	runs = append(runs, row(656, map[float64]string{72: "3", 120: "GN/WH", 220: "0.5", 300: "Fuel pump feed", 420: "Fuel pump pin 1"})...)
+	runs = append(runs, row(642, map[float64]string{72: "4", 120: "BK", 220: "1.0", 300: "Ground", 420: "G101"})...)
+	tables := manual.DetectTables(pdf.Page{Number: 88, Runs: runs})
+
+	wires := ExtractWiring("", tables)
+	if len(wires) != 2 {
+		t.Fatalf("expected 2 wires, got %+v (tables %+v)", wires, tables)
+	}
+	w := wires[0]
+	if w.From != "C101" || w.FromPin != "3" || w.Color != "GN/WH" || w.Gauge != "0.5" || w.Circuit != "Fuel pump feed" ||
+		w.To != "Fuel pump" || w.ToPin != "1" || w.Page != 88 {
+		t.Errorf("unexpected wire %+v", w)
+	}
+	if w := wires[1]; w.To != "G101" || w.ToPin != "" {
+		t.Errorf("unexpected wire %+v", w)
+	}
+}
@@ -0,0 +1,304 @@
+package nhtsa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/WessleyAI/wessley-mvp/engine/graph"
+	"github.com/WessleyAI/wessley-mvp/engine/scraper"
+	"github.com/WessleyAI/wessley-mvp/pkg/fn"
+)
+
+const (
+	recallsURL        = "https://api.nhtsa.gov/recalls/recallsByVehicle"
+	investigationsURL = "https://api.nhtsa.gov/investigations/investigationsByVehicle"
+)
+
+// Recall is a raw NHTSA recall record.
+type Recall struct {
+	Campaign           string `json:"NHTSACampaignNumber"`
+	ActionNumber       string `json:"NHTSAActionNumber"`
+	Manufacturer       string `json:"Manufacturer"`
+	ReportReceivedDate string `json:"ReportReceivedDate"` // "dd/mm/yyyy"
+	Component          string `json:"Component"`
+	Summary            string `json:"Summary"`
+	Consequence        string `json:"Consequence"`
+	Remedy             string `json:"Remedy"`
+	Notes              string `json:"Notes"`
+	ParkIt             bool   `json:"parkIt"`
+	ParkOutSide        bool   `json:"parkOutSide"`
+	PotentialUnits     int    `json:"PotentialNumberofUnitsAffected"`
+}
+
+// Investigation is a raw NHTSA Office of Defects Investigation (ODI) record.
+type Investigation struct {
+	ActionNumber string `json:"nhtsaActionNumber"` // e.g. "PE24012"
+	Type         string `json:"investigationType"` // PE, EA, RQ, DP
+	Manufacturer string `json:"manufacturer"`
+	Component    string `json:"component"`
+	Subject      string `json:"subject"`
+	Summary      string `json:"summary"`
+	OpenDate     string `json:"openDate"`
+	CloseDate    string `json:"closeDate"`
+	Status       string `json:"status"`
+}
+
+type recallsResponse struct {
+	Count   int      `json:"Count"`
+	Results []Recall `json:"results"`
+}
+
+type investigationsResponse struct {
+	Count   int             `json:"count"`
+	Results []Investigation `json:"results"`
+}
+
+// RecallScraper fetches safety recalls and defect investigations for the
+// same makes, models and years as Scraper.
+type RecallScraper struct {
+	*Scraper
+	investigations bool
+}
+
+// NewRecallScraper creates a RecallScraper. With investigations set it also
+// queries ODI investigations for each model.
+func NewRecallScraper(cfg Config, investigations bool) *RecallScraper {
+	return &RecallScraper{Scraper: NewScraper(cfg), investigations: investigations}
+}
+
+// FetchAll scrapes recalls (and investigations) for all configured makes and
+// returns one ScrapedPost per campaign and vehicle.
+func (s *RecallScraper) FetchAll(ctx context.Context) ([]scraper.ScrapedPost, error) {
+	var allPosts []scraper.ScrapedPost
+	limiter := scraper.RateLimiter(ctx, s.cfg.RateLimit)
+
+	for _, year := range s.cfg.Years() {
+		for _, make_ := range s.cfg.Makes {
+			select {
+			case <-ctx.Done():
+				return allPosts, ctx.Err()
+			default:
+			}
+
+			models, err := s.fetchModels(ctx, make_, year, limiter)
+			if err != nil {
+				log.Printf("warning: failed to fetch NHTSA models for %s %d: %v", make_, year, err)
+				continue
+			}
+			if len(models) > 5 {
+				models = models[:5]
+			}
+
+			for _, model := range models {
+				posts, err := s.fetchRecalls(ctx, make_, model, year, limiter)
+				if err != nil {
+					log.Printf("warning: failed to fetch NHTSA recalls for %s %s %d: %v", make_, model, year, err)
+				}
+				allPosts = append(allPosts, posts...)
+
+				if !s.investigations {
+					continue
+				}
+				posts, err = s.fetchInvestigations(ctx, make_, model, year, limiter)
+				if err != nil {
+					log.Printf("warning: failed to fetch NHTSA investigations for %s %s %d: %v", make_, model, year, err)
+				}
+				allPosts = append(allPosts, posts...)
+			}
+		}
+	}
+	return allPosts, nil
+}
+
+func (s *RecallScraper) fetchRecalls(ctx context.Context, make_, model string, year int, limiter *rate.Limiter) ([]scraper.ScrapedPost, error) {
+	url := fmt.Sprintf("%s?make=%s&model=%s&modelYear=%d", recallsURL, neturl.QueryEscape(make_), neturl.QueryEscape(model), year)
+	var resp recallsResponse
+	if err := s.getJSON(ctx, url, limiter, &resp); err != nil {
+		return nil, fmt.Errorf("nhtsa recalls %s %s: %w", make_, model, err)
+	}
+
+	now := time.Now().UTC()
+	vehicle := fmt.Sprintf("%d %s %s", year, make_, model)
+	posts := make([]scraper.ScrapedPost, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		if r.Campaign == "" {
+			continue
+		}
+		published := parseRecallDate(r.ReportReceivedDate)
+		rec := &scraper.Recall{
+			Campaign:     r.Campaign,
+			Kind:         graph.RecallKindRecall,
+			Component:    r.Component,
+			Summary:      r.Summary,
+			Consequence:  r.Consequence,
+			Remedy:       r.Remedy,
+			Manufacturer: r.Manufacturer,
+			Units:        r.PotentialUnits,
+			URL:          "https://www.nhtsa.gov/recalls?nhtsaId=" + r.Campaign,
+		}
+		if !published.IsZero() {
+			rec.Date = published.Format("2006-01-02")
+		}
+
+		var content strings.Builder
+		content.WriteString(r.Summary)
+		for _, part := range []struct{ label, text string }{
+			{"Consequence", r.Consequence}, {"Remedy", r.Remedy}, {"Notes", r.Notes},
+		} {
+			if part.text != "" {
+				fmt.Fprintf(&content, "\n\n%s: %s", part.label, part.text)
+			}
+		}
+		keywords := []string{strings.ToLower(r.Component), "nhtsa", "recall"}
+		if r.ParkIt || r.ParkOutSide {
+			keywords = append(keywords, "do not drive")
+		}
+
+		posts = append(posts, scraper.ScrapedPost{
+			Source:      "nhtsa",
+			SourceID:    fmt.Sprintf("recall-%s-%d-%s-%s", r.Campaign, year, make_, model),
+			Title:       fmt.Sprintf("NHTSA Recall %s: %s - %s", r.Campaign, vehicle, r.Component),
+			Content:     content.String(),
+			Author:      r.Manufacturer,
+			URL:         rec.URL,
+			PublishedAt: published,
+			ScrapedAt:   now,
+			Metadata: scraper.Metadata{
+				Vehicle:     vehicle,
+				VehicleInfo: &scraper.VehicleInfo{Make: make_, Model: model, Year: year},
+				Symptoms:    extractSymptoms(r.Summary + " " + r.Consequence),
+				Fixes:       nonEmpty(r.Remedy),
+				Keywords:    keywords,
+				Components:  r.Component,
+				Recall:      rec,
+			},
+		})
+	}
+	return posts, nil
+}
+
+func (s *RecallScraper) fetchInvestigations(ctx context.Context, make_, model string, year int, limiter *rate.Limiter) ([]scraper.ScrapedPost, error) {
+	url := fmt.Sprintf("%s?make=%s&model=%s&modelYear=%d", investigationsURL, neturl.QueryEscape(make_), neturl.QueryEscape(model), year)
+	var resp investigationsResponse
+	if err := s.getJSON(ctx, url, limiter, &resp); err != nil {
+		return nil, fmt.Errorf("nhtsa investigations %s %s: %w", make_, model, err)
+	}
+
+	now := time.Now().UTC()
+	vehicle := fmt.Sprintf("%d %s %s", year, make_, model)
+	posts := make([]scraper.ScrapedPost, 0, len(resp.Results))
+	for _, inv := range resp.Results {
+		if inv.ActionNumber == "" {
+			continue
+		}
+		published := parseNHTSADate(inv.OpenDate)
+		status := strings.ToLower(inv.Status)
+		if status == "" {
+			status = "open"
+			if inv.CloseDate != "" {
+				status = "closed"
+			}
+		}
+		rec := &scraper.Recall{
+			Campaign:     inv.ActionNumber,
+			Kind:         graph.RecallKindInvestigation,
+			Component:    inv.Component,
+			Summary:      strings.TrimSpace(inv.Subject + ". " + inv.Summary),
+			Manufacturer: inv.Manufacturer,
+			Status:       status,
+			URL:          "https://www.nhtsa.gov/?nhtsaId=" + inv.ActionNumber,
+		}
+		if !published.IsZero() {
+			rec.Date = published.Format("2006-01-02")
+		}
+
+		posts = append(posts, scraper.ScrapedPost{
+			Source:      "nhtsa",
+			SourceID:    fmt.Sprintf("investigation-%s-%d-%s-%s", inv.ActionNumber, year, make_, model),
+			Title:       fmt.Sprintf("NHTSA Investigation %s: %s - %s", inv.ActionNumber, vehicle, inv.Subject),
+			Content:     inv.Summary,
+			Author:      "nhtsa-odi",
+			URL:         rec.URL,
+			PublishedAt: published,
+			ScrapedAt:   now,
+			Metadata: scraper.Metadata{
+				Vehicle:     vehicle,
+				VehicleInfo: &scraper.VehicleInfo{Make: make_, Model: model, Year: year},
+				Symptoms:    extractSymptoms(inv.Subject + " " + inv.Summary),
+				Keywords:    []string{strings.ToLower(inv.Component), "nhtsa", "investigation"},
+				Components:  inv.Component,
+				Recall:      rec,
+			},
+		})
+	}
+	return posts, nil
+}
+
+// getJSON fetches url with retries and decodes the body into v.
+func (s *RecallScraper) getJSON(ctx context.Context, url string, limiter *rate.Limiter, v any) error {
+	result := fn.Retry(ctx, fn.RetryOpts{
+		MaxAttempts: 3,
+		InitialWait: 5 * time.Second,
+		MaxWait:     30 * time.Second,
+		Jitter:      true,
+	}, func(ctx context.Context) fn.Result[[]byte] {
+		if err := limiter.Wait(ctx); err != nil {
+			return fn.Err[[]byte](err)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return fn.Err[[]byte](err)
+		}
+		req.Header.Set("User-Agent", "wessley-scraper/1.0 (automotive repair data collection)")
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return fn.Err[[]byte](err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			return fn.Err[[]byte](fmt.Errorf("http %d from %s", resp.StatusCode, url))
+		}
+		if resp.StatusCode != http.StatusOK {
+			return fn.Err[[]byte](fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url))
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fn.Err[[]byte](fmt.Errorf("read body: %w", err))
+		}
+		return fn.Ok(body)
+	})
+
+	body, err := result.Unwrap()
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+	return nil
+}
+
+// parseRecallDate parses the recalls API's day-first dates, falling back to
+// the complaint formats.
+func parseRecallDate(s string) time.Time {
+	if t, err := time.Parse("02/01/2006", s); err == nil {
+		return t.UTC()
+	}
+	return parseNHTSADate(s)
+}
+
+func nonEmpty(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
@@ -0,0 +1,102 @@
+package nhtsa
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/WessleyAI/wessley-mvp/engine/graph"
+)
+
+func TestRecallScraper_FetchAll(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch {
+		case strings.Contains(r.URL.Path, "models"):
+			json.NewEncoder(w).Encode(modelsResponse{Count: 1, Results: []modelEntry{{Model: "F-150"}}})
+		case strings.Contains(r.URL.Path, "recalls"):
+			if r.URL.Query().Get("model") != "F-150" || r.URL.Query().Get("modelYear") != "2018" {
+				t.Errorf("unexpected recall query %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"Count": 1, "results": [{
+				"Manufacturer": "Ford Motor Company",
+				"NHTSACampaignNumber": "18V123000",
+				"ReportReceivedDate": "01/03/2018",
+				"Component": "FUEL SYSTEM, GASOLINE:DELIVERY:FUEL PUMP",
+				"Summary": "The fuel pump may fail, causing the engine to stall.",
+				"Consequence": "An engine stall increases the risk of a crash.",
+				"Remedy": "Dealers will replace the fuel pump, free of charge.",
+				"parkIt": false
+			}]}`))
+		case strings.Contains(r.URL.Path, "investigations"):
+			json.NewEncoder(w).Encode(investigationsResponse{Count: 1, Results: []Investigation{{
+				ActionNumber: "PE18004", Component: "SERVICE BRAKES, HYDRAULIC", Subject: "Brake fluid leak",
+				Summary: "Loss of brake fluid from the master cylinder.", OpenDate: "2018-01-10",
+			}}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	s := NewRecallScraper(Config{Makes: []string{"FORD"}, ModelYear: 2018, RateLimit: time.Millisecond}, true)
+	s.client = &http.Client{Transport: &redirectTransport{server: srv}, Timeout: 5 * time.Second}
+
+	posts, err := s.FetchAll(context.Background())
+	if err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+	if len(posts) != 2 {
+		t.Fatalf("expected a recall and an investigation, got %d posts (requests %v)", len(posts), paths)
+	}
+
+	p := posts[0]
+	if p.Source != "nhtsa" || p.SourceID != "recall-18V123000-2018-FORD-F-150" {
+		t.Errorf("unexpected post identity %s %s", p.Source, p.SourceID)
+	}
+	if p.PublishedAt.Month() != time.March || p.PublishedAt.Day() != 1 {
+		t.Errorf("report dates are day-first, got %v", p.PublishedAt)
+	}
+	if !strings.Contains(p.Content, "Remedy: Dealers will replace") {
+		t.Errorf("remedy should be in the content: %q", p.Content)
+	}
+	rec := p.Metadata.Recall
+	if rec == nil || rec.Campaign != "18V123000" || rec.Kind != graph.RecallKindRecall || rec.Date != "2018-03-01" {
+		t.Fatalf("unexpected recall metadata %+v", rec)
+	}
+	if p.Metadata.VehicleInfo == nil || p.Metadata.VehicleInfo.Model != "F-150" || p.Metadata.Components != rec.Component {
+		t.Errorf("unexpected vehicle metadata %+v", p.Metadata)
+	}
+
+	inv := posts[1].Metadata.Recall
+	if inv == nil || inv.Kind != graph.RecallKindInvestigation || inv.Status != "open" || inv.Date != "2018-01-10" {
+		t.Errorf("unexpected investigation metadata %+v", inv)
+	}
+}
+
+func TestRecallScraper_SkipsInvestigations(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "models"):
+			json.NewEncoder(w).Encode(modelsResponse{Count: 1, Results: []modelEntry{{Model: "CAMRY"}}})
+		case strings.Contains(r.URL.Path, "investigations"):
+			t.Error("investigations should not be queried")
+		default:
+			w.Write([]byte(`{"Count": 0, "results": []}`))
+		}
+	}))
+	defer srv.Close()
+
+	s := NewRecallScraper(Config{Makes: []string{"TOYOTA"}, ModelYear: 2020, RateLimit: time.Millisecond}, false)
+	s.client = &http.Client{Transport: &redirectTransport{server: srv}, Timeout: 5 * time.Second}
+
+	posts, err := s.FetchAll(context.Background())
+	if err != nil || len(posts) != 0 {
+		t.Errorf("expected no posts, got %d, %v", len(posts), err)
+	}
+}
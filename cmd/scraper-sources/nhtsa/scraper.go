@@ -11,6 +11,8 @@ import (
 	"strings"
 	"time"
 
+	"golang.org/x/time/rate"
+
 	"github.com/WessleyAI/wessley-mvp/engine/scraper"
 	"github.com/WessleyAI/wessley-mvp/pkg/fn"
 )
@@ -39,8 +41,7 @@ func NewScraper(cfg Config) *Scraper {
 // FetchAll scrapes NHTSA complaints for all configured makes and returns ScrapedPosts.
 func (s *Scraper) FetchAll(ctx context.Context) ([]scraper.ScrapedPost, error) {
 	var allPosts []scraper.ScrapedPost
-	limiter := time.NewTicker(s.cfg.RateLimit)
-	defer limiter.Stop()
+	limiter := scraper.RateLimiter(ctx, s.cfg.RateLimit)
 
 	years := s.cfg.Years()
 
@@ -95,10 +96,12 @@ type modelEntry struct {
 	Model string `json:"model"`
 }
 
-func (s *Scraper) fetchModels(ctx context.Context, make_ string, year int, limiter *time.Ticker) ([]string, error) {
+func (s *Scraper) fetchModels(ctx context.Context, make_ string, year int, limiter *rate.Limiter) ([]string, error) {
 	url := fmt.Sprintf("%s?modelYear=%d&make=%s&issueType=c", modelsURL, year, make_)
 
-	<-limiter.C
+	if err := limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -132,7 +135,7 @@ func (s *Scraper) fetchModels(ctx context.Context, make_ string, year int, limit
 	return models, nil
 }
 
-func (s *Scraper) fetchMakeModel(ctx context.Context, make_, model string, year int, limiter *time.Ticker) ([]scraper.ScrapedPost, error) {
+func (s *Scraper) fetchMakeModel(ctx context.Context, make_, model string, year int, limiter *rate.Limiter) ([]scraper.ScrapedPost, error) {
 	url := fmt.Sprintf("%s?make=%s&model=%s&modelYear=%d", complaintsURL, neturl.QueryEscape(make_), neturl.QueryEscape(model), year)
 
 	result := fn.Retry(ctx, fn.RetryOpts{
@@ -141,7 +144,9 @@ func (s *Scraper) fetchMakeModel(ctx context.Context, make_, model string, year
 		MaxWait:     30 * time.Second,
 		Jitter:      true,
 	}, func(ctx context.Context) fn.Result[*apiResponse] {
-		<-limiter.C
+		if err := limiter.Wait(ctx); err != nil {
+			return fn.Err[*apiResponse](err)
+		}
 		return s.doGet(ctx, url)
 	})
 
@@ -193,8 +198,9 @@ func (s *Scraper) fetchMakeModel(ctx context.Context, make_, model string, year
 					Year:  vehicleYear,
 				},
 				Symptoms:   extractSymptoms(c.Summary),
-				Keywords:   []string{strings.ToLower(c.Components), "nhtsa", "complaint"},
+				Keywords:   complaintKeywords(c),
 				Components: c.Components,
+				Complaint:  complaintSeverity(c),
 			},
 		})
 	}
@@ -248,6 +254,36 @@ func parseNHTSADate(s string) time.Time {
 	return time.Time{}
 }
 
+// complaintSeverity keeps what the complaint reports about the incident.
+func complaintSeverity(c Complaint) *scraper.Complaint {
+	sev := &scraper.Complaint{
+		Components: c.Components,
+		Crash:      c.Crash,
+		Fire:       c.Fire,
+		Injuries:   c.NumberOfInjuries,
+		Deaths:     c.NumberOfDeaths,
+		VIN:        c.VIN,
+	}
+	if t := parseNHTSADate(c.DateOfIncident); !t.IsZero() {
+		sev.IncidentDate = t.Format("2006-01-02")
+	}
+	return sev
+}
+
+func complaintKeywords(c Complaint) []string {
+	keywords := []string{strings.ToLower(c.Components), "nhtsa", "complaint"}
+	if c.Crash {
+		keywords = append(keywords, "crash")
+	}
+	if c.Fire {
+		keywords = append(keywords, "fire")
+	}
+	if c.NumberOfInjuries > 0 || c.NumberOfDeaths > 0 {
+		keywords = append(keywords, "injury")
+	}
+	return keywords
+}
+
 func extractSymptoms(summary string) []string {
 	lower := strings.ToLower(summary)
 	knownSymptoms := []string{
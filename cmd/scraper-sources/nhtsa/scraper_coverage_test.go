@@ -247,3 +247,43 @@ func TestFetchAll_PostMetadata(t *testing.T) {
 		t.Errorf("URL should contain HONDA: %s", p.URL)
 	}
 }
+
+func TestFetchAll_Severity(t *testing.T) {
+	modelsResp := modelsResponse{Count: 1, Results: []modelEntry{{Model: "F-150"}}}
+	complaintsResp := apiResponse{
+		Count: 2,
+		Results: []Complaint{
+			{ODINumber: 1, Components: "FUEL SYSTEM, GASOLINE", Summary: "Fire in the engine bay", Crash: true, Fire: true,
+				NumberOfInjuries: 2, DateOfIncident: "05/14/2023", DateComplaintFiled: "06/01/2023", VIN: "1FTEW1E5*JF",
+				Products: []Product{{Type: "Vehicle", ProductYear: "2018", ProductMake: "FORD", ProductModel: "F-150"}}},
+			{ODINumber: 2, Components: "ENGINE", Summary: "noise", DateComplaintFiled: "06/02/2023",
+				Products: []Product{{Type: "Vehicle", ProductYear: "2018", ProductMake: "FORD", ProductModel: "F-150"}}},
+		},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "models") {
+			json.NewEncoder(w).Encode(modelsResp)
+		} else {
+			json.NewEncoder(w).Encode(complaintsResp)
+		}
+	}))
+	defer srv.Close()
+
+	s := NewScraper(Config{Makes: []string{"FORD"}, ModelYear: 2018, MaxPerMake: 10, RateLimit: time.Millisecond})
+	s.client = &http.Client{Transport: &redirectTransport{server: srv}, Timeout: 5 * time.Second}
+
+	posts, err := s.FetchAll(context.Background())
+	if err != nil || len(posts) != 2 {
+		t.Fatalf("FetchAll: %d posts, %v", len(posts), err)
+	}
+	sev := posts[0].Metadata.Complaint
+	if sev == nil || !sev.Crash || !sev.Fire || sev.Injuries != 2 || sev.IncidentDate != "2023-05-14" || sev.VIN != "1FTEW1E5*JF" {
+		t.Fatalf("severity not carried over: %+v", sev)
+	}
+	if kw := strings.Join(posts[0].Metadata.Keywords, ","); !strings.Contains(kw, "crash") || !strings.Contains(kw, "fire") {
+		t.Errorf("expected crash and fire keywords, got %s", kw)
+	}
+	if sev := posts[1].Metadata.Complaint; sev == nil || sev.Crash || sev.IncidentDate != "" {
+		t.Errorf("unexpected severity %+v", sev)
+	}
+}
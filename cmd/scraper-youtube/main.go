@@ -4,7 +4,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
@@ -14,54 +13,47 @@ import (
 	"syscall"
 
 	"github.com/WessleyAI/wessley-mvp/engine/scraper"
-	"github.com/WessleyAI/wessley-mvp/pkg/fn"
 )
 
 func main() {
 	var (
-		apiKey   = flag.String("api-key", os.Getenv("YOUTUBE_API_KEY"), "YouTube Data API v3 key")
-		query    = flag.String("query", "", "search query (default: use automotive keywords)")
-		videoIDs = flag.String("video-ids", "", "comma-separated video IDs to scrape directly (no API key needed)")
-		maxRes   = flag.Int("max", 10, "max results per query")
+		apiKey    = flag.String("api-key", os.Getenv("YOUTUBE_API_KEY"), "YouTube Data API v3 key")
+		query     = flag.String("query", "", "search query (default: use automotive keywords)")
+		videoIDs  = flag.String("video-ids", "", "comma-separated video IDs to scrape directly (no API key needed)")
+		maxRes    = flag.Int("max", 10, "max results per query")
+		outputDir = flag.String("output-dir", "", "directory to write JSON files for ingest pipeline (e.g. /tmp/wessley-data)")
+		interval  = flag.Duration("interval", 0, "polling interval (0 = one-shot)")
 	)
 	flag.Parse()
 
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
-	s := scraper.NewYouTubeScraper(*apiKey, nil)
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-
-	var results <-chan fn.Result[scraper.ScrapedPost]
-
+	src := scraper.YouTubeSource{
+		Scraper: scraper.NewYouTubeScraper(*apiKey, nil),
+		Opts:    scraper.ScrapeOpts{Query: *query, MaxResults: *maxRes},
+	}
 	if *videoIDs != "" {
-		ids := strings.Split(*videoIDs, ",")
-		results = s.ScrapeVideoIDs(ctx, ids)
-	} else {
-		if *apiKey == "" {
-			fmt.Fprintln(os.Stderr, "error: YouTube API key required (set YOUTUBE_API_KEY or use -api-key)")
-			fmt.Fprintln(os.Stderr, "       use -video-ids for direct scraping without API key")
-			os.Exit(1)
-		}
-		results = s.Scrape(ctx, scraper.ScrapeOpts{
-			Query:      *query,
-			MaxResults: *maxRes,
-		})
+		src.VideoIDs = strings.Split(*videoIDs, ",")
+	} else if *apiKey == "" {
+		fmt.Fprintln(os.Stderr, "error: YouTube API key required (set YOUTUBE_API_KEY or use -api-key)")
+		fmt.Fprintln(os.Stderr, "       use -video-ids for direct scraping without API key")
+		os.Exit(1)
 	}
 
-	count := 0
-	for r := range results {
-		post, err := r.Unwrap()
+	sinks := []scraper.Sink{scraper.NewWriterSink(os.Stdout)}
+	if *outputDir != "" {
+		dir, err := scraper.NewDirSink(*outputDir, scraper.DirSinkOpts{})
 		if err != nil {
-			log.Printf("scrape error: %v", err)
-			continue
+			log.Fatalf("output dir: %v", err)
 		}
-		if err := enc.Encode(post); err != nil {
-			log.Printf("encode error: %v", err)
-		}
-		count++
+		sinks = append(sinks, dir)
 	}
 
-	log.Printf("scraped %d videos", count)
+	rt := scraper.NewRuntime(scraper.RuntimeConfig{Sinks: sinks}, scraper.Job{Source: src, Interval: *interval})
+	defer rt.Close()
+
+	if err := rt.Run(ctx); err != nil {
+		log.Printf("scrape: %v", err)
+	}
 }
@@ -53,7 +53,7 @@ func TestValidateScrapedPost_EmptyTitle(t *testing.T) {
 }
 
 func TestValidateScrapedPost_AllSources(t *testing.T) {
-	for _, src := range []string{"reddit", "youtube", "forum"} {
+	for _, src := range []string{"reddit", "youtube", "forum", "nhtsa", "ifixit", "manual", "reddit:MechanicAdvice"} {
 		post := scraper.ScrapedPost{Source: src, SourceID: "x", Title: "T", Content: "C"}
 		if err := ValidateScrapedPost(post); err != nil {
 			t.Errorf("source %q should be valid: %v", src, err)
@@ -2,41 +2,17 @@ package domain
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/WessleyAI/wessley-mvp/engine/scraper"
 )
 
-// ValidSources enumerates accepted scrape sources.
-var ValidSources = map[string]bool{
-	"reddit":  true,
-	"youtube": true,
-	"forum":   true,
-	"nhtsa":   true,
-	"ifixit":  true,
-}
-
-// validSource returns true if the source is known.
-// Sources with prefixes like "reddit:", "forum:", "youtube:" are accepted.
-func validSource(src string) bool {
-	if ValidSources[src] {
-		return true
-	}
-	// Accept prefixed sources (e.g., "reddit:MechanicAdvice", "forum:toyotanation")
-	for base := range ValidSources {
-		if strings.HasPrefix(src, base+":") {
-			return true
-		}
-	}
-	return false
-}
-
-// ValidateScrapedPost checks a ScrapedPost before ingestion.
+// ValidateScrapedPost checks a ScrapedPost before ingestion. The source must
+// be registered with scraper.RegisterSource.
 func ValidateScrapedPost(post scraper.ScrapedPost) error {
 	if post.Content == "" {
 		return fmt.Errorf("validate: content is empty")
 	}
-	if !validSource(post.Source) {
+	if _, ok := scraper.LookupSource(post.Source); !ok {
 		return fmt.Errorf("validate: unknown source %q", post.Source)
 	}
 	if post.SourceID == "" {
@@ -0,0 +1,133 @@
+package dtc
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// causes are the components commonly blamed for trouble codes, with the
+// ways people write them.
+var causes = []struct {
+	name string
+	re   *regexp.Regexp
+}{
+	{"Catalytic converter", regexp.MustCompile(`(?i)catalytic converters?|\bcatalysts?\b|\bcats?\b`)},
+	{"Oxygen sensor", regexp.MustCompile(`(?i)\bo2 sensors?|oxygen sensors?|\bho2s\b|\bo2s\b|air[- ]fuel (?:ratio )?sensors?`)},
+	{"Mass air flow sensor", regexp.MustCompile(`(?i)mass air ?flow|\bmaf\b`)},
+	{"MAP sensor", regexp.MustCompile(`(?i)\bmap sensors?|manifold absolute pressure sensors?`)},
+	{"Intake air temperature sensor", regexp.MustCompile(`(?i)intake air temp(?:erature)? sensors?|\biat sensors?`)},
+	{"Spark plug", regexp.MustCompile(`(?i)spark ?plugs?`)},
+	{"Ignition coil", regexp.MustCompile(`(?i)ignition coils?|coil packs?|coil[- ]on[- ]plugs?`)},
+	{"Fuel injector", regexp.MustCompile(`(?i)\binjectors?\b`)},
+	{"Fuel pump", regexp.MustCompile(`(?i)fuel pumps?`)},
+	{"Fuel filter", regexp.MustCompile(`(?i)fuel filters?`)},
+	{"Fuel pressure sensor", regexp.MustCompile(`(?i)fuel (?:rail )?pressure sensors?`)},
+	{"Fuel pressure regulator", regexp.MustCompile(`(?i)fuel pressure regulators?`)},
+	{"Vacuum hose", regexp.MustCompile(`(?i)vacuum (?:hoses?|lines?|leaks?)`)},
+	{"Intake manifold gasket", regexp.MustCompile(`(?i)intake (?:manifold )?gaskets?`)},
+	{"PCV valve", regexp.MustCompile(`(?i)\bpcv\b`)},
+	{"Thermostat", regexp.MustCompile(`(?i)thermostats?`)},
+	{"Coolant temperature sensor", regexp.MustCompile(`(?i)coolant temp(?:erature)? sensors?|\bect sensors?`)},
+	{"Water pump", regexp.MustCompile(`(?i)water pumps?`)},
+	{"Radiator", regexp.MustCompile(`(?i)\bradiators?\b`)},
+	{"Cooling fan", regexp.MustCompile(`(?i)(?:cooling|radiator) fans?`)},
+	{"Purge valve", regexp.MustCompile(`(?i)purge (?:valves?|solenoids?)|canister purge`)},
+	{"Vent valve", regexp.MustCompile(`(?i)vent (?:valves?|solenoids?)`)},
+	{"Charcoal canister", regexp.MustCompile(`(?i)(?:charcoal|evap) canisters?`)},
+	{"Gas cap", regexp.MustCompile(`(?i)(?:gas|fuel|filler) caps?`)},
+	{"EGR valve", regexp.MustCompile(`(?i)\begr\b`)},
+	{"Secondary air pump", regexp.MustCompile(`(?i)secondary air (?:injection )?pumps?|\bsmog pumps?`)},
+	{"Throttle body", regexp.MustCompile(`(?i)throttle bod(?:y|ies)`)},
+	{"Throttle position sensor", regexp.MustCompile(`(?i)throttle position sensors?|\btps\b`)},
+	{"Camshaft position sensor", regexp.MustCompile(`(?i)cam(?:shaft)? (?:position )?sensors?|\bcmp sensors?`)},
+	{"Crankshaft position sensor", regexp.MustCompile(`(?i)crank(?:shaft)? (?:position )?sensors?|\bckp sensors?`)},
+	{"VVT solenoid", regexp.MustCompile(`(?i)\bvvt\b|variable valve timing|(?:vct|oil control) (?:valves?|solenoids?)`)},
+	{"Cam phaser", regexp.MustCompile(`(?i)cam phasers?`)},
+	{"Timing chain", regexp.MustCompile(`(?i)timing chains?`)},
+	{"Timing belt", regexp.MustCompile(`(?i)timing belts?`)},
+	{"Knock sensor", regexp.MustCompile(`(?i)knock sensors?`)},
+	{"Oil pressure sensor", regexp.MustCompile(`(?i)oil pressure (?:sensors?|switch(?:es)?|senders?)`)},
+	{"Turbocharger", regexp.MustCompile(`(?i)\bturbo(?:chargers?)?\b`)},
+	{"Wastegate", regexp.MustCompile(`(?i)waste ?gates?`)},
+	{"Wheel speed sensor", regexp.MustCompile(`(?i)wheel speed sensors?|\babs sensors?`)},
+	{"ABS module", regexp.MustCompile(`(?i)\babs (?:control )?(?:modules?|pumps?|units?)`)},
+	{"Vehicle speed sensor", regexp.MustCompile(`(?i)vehicle speed sensors?|\bvss\b`)},
+	{"Brake light switch", regexp.MustCompile(`(?i)brake (?:light |pedal )?switch(?:es)?`)},
+	{"Battery", regexp.MustCompile(`(?i)\bbatter(?:y|ies)\b`)},
+	{"Alternator", regexp.MustCompile(`(?i)\balternators?\b`)},
+	{"Ground connection", regexp.MustCompile(`(?i)ground (?:straps?|wires?|connections?)`)},
+	{"Wiring harness", regexp.MustCompile(`(?i)wiring harness(?:es)?|\bharness\b|\bwiring\b`)},
+	{"PCM", regexp.MustCompile(`(?i)\bpcm\b|\becm\b|\becu\b|engine computer`)},
+	{"TCM", regexp.MustCompile(`(?i)\btcm\b|transmission control module`)},
+	{"Shift solenoid", regexp.MustCompile(`(?i)shift solenoids?`)},
+	{"Torque converter", regexp.MustCompile(`(?i)torque converters?`)},
+	{"Transmission fluid", regexp.MustCompile(`(?i)transmission fluid|\batf\b`)},
+	{"Valve body", regexp.MustCompile(`(?i)valve bod(?:y|ies)`)},
+}
+
+// Components returns the likely-cause components named in text, in order
+// of first mention.
+func Components(text string) []string {
+	type hit struct {
+		name string
+		at   int
+	}
+	var hits []hit
+	for _, c := range causes {
+		if loc := c.re.FindStringIndex(text); loc != nil {
+			hits = append(hits, hit{c.name, loc[0]})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].at < hits[j].at })
+	names := make([]string, len(hits))
+	for i, h := range hits {
+		names[i] = h.name
+	}
+	return names
+}
+
+// Mention is a trouble code found in a document with the components named
+// near it.
+type Mention struct {
+	Code       string
+	Components []string
+}
+
+// Mentions finds the trouble codes in sentences. A code's components are
+// those named in its sentence, the one before it and the two after it,
+// where posts usually say what fixed the code.
+func Mentions(sentences []string) []Mention {
+	var mentions []Mention
+	index := map[string]int{}
+	for i, s := range sentences {
+		for _, code := range Find(s) {
+			j, ok := index[code]
+			if !ok {
+				j = len(mentions)
+				index[code] = j
+				mentions = append(mentions, Mention{Code: code})
+			}
+			lo, hi := max(i-1, 0), min(i+3, len(sentences))
+			window := strings.Join(sentences[lo:hi], " ")
+			mentions[j].Components = appendUnique(mentions[j].Components, Components(window)...)
+		}
+	}
+	return mentions
+}
+
+func appendUnique(list []string, items ...string) []string {
+	for _, item := range items {
+		found := false
+		for _, l := range list {
+			if l == item {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,173 @@
+// Package dtc recognizes OBD-II diagnostic trouble codes (P/B/C/U codes) in
+// free text and describes them from the generic SAE J2012 definitions
+// bundled in j2012.tsv.
+package dtc
+
+import (
+	_ "embed"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//go:embed j2012.tsv
+var j2012 string
+
+// Code describes a diagnostic trouble code.
+type Code struct {
+	Code        string `json:"code"`                  // e.g. "P0420"
+	Description string `json:"description,omitempty"` // empty for codes not in the bundled set
+	Category    string `json:"category"`              // J2012 code group, e.g. "Ignition system or misfire"
+	System      string `json:"system"`                // graph system taxonomy name, e.g. "Exhaust"
+	Generic     bool   `json:"generic"`               // SAE-defined rather than manufacturer-specific
+}
+
+var (
+	codePattern = regexp.MustCompile(`(?i)\b[PBCU][0-3][0-9A-F]{3}\b`)
+
+	// notCodes are vehicle model names that read as trouble codes.
+	notCodes = map[string]bool{
+		"C1500": true, "C2500": true, "C3500": true,
+		"B2000": true, "B2200": true, "B2300": true, "B2500": true, "B2600": true, "B3000": true, "B4000": true,
+	}
+
+	descriptions = parseJ2012(j2012)
+)
+
+func parseJ2012(data string) map[string]string {
+	m := make(map[string]string)
+	for _, line := range strings.Split(data, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		code, desc, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		m[strings.ToUpper(strings.TrimSpace(code))] = strings.TrimSpace(desc)
+	}
+	return m
+}
+
+// Valid reports whether code is a well-formed trouble code. Case is ignored.
+func Valid(code string) bool {
+	return len(code) == 5 && codePattern.MatchString(code)
+}
+
+// Find returns the trouble codes mentioned in text, upper-cased and in
+// order of first mention.
+func Find(text string) []string {
+	var codes []string
+	seen := map[string]bool{}
+	for _, m := range codePattern.FindAllString(text, -1) {
+		code := strings.ToUpper(m)
+		if seen[code] || notCodes[code] {
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+	return codes
+}
+
+// Lookup describes code. Codes outside the bundled set get their category
+// and system from the code structure alone. ok is false for malformed codes.
+func Lookup(code string) (c Code, ok bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !Valid(code) {
+		return Code{}, false
+	}
+	c = Code{Code: code, Description: descriptions[code], Generic: generic(code)}
+	c.Category, c.System = category(code)
+	if sys := descriptionSystem(c.Description); sys != "" {
+		c.System = sys
+	}
+	return c, true
+}
+
+// All returns the bundled generic definitions sorted by code.
+func All() []Code {
+	codes := make([]Code, 0, len(descriptions))
+	for code := range descriptions {
+		c, _ := Lookup(code)
+		codes = append(codes, c)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i].Code < codes[j].Code })
+	return codes
+}
+
+// generic reports whether code is in an SAE-controlled range: P0, P2, P34-P39,
+// B0, C0, U0 and U3. The others are manufacturer-specific.
+func generic(code string) bool {
+	switch code[1] {
+	case '0':
+		return true
+	case '2':
+		return code[0] == 'P'
+	case '3':
+		return code[0] == 'U' || (code[0] == 'P' && code[2] >= '4' && code[2] <= '9')
+	}
+	return false
+}
+
+// category returns the J2012 code group of code and the system it maps to.
+func category(code string) (string, string) {
+	switch code[0] {
+	case 'B':
+		return "Body", "Body"
+	case 'C':
+		return "Chassis", "Brakes"
+	case 'U':
+		return "Network communication", "Electrical"
+	}
+	switch code[2] {
+	case '0', '1', '2':
+		return "Fuel and air metering", "Fuel System"
+	case '3':
+		return "Ignition system or misfire", "Engine"
+	case '4':
+		return "Auxiliary emission controls", "Exhaust"
+	case '5':
+		return "Vehicle speed, idle control and auxiliary inputs", "Engine"
+	case '6':
+		return "Computer and output circuits", "Electrical"
+	case '7', '8', '9':
+		return "Transmission", "Transmission"
+	case 'A':
+		return "Hybrid propulsion", "Electrical"
+	}
+	return "Powertrain", "Engine"
+}
+
+// descriptionSystems refines the system of a code from its description,
+// first match wins.
+var descriptionSystems = []struct{ keyword, system string }{
+	{"coolant", "Cooling"},
+	{"cooling fan", "Cooling"},
+	{"overtemperature", "Cooling"},
+	{"evaporative", "Fuel System"},
+	{"catalyst", "Exhaust"},
+	{"o2 sensor", "Exhaust"},
+	{"ho2s", "Exhaust"},
+	{"exhaust gas recirculation", "Exhaust"},
+	{"secondary air", "Exhaust"},
+	{"camshaft", "Engine"},
+	{"crankshaft", "Engine"},
+	{"knock sensor", "Engine"},
+	{"oil pressure", "Engine"},
+	{"turbo", "Engine"},
+	{"wheel speed", "Brakes"},
+	{"anti-lock", "Brakes"},
+	{"voltage", "Electrical"},
+	{"generator", "Electrical"},
+}
+
+func descriptionSystem(desc string) string {
+	desc = strings.ToLower(desc)
+	for _, ds := range descriptionSystems {
+		if strings.Contains(desc, ds.keyword) {
+			return ds.system
+		}
+	}
+	return ""
+}
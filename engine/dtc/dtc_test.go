@@ -0,0 +1,76 @@
+package dtc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	text := "Threw p0420 and P0171, then P0420 again. My C1500 shows C0035 and B1000. Not a code: P12345 or XP0300."
+	want := []string{"P0420", "P0171", "C0035", "B1000"}
+	if got := Find(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("Find = %v, want %v", got, want)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		code                   string
+		desc, category, system string
+		generic                bool
+	}{
+		{"p0420", "Catalyst System Efficiency Below Threshold (Bank 1)", "Auxiliary emission controls", "Exhaust", true},
+		{"P0302", "Cylinder 2 Misfire Detected", "Ignition system or misfire", "Engine", true},
+		{"P0128", "Coolant Thermostat (Coolant Temperature Below Thermostat Regulating Temperature)", "Fuel and air metering", "Cooling", true},
+		{"P1450", "", "Auxiliary emission controls", "Exhaust", false},
+		{"U0100", "Lost Communication With ECM/PCM A", "Network communication", "Electrical", true},
+		{"B1318", "", "Body", "Body", false},
+	}
+	for _, tt := range tests {
+		c, ok := Lookup(tt.code)
+		if !ok {
+			t.Fatalf("Lookup(%q) not ok", tt.code)
+		}
+		if c.Description != tt.desc || c.Category != tt.category || c.System != tt.system || c.Generic != tt.generic {
+			t.Errorf("Lookup(%q) = %+v", tt.code, c)
+		}
+	}
+	if _, ok := Lookup("P04200"); ok {
+		t.Error("malformed code accepted")
+	}
+}
+
+func TestAll(t *testing.T) {
+	all := All()
+	if len(all) < 100 {
+		t.Fatalf("expected the bundled J2012 set, got %d codes", len(all))
+	}
+	for i, c := range all {
+		if c.Description == "" || !c.Generic {
+			t.Errorf("unexpected bundled code %+v", c)
+		}
+		if i > 0 && all[i-1].Code >= c.Code {
+			t.Fatalf("codes not sorted at %s", c.Code)
+		}
+	}
+}
+
+func TestMentions(t *testing.T) {
+	sentences := []string{
+		"Truck has been running rough.",
+		"Scanner shows P0301 and P0171.",
+		"Swapped the coil pack from cylinder 1 to 3.",
+		"The misfire followed, so a new ignition coil fixed it.",
+		"Later the MAF sensor got cleaned and P0171 went away.",
+	}
+	got := Mentions(sentences)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 mentions, got %+v", got)
+	}
+	if got[0].Code != "P0301" || !reflect.DeepEqual(got[0].Components, []string{"Ignition coil"}) {
+		t.Errorf("unexpected P0301 mention %+v", got[0])
+	}
+	if got[1].Code != "P0171" || !reflect.DeepEqual(got[1].Components, []string{"Ignition coil", "Mass air flow sensor"}) {
+		t.Errorf("unexpected P0171 mention %+v", got[1])
+	}
+}
@@ -0,0 +1,191 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Thresholds for flagging a component's complaints as safety-critical.
+const (
+	SafetyCriticalMinComplaints = 3    // fewer complaints are too few to call a pattern
+	SafetyCriticalRate          = 0.10 // share of complaints reporting a crash or fire
+)
+
+// Complaint is the severity record of a safety complaint, e.g. one NHTSA
+// ODI complaint.
+type Complaint struct {
+	Components   string `json:"components"` // raw NHTSA components, e.g. "ELECTRICAL SYSTEM,FUEL SYSTEM, GASOLINE"
+	Crash        bool   `json:"crash,omitempty"`
+	Fire         bool   `json:"fire,omitempty"`
+	Injuries     int    `json:"injuries,omitempty"`
+	Deaths       int    `json:"deaths,omitempty"`
+	IncidentDate string `json:"incident_date,omitempty"` // "2006-01-02"
+	VIN          string `json:"vin,omitempty"`           // as published, usually truncated
+}
+
+// ComplaintComponents splits an NHTSA components string into its
+// components. Components are separated by bare commas; a comma followed by
+// a space belongs to a name, as in "FUEL SYSTEM, GASOLINE".
+func ComplaintComponents(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		name := strings.ToUpper(strings.TrimSpace(part))
+		switch {
+		case name == "":
+		case strings.HasPrefix(part, " ") && len(out) > 0:
+			out[len(out)-1] += ", " + name
+		default:
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// ComponentStats aggregates the complaints filed against one component of a
+// vehicle.
+type ComponentStats struct {
+	Component      string         `json:"component"`
+	System         string         `json:"system,omitempty"` // ClassifyComponent result
+	Complaints     int            `json:"complaints"`
+	Crashes        int            `json:"crashes"`
+	Fires          int            `json:"fires"`
+	Injuries       int            `json:"injuries"`
+	Deaths         int            `json:"deaths"`
+	CrashRate      float64        `json:"crash_rate"`
+	FireRate       float64        `json:"fire_rate"`
+	Trend          []MonthlyCount `json:"trend,omitempty"` // complaints by incident month, oldest first
+	SafetyCritical bool           `json:"safety_critical"`
+}
+
+// MonthlyCount is the number of incidents in a month, "2006-01".
+type MonthlyCount struct {
+	Month string `json:"month"`
+	Count int    `json:"count"`
+}
+
+// complaintStatsID returns the ID of a ComplaintStats node.
+func complaintStatsID(vi VehicleInfo, component string) string {
+	return vehicleScopePrefix(vi) + ":complaints:" + sanitizeID(component)
+}
+
+// EnrichFromComplaints adds each complaint to the ComplaintStats counters of
+// the ModelYear's components. A document is counted once per component: its
+// COUNTED_IN edge records what it added, so ingesting it again is a no-op and
+// RetractDocument can subtract it.
+func (e *Enricher) EnrichFromComplaints(ctx context.Context, vi VehicleInfo, complaints []Complaint, docID string) error {
+	if len(complaints) == 0 || docID == "" {
+		return nil
+	}
+	if err := e.graph.EnsureVehicleHierarchy(ctx, vi); err != nil {
+		return fmt.Errorf("enricher: vehicle hierarchy: %w", err)
+	}
+
+	sess := e.graph.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	_, err := sess.ExecuteWrite(ctx, func(tx CypherRunner) (any, error) {
+		for _, c := range complaints {
+			month := ""
+			if len(c.IncidentDate) >= 7 {
+				month = c.IncidentDate[:7]
+			}
+			crash, fire := 0, 0
+			if c.Crash {
+				crash = 1
+			}
+			if c.Fire {
+				fire = 1
+			}
+			for _, comp := range ComplaintComponents(c.Components) {
+				sys, _ := ClassifyComponent(comp, "")
+				cypher := `MERGE (cs:ComplaintStats {id: $id})
+				           ON CREATE SET cs.name = $component, cs.component = $component, cs.system = $system,
+				                         cs.complaints = 0, cs.crashes = 0, cs.fires = 0, cs.injuries = 0, cs.deaths = 0,
+				                         cs.incident_months = []
+				           WITH cs
+				           MATCH (my:ModelYear {id: $myID})
+				           MERGE (my)-[:HAS_COMPLAINT_STATS]->(cs)
+				           WITH cs
+				           MATCH (d:Component {id: $docID})
+				           MERGE (d)-[c:COUNTED_IN]->(cs)
+				           ON CREATE SET c.crash = $crash, c.fire = $fire, c.injuries = $injuries, c.deaths = $deaths, c.month = $month,
+				                         cs.complaints = cs.complaints + 1, cs.crashes = cs.crashes + $crash, cs.fires = cs.fires + $fire,
+				                         cs.injuries = cs.injuries + $injuries, cs.deaths = cs.deaths + $deaths,
+				                         cs.incident_months = cs.incident_months + CASE WHEN $month = '' THEN [] ELSE [$month] END`
+				if _, err := tx.Run(ctx, cypher, map[string]any{
+					"id": complaintStatsID(vi, comp), "component": comp, "system": sys, "myID": modelYearID(vi), "docID": docID,
+					"crash": crash, "fire": fire, "injuries": c.Injuries, "deaths": c.Deaths, "month": month,
+				}); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// ComplaintStats returns the complaint counters of a ModelYear, e.g.
+// "ford-f-150-2018", with crash and fire rates and monthly incident trends,
+// most complained-about component first.
+func (g *GraphStore) ComplaintStats(ctx context.Context, modelYearID string) ([]ComponentStats, error) {
+	sess := g.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	cypher := `MATCH (:ModelYear {id: $myID})-[:HAS_COMPLAINT_STATS]->(cs:ComplaintStats)
+	           WHERE cs.complaints > 0
+	           RETURN cs.component AS component, cs.system AS system, cs.complaints AS complaints,
+	                  cs.crashes AS crashes, cs.fires AS fires, cs.injuries AS injuries, cs.deaths AS deaths,
+	                  cs.incident_months AS months
+	           ORDER BY complaints DESC, component`
+	result, err := sess.Run(ctx, cypher, map[string]any{"myID": modelYearID})
+	if err != nil {
+		return nil, fmt.Errorf("graph: complaint stats %s: %w", modelYearID, err)
+	}
+	var out []ComponentStats
+	for result.Next(ctx) {
+		rec := result.Record()
+		get := func(key string) any {
+			v, _ := rec.Get(key)
+			return v
+		}
+		num := func(key string) int {
+			n, _ := get(key).(int64)
+			return int(n)
+		}
+		s := ComponentStats{
+			Complaints: num("complaints"), Crashes: num("crashes"), Fires: num("fires"),
+			Injuries: num("injuries"), Deaths: num("deaths"),
+		}
+		s.Component, _ = get("component").(string)
+		s.System, _ = get("system").(string)
+		months, _ := get("months").([]any)
+		s.Trend = monthlyTrend(months)
+		if s.Complaints > 0 {
+			s.CrashRate = float64(s.Crashes) / float64(s.Complaints)
+			s.FireRate = float64(s.Fires) / float64(s.Complaints)
+		}
+		s.SafetyCritical = s.Deaths > 0 ||
+			(s.Complaints >= SafetyCriticalMinComplaints && float64(s.Crashes+s.Fires)/float64(s.Complaints) >= SafetyCriticalRate)
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// monthlyTrend counts incident months, oldest first.
+func monthlyTrend(months []any) []MonthlyCount {
+	counts := map[string]int{}
+	for _, m := range months {
+		if s, ok := m.(string); ok && s != "" {
+			counts[s]++
+		}
+	}
+	trend := make([]MonthlyCount, 0, len(counts))
+	for m, n := range counts {
+		trend = append(trend, MonthlyCount{Month: m, Count: n})
+	}
+	sort.Slice(trend, func(i, j int) bool { return trend[i].Month < trend[j].Month })
+	return trend
+}
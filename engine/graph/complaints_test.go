@@ -0,0 +1,98 @@
+package graph
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestComplaintComponents(t *testing.T) {
+	tests := map[string][]string{
+		"ENGINE":                                  {"ENGINE"},
+		"ELECTRICAL SYSTEM,ENGINE":                {"ELECTRICAL SYSTEM", "ENGINE"},
+		"FUEL SYSTEM, GASOLINE,AIR BAGS":          {"FUEL SYSTEM, GASOLINE", "AIR BAGS"},
+		"service brakes, hydraulic:foundation , ": {"SERVICE BRAKES, HYDRAULIC:FOUNDATION"},
+		"": nil,
+	}
+	for in, want := range tests {
+		if got := ComplaintComponents(in); !reflect.DeepEqual(got, want) {
+			t.Errorf("ComplaintComponents(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestEnrichFromComplaints(t *testing.T) {
+	gs, tx := newTrackingStore()
+	vi := VehicleInfo{Make: "Ford", Model: "F-150", Year: 2018}
+	c := Complaint{Components: "FUEL SYSTEM, GASOLINE,ENGINE", Crash: true, Injuries: 1, IncidentDate: "2023-05-14"}
+	if err := NewEnricher(gs).EnrichFromComplaints(context.Background(), vi, []Complaint{c}, "nhtsa:nhtsa-11111"); err != nil {
+		t.Fatalf("EnrichFromComplaints: %v", err)
+	}
+
+	var counted []map[string]any
+	for i, q := range tx.queries {
+		if strings.Contains(q, "MERGE (cs:ComplaintStats") {
+			counted = append(counted, tx.params[i])
+		}
+	}
+	if len(counted) != 2 {
+		t.Fatalf("expected one counter per component, got %v", tx.queries)
+	}
+	p := counted[0]
+	if p["id"] != "ford-f-150-2018:complaints:fuel-system-gasoline" || p["system"] != "Fuel System" || p["docID"] != "nhtsa:nhtsa-11111" {
+		t.Errorf("unexpected counter params %v", p)
+	}
+	if p["crash"] != 1 || p["fire"] != 0 || p["injuries"] != 1 || p["month"] != "2023-05" {
+		t.Errorf("unexpected counter increments %v", p)
+	}
+	if counted[1]["component"] != "ENGINE" {
+		t.Errorf("unexpected second component %v", counted[1])
+	}
+
+	// Without a document there is nothing to count against.
+	gs, tx = newTrackingStore()
+	if err := NewEnricher(gs).EnrichFromComplaints(context.Background(), vi, []Complaint{c}, ""); err != nil || len(tx.queries) != 0 {
+		t.Errorf("expected no writes without a docID, got %d queries, %v", len(tx.queries), err)
+	}
+}
+
+func TestComplaintStats(t *testing.T) {
+	keys := []string{"component", "system", "complaints", "crashes", "fires", "injuries", "deaths", "months"}
+	sess := &mockSession{runResult: newMockResult(
+		&neo4j.Record{Keys: keys, Values: []any{
+			"ENGINE", "Engine", int64(20), int64(1), int64(3), int64(0), int64(0),
+			[]any{"2023-06", "2023-05", "2023-06"},
+		}},
+		&neo4j.Record{Keys: keys, Values: []any{
+			"AIR BAGS", nil, int64(2), int64(0), int64(0), int64(1), int64(1), []any{},
+		}},
+		&neo4j.Record{Keys: keys, Values: []any{
+			"STEERING", "Steering", int64(2), int64(1), int64(0), int64(0), int64(0), nil,
+		}},
+	)}
+	gs := NewWithOpener(&mockOpener{session: sess})
+
+	stats, err := gs.ComplaintStats(context.Background(), "ford-f-150-2018")
+	if err != nil {
+		t.Fatalf("ComplaintStats: %v", err)
+	}
+	if len(stats) != 3 {
+		t.Fatalf("expected 3 components, got %+v", stats)
+	}
+	engine := stats[0]
+	if engine.Complaints != 20 || engine.CrashRate != 0.05 || engine.FireRate != 0.15 || !engine.SafetyCritical {
+		t.Errorf("unexpected engine stats %+v", engine)
+	}
+	if want := []MonthlyCount{{"2023-05", 1}, {"2023-06", 2}}; !reflect.DeepEqual(engine.Trend, want) {
+		t.Errorf("trend = %v, want %v", engine.Trend, want)
+	}
+	if !stats[1].SafetyCritical {
+		t.Errorf("a death should flag the component: %+v", stats[1])
+	}
+	if stats[2].SafetyCritical {
+		t.Errorf("two complaints are too few to flag: %+v", stats[2])
+	}
+}
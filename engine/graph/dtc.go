@@ -0,0 +1,235 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DTC describes a diagnostic trouble code node.
+type DTC struct {
+	Code        string `json:"code"`
+	Description string `json:"description,omitempty"`
+	Category    string `json:"category,omitempty"`
+	System      string `json:"system,omitempty"` // SystemTaxonomy name
+	Generic     bool   `json:"generic"`
+}
+
+// DTCMention is a trouble code found in a document, with the components
+// the document names as its likely causes.
+type DTCMention struct {
+	DTC
+	Components []string
+}
+
+// DTCCause is a component blamed for a trouble code, with the number of
+// documents that blame it.
+type DTCCause struct {
+	Component string `json:"component"`
+	Evidence  int    `json:"evidence"`
+}
+
+// DTCDocument is a document mentioning a trouble code.
+type DTCDocument struct {
+	ID      string  `json:"id"`
+	Title   string  `json:"title,omitempty"`
+	Source  string  `json:"source,omitempty"`
+	URL     string  `json:"url,omitempty"`
+	Quality float64 `json:"quality"`
+}
+
+// DTCReport is what the graph knows about a trouble code, optionally
+// narrowed to one vehicle.
+type DTCReport struct {
+	DTC
+	Found     bool          `json:"-"` // the DTC node exists
+	Causes    []DTCCause    `json:"causes"`
+	Documents []DTCDocument `json:"documents"`
+}
+
+// dtcProps returns the DTC node properties of d.
+func dtcProps(d DTC) map[string]any {
+	return map[string]any{
+		"code":        d.Code,
+		"description": d.Description,
+		"category":    d.Category,
+		"system":      d.System,
+		"generic":     d.Generic,
+	}
+}
+
+// SeedDTCs creates or updates the DTC nodes of defs, keyed by code.
+func (g *GraphStore) SeedDTCs(ctx context.Context, defs []DTC) error {
+	if len(defs) == 0 {
+		return nil
+	}
+	rows := make([]map[string]any, len(defs))
+	for i, d := range defs {
+		rows[i] = dtcProps(d)
+	}
+
+	sess := g.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	cypher := `UNWIND $rows AS row
+	           MERGE (d:DTC {id: row.code}) SET d += row`
+	if _, err := sess.Run(ctx, cypher, map[string]any{"rows": rows}); err != nil {
+		return fmt.Errorf("graph: seed dtcs: %w", err)
+	}
+	return nil
+}
+
+// EnrichFromDTCs links document docID to the DTC nodes of the codes it
+// mentions with a MENTIONS edge weighted by the document's quality. When vi
+// names a vehicle, the codes are also linked from its ModelYear and System
+// by HAS_DTC and to their likely-cause Components by CAUSED_BY. Those edges
+// record their evidence in source_docs, which DTCReport counts to rank
+// causes and RetractDocument prunes.
+func (e *Enricher) EnrichFromDTCs(ctx context.Context, vi VehicleInfo, docID string, quality float64, mentions []DTCMention) error {
+	if len(mentions) == 0 {
+		return nil
+	}
+	scoped := vi.Make != "" && vi.Model != "" && vi.Year > 0
+	vehiclePrefix := vehicleScopePrefix(vi)
+	myID := modelYearID(vi)
+
+	sess := e.graph.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	_, err := sess.ExecuteWrite(ctx, func(tx CypherRunner) (any, error) {
+		for _, m := range mentions {
+			if m.Code == "" {
+				continue
+			}
+			cypher := `MERGE (d:DTC {id: $code}) ON CREATE SET d += $props
+			           WITH d
+			           MATCH (doc:Component {id: $docID})
+			           MERGE (doc)-[r:MENTIONS]->(d) SET r.quality = $quality`
+			if _, err := tx.Run(ctx, cypher, map[string]any{
+				"code": m.Code, "props": dtcProps(m.DTC), "docID": docID, "quality": quality,
+			}); err != nil {
+				return nil, err
+			}
+			if !scoped {
+				continue
+			}
+
+			cypher = `MATCH (my:ModelYear {id: $myID}), (d:DTC {id: $code})
+			          MERGE (my)-[r:HAS_DTC]->(d)` + addSourceDoc("r")
+			if _, err := tx.Run(ctx, cypher, map[string]any{
+				"myID": myID, "code": m.Code, "docID": docID,
+			}); err != nil {
+				return nil, err
+			}
+
+			if m.System != "" {
+				sysID := vehiclePrefix + ":" + sanitizeID(m.System)
+				cypher = `MERGE (s:System {id: $sysID}) SET s.name = $name` + addSourceDoc("s") + `
+				          WITH s
+				          MATCH (my:ModelYear {id: $myID}), (d:DTC {id: $code})
+				          MERGE (my)-[:HAS_SYSTEM]->(s)
+				          MERGE (s)-[r:HAS_DTC]->(d)` + addSourceDoc("r")
+				if _, err := tx.Run(ctx, cypher, map[string]any{
+					"sysID": sysID, "name": m.System, "myID": myID, "code": m.Code, "docID": docID,
+				}); err != nil {
+					return nil, err
+				}
+			}
+
+			for _, c := range m.Components {
+				cypher = `MERGE (c:Component {id: $cID}) ON CREATE SET c.name = $name, c.type = 'component'
+				          WITH c
+				          MATCH (d:DTC {id: $code})
+				          MERGE (d)-[r:CAUSED_BY]->(c)` + addSourceDoc("r")
+				if _, err := tx.Run(ctx, cypher, map[string]any{
+					"cID": vehiclePrefix + ":" + sanitizeID(c), "name": c, "code": m.Code, "docID": docID,
+				}); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// DTCReport returns the description of code, its likely causes ranked by
+// the number of documents blaming them and up to limit supporting
+// documents ranked by quality. A non-empty modelYearID ("ford-f-150-2018")
+// narrows causes and documents to that vehicle.
+func (g *GraphStore) DTCReport(ctx context.Context, code, modelYearID string, limit int) (DTCReport, error) {
+	if limit <= 0 {
+		limit = 5
+	}
+	code = strings.ToUpper(code)
+	report := DTCReport{DTC: DTC{Code: code}}
+
+	sess := g.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	cypher := `MATCH (d:DTC {id: $code})
+	           RETURN d.description AS description, d.category AS category, d.system AS system, d.generic AS generic`
+	result, err := sess.Run(ctx, cypher, map[string]any{"code": code})
+	if err != nil {
+		return DTCReport{}, fmt.Errorf("graph: dtc %s: %w", code, err)
+	}
+	if result.Next(ctx) {
+		rec := result.Record()
+		get := func(key string) any {
+			v, _ := rec.Get(key)
+			return v
+		}
+		report.Found = true
+		report.Description, _ = get("description").(string)
+		report.Category, _ = get("category").(string)
+		report.System, _ = get("system").(string)
+		report.Generic, _ = get("generic").(bool)
+	}
+
+	cypher = `MATCH (d:DTC {id: $code})-[r:CAUSED_BY]->(c:Component)
+	          WHERE $myID = '' OR c.id STARTS WITH $myID + ':'
+	          WITH c.name AS component, sum(size(coalesce(r.source_docs, []))) AS evidence
+	          RETURN component, evidence ORDER BY evidence DESC, component LIMIT $limit`
+	result, err = sess.Run(ctx, cypher, map[string]any{"code": code, "myID": modelYearID, "limit": limit})
+	if err != nil {
+		return DTCReport{}, fmt.Errorf("graph: dtc %s causes: %w", code, err)
+	}
+	for result.Next(ctx) {
+		rec := result.Record()
+		c := DTCCause{}
+		if v, ok := rec.Get("component"); ok {
+			c.Component, _ = v.(string)
+		}
+		if v, ok := rec.Get("evidence"); ok {
+			n, _ := v.(int64)
+			c.Evidence = int(n)
+		}
+		report.Causes = append(report.Causes, c)
+	}
+
+	cypher = `MATCH (doc:Component)-[m:MENTIONS]->(d:DTC {id: $code})
+	          WHERE $myID = '' OR EXISTS {
+	            MATCH (:ModelYear {id: $myID})-[r:HAS_DTC]->(d) WHERE doc.id IN r.source_docs
+	          }
+	          RETURN doc.id AS id, doc.name AS title, doc.prop_source AS source, doc.prop_url AS url, m.quality AS quality
+	          ORDER BY quality DESC, id LIMIT $limit`
+	result, err = sess.Run(ctx, cypher, map[string]any{"code": code, "myID": modelYearID, "limit": limit})
+	if err != nil {
+		return DTCReport{}, fmt.Errorf("graph: dtc %s documents: %w", code, err)
+	}
+	for result.Next(ctx) {
+		rec := result.Record()
+		get := func(key string) any {
+			v, _ := rec.Get(key)
+			return v
+		}
+		d := DTCDocument{}
+		d.ID, _ = get("id").(string)
+		d.Title, _ = get("title").(string)
+		d.Source, _ = get("source").(string)
+		d.URL, _ = get("url").(string)
+		d.Quality, _ = get("quality").(float64)
+		report.Documents = append(report.Documents, d)
+	}
+	return report, nil
+}
@@ -0,0 +1,118 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestEnrichFromDTCs(t *testing.T) {
+	gs, tx := newTrackingStore()
+	vi := VehicleInfo{Make: "Ford", Model: "F-150", Year: 2018}
+	mentions := []DTCMention{{
+		DTC:        DTC{Code: "P0420", Description: "Catalyst System Efficiency Below Threshold (Bank 1)", System: "Exhaust", Generic: true},
+		Components: []string{"Oxygen sensor", "Catalytic converter"},
+	}}
+	if err := NewEnricher(gs).EnrichFromDTCs(context.Background(), vi, "reddit:abc", 0.8, mentions); err != nil {
+		t.Fatalf("EnrichFromDTCs: %v", err)
+	}
+
+	var mention, modelYear, system map[string]any
+	var causes []map[string]any
+	for i, q := range tx.queries {
+		switch {
+		case strings.Contains(q, "[r:MENTIONS]"):
+			mention = tx.params[i]
+		case strings.Contains(q, "(my)-[r:HAS_DTC]"):
+			modelYear = tx.params[i]
+		case strings.Contains(q, "(s)-[r:HAS_DTC]"):
+			system = tx.params[i]
+		case strings.Contains(q, "[r:CAUSED_BY]"):
+			if !strings.Contains(q, "source_docs") {
+				t.Errorf("CAUSED_BY should record its evidence: %s", q)
+			}
+			causes = append(causes, tx.params[i])
+		}
+	}
+	if mention["code"] != "P0420" || mention["docID"] != "reddit:abc" || mention["quality"] != 0.8 {
+		t.Errorf("unexpected MENTIONS params %v", mention)
+	}
+	if modelYear["myID"] != "ford-f-150-2018" {
+		t.Errorf("unexpected HAS_DTC params %v", modelYear)
+	}
+	if system["sysID"] != "ford-f-150-2018:exhaust" {
+		t.Errorf("unexpected system params %v", system)
+	}
+	if len(causes) != 2 || causes[0]["cID"] != "ford-f-150-2018:oxygen-sensor" || causes[1]["cID"] != "ford-f-150-2018:catalytic-converter" {
+		t.Errorf("unexpected CAUSED_BY edges %v", causes)
+	}
+}
+
+func TestEnrichFromDTCs_NoVehicle(t *testing.T) {
+	gs, tx := newTrackingStore()
+	mentions := []DTCMention{{DTC: DTC{Code: "P0300"}, Components: []string{"Spark plug"}}}
+	if err := NewEnricher(gs).EnrichFromDTCs(context.Background(), VehicleInfo{}, "doc-1", 0.5, mentions); err != nil {
+		t.Fatalf("EnrichFromDTCs: %v", err)
+	}
+	if len(tx.queries) != 1 || !strings.Contains(tx.queries[0], "MENTIONS") {
+		t.Errorf("expected only the MENTIONS query, got %v", tx.queries)
+	}
+}
+
+// queuedSession answers each Run with the next of results.
+type queuedSession struct {
+	mockSession
+	results []CypherResult
+	params  []map[string]any
+}
+
+func (s *queuedSession) Run(_ context.Context, _ string, params map[string]any) (CypherResult, error) {
+	s.params = append(s.params, params)
+	if len(s.results) == 0 {
+		return newMockResult(), nil
+	}
+	r := s.results[0]
+	s.results = s.results[1:]
+	return r, nil
+}
+
+type queuedOpener struct{ session *queuedSession }
+
+func (o *queuedOpener) OpenSession(_ context.Context) CypherSession { return o.session }
+
+func TestDTCReport(t *testing.T) {
+	sess := &queuedSession{results: []CypherResult{
+		newMockResult(&neo4j.Record{
+			Keys:   []string{"description", "category", "system", "generic"},
+			Values: []any{"Catalyst System Efficiency Below Threshold (Bank 1)", "Auxiliary emission controls", "Exhaust", true},
+		}),
+		newMockResult(
+			&neo4j.Record{Keys: []string{"component", "evidence"}, Values: []any{"Oxygen sensor", int64(4)}},
+			&neo4j.Record{Keys: []string{"component", "evidence"}, Values: []any{"Catalytic converter", int64(2)}},
+		),
+		newMockResult(&neo4j.Record{
+			Keys:   []string{"id", "title", "source", "url", "quality"},
+			Values: []any{"reddit:abc", "P0420 fixed", "reddit", "https://reddit.com/abc", 0.9},
+		}),
+	}}
+	gs := NewWithOpener(&queuedOpener{session: sess})
+
+	report, err := gs.DTCReport(context.Background(), "p0420", "ford-f-150-2018", 0)
+	if err != nil {
+		t.Fatalf("DTCReport: %v", err)
+	}
+	if !report.Found || report.Code != "P0420" || report.System != "Exhaust" || !report.Generic {
+		t.Errorf("unexpected report %+v", report.DTC)
+	}
+	if len(report.Causes) != 2 || report.Causes[0] != (DTCCause{Component: "Oxygen sensor", Evidence: 4}) {
+		t.Errorf("unexpected causes %+v", report.Causes)
+	}
+	if len(report.Documents) != 1 || report.Documents[0].URL != "https://reddit.com/abc" || report.Documents[0].Quality != 0.9 {
+		t.Errorf("unexpected documents %+v", report.Documents)
+	}
+	if p := sess.params[1]; p["myID"] != "ford-f-150-2018" || p["limit"] != 5 {
+		t.Errorf("unexpected cause params %v", p)
+	}
+}
@@ -187,13 +187,14 @@ func (e *Enricher) EnrichFromSource(ctx context.Context, vi VehicleInfo, compone
 	defer sess.Close(ctx)
 
 	_, err := sess.ExecuteWrite(ctx, func(tx CypherRunner) (any, error) {
-		// Create System → link to ModelYear.
-		cypher := `MERGE (s:System {id: $id}) SET s.name = $name
+		// Create System → link to ModelYear. source_docs records which
+		// documents the node was derived from, see RetractDocument.
+		cypher := `MERGE (s:System {id: $id}) SET s.name = $name` + addSourceDoc("s") + `
 		           WITH s
 		           MATCH (my:ModelYear {id: $myID})
 		           MERGE (my)-[:HAS_SYSTEM]->(s)`
 		if _, err := tx.Run(ctx, cypher, map[string]any{
-			"id": sysID, "name": sys, "myID": myID,
+			"id": sysID, "name": sys, "myID": myID, "docID": docID,
 		}); err != nil {
 			return nil, err
 		}
@@ -201,12 +202,12 @@ func (e *Enricher) EnrichFromSource(ctx context.Context, vi VehicleInfo, compone
 		targetID := sysID
 		if sub != "" {
 			subID := sysID + ":" + sanitizeID(sub)
-			cypher = `MERGE (ss:Subsystem {id: $id}) SET ss.name = $name, ss.system_id = $sysID
+			cypher = `MERGE (ss:Subsystem {id: $id}) SET ss.name = $name, ss.system_id = $sysID` + addSourceDoc("ss") + `
 			          WITH ss
 			          MATCH (s:System {id: $sysID})
 			          MERGE (s)-[:HAS_SUBSYSTEM]->(ss)`
 			if _, err := tx.Run(ctx, cypher, map[string]any{
-				"id": subID, "name": sub, "sysID": sysID,
+				"id": subID, "name": sub, "sysID": sysID, "docID": docID,
 			}); err != nil {
 				return nil, err
 			}
@@ -229,6 +230,14 @@ func (e *Enricher) EnrichFromSource(ctx context.Context, vi VehicleInfo, compone
 	return err
 }
 
+// addSourceDoc returns a Cypher clause appending $docID to the source_docs
+// list of node v, unless it is empty or already listed.
+func addSourceDoc(v string) string {
+	return fmt.Sprintf(`
+		           FOREACH (_ IN CASE WHEN $docID <> '' AND NOT $docID IN coalesce(%[1]s.source_docs, []) THEN [1] ELSE [] END |
+		             SET %[1]s.source_docs = coalesce(%[1]s.source_docs, []) + $docID)`, v)
+}
+
 // vehicleScopePrefix returns "make-model-year" for scoped node IDs.
 func vehicleScopePrefix(vi VehicleInfo) string {
 	return fmt.Sprintf("%s-%s-%d",
@@ -248,7 +257,8 @@ func modelYearID(vi VehicleInfo) string {
 }
 
 // EnrichFromManualExtraction processes structured extraction output from the Python manual worker.
-// It creates Component nodes with specs, edges between components, and Procedure nodes.
+// It creates Component nodes with specs, edges between components, and Procedure nodes
+// with their Step children.
 func (e *Enricher) EnrichFromManualExtraction(ctx context.Context, vi VehicleInfo, extraction ManualExtraction) error {
 	if len(extraction.Components) == 0 && len(extraction.Relationships) == 0 && len(extraction.Procedures) == 0 {
 		return nil
@@ -331,32 +341,13 @@ func (e *Enricher) EnrichFromManualExtraction(ctx context.Context, vi VehicleInf
 			}
 		}
 
-		// Create Procedure nodes.
-		for i, proc := range extraction.Procedures {
-			procID := fmt.Sprintf("%s:proc-%d-%s", vehiclePrefix, i, sanitizeID(proc.Title))
-			props := map[string]any{
-				"id":    procID,
-				"title": proc.Title,
-			}
-			if len(proc.Steps) > 0 {
-				props["steps"] = strings.Join(proc.Steps, " || ")
-			}
-			if len(proc.ToolsRequired) > 0 {
-				props["tools_required"] = strings.Join(proc.ToolsRequired, ", ")
-			}
-			if len(proc.Warnings) > 0 {
-				props["warnings"] = strings.Join(proc.Warnings, " || ")
-			}
-
-			cypher := `MERGE (p:Procedure {id: $id}) SET p += $props`
-			if _, err := tx.Run(ctx, cypher, map[string]any{"id": procID, "props": props}); err != nil {
-				return nil, err
+		// Create Procedure nodes with their steps.
+		for _, proc := range extraction.Procedures {
+			p := Procedure{Title: proc.Title, Tools: proc.ToolsRequired, Warnings: proc.Warnings, Source: "manual"}
+			for i, step := range proc.Steps {
+				p.Steps = append(p.Steps, ProcedureStep{Order: i + 1, Text: step})
 			}
-
-			// Link procedure to ModelYear.
-			cypher = `MATCH (my:ModelYear {id: $myID}), (p:Procedure {id: $pID})
-			          MERGE (my)-[:HAS_PROCEDURE]->(p)`
-			if _, err := tx.Run(ctx, cypher, map[string]any{"myID": myID, "pID": procID}); err != nil {
+			if err := mergeProcedure(ctx, tx, vi, p, ""); err != nil {
 				return nil, err
 			}
 		}
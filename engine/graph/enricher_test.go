@@ -10,11 +10,17 @@ import (
 type trackingTx struct {
 	queries []string
 	params  []map[string]any
+	results []CypherResult // returned by the first queries, in order
 }
 
 func (t *trackingTx) Run(_ context.Context, cypher string, params map[string]any) (CypherResult, error) {
 	t.queries = append(t.queries, cypher)
 	t.params = append(t.params, params)
+	if len(t.results) > 0 {
+		r := t.results[0]
+		t.results = t.results[1:]
+		return r, nil
+	}
 	return newMockResult(), nil
 }
 
@@ -0,0 +1,268 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// FuseEntry is one position of a fuse or relay box chart read from a manual.
+type FuseEntry struct {
+	Kind     string   `json:"kind"`               // fuse or relay
+	Position string   `json:"position"`           // label in the box, e.g. "F12", "37", "R3"
+	Box      string   `json:"box,omitempty"`      // engine bay, cabin
+	Amperage float64  `json:"amperage,omitempty"` // fuses only
+	Type     string   `json:"type,omitempty"`     // mini, micro, maxi, cartridge, standard
+	Circuits []string `json:"circuits,omitempty"` // what the fuse protects or the relay switches
+	Page     int      `json:"page,omitempty"`
+}
+
+// FuseEntry kinds.
+const (
+	FuseKindFuse  = "fuse"
+	FuseKindRelay = "relay"
+)
+
+// FuseID returns the vehicle-scoped ID of a Fuse or Relay node.
+func FuseID(vi VehicleInfo, f FuseEntry) string {
+	box := sanitizeID(f.Box)
+	if box == "" {
+		box = "main"
+	}
+	return vehicleScopePrefix(vi) + ":" + f.Kind + ":" + box + ":" + sanitizeID(f.Position)
+}
+
+// EnrichFromFuseChart stores fuse box chart entries as Fuse and Relay nodes
+// under the vehicle's Electrical > Fuse Box subsystem. Each fuse gets a
+// PROTECTS edge and each relay a CONTROLS edge to a vehicle-scoped Component
+// per circuit. docID is recorded in source_docs of the nodes and edges for
+// RetractDocument.
+func (e *Enricher) EnrichFromFuseChart(ctx context.Context, vi VehicleInfo, entries []FuseEntry, docID string) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	if err := e.graph.EnsureVehicleHierarchy(ctx, vi); err != nil {
+		return fmt.Errorf("enricher: vehicle hierarchy: %w", err)
+	}
+
+	vehiclePrefix := vehicleScopePrefix(vi)
+	myID := modelYearID(vi)
+	sysID := vehiclePrefix + ":" + sanitizeID("Electrical")
+	subID := sysID + ":" + sanitizeID("Fuse Box")
+
+	sess := e.graph.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	_, err := sess.ExecuteWrite(ctx, func(tx CypherRunner) (any, error) {
+		cypher := `MERGE (s:System {id: $id}) SET s.name = $name` + addSourceDoc("s") + `
+		           WITH s
+		           MATCH (my:ModelYear {id: $myID})
+		           MERGE (my)-[:HAS_SYSTEM]->(s)`
+		if _, err := tx.Run(ctx, cypher, map[string]any{
+			"id": sysID, "name": "Electrical", "myID": myID, "docID": docID,
+		}); err != nil {
+			return nil, err
+		}
+		cypher = `MERGE (ss:Subsystem {id: $id}) SET ss.name = $name, ss.system_id = $sysID` + addSourceDoc("ss") + `
+		          WITH ss
+		          MATCH (s:System {id: $sysID})
+		          MERGE (s)-[:HAS_SUBSYSTEM]->(ss)`
+		if _, err := tx.Run(ctx, cypher, map[string]any{
+			"id": subID, "name": "Fuse Box", "sysID": sysID, "docID": docID,
+		}); err != nil {
+			return nil, err
+		}
+
+		for _, f := range entries {
+			label, rel := "Fuse", "PROTECTS"
+			switch {
+			case f.Position == "":
+				continue
+			case f.Kind == FuseKindRelay:
+				label, rel = "Relay", "CONTROLS"
+			case f.Kind != FuseKindFuse:
+				continue
+			}
+
+			id := FuseID(vi, f)
+			name := strings.TrimSpace(f.Box + " " + f.Kind + " " + f.Position)
+			props := map[string]any{
+				"name":     name,
+				"kind":     f.Kind,
+				"position": f.Position,
+				"box":      f.Box,
+			}
+			if f.Amperage > 0 {
+				props["amperage"] = f.Amperage
+			}
+			if f.Type != "" {
+				props["fuse_type"] = f.Type
+			}
+			if f.Page > 0 {
+				props["page"] = f.Page
+			}
+
+			cypher = fmt.Sprintf(`MERGE (f:%s {id: $id}) SET f += $props`, label) + addSourceDoc("f") + `
+			         WITH f
+			         MATCH (ss:Subsystem {id: $subID})
+			         MERGE (ss)-[:HAS_COMPONENT]->(f)`
+			if _, err := tx.Run(ctx, cypher, map[string]any{
+				"id": id, "props": props, "subID": subID, "docID": docID,
+			}); err != nil {
+				return nil, err
+			}
+
+			for _, circuit := range f.Circuits {
+				if strings.TrimSpace(circuit) == "" {
+					continue
+				}
+				cypher = `MERGE (c:Component {id: $cID})
+				          ON CREATE SET c.name = $name, c.type = 'component'` + addSourceDoc("c") + fmt.Sprintf(`
+				          WITH c
+				          MATCH (f:%s {id: $fID})
+				          MERGE (f)-[r:%s]->(c)`, label, rel) + addSourceDoc("r")
+				if _, err := tx.Run(ctx, cypher, map[string]any{
+					"cID": vehiclePrefix + ":" + sanitizeID(circuit), "name": circuit, "fID": id, "docID": docID,
+				}); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// FuseChart returns the fuses and relays recorded for a ModelYear, e.g.
+// "ford-f-150-2018", with the circuits each protects or switches.
+func (g *GraphStore) FuseChart(ctx context.Context, modelYearID string) ([]FuseEntry, error) {
+	sess := g.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	cypher := `MATCH (:ModelYear {id: $myID})-[:HAS_SYSTEM]->(:System)-[:HAS_SUBSYSTEM]->(:Subsystem)-[:HAS_COMPONENT]->(f)
+	           WHERE f:Fuse OR f:Relay
+	           OPTIONAL MATCH (f)-[:PROTECTS|CONTROLS]->(c:Component)
+	           WITH f, collect(c.name) AS circuits
+	           RETURN f.kind AS kind, f.position AS position, f.box AS box, f.amperage AS amperage,
+	                  f.fuse_type AS type, f.page AS page, circuits
+	           ORDER BY box, kind, position`
+	result, err := sess.Run(ctx, cypher, map[string]any{"myID": modelYearID})
+	if err != nil {
+		return nil, fmt.Errorf("graph: fuse chart %s: %w", modelYearID, err)
+	}
+	var out []FuseEntry
+	for result.Next(ctx) {
+		rec := result.Record()
+		get := func(key string) any {
+			v, _ := rec.Get(key)
+			return v
+		}
+		f := FuseEntry{}
+		f.Kind, _ = get("kind").(string)
+		f.Position, _ = get("position").(string)
+		f.Box, _ = get("box").(string)
+		f.Type, _ = get("type").(string)
+		switch n := get("amperage").(type) {
+		case float64:
+			f.Amperage = n
+		case int64:
+			f.Amperage = float64(n)
+		}
+		if n, ok := get("page").(int64); ok {
+			f.Page = int(n)
+		}
+		if cs, ok := get("circuits").([]any); ok {
+			for _, c := range cs {
+				if s, ok := c.(string); ok {
+					f.Circuits = append(f.Circuits, s)
+				}
+			}
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+// FindFuses returns the fuses and relays of a ModelYear whose circuits match
+// query, e.g. "power outlet". An empty query returns the whole chart.
+func (g *GraphStore) FindFuses(ctx context.Context, modelYearID, query string) ([]FuseEntry, error) {
+	chart, err := g.FuseChart(ctx, modelYearID)
+	if err != nil || strings.TrimSpace(query) == "" {
+		return chart, err
+	}
+	var out []FuseEntry
+	for _, f := range chart {
+		if f.Serves(query) {
+			out = append(out, f)
+		}
+	}
+	return out, nil
+}
+
+// Serves reports whether one of the entry's circuits mentions every word of
+// query. Words are compared case-insensitively and charts' many names for a
+// 12 V socket ("power point", "accessory socket", "cigar lighter") are
+// folded together, so "power outlet" finds them all. A query made only of
+// words the folding drops, such as "power" or "starter motor"'s "motor",
+// is matched word for word instead.
+func (f FuseEntry) Serves(query string) bool {
+	terms := circuitTerms
+	want := terms(query)
+	if len(want) == 0 {
+		terms = circuitWords
+		want = terms(query)
+	}
+	if len(want) == 0 {
+		return false
+	}
+	for _, c := range f.Circuits {
+		have := map[string]bool{}
+		for _, t := range terms(c) {
+			have[t] = true
+		}
+		all := true
+		for _, t := range want {
+			if !have[t] {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true
+		}
+	}
+	return false
+}
+
+// circuitSynonyms folds circuit name variants onto one term. An empty
+// value drops the word.
+var circuitSynonyms = map[string]string{
+	"outlet": "outlet", "outlets": "outlet", "socket": "outlet", "sockets": "outlet",
+	"point": "outlet", "points": "outlet", "receptacle": "outlet", "lighter": "outlet",
+	"cigar": "outlet", "power": "", "accessory": "", "12v": "", "the": "", "for": "",
+	"a": "", "of": "", "and": "", "lamp": "light", "lamps": "light", "lights": "light",
+	"headlamp": "headlight", "headlamps": "headlight", "headlights": "headlight",
+	"wipers": "wiper", "motor": "",
+}
+
+// circuitWords splits s into lower-case words.
+func circuitWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// circuitTerms returns the words of s folded by circuitSynonyms.
+func circuitTerms(s string) []string {
+	var terms []string
+	for _, w := range circuitWords(s) {
+		if t, ok := circuitSynonyms[w]; ok {
+			w = t
+		}
+		if w != "" {
+			terms = append(terms, w)
+		}
+	}
+	return terms
+}
@@ -0,0 +1,114 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestEnrichFromFuseChart(t *testing.T) {
+	gs, tx := newTrackingStore()
+	vi := VehicleInfo{Make: "Ford", Model: "F-150", Year: 2018}
+	entries := []FuseEntry{
+		{Kind: FuseKindFuse, Position: "F12", Box: "cabin", Amperage: 20, Type: "mini", Circuits: []string{"Power point 1", "Rear console"}, Page: 241},
+		{Kind: FuseKindRelay, Position: "R3", Box: "engine bay", Circuits: []string{"Starter motor"}},
+		{Kind: FuseKindFuse}, // no position, skipped
+	}
+	if err := NewEnricher(gs).EnrichFromFuseChart(context.Background(), vi, entries, "manual-1"); err != nil {
+		t.Fatalf("EnrichFromFuseChart: %v", err)
+	}
+
+	var fuse, relay, subsystem map[string]any
+	var protects, controls []map[string]any
+	for i, q := range tx.queries {
+		switch {
+		case strings.Contains(q, "MERGE (f:Fuse"):
+			fuse = tx.params[i]
+		case strings.Contains(q, "MERGE (f:Relay"):
+			relay = tx.params[i]
+		case strings.Contains(q, "MERGE (ss:Subsystem"):
+			subsystem = tx.params[i]
+		case strings.Contains(q, "[r:PROTECTS]"):
+			protects = append(protects, tx.params[i])
+			if strings.Count(q, "source_docs") < 2 {
+				t.Errorf("circuit and PROTECTS edge should record the document: %s", q)
+			}
+		case strings.Contains(q, "[r:CONTROLS]"):
+			controls = append(controls, tx.params[i])
+		}
+	}
+	if subsystem["id"] != "ford-f-150-2018:electrical:fuse-box" || subsystem["docID"] != "manual-1" {
+		t.Errorf("unexpected subsystem params %v", subsystem)
+	}
+	if fuse["id"] != "ford-f-150-2018:fuse:cabin:f12" || fuse["subID"] != "ford-f-150-2018:electrical:fuse-box" {
+		t.Errorf("unexpected fuse params %v", fuse)
+	}
+	props := fuse["props"].(map[string]any)
+	if props["amperage"] != 20.0 || props["fuse_type"] != "mini" || props["page"] != 241 {
+		t.Errorf("unexpected fuse props %v", props)
+	}
+	if relay["id"] != "ford-f-150-2018:relay:engine-bay:r3" {
+		t.Errorf("unexpected relay params %v", relay)
+	}
+	if len(protects) != 2 || protects[0]["cID"] != "ford-f-150-2018:power-point-1" || protects[0]["fID"] != fuse["id"] || protects[0]["docID"] != "manual-1" {
+		t.Errorf("unexpected PROTECTS edges %v", protects)
+	}
+	if len(controls) != 1 || controls[0]["cID"] != "ford-f-150-2018:starter-motor" {
+		t.Errorf("unexpected CONTROLS edges %v", controls)
+	}
+}
+
+func TestFindFuses(t *testing.T) {
+	rec := func(kind, pos string, amps float64, circuits ...any) *neo4j.Record {
+		return &neo4j.Record{
+			Keys:   []string{"kind", "position", "box", "amperage", "type", "page", "circuits"},
+			Values: []any{kind, pos, "cabin", amps, "mini", int64(241), circuits},
+		}
+	}
+	sess := &mockSession{runResult: newMockResult(
+		rec("fuse", "F12", 20, "Power point 1"),
+		rec("fuse", "F13", 10, "Radio"),
+		rec("fuse", "F40", 20, "Cigar lighter"),
+		rec("relay", "R3", 0, "Blower motor"),
+	)}
+	gs := NewWithOpener(&mockOpener{session: sess})
+
+	fuses, err := gs.FindFuses(context.Background(), "ford-f-150-2018", "power outlet")
+	if err != nil {
+		t.Fatalf("FindFuses: %v", err)
+	}
+	if len(fuses) != 2 || fuses[0].Position != "F12" || fuses[1].Position != "F40" {
+		t.Fatalf("unexpected fuses %+v", fuses)
+	}
+	if f := fuses[0]; f.Amperage != 20 || f.Box != "cabin" || f.Type != "mini" || f.Page != 241 {
+		t.Errorf("unexpected fuse fields %+v", f)
+	}
+}
+
+func TestFuseEntryServes(t *testing.T) {
+	f := FuseEntry{Circuits: []string{"Accessory power socket (rear)", "Windshield wipers"}}
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"power outlet", true},
+		{"rear 12V socket", true},
+		{"wiper", true},
+		{"front power outlet", false},
+		// Only dropped words: match them as written.
+		{"power", true},
+		{"accessory power", true},
+		{"motor", false},
+		{"the", false},
+	}
+	for _, tt := range tests {
+		if got := f.Serves(tt.query); got != tt.want {
+			t.Errorf("Serves(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+	if starter := (FuseEntry{Circuits: []string{"Starter motor"}}); !starter.Serves("motor") {
+		t.Error(`"motor" should find the starter motor`)
+	}
+}
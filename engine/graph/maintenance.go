@@ -0,0 +1,256 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MaintenanceItem is one operation of a manual's maintenance schedule, e.g.
+// "Rotate tires every 7,500 miles (12,000 km) or 6 months". Intervals are
+// whichever comes first; zero means the schedule gives none.
+type MaintenanceItem struct {
+	Operation string `json:"operation"`           // e.g. "Rotate tires"
+	Component string `json:"component,omitempty"` // what is serviced, e.g. "Tires"
+	Miles     int    `json:"interval_miles,omitempty"`
+	Km        int    `json:"interval_km,omitempty"`
+	Months    int    `json:"interval_months,omitempty"`
+	Severe    bool   `json:"severe,omitempty"` // the severe-service variant of the operation
+	Page      int    `json:"page,omitempty"`
+}
+
+// MaintenanceItemID returns the vehicle-scoped ID of a MaintenanceItem node.
+func MaintenanceItemID(vi VehicleInfo, m MaintenanceItem) string {
+	id := vehicleScopePrefix(vi) + ":maintenance:" + sanitizeID(m.Operation)
+	if m.Severe {
+		id += ":severe"
+	}
+	return id
+}
+
+// IntervalMiles returns the mileage interval, converting from kilometres
+// when the schedule only gives those.
+func (m MaintenanceItem) IntervalMiles() int {
+	if m.Miles > 0 || m.Km == 0 {
+		return m.Miles
+	}
+	return int(float64(m.Km)*0.621371 + 0.5)
+}
+
+// EnrichFromMaintenance stores schedule operations as MaintenanceItem nodes
+// linked to the ModelYear with HAS_MAINTENANCE, and with SERVICES to the
+// vehicle-scoped Component they service. docID is recorded in source_docs
+// for RetractDocument.
+func (e *Enricher) EnrichFromMaintenance(ctx context.Context, vi VehicleInfo, items []MaintenanceItem, docID string) error {
+	if len(items) == 0 {
+		return nil
+	}
+	if err := e.graph.EnsureVehicleHierarchy(ctx, vi); err != nil {
+		return fmt.Errorf("enricher: vehicle hierarchy: %w", err)
+	}
+
+	vehiclePrefix := vehicleScopePrefix(vi)
+	myID := modelYearID(vi)
+
+	sess := e.graph.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	_, err := sess.ExecuteWrite(ctx, func(tx CypherRunner) (any, error) {
+		for _, m := range items {
+			if m.Operation == "" || (m.Miles == 0 && m.Km == 0 && m.Months == 0) {
+				continue
+			}
+			id := MaintenanceItemID(vi, m)
+			props := map[string]any{
+				"name":      m.Operation,
+				"operation": m.Operation,
+				"severe":    m.Severe,
+			}
+			for k, v := range map[string]int{"interval_miles": m.Miles, "interval_km": m.Km, "interval_months": m.Months, "page": m.Page} {
+				if v > 0 {
+					props[k] = v
+				}
+			}
+			if m.Component != "" {
+				props["component"] = m.Component
+			}
+
+			cypher := `MERGE (m:MaintenanceItem {id: $id}) SET m += $props` + addSourceDoc("m") + `
+			           WITH m
+			           MATCH (my:ModelYear {id: $myID})
+			           MERGE (my)-[:HAS_MAINTENANCE]->(m)`
+			if _, err := tx.Run(ctx, cypher, map[string]any{
+				"id": id, "props": props, "myID": myID, "docID": docID,
+			}); err != nil {
+				return nil, err
+			}
+
+			if m.Component == "" {
+				continue
+			}
+			cypher = `MERGE (c:Component {id: $cID})
+			          ON CREATE SET c.name = $name, c.type = 'component'
+			          WITH c
+			          MATCH (m:MaintenanceItem {id: $mID})
+			          MERGE (m)-[:SERVICES]->(c)`
+			if _, err := tx.Run(ctx, cypher, map[string]any{
+				"cID": vehiclePrefix + ":" + sanitizeID(m.Component), "name": m.Component, "mID": id,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// MaintenanceSchedule returns the schedule recorded for a ModelYear, e.g.
+// "ford-f-150-2018", normal and severe-service items alike.
+func (g *GraphStore) MaintenanceSchedule(ctx context.Context, modelYearID string) ([]MaintenanceItem, error) {
+	sess := g.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	cypher := `MATCH (:ModelYear {id: $myID})-[:HAS_MAINTENANCE]->(m:MaintenanceItem)
+	           RETURN m.operation AS operation, m.component AS component, m.interval_miles AS miles,
+	                  m.interval_km AS km, m.interval_months AS months, m.severe AS severe, m.page AS page
+	           ORDER BY operation`
+	result, err := sess.Run(ctx, cypher, map[string]any{"myID": modelYearID})
+	if err != nil {
+		return nil, fmt.Errorf("graph: maintenance schedule %s: %w", modelYearID, err)
+	}
+	var out []MaintenanceItem
+	for result.Next(ctx) {
+		rec := result.Record()
+		get := func(key string) any {
+			v, _ := rec.Get(key)
+			return v
+		}
+		num := func(key string) int {
+			n, _ := get(key).(int64)
+			return int(n)
+		}
+		m := MaintenanceItem{Miles: num("miles"), Km: num("km"), Months: num("months"), Page: num("page")}
+		m.Operation, _ = get("operation").(string)
+		m.Component, _ = get("component").(string)
+		m.Severe, _ = get("severe").(bool)
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+// Maintenance statuses.
+const (
+	MaintenanceDue      = "due"
+	MaintenanceOverdue  = "overdue"
+	MaintenanceUpcoming = "upcoming"
+)
+
+// MaintenanceWindow bounds what ScheduleAt reports as due and upcoming.
+type MaintenanceWindow struct {
+	Miles, Months           int // a service point this close, either side, is due
+	AheadMiles, AheadMonths int // a service point this far ahead is upcoming
+}
+
+// DefaultMaintenanceWindow is due within 1,000 miles or a month, upcoming
+// within 5,000 miles or 3 months.
+var DefaultMaintenanceWindow = MaintenanceWindow{Miles: 1000, Months: 1, AheadMiles: 5000, AheadMonths: 3}
+
+// ScheduledService is a MaintenanceItem placed against a vehicle's mileage.
+type ScheduledService struct {
+	MaintenanceItem
+	Status     string `json:"status"`
+	AtMiles    int    `json:"at_miles,omitempty"` // the service point the status refers to
+	AtMonths   int    `json:"at_months,omitempty"`
+	OverdueBy  int    `json:"overdue_by_miles,omitempty"`
+	DueInMiles int    `json:"due_in_miles,omitempty"`
+}
+
+// ScheduleAt places each operation of a schedule against the vehicle's
+// mileage and, when known (ageMonths > 0), its age, with service points
+// at every multiple of the interval. With no service history, a point
+// within w of the vehicle either side is due, one passed by more than that
+// is overdue (unless the next is already due or upcoming), and one within
+// the look-ahead is upcoming. Operations with nothing to report are left
+// out. severe selects the severe-service variant of operations that have
+// one. Results are sorted by status, then by the service point.
+func ScheduleAt(items []MaintenanceItem, mileage, ageMonths int, severe bool, w MaintenanceWindow) []ScheduledService {
+	chosen := map[string]MaintenanceItem{}
+	var order []string
+	for _, m := range items {
+		key := strings.ToLower(m.Operation)
+		prev, seen := chosen[key]
+		switch {
+		case m.Severe && !severe:
+			continue
+		case !seen:
+			order = append(order, key)
+		case prev.Severe || !m.Severe:
+			continue // keep the severe variant, or the first normal one
+		}
+		chosen[key] = m
+	}
+
+	var out []ScheduledService
+	for _, key := range order {
+		m := chosen[key]
+		s := ScheduledService{MaintenanceItem: m}
+		if iv := m.IntervalMiles(); iv > 0 && mileage >= 0 {
+			s.Status, s.AtMiles = servicePoint(mileage, iv, w.Miles, w.AheadMiles)
+			switch s.Status {
+			case MaintenanceOverdue:
+				s.OverdueBy = mileage - s.AtMiles
+			case MaintenanceUpcoming:
+				s.DueInMiles = s.AtMiles - mileage
+			}
+		}
+		if m.Months > 0 && ageMonths > 0 {
+			status, at := servicePoint(ageMonths, m.Months, w.Months, w.AheadMonths)
+			if statusRank(status) < statusRank(s.Status) {
+				s.Status, s.AtMonths = status, at
+				s.AtMiles, s.OverdueBy, s.DueInMiles = 0, 0, 0
+			}
+		}
+		if s.Status != "" {
+			out = append(out, s)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if ri, rj := statusRank(out[i].Status), statusRank(out[j].Status); ri != rj {
+			return ri < rj
+		}
+		return out[i].AtMiles < out[j].AtMiles
+	})
+	return out
+}
+
+// servicePoint classifies value against service points at multiples of
+// interval, returning the point the status refers to.
+func servicePoint(value, interval, window, ahead int) (string, int) {
+	last := value / interval * interval
+	next := last + interval
+	switch {
+	case last > 0 && value-last <= window:
+		return MaintenanceDue, last
+	case next-value <= window:
+		return MaintenanceDue, next
+	case next-value <= ahead:
+		return MaintenanceUpcoming, next
+	case last > 0:
+		return MaintenanceOverdue, last
+	}
+	return "", 0
+}
+
+// statusRank orders statuses by urgency; "" ranks last.
+func statusRank(status string) int {
+	switch status {
+	case MaintenanceOverdue:
+		return 0
+	case MaintenanceDue:
+		return 1
+	case MaintenanceUpcoming:
+		return 2
+	}
+	return 3
+}
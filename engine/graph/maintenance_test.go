@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestEnrichFromMaintenance(t *testing.T) {
+	gs, tx := newTrackingStore()
+	vi := VehicleInfo{Make: "Honda", Model: "Civic", Year: 2019}
+	items := []MaintenanceItem{
+		{Operation: "Rotate tires", Component: "Tires", Miles: 7500, Km: 12000, Months: 6, Page: 410},
+		{Operation: "Replace engine oil", Component: "Engine oil", Miles: 3750, Severe: true},
+		{Operation: "Inspect brakes"}, // no interval, skipped
+	}
+	if err := NewEnricher(gs).EnrichFromMaintenance(context.Background(), vi, items, "manual-1"); err != nil {
+		t.Fatalf("EnrichFromMaintenance: %v", err)
+	}
+
+	var merged, services []map[string]any
+	for i, q := range tx.queries {
+		switch {
+		case strings.Contains(q, "MERGE (m:MaintenanceItem"):
+			merged = append(merged, tx.params[i])
+		case strings.Contains(q, "[:SERVICES]"):
+			services = append(services, tx.params[i])
+		}
+	}
+	if len(merged) != 2 {
+		t.Fatalf("expected 2 maintenance items, got %v", tx.queries)
+	}
+	if merged[0]["id"] != "honda-civic-2019:maintenance:rotate-tires" || merged[0]["myID"] != "honda-civic-2019" || merged[0]["docID"] != "manual-1" {
+		t.Errorf("unexpected item params %v", merged[0])
+	}
+	props := merged[0]["props"].(map[string]any)
+	if props["interval_miles"] != 7500 || props["interval_km"] != 12000 || props["interval_months"] != 6 || props["page"] != 410 {
+		t.Errorf("unexpected item props %v", props)
+	}
+	if merged[1]["id"] != "honda-civic-2019:maintenance:replace-engine-oil:severe" {
+		t.Errorf("expected a separate severe-service node, got %v", merged[1]["id"])
+	}
+	if len(services) != 2 || services[0]["cID"] != "honda-civic-2019:tires" || services[0]["mID"] != merged[0]["id"] {
+		t.Errorf("unexpected SERVICES edges %v", services)
+	}
+}
+
+func TestMaintenanceSchedule(t *testing.T) {
+	keys := []string{"operation", "component", "miles", "km", "months", "severe", "page"}
+	sess := &mockSession{runResult: newMockResult(
+		&neo4j.Record{Keys: keys, Values: []any{"Rotate tires", "Tires", int64(7500), int64(12000), int64(6), false, int64(410)}},
+		&neo4j.Record{Keys: keys, Values: []any{"Replace coolant", nil, nil, nil, int64(120), true, nil}},
+	)}
+	gs := NewWithOpener(&mockOpener{session: sess})
+	items, err := gs.MaintenanceSchedule(context.Background(), "honda-civic-2019")
+	if err != nil {
+		t.Fatalf("MaintenanceSchedule: %v", err)
+	}
+	if len(items) != 2 || items[0].Miles != 7500 || items[0].Component != "Tires" || items[0].Page != 410 {
+		t.Fatalf("unexpected items %+v", items)
+	}
+	if items[1].Months != 120 || !items[1].Severe || items[1].Miles != 0 {
+		t.Errorf("unexpected time-only item %+v", items[1])
+	}
+}
+
+func TestScheduleAt(t *testing.T) {
+	items := []MaintenanceItem{
+		{Operation: "Replace engine oil", Miles: 7500, Months: 12},
+		{Operation: "Replace engine oil", Miles: 3750, Months: 6, Severe: true},
+		{Operation: "Rotate tires", Miles: 7500},
+		{Operation: "Replace spark plugs", Miles: 100000},
+		{Operation: "Replace cabin air filter", Km: 24000}, // ~14,913 miles
+		{Operation: "Replace timing belt", Miles: 105000},
+	}
+	byOp := func(got []ScheduledService) map[string]ScheduledService {
+		m := map[string]ScheduledService{}
+		for _, s := range got {
+			m[s.Operation] = s
+		}
+		return m
+	}
+
+	got := byOp(ScheduleAt(items, 30400, 0, false, DefaultMaintenanceWindow))
+	if s := got["Replace engine oil"]; s.Status != MaintenanceDue || s.AtMiles != 30000 || s.Severe {
+		t.Errorf("oil: %+v", s)
+	}
+	if s := got["Replace cabin air filter"]; s.Status != MaintenanceDue || s.AtMiles != 29826 {
+		t.Errorf("cabin filter from km: %+v", s)
+	}
+	if s := got["Replace spark plugs"]; s.Status != "" {
+		t.Errorf("spark plugs are far off and should be left out: %+v", s)
+	}
+
+	got = byOp(ScheduleAt(items, 103000, 0, false, DefaultMaintenanceWindow))
+	if s := got["Replace spark plugs"]; s.Status != MaintenanceOverdue || s.AtMiles != 100000 || s.OverdueBy != 3000 {
+		t.Errorf("spark plugs: %+v", s)
+	}
+	if s := got["Replace timing belt"]; s.Status != MaintenanceUpcoming || s.AtMiles != 105000 || s.DueInMiles != 2000 {
+		t.Errorf("timing belt: %+v", s)
+	}
+
+	// Severe service picks the shorter variant; age can make an item due first.
+	got = byOp(ScheduleAt(items, 5000, 12, true, DefaultMaintenanceWindow))
+	if s := got["Replace engine oil"]; !s.Severe || s.Status != MaintenanceDue || s.AtMonths != 12 {
+		t.Errorf("severe oil: %+v", s)
+	}
+
+	list := ScheduleAt(items, 103000, 0, false, DefaultMaintenanceWindow)
+	if list[0].Status != MaintenanceOverdue || list[len(list)-1].Status != MaintenanceUpcoming {
+		t.Errorf("expected results ordered by urgency, got %+v", list)
+	}
+}
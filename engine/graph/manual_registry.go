@@ -26,6 +26,11 @@ type ManualEntry struct {
 	DownloadedAt *time.Time `json:"downloaded_at,omitempty"`
 	IngestedAt   *time.Time `json:"ingested_at,omitempty"`
 	LocalPath    string     `json:"local_path,omitempty"`
+	SHA256       string     `json:"sha256,omitempty"` // hex digest of the downloaded file
+	ETag         string     `json:"etag,omitempty"`   // validators for conditional re-downloads
+	LastModified string     `json:"last_modified,omitempty"`
+	DuplicateOf  string     `json:"duplicate_of,omitempty"` // ID of the entry with the same content
+	Blob         string     `json:"blob,omitempty"`         // SHA-256 key of the PDF in the blob store; empty once evicted
 	Status       string     `json:"status"`
 	Error        string     `json:"error,omitempty"`
 }
@@ -73,6 +78,15 @@ func (g *GraphStore) SaveManualEntry(ctx context.Context, m ManualEntry) error {
 		"error":         m.Error,
 		"local_path":    m.LocalPath,
 	}
+	// Download results are only set, so re-discovering a manual keeps them.
+	for k, v := range map[string]string{
+		"sha256": m.SHA256, "etag": m.ETag, "last_modified": m.LastModified, "duplicate_of": m.DuplicateOf,
+		"blob": m.Blob,
+	} {
+		if v != "" {
+			props[k] = v
+		}
+	}
 	if m.DownloadedAt != nil {
 		props["downloaded_at"] = m.DownloadedAt.Unix()
 	}
@@ -81,10 +95,55 @@ func (g *GraphStore) SaveManualEntry(ctx context.Context, m ManualEntry) error {
 	}
 
 	cypher := `MERGE (n:ManualEntry {id: $id}) SET n += $props`
-	_, err := sess.Run(ctx, cypher, map[string]any{"id": m.ID, "props": props})
+	if _, err := sess.Run(ctx, cypher, map[string]any{"id": m.ID, "props": props}); err != nil {
+		return err
+	}
+	if m.DuplicateOf == "" {
+		return nil
+	}
+	cypher = `MATCH (n:ManualEntry {id: $id}), (o:ManualEntry {id: $orig})
+	          MERGE (n)-[:DUPLICATE_OF]->(o)`
+	_, err := sess.Run(ctx, cypher, map[string]any{"id": m.ID, "orig": m.DuplicateOf})
 	return err
 }
 
+// FindManualByHash returns the first entry other than excludeID whose
+// downloaded file has the given SHA-256, or nil. Duplicates are skipped so
+// the entry returned is the one holding the content.
+func (g *GraphStore) FindManualByHash(ctx context.Context, sha256Hex, excludeID string) (*ManualEntry, error) {
+	sess := g.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	cypher := `MATCH (n:ManualEntry {sha256: $hash})
+	           WHERE n.id <> $exclude AND coalesce(n.duplicate_of, '') = ''
+	           RETURN n ORDER BY n.downloaded_at LIMIT 1`
+	result, err := sess.Run(ctx, cypher, map[string]any{"hash": sha256Hex, "exclude": excludeID})
+	if err != nil {
+		return nil, err
+	}
+	entries, err := collectManualEntries(ctx, result)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return &entries[0], nil
+}
+
+// GetManual returns the entry with the given ID, or nil.
+func (g *GraphStore) GetManual(ctx context.Context, id string) (*ManualEntry, error) {
+	sess := g.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	result, err := sess.Run(ctx, `MATCH (n:ManualEntry {id: $id}) RETURN n`, map[string]any{"id": id})
+	if err != nil {
+		return nil, err
+	}
+	entries, err := collectManualEntries(ctx, result)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return &entries[0], nil
+}
+
 // FindManuals returns manuals matching the given filter.
 func (g *GraphStore) FindManuals(ctx context.Context, f ManualFilter) ([]ManualEntry, error) {
 	sess := g.opener.OpenSession(ctx)
@@ -153,6 +212,53 @@ func (g *GraphStore) GetPendingIngestion(ctx context.Context, limit int) ([]Manu
 	return collectManualEntries(ctx, result)
 }
 
+// GetStoredManuals returns up to limit manuals whose PDF is in the blob
+// store, skipping duplicates of another entry.
+func (g *GraphStore) GetStoredManuals(ctx context.Context, limit int) ([]ManualEntry, error) {
+	sess := g.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	cypher := `MATCH (n:ManualEntry) WHERE n.blob IS NOT NULL AND coalesce(n.duplicate_of, '') = ''
+	           RETURN n ORDER BY n.id LIMIT $limit`
+	result, err := sess.Run(ctx, cypher, map[string]any{"limit": limit})
+	if err != nil {
+		return nil, err
+	}
+	return collectManualEntries(ctx, result)
+}
+
+// PendingManualBlobs returns the blob keys of manuals downloaded but not
+// yet ingested, which blob retention must keep.
+func (g *GraphStore) PendingManualBlobs(ctx context.Context) ([]string, error) {
+	sess := g.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	cypher := `MATCH (n:ManualEntry {status: "downloaded"}) WHERE n.blob IS NOT NULL RETURN DISTINCT n.blob AS blob`
+	result, err := sess.Run(ctx, cypher, nil)
+	if err != nil {
+		return nil, err
+	}
+	var blobs []string
+	for result.Next(ctx) {
+		if b, ok := result.Record().Get("blob"); ok {
+			if s, ok := b.(string); ok {
+				blobs = append(blobs, s)
+			}
+		}
+	}
+	return blobs, nil
+}
+
+// ClearManualBlob drops the reference to an evicted blob from every entry
+// holding it, so downloads fall back to the origin URL.
+func (g *GraphStore) ClearManualBlob(ctx context.Context, sum string) error {
+	sess := g.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	_, err := sess.Run(ctx, `MATCH (n:ManualEntry {blob: $blob}) REMOVE n.blob`, map[string]any{"blob": sum})
+	return err
+}
+
 // ManualStats returns aggregate counts for manual entries.
 func (g *GraphStore) ManualStats(ctx context.Context) (ManualStats, error) {
 	sess := g.opener.OpenSession(ctx)
@@ -220,17 +326,22 @@ func collectManualEntries(ctx context.Context, result CypherResult) ([]ManualEnt
 
 func manualEntryFromProps(p map[string]any) ManualEntry {
 	m := ManualEntry{
-		ID:         strProp(p, "id"),
-		URL:        strProp(p, "url"),
-		SourceSite: strProp(p, "source_site"),
-		Make:       strProp(p, "make"),
-		Model:      strProp(p, "model"),
-		Trim:       strProp(p, "trim"),
-		ManualType: strProp(p, "manual_type"),
-		Language:   strProp(p, "language"),
-		Status:     strProp(p, "status"),
-		Error:      strProp(p, "error"),
-		LocalPath:  strProp(p, "local_path"),
+		ID:           strProp(p, "id"),
+		URL:          strProp(p, "url"),
+		SourceSite:   strProp(p, "source_site"),
+		Make:         strProp(p, "make"),
+		Model:        strProp(p, "model"),
+		Trim:         strProp(p, "trim"),
+		ManualType:   strProp(p, "manual_type"),
+		Language:     strProp(p, "language"),
+		Status:       strProp(p, "status"),
+		Error:        strProp(p, "error"),
+		LocalPath:    strProp(p, "local_path"),
+		SHA256:       strProp(p, "sha256"),
+		ETag:         strProp(p, "etag"),
+		DuplicateOf:  strProp(p, "duplicate_of"),
+		Blob:         strProp(p, "blob"),
+		LastModified: strProp(p, "last_modified"),
 	}
 	if v, ok := p["year"]; ok {
 		switch y := v.(type) {
@@ -269,4 +380,3 @@ func manualEntryFromProps(p map[string]any) ManualEntry {
 	}
 	return m
 }
-
@@ -1,8 +1,12 @@
 package graph
 
 import (
+	"context"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 )
 
 func TestManualEntryID(t *testing.T) {
@@ -62,3 +66,92 @@ func TestManualEntryFromProps(t *testing.T) {
 		t.Errorf("Status = %q", m.Status)
 	}
 }
+
+func TestSaveManualEntry_Duplicate(t *testing.T) {
+	gs, tx := newTrackingStore()
+	m := ManualEntry{ID: "mirror", URL: "https://mirror.example/m.pdf", SHA256: "abc", DuplicateOf: "orig", Status: "duplicate"}
+	if err := gs.SaveManualEntry(context.Background(), m); err != nil {
+		t.Fatalf("SaveManualEntry: %v", err)
+	}
+	if len(tx.queries) != 2 || !strings.Contains(tx.queries[1], "DUPLICATE_OF") || tx.params[1]["orig"] != "orig" {
+		t.Fatalf("expected a DUPLICATE_OF link, got %v", tx.queries)
+	}
+	props := tx.params[0]["props"].(map[string]any)
+	if props["sha256"] != "abc" {
+		t.Errorf("hash not saved: %v", props)
+	}
+	if _, ok := props["etag"]; ok {
+		t.Error("empty etag should not overwrite a stored one")
+	}
+}
+
+func TestFindManualByHash(t *testing.T) {
+	sess := &mockSession{runResult: newMockResult(makeNodeRecord(map[string]any{
+		"id": "orig", "url": "https://oem.example/m.pdf", "sha256": "abc", "local_path": "/data/m.pdf",
+	}))}
+	gs := NewWithOpener(&mockOpener{session: sess})
+	m, err := gs.FindManualByHash(context.Background(), "abc", "mirror")
+	if err != nil {
+		t.Fatalf("FindManualByHash: %v", err)
+	}
+	if m == nil || m.ID != "orig" || m.SHA256 != "abc" || m.LocalPath != "/data/m.pdf" {
+		t.Fatalf("unexpected entry %+v", m)
+	}
+
+	gs = NewWithOpener(&mockOpener{session: &mockSession{runResult: newMockResult()}})
+	if m, err := gs.FindManualByHash(context.Background(), "def", ""); err != nil || m != nil {
+		t.Errorf("expected no entry, got %+v, %v", m, err)
+	}
+}
+
+func TestSaveManualEntry_Blob(t *testing.T) {
+	gs, tx := newTrackingStore()
+	if err := gs.SaveManualEntry(context.Background(), ManualEntry{ID: "m", URL: "u", Blob: "abc", Status: "downloaded"}); err != nil {
+		t.Fatalf("SaveManualEntry: %v", err)
+	}
+	if props := tx.params[0]["props"].(map[string]any); props["blob"] != "abc" {
+		t.Errorf("blob not saved: %v", props)
+	}
+}
+
+func TestGetStoredManuals(t *testing.T) {
+	sess := &mockSession{runResult: newMockResult(makeNodeRecord(map[string]any{
+		"id": "m1", "url": "https://oem.example/m.pdf", "blob": "abc", "status": "ingested",
+	}))}
+	gs := NewWithOpener(&mockOpener{session: sess})
+	entries, err := gs.GetStoredManuals(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("GetStoredManuals: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Blob != "abc" {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+}
+
+func TestPendingManualBlobs(t *testing.T) {
+	sess := &mockSession{runResult: newMockResult(
+		&neo4j.Record{Keys: []string{"blob"}, Values: []any{"abc"}},
+		&neo4j.Record{Keys: []string{"blob"}, Values: []any{"def"}},
+	)}
+	gs := NewWithOpener(&mockOpener{session: sess})
+	blobs, err := gs.PendingManualBlobs(context.Background())
+	if err != nil {
+		t.Fatalf("PendingManualBlobs: %v", err)
+	}
+	if len(blobs) != 2 || blobs[0] != "abc" || blobs[1] != "def" {
+		t.Errorf("unexpected blobs %v", blobs)
+	}
+}
+
+func TestGetManual(t *testing.T) {
+	sess := &mockSession{runResult: newMockResult(makeNodeRecord(map[string]any{"id": "m1", "url": "https://oem.example/m.pdf"}))}
+	gs := NewWithOpener(&mockOpener{session: sess})
+	if m, err := gs.GetManual(context.Background(), "m1"); err != nil || m == nil || m.URL != "https://oem.example/m.pdf" {
+		t.Fatalf("unexpected entry %+v, %v", m, err)
+	}
+
+	gs = NewWithOpener(&mockOpener{session: &mockSession{runResult: newMockResult()}})
+	if m, err := gs.GetManual(context.Background(), "missing"); err != nil || m != nil {
+		t.Errorf("expected no entry, got %+v, %v", m, err)
+	}
+}
@@ -0,0 +1,300 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Procedure is a step-by-step repair guide, e.g. "Replace the alternator",
+// from a manual or an iFixit guide.
+type Procedure struct {
+	Title      string          `json:"title"`
+	Operation  string          `json:"operation,omitempty"`  // normalized verb, e.g. "replace"
+	Components []string        `json:"components,omitempty"` // what the procedure works on
+	Steps      []ProcedureStep `json:"steps"`
+	Tools      []string        `json:"tools,omitempty"`
+	Warnings   []string        `json:"warnings,omitempty"`
+	Source     string          `json:"source,omitempty"` // manual, ifixit
+	URL        string          `json:"url,omitempty"`
+}
+
+// ProcedureStep is one numbered step of a Procedure.
+type ProcedureStep struct {
+	Order int    `json:"order"` // 1-based
+	Title string `json:"title,omitempty"`
+	Text  string `json:"text"`
+}
+
+// ProcedureID returns the vehicle-scoped ID of a Procedure node.
+func ProcedureID(vi VehicleInfo, p Procedure) string {
+	return vehicleScopePrefix(vi) + ":procedure:" + sanitizeID(p.Title)
+}
+
+// procedureOperations maps the verbs and nouns guide titles use to the
+// operation stored on a Procedure.
+var procedureOperations = map[string]string{
+	"replace": "replace", "replacing": "replace", "replacement": "replace", "change": "replace", "changing": "replace", "swap": "replace",
+	"remove": "remove", "removing": "remove", "removal": "remove",
+	"install": "install", "installing": "install", "installation": "install",
+	"repair": "repair", "repairing": "repair", "fix": "repair",
+	"inspect": "inspect", "inspecting": "inspect", "inspection": "inspect", "check": "inspect",
+	"test": "test", "testing": "test", "diagnose": "test", "diagnosis": "test",
+	"adjust": "adjust", "adjusting": "adjust", "adjustment": "adjust",
+	"clean": "clean", "cleaning": "clean",
+	"bleed": "bleed", "bleeding": "bleed",
+	"flush": "flush", "flushing": "flush",
+	"reset": "reset", "resetting": "reset",
+}
+
+// NormalizeOperation returns the stored operation for a verb or noun such
+// as "Replacement" or "changing", or "" if it is not one.
+func NormalizeOperation(word string) string {
+	return procedureOperations[strings.ToLower(strings.TrimSpace(word))]
+}
+
+// ParseProcedureTitle reads the operation and the component from a guide
+// title: "How to replace the alternator" and "Alternator Replacement" both
+// give ("replace", "alternator"). Either is "" when the title doesn't say.
+func ParseProcedureTitle(title string) (operation, component string) {
+	words := strings.Fields(strings.ToLower(title))
+	if len(words) >= 2 && words[0] == "how" && words[1] == "to" {
+		words = words[2:]
+	}
+	if len(words) == 0 {
+		return "", ""
+	}
+	if op := NormalizeOperation(words[0]); op != "" {
+		rest := words[1:]
+		for len(rest) > 0 && (rest[0] == "the" || rest[0] == "a" || rest[0] == "an" || rest[0] == "your" || rest[0] == "my") {
+			rest = rest[1:]
+		}
+		for i, w := range rest {
+			if w == "on" || w == "in" || w == "for" || w == "from" || w == "-" || strings.HasPrefix(w, "(") {
+				rest = rest[:i]
+				break
+			}
+		}
+		return op, strings.Join(rest, " ")
+	}
+	last := len(words) - 1
+	if op := NormalizeOperation(words[last]); op != "" {
+		rest := words[:last]
+		for i := len(rest) - 1; i >= 0; i-- {
+			if strings.HasSuffix(rest[i], ")") || strings.HasPrefix(rest[i], "(") {
+				rest = rest[i+1:] // drop a "(2009-2014)" model range and what precedes it
+				break
+			}
+		}
+		return op, strings.Join(rest, " ")
+	}
+	return "", ""
+}
+
+// EnrichFromProcedures stores procedures as Procedure nodes linked to the
+// ModelYear with HAS_PROCEDURE, with ordered Step children (HAS_STEP) and a
+// TOUCHES edge to the vehicle-scoped Component of each component it works
+// on. Operation and components are read from the title when not set. A
+// procedure's steps are replaced when it is stored again. docID is recorded
+// in source_docs for RetractDocument.
+func (e *Enricher) EnrichFromProcedures(ctx context.Context, vi VehicleInfo, procs []Procedure, docID string) error {
+	if len(procs) == 0 {
+		return nil
+	}
+	if err := e.graph.EnsureVehicleHierarchy(ctx, vi); err != nil {
+		return fmt.Errorf("enricher: vehicle hierarchy: %w", err)
+	}
+
+	sess := e.graph.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	_, err := sess.ExecuteWrite(ctx, func(tx CypherRunner) (any, error) {
+		for _, p := range procs {
+			if err := mergeProcedure(ctx, tx, vi, p, docID); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// mergeProcedure writes one procedure for EnrichFromProcedures and
+// EnrichFromManualExtraction. Procedures without a title or steps are skipped.
+func mergeProcedure(ctx context.Context, tx CypherRunner, vi VehicleInfo, p Procedure, docID string) error {
+	if p.Title == "" || len(p.Steps) == 0 {
+		return nil
+	}
+	op, comp := ParseProcedureTitle(p.Title)
+	if p.Operation == "" {
+		p.Operation = op
+	}
+	if len(p.Components) == 0 && comp != "" {
+		p.Components = []string{comp}
+	}
+
+	id := ProcedureID(vi, p)
+	props := map[string]any{
+		"name":       p.Title,
+		"title":      p.Title,
+		"step_count": len(p.Steps),
+	}
+	for k, v := range map[string]string{"operation": p.Operation, "source": p.Source, "url": p.URL} {
+		if v != "" {
+			props[k] = v
+		}
+	}
+	if len(p.Tools) > 0 {
+		props["tools"] = p.Tools
+	}
+	if len(p.Warnings) > 0 {
+		props["warnings"] = p.Warnings
+	}
+
+	cypher := `MERGE (p:Procedure {id: $id}) SET p += $props` + addSourceDoc("p") + `
+	           WITH p
+	           MATCH (my:ModelYear {id: $myID})
+	           MERGE (my)-[:HAS_PROCEDURE]->(p)`
+	if _, err := tx.Run(ctx, cypher, map[string]any{
+		"id": id, "props": props, "myID": modelYearID(vi), "docID": docID,
+	}); err != nil {
+		return err
+	}
+
+	steps := make([]map[string]any, len(p.Steps))
+	for i, s := range p.Steps {
+		order := s.Order
+		if order == 0 {
+			order = i + 1
+		}
+		steps[i] = map[string]any{
+			"id": fmt.Sprintf("%s:step-%d", id, order), "order": order, "title": s.Title, "text": s.Text,
+		}
+	}
+	cypher = `MATCH (p:Procedure {id: $id})
+	          OPTIONAL MATCH (p)-[:HAS_STEP]->(old:Step)
+	          DETACH DELETE old
+	          WITH DISTINCT p
+	          UNWIND $steps AS st
+	          CREATE (p)-[:HAS_STEP]->(:Step {id: st.id, order: st.order, title: st.title, text: st.text})`
+	if _, err := tx.Run(ctx, cypher, map[string]any{"id": id, "steps": steps}); err != nil {
+		return err
+	}
+
+	for _, c := range p.Components {
+		cypher = `MERGE (c:Component {id: $cID})
+		          ON CREATE SET c.name = $name, c.type = 'component'
+		          WITH c
+		          MATCH (p:Procedure {id: $pID})
+		          MERGE (p)-[:TOUCHES]->(c)`
+		if _, err := tx.Run(ctx, cypher, map[string]any{
+			"cID": vehicleScopePrefix(vi) + ":" + sanitizeID(c), "name": c, "pID": id,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Procedures returns the procedures recorded for a ModelYear, e.g.
+// "ford-f-150-2018", with their steps in order.
+func (g *GraphStore) Procedures(ctx context.Context, modelYearID string) ([]Procedure, error) {
+	sess := g.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	cypher := `MATCH (:ModelYear {id: $myID})-[:HAS_PROCEDURE]->(p:Procedure)
+	           OPTIONAL MATCH (p)-[:TOUCHES]->(c:Component)
+	           WITH p, collect(DISTINCT c.name) AS components
+	           OPTIONAL MATCH (p)-[:HAS_STEP]->(s:Step)
+	           WITH p, components, collect({order: s.order, title: s.title, text: s.text}) AS steps
+	           RETURN p.title AS title, p.operation AS operation, p.tools AS tools, p.warnings AS warnings,
+	                  p.source AS source, p.url AS url, components, steps
+	           ORDER BY title`
+	result, err := sess.Run(ctx, cypher, map[string]any{"myID": modelYearID})
+	if err != nil {
+		return nil, fmt.Errorf("graph: procedures %s: %w", modelYearID, err)
+	}
+	strs := func(v any) []string {
+		var out []string
+		list, _ := v.([]any)
+		for _, x := range list {
+			if s, ok := x.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	var out []Procedure
+	for result.Next(ctx) {
+		rec := result.Record()
+		get := func(key string) any {
+			v, _ := rec.Get(key)
+			return v
+		}
+		p := Procedure{Components: strs(get("components")), Tools: strs(get("tools")), Warnings: strs(get("warnings"))}
+		p.Title, _ = get("title").(string)
+		p.Operation, _ = get("operation").(string)
+		p.Source, _ = get("source").(string)
+		p.URL, _ = get("url").(string)
+		steps, _ := get("steps").([]any)
+		for _, s := range steps {
+			m, _ := s.(map[string]any)
+			text, _ := m["text"].(string)
+			if text == "" {
+				continue // the null row of a procedure without steps
+			}
+			step := ProcedureStep{Text: text}
+			step.Title, _ = m["title"].(string)
+			if n, ok := m["order"].(int64); ok {
+				step.Order = int(n)
+			}
+			p.Steps = append(p.Steps, step)
+		}
+		sort.Slice(p.Steps, func(i, j int) bool { return p.Steps[i].Order < p.Steps[j].Order })
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// FindProcedure returns the ModelYear's procedure for operation on
+// component, e.g. ("alternator", "replace"), or nil if there is none. Among
+// several matches the one with the most steps wins.
+func (g *GraphStore) FindProcedure(ctx context.Context, modelYearID, component, operation string) (*Procedure, error) {
+	procs, err := g.Procedures(ctx, modelYearID)
+	if err != nil {
+		return nil, err
+	}
+	var best *Procedure
+	for i := range procs {
+		if procs[i].Performs(component, operation) && (best == nil || len(procs[i].Steps) > len(best.Steps)) {
+			best = &procs[i]
+		}
+	}
+	return best, nil
+}
+
+// Performs reports whether the procedure does operation (any verb
+// NormalizeOperation accepts; "" matches all) on component, matched
+// case-insensitively against its components and title.
+func (p Procedure) Performs(component, operation string) bool {
+	if operation != "" {
+		op := NormalizeOperation(operation)
+		if op == "" {
+			op = strings.ToLower(operation)
+		}
+		if p.Operation != op {
+			return false
+		}
+	}
+	component = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(component)), "s")
+	if component == "" {
+		return true
+	}
+	for _, c := range p.Components {
+		if strings.Contains(strings.ToLower(c), component) {
+			return true
+		}
+	}
+	return strings.Contains(strings.ToLower(p.Title), component)
+}
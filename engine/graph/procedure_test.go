@@ -0,0 +1,123 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestParseProcedureTitle(t *testing.T) {
+	tests := []struct {
+		title, op, comp string
+	}{
+		{"How to replace the alternator", "replace", "alternator"},
+		{"Replace Brake Pads on a 2015 Civic", "replace", "brake pads"},
+		{"Ford F-150 (2009-2014) Starter Motor Replacement", "replace", "starter motor"},
+		{"Serpentine Belt Removal", "remove", "serpentine belt"},
+		{"Bleeding the brakes", "bleed", "brakes"},
+		{"Maintenance overview", "", ""},
+	}
+	for _, tt := range tests {
+		op, comp := ParseProcedureTitle(tt.title)
+		if op != tt.op || comp != tt.comp {
+			t.Errorf("ParseProcedureTitle(%q) = %q, %q; want %q, %q", tt.title, op, comp, tt.op, tt.comp)
+		}
+	}
+}
+
+func TestEnrichFromProcedures(t *testing.T) {
+	gs, tx := newTrackingStore()
+	vi := VehicleInfo{Make: "Ford", Model: "F-150", Year: 2018}
+	procs := []Procedure{
+		{
+			Title: "Alternator Replacement",
+			Steps: []ProcedureStep{
+				{Title: "Battery", Text: "Disconnect the negative battery cable."},
+				{Title: "Belt", Text: "Release the belt tensioner."},
+			},
+			Tools:    []string{"13 mm socket"},
+			Warnings: []string{"Disconnect the battery first."},
+			Source:   "ifixit",
+		},
+		{Title: "No steps"},
+	}
+	if err := NewEnricher(gs).EnrichFromProcedures(context.Background(), vi, procs, "ifixit-1"); err != nil {
+		t.Fatalf("EnrichFromProcedures: %v", err)
+	}
+
+	var merged, steps, touches []map[string]any
+	for i, q := range tx.queries {
+		switch {
+		case strings.Contains(q, "MERGE (p:Procedure"):
+			merged = append(merged, tx.params[i])
+		case strings.Contains(q, "HAS_STEP"):
+			steps = append(steps, tx.params[i])
+		case strings.Contains(q, "[:TOUCHES]"):
+			touches = append(touches, tx.params[i])
+		}
+	}
+	if len(merged) != 1 {
+		t.Fatalf("expected 1 procedure, got %v", tx.queries)
+	}
+	if merged[0]["id"] != "ford-f-150-2018:procedure:alternator-replacement" || merged[0]["myID"] != "ford-f-150-2018" || merged[0]["docID"] != "ifixit-1" {
+		t.Errorf("unexpected procedure params %v", merged[0])
+	}
+	props := merged[0]["props"].(map[string]any)
+	if props["operation"] != "replace" || props["step_count"] != 2 || props["source"] != "ifixit" {
+		t.Errorf("unexpected procedure props %v", props)
+	}
+	if len(steps) != 1 {
+		t.Fatalf("expected one step write, got %d", len(steps))
+	}
+	st := steps[0]["steps"].([]map[string]any)
+	if len(st) != 2 || st[1]["order"] != 2 || st[1]["id"] != "ford-f-150-2018:procedure:alternator-replacement:step-2" {
+		t.Errorf("unexpected steps %v", st)
+	}
+	if len(touches) != 1 || touches[0]["cID"] != "ford-f-150-2018:alternator" {
+		t.Errorf("unexpected TOUCHES edges %v", touches)
+	}
+}
+
+func TestFindProcedure(t *testing.T) {
+	keys := []string{"title", "operation", "tools", "warnings", "source", "url", "components", "steps"}
+	sess := &mockSession{runResult: newMockResult(
+		&neo4j.Record{Keys: keys, Values: []any{
+			"Alternator Inspection", "inspect", nil, nil, "manual", nil, []any{"alternator"},
+			[]any{map[string]any{"order": int64(1), "title": nil, "text": "Check the belt."}},
+		}},
+		&neo4j.Record{Keys: keys, Values: []any{
+			"Alternator Replacement", "replace", []any{"13 mm socket"}, []any{"Disconnect the battery."}, "ifixit", "https://ifixit.com/g/1",
+			[]any{"Alternator"},
+			[]any{
+				map[string]any{"order": int64(2), "title": "Belt", "text": "Release the tensioner."},
+				map[string]any{"order": int64(1), "title": "Battery", "text": "Disconnect the cable."},
+			},
+		}},
+		&neo4j.Record{Keys: keys, Values: []any{
+			"Replace wiper blades", "replace", nil, nil, nil, nil, []any{},
+			[]any{map[string]any{"order": nil, "title": nil, "text": nil}},
+		}},
+	)}
+	gs := NewWithOpener(&mockOpener{session: sess})
+
+	p, err := gs.FindProcedure(context.Background(), "ford-f-150-2018", "Alternator", "replacing")
+	if err != nil {
+		t.Fatalf("FindProcedure: %v", err)
+	}
+	if p == nil || p.Title != "Alternator Replacement" || p.URL != "https://ifixit.com/g/1" {
+		t.Fatalf("unexpected procedure %+v", p)
+	}
+	if len(p.Steps) != 2 || p.Steps[0].Title != "Battery" || p.Steps[1].Order != 2 {
+		t.Errorf("steps should be in order: %+v", p.Steps)
+	}
+	if len(p.Tools) != 1 || len(p.Warnings) != 1 {
+		t.Errorf("unexpected tools/warnings %+v", p)
+	}
+
+	sess.runResult = newMockResult()
+	if p, err := gs.FindProcedure(context.Background(), "ford-f-150-2018", "starter", "replace"); err != nil || p != nil {
+		t.Errorf("expected no procedure, got %+v, %v", p, err)
+	}
+}
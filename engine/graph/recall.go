@@ -0,0 +1,179 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+)
+
+// Recall kinds.
+const (
+	RecallKindRecall        = "recall"
+	RecallKindInvestigation = "investigation" // an NHTSA ODI defect investigation
+)
+
+// Recall is a safety recall campaign or a defect investigation affecting a
+// vehicle, e.g. NHTSA campaign 24V123000.
+type Recall struct {
+	Campaign     string `json:"campaign"`       // NHTSA campaign or ODI action number
+	Kind         string `json:"kind,omitempty"` // RecallKindRecall (default) or RecallKindInvestigation
+	Component    string `json:"component,omitempty"`
+	Summary      string `json:"summary,omitempty"`
+	Consequence  string `json:"consequence,omitempty"`
+	Remedy       string `json:"remedy,omitempty"`
+	Manufacturer string `json:"manufacturer,omitempty"`
+	Date         string `json:"date,omitempty"`   // report or open date, "2006-01-02"
+	Status       string `json:"status,omitempty"` // investigations: "open" or "closed"
+	Units        int    `json:"units,omitempty"`  // potentially affected vehicles
+	URL          string `json:"url,omitempty"`
+
+	Affects []string `json:"affects,omitempty"` // System/Subsystem names, filled by Recalls
+}
+
+// RecallID returns the ID of a Recall node. A campaign covers many
+// vehicles, so the ID is not vehicle-scoped.
+func RecallID(r Recall) string {
+	kind := r.Kind
+	if kind == "" {
+		kind = RecallKindRecall
+	}
+	return kind + ":" + sanitizeID(r.Campaign)
+}
+
+// EnrichFromRecalls stores recalls as Recall nodes linked to the ModelYear
+// with HAS_RECALL and, when the component classifies, AFFECTS edges to the
+// vehicle-scoped System or Subsystem. docID is recorded in source_docs of
+// the nodes and edges for RetractDocument; a campaign is shared by every
+// vehicle it covers, so retracting one vehicle's recall document removes
+// only that vehicle's edges.
+func (e *Enricher) EnrichFromRecalls(ctx context.Context, vi VehicleInfo, recalls []Recall, docID string) error {
+	if len(recalls) == 0 {
+		return nil
+	}
+	if err := e.graph.EnsureVehicleHierarchy(ctx, vi); err != nil {
+		return fmt.Errorf("enricher: vehicle hierarchy: %w", err)
+	}
+
+	sess := e.graph.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	_, err := sess.ExecuteWrite(ctx, func(tx CypherRunner) (any, error) {
+		for _, r := range recalls {
+			if r.Campaign == "" {
+				continue
+			}
+			if r.Kind == "" {
+				r.Kind = RecallKindRecall
+			}
+			id := RecallID(r)
+			props := map[string]any{"name": r.Campaign, "campaign": r.Campaign, "kind": r.Kind}
+			for k, v := range map[string]string{
+				"component": r.Component, "summary": r.Summary, "consequence": r.Consequence, "remedy": r.Remedy,
+				"manufacturer": r.Manufacturer, "date": r.Date, "status": r.Status, "url": r.URL,
+			} {
+				if v != "" {
+					props[k] = v
+				}
+			}
+			if r.Units > 0 {
+				props["units"] = r.Units
+			}
+
+			cypher := `MERGE (r:Recall {id: $id}) SET r += $props` + addSourceDoc("r") + `
+			           WITH r
+			           MATCH (my:ModelYear {id: $myID})
+			           MERGE (my)-[h:HAS_RECALL]->(r)` + addSourceDoc("h")
+			if _, err := tx.Run(ctx, cypher, map[string]any{
+				"id": id, "props": props, "myID": modelYearID(vi), "docID": docID,
+			}); err != nil {
+				return nil, err
+			}
+
+			sys, sub := ClassifyComponent(r.Component, r.Summary)
+			if sys == "" {
+				continue
+			}
+			sysID := vehicleScopePrefix(vi) + ":" + sanitizeID(sys)
+			cypher = `MERGE (s:System {id: $sysID}) SET s.name = $name` + addSourceDoc("s") + `
+			          WITH s
+			          MATCH (my:ModelYear {id: $myID})
+			          MERGE (my)-[:HAS_SYSTEM]->(s)`
+			if _, err := tx.Run(ctx, cypher, map[string]any{
+				"sysID": sysID, "name": sys, "myID": modelYearID(vi), "docID": docID,
+			}); err != nil {
+				return nil, err
+			}
+
+			targetID := sysID
+			if sub != "" {
+				targetID = sysID + ":" + sanitizeID(sub)
+				cypher = `MERGE (ss:Subsystem {id: $id}) SET ss.name = $name, ss.system_id = $sysID` + addSourceDoc("ss") + `
+				          WITH ss
+				          MATCH (s:System {id: $sysID})
+				          MERGE (s)-[:HAS_SUBSYSTEM]->(ss)`
+				if _, err := tx.Run(ctx, cypher, map[string]any{
+					"id": targetID, "name": sub, "sysID": sysID, "docID": docID,
+				}); err != nil {
+					return nil, err
+				}
+			}
+
+			cypher = `MATCH (r:Recall {id: $id}), (t {id: $tID})
+			          MERGE (r)-[a:AFFECTS]->(t)` + addSourceDoc("a")
+			if _, err := tx.Run(ctx, cypher, map[string]any{"id": id, "tID": targetID, "docID": docID}); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// Recalls returns the recalls and investigations recorded for a ModelYear,
+// e.g. "ford-f-150-2018", newest first, with the systems they affect.
+func (g *GraphStore) Recalls(ctx context.Context, modelYearID string) ([]Recall, error) {
+	sess := g.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	cypher := `MATCH (:ModelYear {id: $myID})-[:HAS_RECALL]->(r:Recall)
+	           OPTIONAL MATCH (r)-[:AFFECTS]->(t)
+	           WHERE t.id STARTS WITH $myID
+	           WITH r, collect(DISTINCT t.name) AS affects
+	           RETURN r.campaign AS campaign, r.kind AS kind, r.component AS component, r.summary AS summary,
+	                  r.consequence AS consequence, r.remedy AS remedy, r.manufacturer AS manufacturer,
+	                  r.date AS date, r.status AS status, r.units AS units, r.url AS url, affects
+	           ORDER BY date DESC, campaign`
+	result, err := sess.Run(ctx, cypher, map[string]any{"myID": modelYearID})
+	if err != nil {
+		return nil, fmt.Errorf("graph: recalls %s: %w", modelYearID, err)
+	}
+	var out []Recall
+	for result.Next(ctx) {
+		rec := result.Record()
+		get := func(key string) any {
+			v, _ := rec.Get(key)
+			return v
+		}
+		var r Recall
+		r.Campaign, _ = get("campaign").(string)
+		r.Kind, _ = get("kind").(string)
+		r.Component, _ = get("component").(string)
+		r.Summary, _ = get("summary").(string)
+		r.Consequence, _ = get("consequence").(string)
+		r.Remedy, _ = get("remedy").(string)
+		r.Manufacturer, _ = get("manufacturer").(string)
+		r.Date, _ = get("date").(string)
+		r.Status, _ = get("status").(string)
+		r.URL, _ = get("url").(string)
+		if n, ok := get("units").(int64); ok {
+			r.Units = int(n)
+		}
+		affects, _ := get("affects").([]any)
+		for _, a := range affects {
+			if s, ok := a.(string); ok && s != "" {
+				r.Affects = append(r.Affects, s)
+			}
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestEnrichFromRecalls(t *testing.T) {
+	gs, tx := newTrackingStore()
+	vi := VehicleInfo{Make: "Ford", Model: "F-150", Year: 2018}
+	recalls := []Recall{
+		{
+			Campaign:    "18V123000",
+			Component:   "FUEL SYSTEM, GASOLINE:DELIVERY:FUEL PUMP",
+			Summary:     "The fuel pump may fail.",
+			Consequence: "An engine stall increases the risk of a crash.",
+			Remedy:      "Dealers will replace the fuel pump.",
+			Date:        "2018-03-01",
+			Units:       1200,
+		},
+		{Campaign: "PE18004", Kind: RecallKindInvestigation, Component: "UNKNOWN OR OTHER", Status: "open"},
+		{Summary: "no campaign"},
+	}
+	if err := NewEnricher(gs).EnrichFromRecalls(context.Background(), vi, recalls, "nhtsa:recall-18V123000"); err != nil {
+		t.Fatalf("EnrichFromRecalls: %v", err)
+	}
+
+	var merged, affects []map[string]any
+	for i, q := range tx.queries {
+		switch {
+		case strings.Contains(q, "MERGE (r:Recall"):
+			merged = append(merged, tx.params[i])
+		case strings.Contains(q, "[a:AFFECTS]"):
+			affects = append(affects, tx.params[i])
+		}
+	}
+	if len(merged) != 2 {
+		t.Fatalf("expected 2 recalls, got %v", tx.queries)
+	}
+	if merged[0]["id"] != "recall:18v123000" || merged[0]["myID"] != "ford-f-150-2018" || merged[0]["docID"] != "nhtsa:recall-18V123000" {
+		t.Errorf("unexpected recall params %v", merged[0])
+	}
+	props := merged[0]["props"].(map[string]any)
+	if props["kind"] != RecallKindRecall || props["units"] != 1200 || props["remedy"] != "Dealers will replace the fuel pump." {
+		t.Errorf("unexpected recall props %v", props)
+	}
+	if merged[1]["id"] != "investigation:pe18004" {
+		t.Errorf("investigations get their own IDs, got %v", merged[1]["id"])
+	}
+	if len(affects) != 1 || affects[0]["tID"] != "ford-f-150-2018:fuel-system:fuel-pump" || affects[0]["docID"] != "nhtsa:recall-18V123000" {
+		t.Errorf("unexpected AFFECTS edges %v", affects)
+	}
+}
+
+func TestRecalls(t *testing.T) {
+	keys := []string{"campaign", "kind", "component", "summary", "consequence", "remedy", "manufacturer", "date", "status", "units", "url", "affects"}
+	sess := &mockSession{runResult: newMockResult(
+		&neo4j.Record{Keys: keys, Values: []any{
+			"18V123000", "recall", "FUEL SYSTEM", "The fuel pump may fail.", "Stall.", "Replace the pump.", "Ford Motor Company",
+			"2018-03-01", nil, int64(1200), "https://www.nhtsa.gov/recalls?nhtsaId=18V123000", []any{"Fuel Pump"},
+		}},
+		&neo4j.Record{Keys: keys, Values: []any{
+			"PE18004", "investigation", nil, "Brake fluid leak.", nil, nil, nil, "2018-01-10", "open", nil, nil, []any{},
+		}},
+	)}
+	gs := NewWithOpener(&mockOpener{session: sess})
+
+	recalls, err := gs.Recalls(context.Background(), "ford-f-150-2018")
+	if err != nil {
+		t.Fatalf("Recalls: %v", err)
+	}
+	if len(recalls) != 2 {
+		t.Fatalf("expected 2 recalls, got %+v", recalls)
+	}
+	if r := recalls[0]; r.Campaign != "18V123000" || r.Units != 1200 || r.Remedy != "Replace the pump." || len(r.Affects) != 1 {
+		t.Errorf("unexpected recall %+v", r)
+	}
+	if r := recalls[1]; r.Kind != RecallKindInvestigation || r.Status != "open" || r.Units != 0 || r.Affects != nil {
+		t.Errorf("unexpected investigation %+v", r)
+	}
+}
@@ -0,0 +1,303 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RetractStats counts the graph nodes removed by RetractDocument.
+type RetractStats struct {
+	Documents int `json:"documents"` // document nodes (0 or 1)
+	Derived   int `json:"derived"`   // System/Subsystem/Spec/Fuse/Relay/MaintenanceItem/Procedure/Recall/ComplaintStats and wiring nodes only that document backed
+}
+
+// RetractDocument deletes a document node with all its edges, then removes
+// the System, Subsystem, Spec, Fuse, Relay, MaintenanceItem, Procedure and
+// Recall nodes the enricher created for it once no other document backs them;
+// a Procedure takes its Steps with it. A derived node survives while any
+// document is still listed in its source_docs, still DOCUMENTED_IN it, or it
+// still has subsystems or components beneath it. HAS_DTC, CAUSED_BY and
+// CONNECTS_TO edges are deleted once no document backs them, then wiring pins,
+// connectors and components left unbacked and unconnected. The document's
+// complaint is subtracted from the ComplaintStats counters it was added to.
+func (g *GraphStore) RetractDocument(ctx context.Context, docID string) (RetractStats, error) {
+	sess := g.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	res, err := sess.ExecuteWrite(ctx, func(tx CypherRunner) (any, error) {
+		var stats RetractStats
+
+		// Drop the document from the provenance of everything it enriched.
+		cypher := `MATCH (n) WHERE $id IN n.source_docs
+		           SET n.source_docs = [d IN n.source_docs WHERE d <> $id]
+		           RETURN n.id AS id`
+		result, err := tx.Run(ctx, cypher, map[string]any{"id": docID})
+		if err != nil {
+			return nil, err
+		}
+		var touched []string
+		for result.Next(ctx) {
+			if v, ok := result.Record().Get("id"); ok {
+				if s, ok := v.(string); ok {
+					touched = append(touched, s)
+				}
+			}
+		}
+
+		// Take the document's complaint back out of the counters it added to.
+		cypher = `MATCH (:Component {id: $id})-[c:COUNTED_IN]->(cs:ComplaintStats)
+		          WITH cs, c, [i IN range(0, size(cs.incident_months) - 1) WHERE cs.incident_months[i] = c.month] AS at
+		          SET cs.complaints = cs.complaints - 1, cs.crashes = cs.crashes - c.crash, cs.fires = cs.fires - c.fire,
+		              cs.injuries = cs.injuries - c.injuries, cs.deaths = cs.deaths - c.deaths,
+		              cs.incident_months = CASE WHEN size(at) = 0 THEN cs.incident_months
+		                                   ELSE cs.incident_months[..at[0]] + cs.incident_months[at[0] + 1..] END
+		          WITH cs WHERE cs.complaints <= 0
+		          DETACH DELETE cs RETURN count(cs) AS n`
+		removed, err := runCount(ctx, tx, cypher, map[string]any{"id": docID})
+		if err != nil {
+			return nil, err
+		}
+		stats.Derived += removed
+
+		cypher = `MATCH (d:Component {id: $id}) DETACH DELETE d RETURN count(d) AS n`
+		if stats.Documents, err = runCount(ctx, tx, cypher, map[string]any{"id": docID}); err != nil {
+			return nil, err
+		}
+
+		// HAS_DTC, CAUSED_BY, CONNECTS_TO, HAS_RECALL, AFFECTS, PROTECTS and
+		// CONTROLS edges keep their own provenance; drop the ones no other
+		// document backs.
+		cypher = `MATCH ()-[r:HAS_DTC|CAUSED_BY|CONNECTS_TO|HAS_RECALL|AFFECTS|PROTECTS|CONTROLS]->() WHERE $id IN r.source_docs
+		          SET r.source_docs = [d IN r.source_docs WHERE d <> $id]
+		          WITH r WHERE size(r.source_docs) = 0
+		          DELETE r`
+		if _, err := tx.Run(ctx, cypher, map[string]any{"id": docID}); err != nil {
+			return nil, err
+		}
+		if len(touched) == 0 {
+			return stats, nil
+		}
+
+		cypher = `MATCH (p:Procedure) WHERE p.id IN $ids AND size(p.source_docs) = 0
+		          OPTIONAL MATCH (p)-[:HAS_STEP]->(s:Step)
+		          DETACH DELETE s
+		          WITH DISTINCT p
+		          DETACH DELETE p RETURN count(p) AS n`
+		n, err := runCount(ctx, tx, cypher, map[string]any{"ids": touched})
+		if err != nil {
+			return nil, err
+		}
+		stats.Derived += n
+
+		// Leaves first, so a Subsystem or System whose last child goes can follow.
+		for _, label := range []string{"Spec", "Fuse", "Relay", "MaintenanceItem", "Recall", "Subsystem", "System"} {
+			cypher = fmt.Sprintf(`MATCH (n:%s) WHERE n.id IN $ids AND size(n.source_docs) = 0
+			           AND NOT EXISTS { (n)<-[:DOCUMENTED_IN]-() }
+			           AND NOT EXISTS { (n)-[:HAS_SUBSYSTEM|HAS_COMPONENT]->() }
+			           DETACH DELETE n RETURN count(n) AS n`, label)
+			n, err := runCount(ctx, tx, cypher, map[string]any{"ids": touched})
+			if err != nil {
+				return nil, err
+			}
+			stats.Derived += n
+		}
+
+		// Wiring and fuse circuits: pins go once no wire is left on them,
+		// then the connectors and components with nothing left attached.
+		for _, cypher := range []string{
+			`MATCH (n:Component {type: 'pin'}) WHERE n.id IN $ids AND size(n.source_docs) = 0
+			 AND NOT EXISTS { (n)-[:CONNECTS_TO]-() }
+			 DETACH DELETE n RETURN count(n) AS n`,
+			`MATCH (n:Component) WHERE n.id IN $ids AND n.type IN ['connector', 'component'] AND size(n.source_docs) = 0
+			 AND NOT EXISTS { (n)--() }
+			 DELETE n RETURN count(n) AS n`,
+		} {
+			n, err := runCount(ctx, tx, cypher, map[string]any{"ids": touched})
+			if err != nil {
+				return nil, err
+			}
+			stats.Derived += n
+		}
+		return stats, nil
+	})
+	if err != nil {
+		return RetractStats{}, fmt.Errorf("graph: retract %s: %w", docID, err)
+	}
+	stats, _ := res.(RetractStats)
+	return stats, nil
+}
+
+// runCount runs a query returning a single "n" count.
+func runCount(ctx context.Context, tx CypherRunner, cypher string, params map[string]any) (int, error) {
+	result, err := tx.Run(ctx, cypher, params)
+	if err != nil {
+		return 0, err
+	}
+	if !result.Next(ctx) {
+		return 0, nil
+	}
+	v, _ := result.Record().Get("n")
+	n, _ := v.(int64)
+	return int(n), nil
+}
+
+// DocumentFilter selects document nodes for bulk retraction. At least one
+// field must be set.
+type DocumentFilter struct {
+	Source     string // "reddit" also matches "reddit:<sub>"
+	URLPattern string // glob where * matches any run of characters
+}
+
+// FindDocumentIDs returns the IDs of document nodes matching f. Only
+// documents ingested with their URL recorded can match URLPattern.
+func (g *GraphStore) FindDocumentIDs(ctx context.Context, f DocumentFilter) ([]string, error) {
+	if f.Source == "" && f.URLPattern == "" {
+		return nil, fmt.Errorf("graph: document filter needs a source or URL pattern")
+	}
+	sess := g.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	cypher := `MATCH (d:Component {type: 'document'})
+	           WHERE ($source = '' OR d.prop_source = $source OR d.prop_source STARTS WITH $source + ':')
+	             AND ($url = '' OR d.prop_url =~ $url)
+	           RETURN d.id AS id ORDER BY id`
+	params := map[string]any{"source": f.Source, "url": ""}
+	if f.URLPattern != "" {
+		params["url"] = globToRegex(f.URLPattern)
+	}
+	result, err := sess.Run(ctx, cypher, params)
+	if err != nil {
+		return nil, fmt.Errorf("graph: find documents: %w", err)
+	}
+	var ids []string
+	for result.Next(ctx) {
+		if v, ok := result.Record().Get("id"); ok {
+			if s, ok := v.(string); ok {
+				ids = append(ids, s)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// globToRegex converts a * glob into a Cypher (Java) regex matching the
+// whole string, quoting everything else literally.
+func globToRegex(glob string) string {
+	parts := strings.Split(glob, "*")
+	for i, p := range parts {
+		if p != "" {
+			parts[i] = `\Q` + strings.ReplaceAll(p, `\E`, `\E\\E\Q`) + `\E`
+		}
+	}
+	return strings.Join(parts, ".*")
+}
+
+// Tombstone records a retracted document so re-scrapes do not bring it back.
+// A tombstone saved by a bulk retraction carries its Source or URLPattern
+// instead, so documents from that source or URL that are scraped later are
+// skipped too; its DocID is then FilterTombstoneID of the filter.
+type Tombstone struct {
+	DocID       string    `json:"doc_id"`
+	Source      string    `json:"source,omitempty"`
+	URLPattern  string    `json:"url_pattern,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	RetractedAt time.Time `json:"retracted_at"`
+}
+
+// FilterTombstoneID returns the ID of the tombstone covering f, e.g.
+// "filter:source=forum;url=".
+func FilterTombstoneID(f DocumentFilter) string {
+	return "filter:source=" + f.Source + ";url=" + f.URLPattern
+}
+
+// SaveTombstone creates or updates the tombstone for t.DocID.
+func (g *GraphStore) SaveTombstone(ctx context.Context, t Tombstone) error {
+	sess := g.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	cypher := `MERGE (t:Tombstone {id: $id})
+	           SET t.reason = $reason, t.retracted_at = $at,
+	               t.source = $source, t.url_pattern = $urlPattern, t.url_regex = $urlRegex`
+	params := map[string]any{
+		"id":         t.DocID,
+		"reason":     t.Reason,
+		"at":         t.RetractedAt.UTC().Format(time.RFC3339),
+		"source":     nil,
+		"urlPattern": nil,
+		"urlRegex":   nil,
+	}
+	// Unset properties stay null, which IsTombstoned reads as "any".
+	if t.Source != "" {
+		params["source"] = t.Source
+	}
+	if t.URLPattern != "" {
+		params["urlPattern"] = t.URLPattern
+		params["urlRegex"] = globToRegex(t.URLPattern)
+	}
+	_, err := sess.Run(ctx, cypher, params)
+	return err
+}
+
+// DeleteTombstone lifts the tombstone for docID so it can be ingested again.
+func (g *GraphStore) DeleteTombstone(ctx context.Context, docID string) error {
+	sess := g.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	_, err := sess.Run(ctx, `MATCH (t:Tombstone {id: $id}) DELETE t`, map[string]any{"id": docID})
+	return err
+}
+
+// IsTombstoned reports whether docID has been retracted, or a source or URL
+// tombstone covers a document from source at url. Source matches like
+// DocumentFilter.Source.
+func (g *GraphStore) IsTombstoned(ctx context.Context, docID, source, url string) (bool, error) {
+	sess := g.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	cypher := `MATCH (t:Tombstone)
+	           WHERE t.id = $id
+	              OR ((t.source IS NOT NULL OR t.url_regex IS NOT NULL)
+	                  AND (t.source IS NULL OR $source = t.source OR $source STARTS WITH t.source + ':')
+	                  AND (t.url_regex IS NULL OR $url =~ t.url_regex))
+	           RETURN t.id AS id LIMIT 1`
+	result, err := sess.Run(ctx, cypher, map[string]any{"id": docID, "source": source, "url": url})
+	if err != nil {
+		return false, err
+	}
+	return result.Next(ctx), nil
+}
+
+// Tombstones returns every tombstone, oldest first.
+func (g *GraphStore) Tombstones(ctx context.Context) ([]Tombstone, error) {
+	sess := g.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	cypher := `MATCH (t:Tombstone)
+	           RETURN t.id AS id, t.source AS source, t.url_pattern AS urlPattern, t.reason AS reason, t.retracted_at AS at
+	           ORDER BY at`
+	result, err := sess.Run(ctx, cypher, nil)
+	if err != nil {
+		return nil, err
+	}
+	var out []Tombstone
+	for result.Next(ctx) {
+		rec := result.Record()
+		id, _ := rec.Get("id")
+		source, _ := rec.Get("source")
+		urlPattern, _ := rec.Get("urlPattern")
+		reason, _ := rec.Get("reason")
+		at, _ := rec.Get("at")
+		t := Tombstone{}
+		t.DocID, _ = id.(string)
+		t.Source, _ = source.(string)
+		t.URLPattern, _ = urlPattern.(string)
+		t.Reason, _ = reason.(string)
+		if s, ok := at.(string); ok {
+			t.RetractedAt, _ = time.Parse(time.RFC3339, s)
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
@@ -0,0 +1,241 @@
+package graph
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestRetractDocument(t *testing.T) {
+	gs, tx := newTrackingStore()
+	if _, err := gs.RetractDocument(context.Background(), "nhtsa:123"); err != nil {
+		t.Fatalf("RetractDocument: %v", err)
+	}
+	if len(tx.queries) < 3 {
+		t.Fatalf("expected provenance and document queries, got %d", len(tx.queries))
+	}
+	if !strings.Contains(tx.queries[0], "source_docs") {
+		t.Errorf("first query should drop the doc from source_docs: %s", tx.queries[0])
+	}
+	if !strings.Contains(tx.queries[1], "COUNTED_IN") || tx.params[1]["id"] != "nhtsa:123" {
+		t.Errorf("second query should subtract the doc's complaint counts: %s %v", tx.queries[1], tx.params[1])
+	}
+	if !strings.Contains(tx.queries[2], "DETACH DELETE d") || tx.params[2]["id"] != "nhtsa:123" {
+		t.Errorf("third query should delete the document node: %s %v", tx.queries[2], tx.params[2])
+	}
+}
+
+func TestEnrichFromSourceRecordsProvenance(t *testing.T) {
+	gs, tx := newTrackingStore()
+	vi := VehicleInfo{Make: "Toyota", Model: "Camry", Year: 2024}
+	if err := NewEnricher(gs).EnrichFromSource(context.Background(), vi, "ELECTRICAL SYSTEM", "doc-123"); err != nil {
+		t.Fatalf("EnrichFromSource: %v", err)
+	}
+	if !strings.Contains(tx.queries[0], "source_docs") || tx.params[0]["docID"] != "doc-123" {
+		t.Errorf("system node should record doc-123 in source_docs: %s", tx.queries[0])
+	}
+}
+
+func TestFindDocumentIDs_RequiresFilter(t *testing.T) {
+	gs, _ := newTrackingStore()
+	if _, err := gs.FindDocumentIDs(context.Background(), DocumentFilter{}); err == nil {
+		t.Fatal("expected error for empty filter")
+	}
+}
+
+func TestFindDocumentIDs_URLPattern(t *testing.T) {
+	gs, tx := newTrackingStore()
+	if _, err := gs.FindDocumentIDs(context.Background(), DocumentFilter{URLPattern: "https://forum.example.com/*"}); err != nil {
+		t.Fatalf("FindDocumentIDs: %v", err)
+	}
+	if got := tx.params[0]["url"]; got != `\Qhttps://forum.example.com/\E.*` {
+		t.Errorf("unexpected url regex %v", got)
+	}
+}
+
+func TestTombstone_Filter(t *testing.T) {
+	gs, tx := newTrackingStore()
+	f := DocumentFilter{URLPattern: "https://forum.example.com/*"}
+	if err := gs.SaveTombstone(context.Background(), Tombstone{DocID: FilterTombstoneID(f), URLPattern: f.URLPattern, Reason: "spam"}); err != nil {
+		t.Fatalf("SaveTombstone: %v", err)
+	}
+	if p := tx.params[0]; p["id"] != "filter:source=;url=https://forum.example.com/*" || p["source"] != nil || p["urlRegex"] != `\Qhttps://forum.example.com/\E.*` {
+		t.Errorf("unexpected filter tombstone params %v", p)
+	}
+	if err := gs.SaveTombstone(context.Background(), Tombstone{DocID: "reddit:abc"}); err != nil {
+		t.Fatalf("SaveTombstone: %v", err)
+	}
+	if p := tx.params[1]; p["source"] != nil || p["urlRegex"] != nil {
+		t.Errorf("a document tombstone should not match by source or URL: %v", p)
+	}
+
+	if _, err := gs.IsTombstoned(context.Background(), "forum:t-1", "forum", "https://forum.example.com/t/1"); err != nil {
+		t.Fatalf("IsTombstoned: %v", err)
+	}
+	if p := tx.params[2]; p["id"] != "forum:t-1" || p["source"] != "forum" || p["url"] != "https://forum.example.com/t/1" {
+		t.Errorf("unexpected IsTombstoned params %v", p)
+	}
+	if q := tx.queries[2]; !strings.Contains(q, "$url =~ t.url_regex") || !strings.Contains(q, "STARTS WITH t.source + ':'") {
+		t.Errorf("IsTombstoned should match source and URL tombstones: %s", q)
+	}
+}
+
+func TestGlobToRegex(t *testing.T) {
+	tests := []struct {
+		glob, in string
+		want     bool
+	}{
+		{"https://reddit.com/r/cars/*", "https://reddit.com/r/cars/comments/abc", true},
+		{"https://reddit.com/r/cars/*", "https://reddit.com/r/trucks/comments/abc", false},
+		{"*example.com*", "https://www.example.com/a?b=c", true},
+		{"https://a.com/x.pdf", "https://a.com/xypdf", false},
+	}
+	for _, tt := range tests {
+		// Go's regexp understands \Q...\E like Java's, which Neo4j uses.
+		re := regexp.MustCompile("^(?:" + globToRegex(tt.glob) + ")$")
+		if got := re.MatchString(tt.in); got != tt.want {
+			t.Errorf("glob %q on %q = %v, want %v", tt.glob, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRetractDocument_Wiring(t *testing.T) {
+	gs, tx := newTrackingStore()
+	keys := []string{"id"}
+	tx.results = []CypherResult{newMockResult(
+		&neo4j.Record{Keys: keys, Values: []any{"honda-civic-2019:c101"}},
+		&neo4j.Record{Keys: keys, Values: []any{"honda-civic-2019:c101:pin-3"}},
+	)}
+	if _, err := gs.RetractDocument(context.Background(), "manual:civic-sec-3"); err != nil {
+		t.Fatalf("RetractDocument: %v", err)
+	}
+
+	edges, pins, ends := -1, -1, -1
+	for i, q := range tx.queries {
+		switch {
+		case strings.Contains(q, "|CONNECTS_TO|"):
+			edges = i
+		case strings.Contains(q, "type: 'pin'"):
+			pins = i
+		case strings.Contains(q, "'connector', 'component'"):
+			ends = i
+		}
+	}
+	if edges < 0 || pins < 0 || ends < 0 {
+		t.Fatalf("expected wire, pin and connector queries, got %v", tx.queries)
+	}
+	if !(edges < pins && pins < ends) {
+		t.Errorf("wires should go before pins, and pins before connectors: %d %d %d", edges, pins, ends)
+	}
+	if ids, _ := tx.params[pins]["ids"].([]string); len(ids) != 2 || ids[1] != "honda-civic-2019:c101:pin-3" {
+		t.Errorf("pin query should get the touched nodes: %v", tx.params[pins])
+	}
+	if !strings.Contains(tx.queries[pins], "NOT EXISTS { (n)-[:CONNECTS_TO]-() }") {
+		t.Errorf("a pin with wires left should survive: %s", tx.queries[pins])
+	}
+}
+
+func TestRetractDocument_ManualSection(t *testing.T) {
+	gs, tx := newTrackingStore()
+	specID := SpecID(VehicleInfo{Make: "Toyota", Model: "Camry", Year: 2020}, "torque", "Wheel Lug Nuts")
+	tx.results = []CypherResult{newMockResult(&neo4j.Record{Keys: []string{"id"}, Values: []any{specID}})}
+	if _, err := gs.RetractDocument(context.Background(), "manual:2020_Toyota_Camry-sec-7"); err != nil {
+		t.Fatalf("RetractDocument: %v", err)
+	}
+	for _, label := range []string{"Spec", "Fuse", "MaintenanceItem"} {
+		found := false
+		for i, q := range tx.queries {
+			if strings.Contains(q, "MATCH (n:"+label+")") {
+				found = true
+				if ids, _ := tx.params[i]["ids"].([]string); len(ids) != 1 || ids[0] != specID {
+					t.Errorf("%s query should get the section's nodes: %v", label, tx.params[i])
+				}
+			}
+		}
+		if !found {
+			t.Errorf("expected a %s delete query", label)
+		}
+	}
+}
+
+func TestRetractDocument_RecallSharedByTwoVehicles(t *testing.T) {
+	// One campaign covers both trucks; each vehicle's recall document backs
+	// only its own HAS_RECALL and AFFECTS edges.
+	gs, tx := newTrackingStore()
+	recall := []Recall{{Campaign: "18V123000", Component: "FUEL SYSTEM, GASOLINE:DELIVERY:FUEL PUMP"}}
+	e := NewEnricher(gs)
+	for _, vi := range []VehicleInfo{{Make: "Ford", Model: "F-150", Year: 2018}, {Make: "Ford", Model: "F-250", Year: 2018}} {
+		if err := e.EnrichFromRecalls(context.Background(), vi, recall, "nhtsa:recall-"+sanitizeID(vi.Model)); err != nil {
+			t.Fatalf("EnrichFromRecalls: %v", err)
+		}
+	}
+	edges := map[string]string{}
+	for i, q := range tx.queries {
+		for _, edge := range []string{"[h:HAS_RECALL]", "[a:AFFECTS]"} {
+			if strings.Contains(q, edge) {
+				if !strings.Contains(q, "source_docs") {
+					t.Errorf("%s should record its document: %s", edge, q)
+				}
+				edges[edge+" "+tx.params[i]["docID"].(string)] = q
+			}
+		}
+	}
+	if len(edges) != 4 {
+		t.Fatalf("expected HAS_RECALL and AFFECTS per vehicle document, got %v", edges)
+	}
+
+	gs, tx = newTrackingStore()
+	tx.results = []CypherResult{newMockResult(&neo4j.Record{Keys: []string{"id"}, Values: []any{"recall:18v123000"}})}
+	if _, err := gs.RetractDocument(context.Background(), "nhtsa:recall-f-150"); err != nil {
+		t.Fatalf("RetractDocument: %v", err)
+	}
+	pruned, recallDelete := false, ""
+	for i, q := range tx.queries {
+		if strings.Contains(q, "|HAS_RECALL|AFFECTS|") && tx.params[i]["id"] == "nhtsa:recall-f-150" {
+			pruned = true
+		}
+		if strings.Contains(q, "MATCH (n:Recall)") {
+			recallDelete = q
+		}
+	}
+	if !pruned {
+		t.Errorf("the F-150's recall edges should be pruned: %v", tx.queries)
+	}
+	// The F-250 still backs the campaign node.
+	if !strings.Contains(recallDelete, "size(n.source_docs) = 0") {
+		t.Errorf("a Recall another document backs should survive: %q", recallDelete)
+	}
+}
+
+func TestRetractDocument_FuseChart(t *testing.T) {
+	gs, tx := newTrackingStore()
+	vi := VehicleInfo{Make: "Ford", Model: "F-150", Year: 2018}
+	fuseID := FuseID(vi, FuseEntry{Kind: FuseKindFuse, Position: "F12", Box: "cabin"})
+	keys := []string{"id"}
+	tx.results = []CypherResult{newMockResult(
+		&neo4j.Record{Keys: keys, Values: []any{fuseID}},
+		&neo4j.Record{Keys: keys, Values: []any{"ford-f-150-2018:power-point-1"}},
+	)}
+	if _, err := gs.RetractDocument(context.Background(), "manual:f150-sec-12"); err != nil {
+		t.Fatalf("RetractDocument: %v", err)
+	}
+
+	edges, circuits := -1, -1
+	for i, q := range tx.queries {
+		switch {
+		case strings.Contains(q, "|PROTECTS|CONTROLS]->()"):
+			edges = i
+		case strings.Contains(q, "'connector', 'component'"):
+			circuits = i
+		}
+	}
+	if edges < 0 || circuits < 0 || edges > circuits {
+		t.Fatalf("PROTECTS and CONTROLS edges should be pruned before the circuits: %v", tx.queries)
+	}
+	if ids, _ := tx.params[circuits]["ids"].([]string); len(ids) != 2 || ids[1] != "ford-f-150-2018:power-point-1" {
+		t.Errorf("circuit query should get the touched components: %v", tx.params[circuits])
+	}
+}
@@ -0,0 +1,77 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+)
+
+// ManualSpec is one row of a specification table read from a manual, e.g.
+// a torque value, a fluid capacity or a bulb type.
+type ManualSpec struct {
+	Kind   string            `json:"kind"` // torque, fluid_capacity, bulb
+	Item   string            `json:"item"` // what the row applies to, e.g. "Wheel lug nuts"
+	Values map[string]string `json:"values"`
+	Page   int               `json:"page,omitempty"`
+}
+
+// SpecID returns the vehicle-scoped ID of a Spec node.
+func SpecID(vi VehicleInfo, kind, item string) string {
+	return vehicleScopePrefix(vi) + ":spec:" + sanitizeID(kind) + ":" + sanitizeID(item)
+}
+
+// EnrichFromSpecs stores manual table rows as Spec nodes linked to the
+// ModelYear, and to the vehicle-scoped Component of the same name when one
+// exists. docID is recorded in source_docs so RetractDocument can remove
+// specs only that document backed.
+func (e *Enricher) EnrichFromSpecs(ctx context.Context, vi VehicleInfo, specs []ManualSpec, docID string) error {
+	if len(specs) == 0 {
+		return nil
+	}
+	if err := e.graph.EnsureVehicleHierarchy(ctx, vi); err != nil {
+		return fmt.Errorf("enricher: vehicle hierarchy: %w", err)
+	}
+
+	vehiclePrefix := vehicleScopePrefix(vi)
+	myID := modelYearID(vi)
+
+	sess := e.graph.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	_, err := sess.ExecuteWrite(ctx, func(tx CypherRunner) (any, error) {
+		for _, spec := range specs {
+			if spec.Item == "" || spec.Kind == "" {
+				continue
+			}
+			props := map[string]any{
+				"name": spec.Item,
+				"kind": spec.Kind,
+			}
+			if spec.Page > 0 {
+				props["page"] = spec.Page
+			}
+			for k, v := range spec.Values {
+				props["spec_"+sanitizeID(k)] = v
+			}
+
+			cypher := `MERGE (s:Spec {id: $id}) SET s += $props` + addSourceDoc("s") + `
+			           WITH s
+			           MATCH (my:ModelYear {id: $myID})
+			           MERGE (my)-[:HAS_SPEC]->(s)`
+			if _, err := tx.Run(ctx, cypher, map[string]any{
+				"id": SpecID(vi, spec.Kind, spec.Item), "props": props, "myID": myID, "docID": docID,
+			}); err != nil {
+				return nil, err
+			}
+
+			cypher = `MATCH (c:Component {id: $cID}), (s:Spec {id: $sID})
+			          MERGE (c)-[:HAS_SPEC]->(s)`
+			if _, err := tx.Run(ctx, cypher, map[string]any{
+				"cID": vehiclePrefix + ":" + sanitizeID(spec.Item), "sID": SpecID(vi, spec.Kind, spec.Item),
+			}); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	return err
+}
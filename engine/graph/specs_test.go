@@ -0,0 +1,43 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestEnrichFromSpecs(t *testing.T) {
+	gs, tx := newTrackingStore()
+	vi := VehicleInfo{Make: "Honda", Model: "Civic", Year: 2019}
+	specs := []ManualSpec{
+		{Kind: "torque", Item: "Wheel lug nuts", Values: map[string]string{"torque": "108 N·m"}, Page: 7},
+		{Kind: "bulb", Item: ""}, // skipped
+	}
+	if err := NewEnricher(gs).EnrichFromSpecs(context.Background(), vi, specs, "manual-1"); err != nil {
+		t.Fatalf("EnrichFromSpecs: %v", err)
+	}
+
+	specQuery, linkQuery := -1, -1
+	for i, q := range tx.queries {
+		switch {
+		case strings.Contains(q, "MERGE (s:Spec"):
+			specQuery = i
+		case strings.Contains(q, "MERGE (c)-[:HAS_SPEC]->(s)"):
+			linkQuery = i
+		}
+	}
+	if specQuery < 0 || linkQuery < 0 {
+		t.Fatalf("expected spec and component link queries: %v", tx.queries)
+	}
+	p := tx.params[specQuery]
+	if p["id"] != "honda-civic-2019:spec:torque:wheel-lug-nuts" || p["docID"] != "manual-1" {
+		t.Errorf("unexpected spec params %v", p)
+	}
+	props := p["props"].(map[string]any)
+	if props["spec_torque"] != "108 N·m" || props["page"] != 7 {
+		t.Errorf("unexpected spec props %v", props)
+	}
+	if tx.params[linkQuery]["cID"] != "honda-civic-2019:wheel-lug-nuts" {
+		t.Errorf("unexpected component link %v", tx.params[linkQuery])
+	}
+}
@@ -0,0 +1,140 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// WireRun is one wire read from a wiring diagram or connector pinout. A
+// pinout row without a destination only describes the pin.
+type WireRun struct {
+	From    string `json:"from"` // component or connector, e.g. "C101"
+	FromPin string `json:"from_pin,omitempty"`
+	To      string `json:"to,omitempty"`
+	ToPin   string `json:"to_pin,omitempty"`
+	Color   string `json:"color,omitempty"`   // e.g. "BLK/WHT"
+	Gauge   string `json:"gauge,omitempty"`   // e.g. "18 AWG", "0.5 mm²"
+	Circuit string `json:"circuit,omitempty"` // circuit number or pin function
+	Page    int    `json:"page,omitempty"`
+}
+
+// connectorName matches connector designators such as "C101", "X2" and
+// "Connector C220A".
+var connectorName = regexp.MustCompile(`(?i)^(?:connector\s+)?([cx]\d{1,4}[a-z]?)$`)
+
+// wireEndpoint returns the vehicle-scoped node ID, display name and type of
+// a wire end. Connector designators are upper-cased so "connector c101"
+// and "C101" are one node.
+func wireEndpoint(prefix, name string) (id, display, typ string) {
+	name = strings.TrimSpace(name)
+	if m := connectorName.FindStringSubmatch(name); m != nil {
+		display = strings.ToUpper(m[1])
+		return prefix + ":" + sanitizeID(display), display, "connector"
+	}
+	return prefix + ":" + sanitizeID(name), name, "component"
+}
+
+// PinID returns the ID of the pin node of a component or connector node.
+func PinID(endpointID, pin string) string {
+	return endpointID + ":pin-" + sanitizeID(pin)
+}
+
+// EnrichFromWiring stores wiring diagram and pinout data. Every wire end
+// becomes a Component node, typed "connector" for connector designators,
+// with a HAS_PIN edge to a "pin" Component when a pin is given. A wire
+// with both ends becomes a CONNECTS_TO edge between the pins, or the
+// components when no pin is known, carrying wire_color, gauge, pin and
+// circuit, so TracePath can follow a circuit through its connectors. Nodes
+// and edges record docID in their source_docs so the wiring can be retracted.
+func (e *Enricher) EnrichFromWiring(ctx context.Context, vi VehicleInfo, wires []WireRun, docID string) error {
+	if len(wires) == 0 {
+		return nil
+	}
+	if err := e.graph.EnsureVehicleHierarchy(ctx, vi); err != nil {
+		return fmt.Errorf("enricher: vehicle hierarchy: %w", err)
+	}
+
+	vehiclePrefix := vehicleScopePrefix(vi)
+
+	sess := e.graph.opener.OpenSession(ctx)
+	defer sess.Close(ctx)
+
+	_, err := sess.ExecuteWrite(ctx, func(tx CypherRunner) (any, error) {
+		// end merges a wire end and returns the ID of the node the wire
+		// attaches to.
+		end := func(name, pin string, w WireRun, describe bool) (string, error) {
+			id, display, typ := wireEndpoint(vehiclePrefix, name)
+			cypher := `MERGE (c:Component {id: $id}) ON CREATE SET c.name = $name, c.type = $type` + addSourceDoc("c")
+			if _, err := tx.Run(ctx, cypher, map[string]any{"id": id, "name": display, "type": typ, "docID": docID}); err != nil {
+				return "", err
+			}
+			if pin == "" {
+				return id, nil
+			}
+
+			pinID := PinID(id, pin)
+			props := map[string]any{
+				"name": display + " pin " + pin,
+				"type": "pin",
+				"pin":  pin,
+			}
+			if describe {
+				for k, v := range map[string]string{"wire_color": w.Color, "gauge": w.Gauge, "circuit": w.Circuit} {
+					if v != "" {
+						props[k] = v
+					}
+				}
+			}
+			cypher = `MERGE (p:Component {id: $id}) SET p += $props` + addSourceDoc("p") + `
+			          WITH p
+			          MATCH (c:Component {id: $cID})
+			          MERGE (c)-[:HAS_PIN]->(p)`
+			if _, err := tx.Run(ctx, cypher, map[string]any{"id": pinID, "props": props, "cID": id, "docID": docID}); err != nil {
+				return "", err
+			}
+			return pinID, nil
+		}
+
+		for _, w := range wires {
+			if strings.TrimSpace(w.From) == "" {
+				continue
+			}
+			fromID, err := end(w.From, w.FromPin, w, true)
+			if err != nil {
+				return nil, err
+			}
+			if strings.TrimSpace(w.To) == "" {
+				continue
+			}
+			toID, err := end(w.To, w.ToPin, w, false)
+			if err != nil {
+				return nil, err
+			}
+
+			props := map[string]any{}
+			for k, v := range map[string]string{
+				"wire": w.Color, "wire_color": w.Color, "gauge": w.Gauge,
+				"pin": w.FromPin, "to_pin": w.ToPin, "circuit": w.Circuit,
+			} {
+				if v != "" {
+					props[k] = v
+				}
+			}
+			if w.Page > 0 {
+				props["page"] = w.Page
+			}
+			cypher := `MATCH (a:Component {id: $fromID}), (b:Component {id: $toID})
+			           MERGE (a)-[r:CONNECTS_TO]->(b)
+			           SET r += $props` + addSourceDoc("r")
+			if _, err := tx.Run(ctx, cypher, map[string]any{
+				"fromID": fromID, "toID": toID, "props": props, "docID": docID,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	return err
+}
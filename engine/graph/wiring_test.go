@@ -0,0 +1,63 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestEnrichFromWiring(t *testing.T) {
+	gs, tx := newTrackingStore()
+	vi := VehicleInfo{Make: "Honda", Model: "Civic", Year: 2019}
+	wires := []WireRun{
+		{From: "Fuel pump relay", FromPin: "87", To: "Connector c101", ToPin: "3", Color: "GRN/WHT", Gauge: "0.5", Page: 12},
+		{From: "C220", FromPin: "1", Color: "BLK", Circuit: "Ground"},
+		{To: "Fuel pump"}, // no from, skipped
+	}
+	if err := NewEnricher(gs).EnrichFromWiring(context.Background(), vi, wires, "manual:civic-sec-3"); err != nil {
+		t.Fatalf("EnrichFromWiring: %v", err)
+	}
+
+	var ends, pins, edges []map[string]any
+	for i, q := range tx.queries {
+		switch {
+		case strings.Contains(q, "MERGE (c:Component"):
+			ends = append(ends, tx.params[i])
+		case strings.Contains(q, "HAS_PIN"):
+			pins = append(pins, tx.params[i])
+		case strings.Contains(q, "CONNECTS_TO"):
+			edges = append(edges, tx.params[i])
+		}
+	}
+	if len(ends) != 3 || ends[1]["id"] != "honda-civic-2019:c101" || ends[1]["name"] != "C101" || ends[1]["type"] != "connector" {
+		t.Fatalf("unexpected wire ends %v", ends)
+	}
+	if ends[0]["type"] != "component" {
+		t.Errorf("relay should be a component: %v", ends[0])
+	}
+	if len(pins) != 3 || pins[0]["id"] != "honda-civic-2019:fuel-pump-relay:pin-87" || pins[1]["id"] != "honda-civic-2019:c101:pin-3" {
+		t.Fatalf("unexpected pins %v", pins)
+	}
+	if props := pins[2]["props"].(map[string]any); props["wire_color"] != "BLK" || props["circuit"] != "Ground" || props["type"] != "pin" {
+		t.Errorf("unexpected pinout pin props %v", props)
+	}
+	if len(edges) != 1 {
+		t.Fatalf("expected 1 CONNECTS_TO edge, got %v", edges)
+	}
+	e := edges[0]
+	props := e["props"].(map[string]any)
+	if e["fromID"] != "honda-civic-2019:fuel-pump-relay:pin-87" || e["toID"] != "honda-civic-2019:c101:pin-3" ||
+		props["wire_color"] != "GRN/WHT" || props["gauge"] != "0.5" || props["pin"] != "87" || props["to_pin"] != "3" {
+		t.Errorf("unexpected edge %v", e)
+	}
+	for _, p := range append(append(ends, pins...), edges...) {
+		if p["docID"] != "manual:civic-sec-3" {
+			t.Errorf("wiring should record its document: %v", p)
+		}
+	}
+	for _, q := range tx.queries {
+		if strings.Contains(q, ":Component") && !strings.Contains(q, "source_docs") {
+			t.Errorf("wiring query should add to source_docs: %s", q)
+		}
+	}
+}
@@ -0,0 +1,420 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/WessleyAI/wessley-mvp/engine/scraper"
+	"github.com/WessleyAI/wessley-mvp/engine/semantic"
+	mlpb "github.com/WessleyAI/wessley-mvp/ml/proto/wessley/ml/v1"
+	"github.com/WessleyAI/wessley-mvp/pkg/fn"
+)
+
+// Defaults for BatchOptions.
+const (
+	DefaultEmbedWorkers    = 4
+	DefaultStoreWorkers    = 4
+	DefaultUpsertBatchSize = 256
+	DefaultFlushInterval   = 250 * time.Millisecond
+	DefaultQueueSize       = 64
+)
+
+// BatchOptions configures a BatchPipeline. Zero values select the defaults.
+type BatchOptions struct {
+	PrepareWorkers  int           // validate/parse/chunk workers (default NumCPU)
+	EmbedWorkers    int           // concurrent EmbedBatch calls
+	StoreWorkers    int           // concurrent graph writers
+	EmbedBatchSize  int           // chunks per EmbedBatch call, across documents (default EmbedBatchSize)
+	UpsertBatchSize int           // points per Qdrant upsert, across documents
+	FlushInterval   time.Duration // max time a partial batch waits for more input
+	QueueSize       int           // buffered documents between stages; bounds memory and applies backpressure
+}
+
+func (o BatchOptions) withDefaults() BatchOptions {
+	if o.PrepareWorkers <= 0 {
+		o.PrepareWorkers = runtime.NumCPU()
+	}
+	if o.EmbedWorkers <= 0 {
+		o.EmbedWorkers = DefaultEmbedWorkers
+	}
+	if o.StoreWorkers <= 0 {
+		o.StoreWorkers = DefaultStoreWorkers
+	}
+	if o.EmbedBatchSize <= 0 {
+		o.EmbedBatchSize = EmbedBatchSize
+	}
+	if o.UpsertBatchSize <= 0 {
+		o.UpsertBatchSize = DefaultUpsertBatchSize
+	}
+	if o.FlushInterval <= 0 {
+		o.FlushInterval = DefaultFlushInterval
+	}
+	if o.QueueSize <= 0 {
+		o.QueueSize = DefaultQueueSize
+	}
+	return o
+}
+
+// DocResult is the outcome of ingesting one post. Err names the stage that failed.
+type DocResult struct {
+	DocID    string
+	SourceID string
+	Chunks   int
+	Err      error
+}
+
+// BatchPipeline runs the same stages as NewPipeline, but micro-batches chunks
+// from many documents into each EmbedBatch call and coalesces Qdrant upserts.
+// Every stage is connected by bounded channels, so a slow embedder blocks the
+// producer instead of buffering the whole backlog in memory.
+type BatchPipeline struct {
+	embedder mlpb.EmbedServiceClient
+	vs       *semantic.VectorStore
+	deps     Deps
+	opts     BatchOptions
+	log      *slog.Logger
+	prepare  fn.Stage[scraper.ScrapedPost, ChunkedDoc] // Validate → Parse → Tombstone → Scrub → Score → DTCs → ChunkDoc
+}
+
+// NewBatchPipeline creates a BatchPipeline from the same dependencies as NewPipeline.
+func NewBatchPipeline(deps Deps, opts BatchOptions) *BatchPipeline {
+	log := deps.Logger
+	if log == nil {
+		log = slog.Default()
+	}
+	return &BatchPipeline{
+		embedder: deps.Embedder,
+		vs:       deps.VectorStore,
+		deps:     deps,
+		opts:     opts.withDefaults(),
+		log:      log,
+		prepare:  fn.Then(fn.Then(fn.Then(fn.Then(fn.Then(fn.Then(Validate, Parse), NewTombstoneCheck(deps.Tombstoned)), NewScrub(deps.Scrub)), NewScore(deps.Quality)), ExtractDTCs), ChunkDoc),
+	}
+}
+
+// batchDoc tracks one document while its chunks are spread over batches.
+type batchDoc struct {
+	seq  int
+	post scraper.ScrapedPost
+	doc  EmbeddedDoc
+	mu   sync.Mutex
+	left int // chunks awaiting embedding, then points awaiting upsert
+	err  error
+}
+
+// fail records the first error for the document.
+func (d *batchDoc) fail(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+func (d *batchDoc) result() DocResult {
+	return DocResult{DocID: d.doc.ID, SourceID: d.post.SourceID, Chunks: len(d.doc.Chunks), Err: d.err}
+}
+
+// chunkRef addresses one chunk (or one point) of a document in a batch.
+type chunkRef struct {
+	doc *batchDoc
+	idx int
+}
+
+// Run ingests posts until the channel is closed and emits one DocResult per
+// post. Results arrive in completion order; the returned channel is closed once
+// every post has been accounted for. The caller must drain it.
+func (p *BatchPipeline) Run(ctx context.Context, posts <-chan scraper.ScrapedPost) <-chan DocResult {
+	out := make(chan DocResult, p.opts.QueueSize)
+	go func() {
+		defer close(out)
+		for d := range p.run(ctx, posts) {
+			out <- d.result()
+		}
+	}()
+	return out
+}
+
+// Ingest runs posts through the pipeline and returns their results in input order.
+func (p *BatchPipeline) Ingest(ctx context.Context, posts []scraper.ScrapedPost) []DocResult {
+	in := make(chan scraper.ScrapedPost)
+	go func() {
+		defer close(in)
+		for _, post := range posts {
+			in <- post
+		}
+	}()
+	out := make([]DocResult, len(posts))
+	for d := range p.run(ctx, in) {
+		out[d.seq] = d.result()
+	}
+	return out
+}
+
+// run wires the stages together and returns finished documents.
+func (p *BatchPipeline) run(ctx context.Context, posts <-chan scraper.ScrapedPost) <-chan *batchDoc {
+	done := make(chan *batchDoc, p.opts.QueueSize)
+	chunked := make(chan *batchDoc, p.opts.QueueSize)
+	embedded := make(chan *batchDoc, p.opts.QueueSize)
+	stored := make(chan *batchDoc, p.opts.QueueSize)
+
+	// Sequence numbers are assigned before fan-out so Ingest can restore input order.
+	numbered := make(chan *batchDoc)
+	go func() {
+		defer close(numbered)
+		seq := 0
+		for post := range posts {
+			numbered <- &batchDoc{seq: seq, post: post}
+			seq++
+		}
+	}()
+
+	// Validate, parse and chunk in parallel.
+	var prepWG sync.WaitGroup
+	for i := 0; i < p.opts.PrepareWorkers; i++ {
+		prepWG.Add(1)
+		go func() {
+			defer prepWG.Done()
+			for d := range numbered {
+				d.doc.ID = d.post.Source + ":" + d.post.SourceID
+				cd, err := p.prepare(ctx, d.post).Unwrap()
+				if err != nil {
+					d.err = fmt.Errorf("prepare: %w", err)
+					done <- d
+					continue
+				}
+				d.doc = EmbeddedDoc{ChunkedDoc: cd, Embeddings: make([][]float32, len(cd.Chunks))}
+				d.left = len(cd.Chunks)
+				chunked <- d
+			}
+		}()
+	}
+	go func() { prepWG.Wait(); close(chunked) }()
+
+	go p.embedLoop(ctx, chunked, embedded)
+
+	// Graph writes are per document; only successfully embedded docs reach Qdrant.
+	var storeWG sync.WaitGroup
+	for i := 0; i < p.opts.StoreWorkers; i++ {
+		storeWG.Add(1)
+		go func() {
+			defer storeWG.Done()
+			for d := range embedded {
+				if d.err == nil {
+					d.err = ctx.Err()
+				}
+				if d.err == nil {
+					d.err = storeGraph(ctx, p.deps.GraphStore, d.doc.ChunkedDoc)
+				}
+				if d.err != nil {
+					done <- d
+					continue
+				}
+				d.left = len(d.doc.Chunks)
+				stored <- d
+			}
+		}()
+	}
+	go func() { storeWG.Wait(); close(stored) }()
+
+	var upsertWG sync.WaitGroup
+	upsertWG.Add(1)
+	go func() { defer upsertWG.Done(); p.upsertLoop(ctx, stored, done) }()
+
+	// done is written by the prepare, store and upsert stages; close it once
+	// all three have drained.
+	go func() {
+		prepWG.Wait()
+		storeWG.Wait()
+		upsertWG.Wait()
+		close(done)
+	}()
+	return done
+}
+
+// embedLoop groups chunks from consecutive documents into batches of
+// EmbedBatchSize and hands them to EmbedWorkers. The hand-off is unbuffered,
+// so when every worker is busy the loop stops reading and upstream blocks.
+func (p *BatchPipeline) embedLoop(ctx context.Context, in <-chan *batchDoc, out chan<- *batchDoc) {
+	jobs := make(chan []chunkRef)
+	var wg sync.WaitGroup
+	for i := 0; i < p.opts.EmbedWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range jobs {
+				p.embedBatch(ctx, batch)
+				release(batch, out)
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(p.opts.FlushInterval)
+	defer ticker.Stop()
+	var pending []chunkRef
+	for {
+		select {
+		case d, ok := <-in:
+			if !ok {
+				if len(pending) > 0 {
+					jobs <- pending
+				}
+				close(jobs)
+				wg.Wait()
+				close(out)
+				return
+			}
+			for i := range d.doc.Chunks {
+				pending = append(pending, chunkRef{doc: d, idx: i})
+			}
+			for len(pending) >= p.opts.EmbedBatchSize {
+				jobs <- pending[:p.opts.EmbedBatchSize:p.opts.EmbedBatchSize]
+				pending = pending[p.opts.EmbedBatchSize:]
+			}
+		case <-ticker.C:
+			if len(pending) > 0 {
+				jobs <- pending
+				pending = nil
+			}
+		}
+	}
+}
+
+// embedBatch embeds one cross-document batch. If the batch fails it is
+// retried per document, so one bad input only fails the document it came from.
+func (p *BatchPipeline) embedBatch(ctx context.Context, batch []chunkRef) {
+	err := p.embedRefs(ctx, batch)
+	if err == nil {
+		return
+	}
+	groups := groupByDoc(batch)
+	if len(groups) == 1 {
+		batch[0].doc.fail(err)
+		return
+	}
+	p.log.Warn("ingest: embed batch failed, retrying per document", "docs", len(groups), "error", err)
+	for _, g := range groups {
+		if err := p.embedRefs(ctx, g); err != nil {
+			g[0].doc.fail(err)
+		}
+	}
+}
+
+func (p *BatchPipeline) embedRefs(ctx context.Context, refs []chunkRef) error {
+	texts := make([]string, len(refs))
+	for i, r := range refs {
+		texts[i] = r.doc.doc.Chunks[r.idx].Text
+	}
+	resp, err := p.embedder.EmbedBatch(ctx, &mlpb.EmbedBatchRequest{Texts: texts})
+	if err != nil {
+		return fmt.Errorf("embed batch: %w", err)
+	}
+	embs := resp.GetEmbeddings()
+	if len(embs) != len(refs) {
+		return fmt.Errorf("embed batch: got %d embeddings for %d chunks", len(embs), len(refs))
+	}
+	for i, r := range refs {
+		r.doc.doc.Embeddings[r.idx] = embs[i].GetValues()
+	}
+	return nil
+}
+
+// upsertLoop coalesces points from many documents into UpsertBatchSize writes
+// and reports each document once all of its points have been written.
+func (p *BatchPipeline) upsertLoop(ctx context.Context, in <-chan *batchDoc, out chan<- *batchDoc) {
+	ticker := time.NewTicker(p.opts.FlushInterval)
+	defer ticker.Stop()
+	var (
+		refs    []chunkRef
+		records []semantic.VectorRecord
+	)
+	flush := func(n int) {
+		p.upsertBatch(ctx, refs[:n], records[:n])
+		release(refs[:n], out)
+		refs, records = refs[n:], records[n:]
+	}
+	for {
+		select {
+		case d, ok := <-in:
+			if !ok {
+				if len(refs) > 0 {
+					flush(len(refs))
+				}
+				return
+			}
+			for i, rec := range vectorRecords(d.doc, p.deps.Model) {
+				refs = append(refs, chunkRef{doc: d, idx: i})
+				records = append(records, rec)
+			}
+			for len(refs) >= p.opts.UpsertBatchSize {
+				flush(p.opts.UpsertBatchSize)
+			}
+		case <-ticker.C:
+			if len(refs) > 0 {
+				flush(len(refs))
+			}
+		}
+	}
+}
+
+// upsertBatch writes one coalesced batch, falling back to per-document
+// upserts on failure to attribute the error. The whole batch fails without
+// a write if Deps.CheckModel rejects the target collection.
+func (p *BatchPipeline) upsertBatch(ctx context.Context, refs []chunkRef, records []semantic.VectorRecord) {
+	if p.deps.CheckModel != nil {
+		if err := p.deps.CheckModel(ctx); err != nil {
+			for _, g := range groupByDoc(refs) {
+				g[0].doc.fail(fmt.Errorf("model check: %w", err))
+			}
+			return
+		}
+	}
+	err := p.vs.Upsert(ctx, records)
+	if err == nil {
+		return
+	}
+	groups := groupByDoc(refs)
+	if len(groups) == 1 {
+		refs[0].doc.fail(fmt.Errorf("vector upsert: %w", err))
+		return
+	}
+	p.log.Warn("ingest: upsert batch failed, retrying per document", "docs", len(groups), "error", err)
+	offset := 0
+	for _, g := range groups {
+		if err := p.vs.Upsert(ctx, records[offset:offset+len(g)]); err != nil {
+			g[0].doc.fail(fmt.Errorf("vector upsert: %w", err))
+		}
+		offset += len(g)
+	}
+}
+
+// groupByDoc splits a batch into runs of refs belonging to the same document.
+// Batches are filled document by document, so each document forms one run.
+func groupByDoc(refs []chunkRef) [][]chunkRef {
+	var groups [][]chunkRef
+	start := 0
+	for i := 1; i <= len(refs); i++ {
+		if i == len(refs) || refs[i].doc != refs[start].doc {
+			groups = append(groups, refs[start:i])
+			start = i
+		}
+	}
+	return groups
+}
+
+// release marks refs as processed and forwards documents that have none left.
+func release(refs []chunkRef, out chan<- *batchDoc) {
+	for _, r := range refs {
+		r.doc.mu.Lock()
+		r.doc.left--
+		last := r.doc.left == 0
+		r.doc.mu.Unlock()
+		if last {
+			out <- r.doc
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/WessleyAI/wessley-mvp/engine/graph"
+	"github.com/WessleyAI/wessley-mvp/engine/scraper"
+	"github.com/WessleyAI/wessley-mvp/engine/semantic"
+	mlpb "github.com/WessleyAI/wessley-mvp/ml/proto/wessley/ml/v1"
+	pb "github.com/qdrant/go-client/qdrant"
+	"google.golang.org/grpc"
+)
+
+// recordingEmbedder counts calls and fails any batch containing "POISON".
+type recordingEmbedder struct {
+	mu    sync.Mutex
+	sizes []int
+}
+
+func (m *recordingEmbedder) Embed(_ context.Context, _ *mlpb.EmbedRequest, _ ...grpc.CallOption) (*mlpb.EmbedResponse, error) {
+	return nil, nil
+}
+
+func (m *recordingEmbedder) EmbedBatch(_ context.Context, req *mlpb.EmbedBatchRequest, _ ...grpc.CallOption) (*mlpb.EmbedBatchResponse, error) {
+	m.mu.Lock()
+	m.sizes = append(m.sizes, len(req.Texts))
+	m.mu.Unlock()
+	embs := make([]*mlpb.EmbedResponse, len(req.Texts))
+	for i, t := range req.Texts {
+		if strings.Contains(t, "POISON") {
+			return nil, fmt.Errorf("bad input")
+		}
+		embs[i] = &mlpb.EmbedResponse{Values: []float32{1, 0, 0, 0}}
+	}
+	return &mlpb.EmbedBatchResponse{Embeddings: embs}, nil
+}
+
+// recordingPoints counts upserted points per call.
+type recordingPoints struct {
+	mockPoints
+	mu    sync.Mutex
+	sizes []int
+}
+
+func (m *recordingPoints) Upsert(_ context.Context, req *pb.UpsertPoints, _ ...grpc.CallOption) (*pb.PointsOperationResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sizes = append(m.sizes, len(req.Points))
+	return &pb.PointsOperationResponse{}, nil
+}
+
+func batchPosts(n int) []scraper.ScrapedPost {
+	posts := make([]scraper.ScrapedPost, n)
+	for i := range posts {
+		p := validPost()
+		p.SourceID = fmt.Sprintf("p%d", i)
+		posts[i] = p
+	}
+	return posts
+}
+
+func batchDeps(emb mlpb.EmbedServiceClient, points semantic.PointsAPI) Deps {
+	return Deps{
+		Embedder:    emb,
+		VectorStore: semantic.NewWithClients(points, &mockCollections{}, "test"),
+		GraphStore:  graph.NewWithOpener(&mockOpener{}),
+	}
+}
+
+func TestBatchPipeline_CoalescesAcrossDocuments(t *testing.T) {
+	emb := &recordingEmbedder{}
+	points := &recordingPoints{}
+	p := NewBatchPipeline(batchDeps(emb, points), BatchOptions{
+		EmbedBatchSize:  8,
+		UpsertBatchSize: 8,
+		FlushInterval:   time.Hour,
+	})
+
+	results := p.Ingest(context.Background(), batchPosts(20))
+	for i, r := range results {
+		if r.Err != nil {
+			t.Fatalf("doc %d failed: %v", i, r.Err)
+		}
+		if r.SourceID != fmt.Sprintf("p%d", i) {
+			t.Fatalf("result %d out of order: %s", i, r.SourceID)
+		}
+	}
+
+	// 20 single-chunk docs → 3 embed calls (8+8+4) and 3 upserts.
+	if len(emb.sizes) != 3 {
+		t.Errorf("expected 3 embed calls, got %v", emb.sizes)
+	}
+	if len(points.sizes) != 3 {
+		t.Errorf("expected 3 upserts, got %v", points.sizes)
+	}
+}
+
+func TestBatchPipeline_PerDocumentErrors(t *testing.T) {
+	emb := &recordingEmbedder{}
+	posts := batchPosts(5)
+	posts[2].Content = "POISON " + posts[2].Content
+	posts[4].Source = "unknown"
+
+	p := NewBatchPipeline(batchDeps(emb, &recordingPoints{}), BatchOptions{FlushInterval: time.Hour})
+	results := p.Ingest(context.Background(), posts)
+
+	for i, r := range results {
+		switch i {
+		case 2:
+			if r.Err == nil || !strings.Contains(r.Err.Error(), "embed batch") {
+				t.Errorf("doc 2: expected embed error, got %v", r.Err)
+			}
+		case 4:
+			if r.Err == nil || !strings.Contains(r.Err.Error(), "prepare") {
+				t.Errorf("doc 4: expected prepare error, got %v", r.Err)
+			}
+		default:
+			if r.Err != nil {
+				t.Errorf("doc %d: unexpected error %v", i, r.Err)
+			}
+		}
+	}
+}
+
+func TestBatchPipeline_ModelCheckBlocksUpsert(t *testing.T) {
+	points := &recordingPoints{}
+	deps := batchDeps(&recordingEmbedder{}, points)
+	var switched atomic.Bool
+	deps.CheckModel = func(context.Context) error {
+		if switched.Load() {
+			return semantic.ErrModelMismatch
+		}
+		return nil
+	}
+	p := NewBatchPipeline(deps, BatchOptions{FlushInterval: time.Hour})
+	for _, r := range p.Ingest(context.Background(), batchPosts(2)) {
+		if r.Err != nil {
+			t.Fatalf("%s: unexpected error %v", r.SourceID, r.Err)
+		}
+	}
+	written := len(points.sizes)
+
+	// cmd/reembed moved the alias to another model's collection.
+	switched.Store(true)
+	for _, r := range p.Ingest(context.Background(), batchPosts(2)) {
+		if !errors.Is(r.Err, semantic.ErrModelMismatch) {
+			t.Errorf("%s: expected ErrModelMismatch, got %v", r.SourceID, r.Err)
+		}
+	}
+	if len(points.sizes) != written {
+		t.Fatalf("upserted after the alias moved: %v", points.sizes)
+	}
+}
+
+func TestBatchPipeline_FlushInterval(t *testing.T) {
+	emb := &recordingEmbedder{}
+	p := NewBatchPipeline(batchDeps(emb, &recordingPoints{}), BatchOptions{FlushInterval: 10 * time.Millisecond})
+
+	in := make(chan scraper.ScrapedPost)
+	out := p.Run(context.Background(), in)
+	in <- batchPosts(1)[0]
+
+	// The partial batch must be flushed without closing the input.
+	select {
+	case r := <-out:
+		if r.Err != nil {
+			t.Fatalf("unexpected error: %v", r.Err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("partial batch was not flushed")
+	}
+	close(in)
+	for range out {
+	}
+}
+
+func TestGroupByDoc(t *testing.T) {
+	a, b := &batchDoc{}, &batchDoc{}
+	groups := groupByDoc([]chunkRef{{doc: a}, {doc: a}, {doc: b}})
+	if len(groups) != 2 || len(groups[0]) != 2 || len(groups[1]) != 1 {
+		t.Fatalf("unexpected groups: %v", groups)
+	}
+}
+
+// recordingOpener records the parameters of every graph query.
+type recordingOpener struct {
+	mu     sync.Mutex
+	params []map[string]any
+}
+
+func (o *recordingOpener) OpenSession(_ context.Context) graph.CypherSession { return o }
+func (o *recordingOpener) Close(_ context.Context) error                     { return nil }
+
+func (o *recordingOpener) Run(_ context.Context, _ string, params map[string]any) (graph.CypherResult, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.params = append(o.params, params)
+	return &mockCR{}, nil
+}
+
+func (o *recordingOpener) ExecuteWrite(_ context.Context, work func(tx graph.CypherRunner) (any, error)) (any, error) {
+	return work(o)
+}
+
+func TestBatchPipeline_StoresDTCs(t *testing.T) {
+	opener := &recordingOpener{}
+	deps := batchDeps(&recordingEmbedder{}, &recordingPoints{})
+	deps.GraphStore = graph.NewWithOpener(opener)
+	post := validPost()
+	post.Content = "Check engine light came back with P0420 after the new converter went in."
+
+	results := NewBatchPipeline(deps, BatchOptions{}).Ingest(context.Background(), []scraper.ScrapedPost{post})
+	if len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	for _, p := range opener.params {
+		if p["code"] == "P0420" && p["docID"] == results[0].DocID {
+			return
+		}
+	}
+	t.Errorf("P0420 mention not stored in the graph: %v", opener.params)
+}
@@ -0,0 +1,76 @@
+package ingest
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/WessleyAI/wessley-mvp/engine/dtc"
+	"github.com/WessleyAI/wessley-mvp/engine/graph"
+	"github.com/WessleyAI/wessley-mvp/pkg/fn"
+)
+
+// ExtractDTCs finds the diagnostic trouble codes a document mentions, in its
+// title, body and answers, and sets ParsedDoc.DTCs and Metadata["dtc_codes"].
+// Components named by an answer the asker confirmed as the fix count as
+// likely causes of every code in the thread.
+var ExtractDTCs fn.Stage[ParsedDoc, ParsedDoc] = func(_ context.Context, doc ParsedDoc) fn.Result[ParsedDoc] {
+	sentences := append([]string{doc.Title}, doc.Sentences...)
+	var fixes []string
+	for _, a := range doc.Answers {
+		sentences = append(sentences, splitSentences(a.Text)...)
+		if a.Resolved {
+			fixes = append(fixes, dtc.Components(a.Text)...)
+		}
+	}
+	mentions := dtc.Mentions(sentences)
+	if len(mentions) == 0 {
+		return fn.Ok(doc)
+	}
+
+	codes := make([]string, len(mentions))
+	for i, m := range mentions {
+		codes[i] = m.Code
+		for _, c := range fixes {
+			if !containsString(m.Components, c) {
+				mentions[i].Components = append(mentions[i].Components, c)
+			}
+		}
+	}
+	meta := make(map[string]string, len(doc.Metadata)+1)
+	for k, v := range doc.Metadata {
+		meta[k] = v
+	}
+	meta["dtc_codes"] = strings.Join(codes, ",")
+	doc.Metadata = meta
+	doc.DTCs = mentions
+	return fn.Ok(doc)
+}
+
+func containsString(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+// enrichDTCs links the document to the trouble codes it mentions. Failures
+// are logged, like the other enrichment in storeGraph.
+func enrichDTCs(ctx context.Context, gs *graph.GraphStore, vi graph.VehicleInfo, doc ChunkedDoc) {
+	if len(doc.DTCs) == 0 {
+		return
+	}
+	mentions := make([]graph.DTCMention, 0, len(doc.DTCs))
+	for _, m := range doc.DTCs {
+		c, ok := dtc.Lookup(m.Code)
+		if !ok {
+			continue
+		}
+		mentions = append(mentions, graph.DTCMention{DTC: graph.DTC(c), Components: m.Components})
+	}
+	if err := graph.NewEnricher(gs).EnrichFromDTCs(ctx, vi, doc.ID, doc.Quality, mentions); err != nil {
+		slog.Warn("ingest: dtc enrichment", "error", err, "doc_id", doc.ID)
+	}
+}
@@ -0,0 +1,47 @@
+package ingest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/WessleyAI/wessley-mvp/engine/scraper"
+)
+
+func TestExtractDTCs(t *testing.T) {
+	post := validPost()
+	post.Title = "P0420 on my truck"
+	post.Content = "Check engine light came back with P0420 and p0171. The shop quoted a new catalytic converter."
+	post.Metadata.Answers = []scraper.Answer{
+		{Text: "Replace the downstream O2 sensor first, that fixed mine.", Resolved: true},
+		{Text: "Could be a vacuum leak causing P0171."},
+	}
+	doc, err := ExtractDTCs(context.Background(), parsedDocFromPost(post)).Unwrap()
+	if err != nil {
+		t.Fatalf("ExtractDTCs: %v", err)
+	}
+	if doc.Metadata["dtc_codes"] != "P0420,P0171" || len(doc.DTCs) != 2 {
+		t.Fatalf("unexpected codes %q %+v", doc.Metadata["dtc_codes"], doc.DTCs)
+	}
+	if got := doc.DTCs[0].Components; !reflect.DeepEqual(got, []string{"Catalytic converter", "Oxygen sensor"}) {
+		t.Errorf("unexpected P0420 causes %v", got)
+	}
+	if got := doc.DTCs[1].Components; !reflect.DeepEqual(got, []string{"Catalytic converter", "Oxygen sensor", "Vacuum hose"}) {
+		t.Errorf("unexpected P0171 causes %v", got)
+	}
+
+	chunked, _ := ChunkDoc(context.Background(), doc).Unwrap()
+	records := vectorRecords(EmbeddedDoc{ChunkedDoc: chunked, Embeddings: make([][]float32, len(chunked.Chunks))}, testDeps().Model)
+	codes, ok := records[0].Payload["dtc_codes"].([]string)
+	if !ok || len(codes) == 0 || codes[0] != "P0420" {
+		t.Errorf("dtc_codes not recorded in payload: %v", records[0].Payload)
+	}
+}
+
+func TestExtractDTCs_None(t *testing.T) {
+	doc := parsedDocFromPost(validPost())
+	out, _ := ExtractDTCs(context.Background(), doc).Unwrap()
+	if out.DTCs != nil || out.Metadata["dtc_codes"] != "" {
+		t.Errorf("unexpected codes %+v", out.DTCs)
+	}
+}
@@ -5,11 +5,15 @@ package ingest
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/WessleyAI/wessley-mvp/engine/domain"
+	"github.com/WessleyAI/wessley-mvp/engine/dtc"
 	"github.com/WessleyAI/wessley-mvp/engine/graph"
 	"github.com/WessleyAI/wessley-mvp/engine/scraper"
 	"github.com/WessleyAI/wessley-mvp/engine/semantic"
@@ -35,8 +39,13 @@ type Deps struct {
 	Embedder     mlpb.EmbedServiceClient
 	VectorStore  *semantic.VectorStore
 	GraphStore   *graph.GraphStore
-	DeduplicateF func(ctx context.Context, docID string) (bool, error) // returns true if already ingested
+	DeduplicateF func(ctx context.Context, docID string) (bool, error)              // returns true if already ingested
+	Tombstoned   func(ctx context.Context, docID, source, url string) (bool, error) // returns true if the doc, its source or its URL was retracted; nil skips the check
+	CheckModel   func(ctx context.Context) error                                    // run before every vector upsert; an error fails the write, nil skips the check
 	Logger       *slog.Logger
+	Model        semantic.EmbeddingModel // recorded on every point; empty Name records nothing
+	Scrub        ScrubPolicies           // per-source PII redaction; nil uses DefaultScrubPolicies
+	Quality      QualityOptions          // quality threshold; the zero value scores without dropping
 }
 
 // --- Pipeline Stages ---
@@ -54,12 +63,59 @@ var Parse fn.Stage[scraper.ScrapedPost, ParsedDoc] = func(_ context.Context, pos
 	return fn.Ok(parsedDocFromPost(post))
 }
 
-// ChunkDoc splits a ParsedDoc into a ChunkedDoc.
+// ErrRetracted is returned for documents that were retracted (see Retractor).
+// Like ErrLowQuality it is a skip, not a failure to retry.
+var ErrRetracted = errors.New("ingest: document retracted")
+
+// NewTombstoneCheck creates a stage that fails retracted documents with
+// ErrRetracted, so a re-scrape cannot bring them back. Documents are checked
+// by ID and by source and URL, which a bulk retraction (RetractWhere)
+// tombstones. A nil tombstoned func passes every document.
+func NewTombstoneCheck(tombstoned func(ctx context.Context, docID, source, url string) (bool, error)) fn.Stage[ParsedDoc, ParsedDoc] {
+	return func(ctx context.Context, doc ParsedDoc) fn.Result[ParsedDoc] {
+		if tombstoned == nil {
+			return fn.Ok(doc)
+		}
+		retracted, err := tombstoned(ctx, doc.ID, doc.Source, doc.Metadata["url"])
+		if err != nil {
+			return fn.Err[ParsedDoc](fmt.Errorf("tombstone check: %w", err))
+		}
+		if retracted {
+			return fn.Err[ParsedDoc](fmt.Errorf("%w: %s", ErrRetracted, doc.ID))
+		}
+		return fn.Ok(doc)
+	}
+}
+
+// NewModelCheck creates a stage that runs check before a document's vectors
+// are written, so points are not upserted into a collection built by another
+// embedding model after the alias moves. A nil check passes every document.
+func NewModelCheck(check func(ctx context.Context) error) fn.Stage[EmbeddedDoc, EmbeddedDoc] {
+	return func(ctx context.Context, doc EmbeddedDoc) fn.Result[EmbeddedDoc] {
+		if check == nil {
+			return fn.Ok(doc)
+		}
+		if err := check(ctx); err != nil {
+			return fn.Err[EmbeddedDoc](fmt.Errorf("model check: %w", err))
+		}
+		return fn.Ok(doc)
+	}
+}
+
+// ChunkDoc splits a ParsedDoc into a ChunkedDoc using its source's chunking
+// policy. Thread sources carrying answers (e.g. Reddit) are chunked
+// question-with-answer.
 var ChunkDoc fn.Stage[ParsedDoc, ChunkedDoc] = func(_ context.Context, doc ParsedDoc) fn.Result[ChunkedDoc] {
-	chunks := chunkSentences(doc.ID, doc.Sentences, DefaultChunkSize, DefaultOverlap)
+	policy := chunkPolicy(doc.Source)
+	var chunks []Chunk
+	if policy.Threads && len(doc.Answers) > 0 {
+		chunks = chunkThread(doc, policy.Size)
+	} else {
+		chunks = chunkSentences(doc.ID, doc.Sentences, policy.Size, policy.Overlap)
+	}
 	if len(chunks) == 0 {
 		// Single chunk fallback for short content.
-		chunks = []Chunk{{Text: doc.Content, Index: 0, DocID: doc.ID}}
+		chunks = []Chunk{{Text: doc.Content, Index: 0, DocID: doc.ID, Score: doc.Score}}
 	}
 	return fn.Ok(ChunkedDoc{ParsedDoc: doc, Chunks: chunks})
 }
@@ -95,101 +151,183 @@ func NewEmbed(client mlpb.EmbedServiceClient) fn.Stage[ChunkedDoc, EmbeddedDoc]
 	}
 }
 
-// NewStore creates a Store stage that writes to Neo4j and Qdrant.
-func NewStore(vs *semantic.VectorStore, gs *graph.GraphStore) fn.Stage[EmbeddedDoc, string] {
+// NewStore creates a Store stage that writes to Neo4j and Qdrant, stamping
+// each point with the embedding model that produced it.
+func NewStore(vs *semantic.VectorStore, gs *graph.GraphStore, model semantic.EmbeddingModel) fn.Stage[EmbeddedDoc, string] {
 	return func(ctx context.Context, doc EmbeddedDoc) fn.Result[string] {
-		// Store component in knowledge graph.
-		comp := graph.Component{
-			ID:      doc.ID,
-			Name:    doc.Title,
-			Type:    "document",
-			Vehicle: doc.Vehicle,
-			Properties: map[string]string{
-				"source": doc.Source,
-			},
+		if err := storeGraph(ctx, gs, doc.ChunkedDoc); err != nil {
+			return fn.Err[string](err)
 		}
-		if err := gs.SaveComponent(ctx, comp); err != nil {
-			return fn.Err[string](fmt.Errorf("graph save: %w", err))
+
+		// Store vectors in Qdrant.
+		slog.Info("store: preparing vectors", "doc_id", doc.ID, "chunks", len(doc.Chunks), "embeddings", len(doc.Embeddings))
+		if err := vs.Upsert(ctx, vectorRecords(doc, model)); err != nil {
+			return fn.Err[string](fmt.Errorf("vector upsert: %w", err))
 		}
 
-		// If VehicleInfo is present, ensure the vehicle hierarchy exists and enrich.
-		if doc.VehicleInfo != nil {
-			vi := graph.VehicleInfo{
-				Make:  doc.VehicleInfo.Make,
-				Model: doc.VehicleInfo.Model,
-				Year:  doc.VehicleInfo.Year,
-				Trim:  doc.VehicleInfo.Trim,
-			}
-			if err := gs.EnsureVehicleHierarchy(ctx, vi); err != nil {
-				// Log but don't fail the pipeline for hierarchy errors.
-				slog.Warn("ingest: vehicle hierarchy", "error", err, "doc_id", doc.ID)
-			}
+		return fn.Ok(doc.ID)
+	}
+}
 
-			enricher := graph.NewEnricher(gs)
+// storeGraph saves the document node and its vehicle/system enrichment.
+// Only the document node write is fatal; enrichment failures are logged.
+func storeGraph(ctx context.Context, gs *graph.GraphStore, doc ChunkedDoc) error {
+	comp := graph.Component{
+		ID:      doc.ID,
+		Name:    doc.Title,
+		Type:    "document",
+		Vehicle: doc.Vehicle,
+		Properties: map[string]string{
+			"source": doc.Source,
+			"url":    doc.Metadata["url"],
+		},
+	}
+	if err := gs.SaveComponent(ctx, comp); err != nil {
+		return fmt.Errorf("graph save: %w", err)
+	}
 
-			// For manual sources with section info, classify and create vehicle-scoped nodes.
-			if doc.Source == "manual" && doc.Metadata["section"] != "" {
-				sys, sub := graph.ClassifySection(doc.Metadata["section"], doc.ParsedDoc.Content)
-				if sys != "" {
-					if err := enricher.EnrichFromSource(ctx, vi, sys, doc.ID); err != nil {
-						slog.Warn("ingest: manual enrichment", "error", err, "doc_id", doc.ID)
-					}
-					_ = sub // subsystem handled inside EnrichFromSource
-				}
-			}
+	// If VehicleInfo is present, ensure the vehicle hierarchy exists and enrich.
+	if doc.VehicleInfo == nil {
+		enrichDTCs(ctx, gs, graph.VehicleInfo{}, doc)
+		return nil
+	}
+	vi := graph.VehicleInfo{
+		Make:  doc.VehicleInfo.Make,
+		Model: doc.VehicleInfo.Model,
+		Year:  doc.VehicleInfo.Year,
+		Trim:  doc.VehicleInfo.Trim,
+	}
+	if err := gs.EnsureVehicleHierarchy(ctx, vi); err != nil {
+		// Log but don't fail the pipeline for hierarchy errors.
+		slog.Warn("ingest: vehicle hierarchy", "error", err, "doc_id", doc.ID)
+	}
+	enrichDTCs(ctx, gs, vi, doc)
+	if doc.Procedure != nil {
+		if err := graph.NewEnricher(gs).EnrichFromProcedures(ctx, vi, []graph.Procedure{*doc.Procedure}, doc.ID); err != nil {
+			slog.Warn("ingest: procedure enrichment", "error", err, "doc_id", doc.ID)
+		}
+	}
+	if doc.Recall != nil {
+		if err := graph.NewEnricher(gs).EnrichFromRecalls(ctx, vi, []graph.Recall{*doc.Recall}, doc.ID); err != nil {
+			slog.Warn("ingest: recall enrichment", "error", err, "doc_id", doc.ID)
+		}
+	}
+	if doc.Complaint != nil {
+		if err := graph.NewEnricher(gs).EnrichFromComplaints(ctx, vi, []graph.Complaint{*doc.Complaint}, doc.ID); err != nil {
+			slog.Warn("ingest: complaint enrichment", "error", err, "doc_id", doc.ID)
+		}
+	}
 
-			// For NHTSA/iFixit sources, classify the component/keyword string.
-			if doc.Source == "nhtsa" || doc.Source == "ifixit" {
-				componentStr := doc.Metadata["components"]
-				if componentStr == "" {
-					// Try keywords.
-					for _, kw := range doc.ChunkedDoc.ParsedDoc.Sentences {
-						componentStr = kw
-						break
-					}
-				}
-				if componentStr != "" {
-					if err := enricher.EnrichFromSource(ctx, vi, componentStr, doc.ID); err != nil {
-						slog.Warn("ingest: source enrichment", "error", err, "doc_id", doc.ID)
-					}
-				}
-			}
+	// Link the document under the component or system its source names.
+	src, ok := scraper.LookupSource(doc.Source)
+	if !ok {
+		return nil
+	}
+	if key := sourceKey(src, doc); key != "" {
+		if err := graph.NewEnricher(gs).EnrichFromSource(ctx, vi, key, doc.ID); err != nil {
+			slog.Warn("ingest: source enrichment", "error", err, "doc_id", doc.ID, "source", src.Name)
 		}
+	}
+	return nil
+}
 
-		// Store vectors in Qdrant.
-		slog.Info("store: preparing vectors", "doc_id", doc.ID, "chunks", len(doc.Chunks), "embeddings", len(doc.Embeddings))
-		records := make([]semantic.VectorRecord, len(doc.Chunks))
-		for i, chunk := range doc.Chunks {
-			// Generate deterministic UUID from doc ID and chunk index
-			pointID := uuid.NewSHA1(uuid.NameSpaceURL, []byte(fmt.Sprintf("%s-%d", doc.ID, chunk.Index))).String()
-			payload := map[string]any{
-				"content":     chunk.Text,
-				"doc_id":      doc.ID,
-				"source":      doc.Source,
-				"vehicle":     doc.Vehicle,
-				"chunk_index": chunk.Index,
+// sourceKey returns the component or system string a document is linked
+// under: the classified system of a manual section, or what the source's
+// Enrich hook names.
+func sourceKey(src scraper.SourceAdapter, doc ChunkedDoc) string {
+	if src.Sections && doc.Metadata["section"] != "" {
+		sys, _ := graph.ClassifySection(doc.Metadata["section"], doc.ParsedDoc.Content)
+		return sys
+	}
+	if src.Enrich == nil {
+		return ""
+	}
+	return src.Enrich(scraper.EnrichDoc{
+		Metadata:  doc.Metadata,
+		Content:   doc.ParsedDoc.Content,
+		Sentences: doc.ParsedDoc.Sentences,
+	})
+}
+
+// vectorRecords builds the Qdrant points for an embedded document.
+func vectorRecords(doc EmbeddedDoc, model semantic.EmbeddingModel) []semantic.VectorRecord {
+	records := make([]semantic.VectorRecord, len(doc.Chunks))
+	for i, chunk := range doc.Chunks {
+		// Generate deterministic UUID from doc ID and chunk index
+		pointID := uuid.NewSHA1(uuid.NameSpaceURL, []byte(fmt.Sprintf("%s-%d", doc.ID, chunk.Index))).String()
+		payload := map[string]any{
+			"content":     chunk.Text,
+			"doc_id":      doc.ID,
+			"source":      doc.Source,
+			"vehicle":     doc.Vehicle,
+			"chunk_index": chunk.Index,
+			"score":       chunk.Score,
+		}
+		if chunk.Resolved {
+			payload["resolved"] = true
+		}
+		if codes := dtc.Find(chunk.Text); len(codes) > 0 {
+			payload["dtc_codes"] = codes
+		}
+		if r := doc.Metadata["redactions"]; r != "" {
+			payload["redactions"] = r
+		}
+		if doc.Metadata["quality"] != "" {
+			payload["quality"] = doc.Quality
+		}
+		if doc.Metadata["low_quality"] == "true" {
+			payload["low_quality"] = true
+		}
+		// Manual sections carry where they are in the PDF, for citations.
+		for _, k := range []string{"manual_id", "section_title", "page_range"} {
+			if v := doc.Metadata[k]; v != "" {
+				payload[k] = v
 			}
-			// Add structured vehicle info to Qdrant payload.
-			if doc.VehicleInfo != nil {
-				payload["vehicle_make"] = doc.VehicleInfo.Make
-				payload["vehicle_model"] = doc.VehicleInfo.Model
-				payload["vehicle_year"] = doc.VehicleInfo.Year
-				if doc.VehicleInfo.Trim != "" {
-					payload["vehicle_trim"] = doc.VehicleInfo.Trim
-				}
+		}
+		if p := firstPage(doc.Metadata["page_range"]); p > 0 {
+			payload["page"] = p
+		}
+		// NHTSA complaints carry their severity, to filter for crashes and fires.
+		if c := doc.Complaint; c != nil {
+			payload["crash"] = c.Crash
+			payload["fire"] = c.Fire
+			payload["injuries"] = c.Injuries
+			payload["deaths"] = c.Deaths
+			if c.IncidentDate != "" {
+				payload["incident_date"] = c.IncidentDate
 			}
-			records[i] = semantic.VectorRecord{
-				ID:        pointID,
-				Embedding: doc.Embeddings[i],
-				Payload:   payload,
+			if c.VIN != "" {
+				payload["vin"] = c.VIN
 			}
 		}
-		if err := vs.Upsert(ctx, records); err != nil {
-			return fn.Err[string](fmt.Errorf("vector upsert: %w", err))
+		if model.Name != "" {
+			for k, v := range model.Payload() {
+				payload[k] = v
+			}
+		}
+		// Add structured vehicle info to Qdrant payload.
+		if doc.VehicleInfo != nil {
+			payload["vehicle_make"] = doc.VehicleInfo.Make
+			payload["vehicle_model"] = doc.VehicleInfo.Model
+			payload["vehicle_year"] = doc.VehicleInfo.Year
+			if doc.VehicleInfo.Trim != "" {
+				payload["vehicle_trim"] = doc.VehicleInfo.Trim
+			}
+		}
+		records[i] = semantic.VectorRecord{
+			ID:        pointID,
+			Embedding: doc.Embeddings[i],
+			Payload:   payload,
 		}
-
-		return fn.Ok(doc.ID)
 	}
+	return records
+}
+
+// firstPage returns the first page of a range like "45-52", or 0.
+func firstPage(pageRange string) int {
+	first, _, _ := strings.Cut(pageRange, "-")
+	p, _ := strconv.Atoi(strings.TrimSpace(first))
+	return p
 }
 
 // TapStage wraps any stage with logging at entry and exit.
@@ -201,9 +339,11 @@ func TapStage[T any](name string, log *slog.Logger) fn.Stage[T, T] {
 	})
 }
 
-// LoggedTap returns a stage that logs entry/exit with duration.
+// LoggedTap returns a stage that logs entry/exit with duration. It also
+// records the stage on the DocReport of a RunReported call.
 func LoggedTap[T any](name string, log *slog.Logger) fn.Stage[T, T] {
 	return func(ctx context.Context, t T) fn.Result[T] {
+		recordStage(ctx, name, t)
 		log.Info("stage.enter", "stage", name)
 		start := time.Now()
 		defer func() {
@@ -220,13 +360,18 @@ func NewPipeline(deps Deps) fn.Stage[scraper.ScrapedPost, string] {
 		log = slog.Default()
 	}
 
-	// Compose: Validate → Parse → Chunk → Embed → Store
+	// Compose: Validate → Parse → Tombstone → Scrub → Score → DTC → Chunk → Embed → ModelCheck → Store
 	// with logging taps between stages.
 	validated := fn.Then(LoggedTap[scraper.ScrapedPost]("validate", log), Validate)
 	parsed := fn.Then(validated, fn.Then(LoggedTap[scraper.ScrapedPost]("parse", log), Parse))
-	chunked := fn.Then(parsed, fn.Then(LoggedTap[ParsedDoc]("chunk", log), ChunkDoc))
+	live := fn.Then(parsed, fn.Then(LoggedTap[ParsedDoc]("tombstone", log), NewTombstoneCheck(deps.Tombstoned)))
+	scrubbed := fn.Then(live, fn.Then(LoggedTap[ParsedDoc]("scrub", log), NewScrub(deps.Scrub)))
+	scored := fn.Then(scrubbed, fn.Then(LoggedTap[ParsedDoc]("score", log), NewScore(deps.Quality)))
+	coded := fn.Then(scored, fn.Then(LoggedTap[ParsedDoc]("dtc", log), ExtractDTCs))
+	chunked := fn.Then(coded, fn.Then(LoggedTap[ParsedDoc]("chunk", log), ChunkDoc))
 	embedded := fn.Then(chunked, fn.Then(LoggedTap[ChunkedDoc]("embed", log), NewEmbed(deps.Embedder)))
-	stored := fn.Then(embedded, fn.Then(LoggedTap[EmbeddedDoc]("store", log), NewStore(deps.VectorStore, deps.GraphStore)))
+	checked := fn.Then(embedded, NewModelCheck(deps.CheckModel))
+	stored := fn.Then(checked, fn.Then(LoggedTap[EmbeddedDoc]("store", log), NewStore(deps.VectorStore, deps.GraphStore, deps.Model)))
 
 	return stored
 }
@@ -280,8 +425,9 @@ func StartConsumer(nc *nats.Conn, deps Deps) (*nats.Subscription, error) {
 		}
 
 		result := pipeline(ctx, post)
-		if result.IsErr() {
-			_, pipeErr := result.Unwrap()
+		if _, pipeErr := result.Unwrap(); errors.Is(pipeErr, ErrLowQuality) || errors.Is(pipeErr, ErrRetracted) {
+			log.Info("ingest: dropped document", "source_id", post.SourceID, "reason", pipeErr)
+		} else if result.IsErr() {
 			retries++
 			log.Error("ingest: pipeline failed",
 				"error", pipeErr,
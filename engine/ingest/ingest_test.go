@@ -2,9 +2,12 @@ package ingest
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/WessleyAI/wessley-mvp/engine/graph"
 	"github.com/WessleyAI/wessley-mvp/engine/scraper"
 )
 
@@ -76,6 +79,35 @@ func TestParseStage(t *testing.T) {
 	}
 }
 
+func TestParseStage_StructuredMetadata(t *testing.T) {
+	post := validPost()
+	post.Metadata.Procedure = &scraper.Procedure{
+		Title: "Replace the fuel pump", Source: "ifixit",
+		Steps: []scraper.ProcedureStep{{Order: 1, Text: "Relieve fuel pressure."}, {Order: 2, Title: "Pump", Text: "Lift out the pump."}},
+	}
+	post.Metadata.Recall = &scraper.Recall{Campaign: "18V123000", Kind: graph.RecallKindRecall, Units: 1200}
+	post.Metadata.Complaint = &scraper.Complaint{Components: "FUEL SYSTEM, GASOLINE", Fire: true, Injuries: 1}
+
+	doc, err := Parse(context.Background(), post).Unwrap()
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if p := doc.Procedure; p == nil || p.Title != "Replace the fuel pump" || len(p.Steps) != 2 || p.Steps[1] != (graph.ProcedureStep{Order: 2, Title: "Pump", Text: "Lift out the pump."}) {
+		t.Errorf("procedure not converted: %+v", doc.Procedure)
+	}
+	if r := doc.Recall; r == nil || r.Campaign != "18V123000" || r.Kind != graph.RecallKindRecall || r.Units != 1200 {
+		t.Errorf("recall not converted: %+v", doc.Recall)
+	}
+	if c := doc.Complaint; c == nil || *c != (graph.Complaint{Components: "FUEL SYSTEM, GASOLINE", Fire: true, Injuries: 1}) {
+		t.Errorf("complaint not converted: %+v", doc.Complaint)
+	}
+
+	doc, _ = Parse(context.Background(), validPost()).Unwrap()
+	if doc.Procedure != nil || doc.Recall != nil || doc.Complaint != nil {
+		t.Errorf("plain post should carry no structured metadata: %+v", doc)
+	}
+}
+
 func TestChunkDocStage(t *testing.T) {
 	ctx := context.Background()
 	doc := ParsedDoc{
@@ -170,3 +202,163 @@ func TestPipelineComposition(t *testing.T) {
 		t.Fatal("expected chunks")
 	}
 }
+
+func TestChunkDoc_ThreadAnswers(t *testing.T) {
+	ctx := context.Background()
+	post := validPost()
+	post.Metadata.Score = 30
+	post.Metadata.Answers = []scraper.Answer{
+		{Text: "Clean the ground strap. That fixed mine.", Score: 12, Resolved: true},
+		{Text: "Could be the starter relay.", Score: 4},
+	}
+	doc := parsedDocFromPost(post)
+
+	result := ChunkDoc(ctx, doc)
+	chunked, err := result.Unwrap()
+	if err != nil {
+		t.Fatalf("chunk failed: %v", err)
+	}
+	if len(chunked.Chunks) != 3 {
+		t.Fatalf("expected question + 2 answer chunks, got %d", len(chunked.Chunks))
+	}
+	if chunked.Chunks[0].Score != 30 {
+		t.Errorf("question chunk should carry post score, got %d", chunked.Chunks[0].Score)
+	}
+	ans := chunked.Chunks[1]
+	if !strings.HasPrefix(ans.Text, "Q: "+post.Title) || !strings.Contains(ans.Text, "A: Clean the ground strap") {
+		t.Errorf("answer chunk should pair question and answer, got %q", ans.Text)
+	}
+	if ans.Score != 12 || !ans.Resolved || ans.Index != 1 {
+		t.Errorf("unexpected answer chunk %+v", ans)
+	}
+}
+
+func TestChunkDoc_NonThreadSourceIgnoresAnswers(t *testing.T) {
+	post := validPost()
+	post.Source = "nhtsa"
+	post.Metadata.Answers = []scraper.Answer{{Text: "Dealer replaced the pump.", Score: 1}}
+
+	chunked, err := ChunkDoc(context.Background(), parsedDocFromPost(post)).Unwrap()
+	if err != nil {
+		t.Fatalf("chunk failed: %v", err)
+	}
+	for _, c := range chunked.Chunks {
+		if strings.HasPrefix(c.Text, "Q: ") {
+			t.Fatalf("nhtsa chunking policy has no threads, got %q", c.Text)
+		}
+	}
+}
+
+func TestChunkThread_LongAnswerSplits(t *testing.T) {
+	long := strings.Repeat("Replace the relay and recheck voltage at the starter. ", 60)
+	doc := ParsedDoc{ID: "reddit:x", Title: "No crank", Answers: []scraper.Answer{{Text: long, Score: 2}}}
+	chunks := chunkThread(doc, 100)
+	if len(chunks) < 2 {
+		t.Fatalf("expected long answer to split, got %d chunks", len(chunks))
+	}
+	for i, c := range chunks {
+		if c.Index != i || !strings.HasPrefix(c.Text, "Q: No crank") {
+			t.Fatalf("chunk %d malformed: %+v", i, c)
+		}
+	}
+}
+
+func TestTombstoneCheck(t *testing.T) {
+	ctx := context.Background()
+	doc := parsedDocFromPost(validPost())
+	tombstoned := func(_ context.Context, docID, source, url string) (bool, error) {
+		return docID == "reddit:abc123" || source == "forum" || strings.HasPrefix(url, "https://spam.example.com/"), nil
+	}
+
+	_, err := NewTombstoneCheck(tombstoned)(ctx, doc).Unwrap()
+	if !errors.Is(err, ErrRetracted) {
+		t.Fatalf("expected ErrRetracted, got %v", err)
+	}
+
+	doc.ID = "reddit:other"
+	if _, err := NewTombstoneCheck(tombstoned)(ctx, doc).Unwrap(); err != nil {
+		t.Fatalf("live document should pass, got %v", err)
+	}
+
+	// Bulk retractions tombstone a source or URL pattern, not just IDs.
+	forum := doc
+	forum.ID, forum.Source = "forum:new", "forum"
+	if _, err := NewTombstoneCheck(tombstoned)(ctx, forum).Unwrap(); !errors.Is(err, ErrRetracted) {
+		t.Fatalf("document from a retracted source: expected ErrRetracted, got %v", err)
+	}
+	spam := parsedDocFromPost(validPost())
+	spam.ID = "reddit:new"
+	spam.Metadata["url"] = "https://spam.example.com/t/1"
+	if _, err := NewTombstoneCheck(tombstoned)(ctx, spam).Unwrap(); !errors.Is(err, ErrRetracted) {
+		t.Fatalf("document under a retracted URL: expected ErrRetracted, got %v", err)
+	}
+	if _, err := NewTombstoneCheck(nil)(ctx, doc).Unwrap(); err != nil {
+		t.Fatalf("nil check should pass, got %v", err)
+	}
+}
+
+func TestVectorRecords_ManualCitation(t *testing.T) {
+	post := validPost()
+	post.Source = "manual"
+	post.Metadata.ManualID = "0123456789abcdef"
+	post.Metadata.SectionTitle = "Fuses"
+	post.Metadata.PageRange = "241-245"
+	chunked, _ := ChunkDoc(context.Background(), parsedDocFromPost(post)).Unwrap()
+	records := vectorRecords(EmbeddedDoc{ChunkedDoc: chunked, Embeddings: make([][]float32, len(chunked.Chunks))}, testDeps().Model)
+	p := records[0].Payload
+	if p["manual_id"] != "0123456789abcdef" || p["section_title"] != "Fuses" || p["page_range"] != "241-245" || p["page"] != 241 {
+		t.Errorf("citation fields not recorded in payload: %v", p)
+	}
+
+	chunked, _ = ChunkDoc(context.Background(), parsedDocFromPost(validPost())).Unwrap()
+	records = vectorRecords(EmbeddedDoc{ChunkedDoc: chunked, Embeddings: make([][]float32, len(chunked.Chunks))}, testDeps().Model)
+	if _, ok := records[0].Payload["page"]; ok {
+		t.Errorf("unexpected page on a forum post: %v", records[0].Payload)
+	}
+}
+
+func TestSourceKey(t *testing.T) {
+	manual, _ := scraper.LookupSource("manual")
+	post := validPost()
+	post.Source = "manual"
+	post.Metadata.Section = "Brake System"
+	post.Content = "Inspect the brake pads and rotors."
+	chunked, _ := ChunkDoc(context.Background(), parsedDocFromPost(post)).Unwrap()
+	if got := sourceKey(manual, chunked); got != "Brakes" {
+		t.Errorf("manual section should link under its system, got %q", got)
+	}
+
+	post.Metadata.Section = ""
+	chunked, _ = ChunkDoc(context.Background(), parsedDocFromPost(post)).Unwrap()
+	if got := sourceKey(manual, chunked); got != "" {
+		t.Errorf("manual without section should not enrich, got %q", got)
+	}
+
+	nhtsa, _ := scraper.LookupSource("nhtsa")
+	post = validPost()
+	post.Source = "nhtsa"
+	post.Metadata.Components = "ENGINE"
+	chunked, _ = ChunkDoc(context.Background(), parsedDocFromPost(post)).Unwrap()
+	if got := sourceKey(nhtsa, chunked); got != "ENGINE" {
+		t.Errorf("nhtsa key = %q", got)
+	}
+}
+
+func TestVectorRecords_ComplaintSeverity(t *testing.T) {
+	post := validPost()
+	post.Source = "nhtsa"
+	post.Metadata.Complaint = &scraper.Complaint{Components: "AIR BAGS", Crash: true, Injuries: 2, IncidentDate: "2023-05-14", VIN: "1FTEW1E5*JF"}
+	chunked, _ := ChunkDoc(context.Background(), parsedDocFromPost(post)).Unwrap()
+	records := vectorRecords(EmbeddedDoc{ChunkedDoc: chunked, Embeddings: make([][]float32, len(chunked.Chunks))}, testDeps().Model)
+	p := records[0].Payload
+	if p["crash"] != true || p["fire"] != false || p["injuries"] != 2 || p["deaths"] != 0 ||
+		p["incident_date"] != "2023-05-14" || p["vin"] != "1FTEW1E5*JF" {
+		t.Errorf("severity fields not recorded in payload: %v", p)
+	}
+
+	chunked, _ = ChunkDoc(context.Background(), parsedDocFromPost(validPost())).Unwrap()
+	records = vectorRecords(EmbeddedDoc{ChunkedDoc: chunked, Embeddings: make([][]float32, len(chunked.Chunks))}, testDeps().Model)
+	if _, ok := records[0].Payload["crash"]; ok {
+		t.Errorf("unexpected severity on a forum post: %v", records[0].Payload)
+	}
+}
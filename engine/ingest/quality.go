@@ -0,0 +1,259 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/WessleyAI/wessley-mvp/pkg/fn"
+)
+
+// DefaultQualityThreshold is the suggested minimum quality for cmd/ingest.
+const DefaultQualityThreshold = 0.35
+
+// ErrLowQuality is returned by the Score stage for documents it drops.
+// Callers treat it as a skip, not a failure to retry.
+var ErrLowQuality = errors.New("ingest: below quality threshold")
+
+// QualityOptions configures the Score stage.
+type QualityOptions struct {
+	Threshold  float64            // documents scoring below are dropped; 0 keeps everything
+	TagOnly    bool               // keep documents below Threshold and mark them low_quality
+	Reputation map[string]float64 // per-source prior in [0,1], "*" fallback; nil uses DefaultSourceReputation
+}
+
+// DefaultSourceReputation ranks sources by how often their content is accurate.
+// Manuals and recall/repair databases are curated; user posts vary widely.
+var DefaultSourceReputation = map[string]float64{
+	"manual":  1.0,
+	"nhtsa":   0.9,
+	"ifixit":  0.9,
+	"forum":   0.6,
+	"reddit":  0.5,
+	"youtube": 0.4,
+	"*":       0.5,
+}
+
+// QualitySignals are the components of a quality score, each in [0,1].
+type QualitySignals struct {
+	Length     float64 // word count, saturating
+	Density    float64 // lexical variety of real words; links and filler score low
+	Automotive float64 // share of automotive terms, saturating at 5%
+	Reputation float64 // source prior
+	Engagement float64 // votes and replies; only for sources that have them
+	Duplicate  float64 // share of sentences already seen in other documents
+}
+
+// Weights of each signal in the final score. Engagement is left out, and the
+// rest renormalised, for sources without votes.
+const (
+	wLength     = 0.20
+	wDensity    = 0.15
+	wAutomotive = 0.25
+	wReputation = 0.20
+	wEngagement = 0.10
+	wUnique     = 0.10
+)
+
+// Score combines the signals into a quality score in [0,1]. Documents with
+// almost no automotive terms are off-topic however well written, so their
+// score is halved.
+func (s QualitySignals) Score(hasEngagement bool) float64 {
+	sum := wLength*s.Length + wDensity*s.Density + wAutomotive*s.Automotive +
+		wReputation*s.Reputation + wUnique*(1-s.Duplicate)
+	total := wLength + wDensity + wAutomotive + wReputation + wUnique
+	if hasEngagement {
+		sum += wEngagement * s.Engagement
+		total += wEngagement
+	}
+	topical := 0.5 + 0.5*clamp01(s.Automotive*4)
+	return sum / total * topical
+}
+
+var (
+	wordPattern = regexp.MustCompile(`[A-Za-z0-9][A-Za-z0-9'./-]*`)
+	urlPattern  = regexp.MustCompile(`(?i)\bhttps?://\S+|\bwww\.\S+`)
+	dtcPattern  = regexp.MustCompile(`(?i)^[PBCU][0-3][0-9A-F]{3}$`)
+)
+
+// automotiveTerms are single words that mark a document as about vehicle repair.
+var automotiveTerms = toSet(
+	"abs", "actuator", "airbag", "alternator", "amp", "amps", "axle", "battery", "bearing",
+	"belt", "brake", "brakes", "bulb", "caliper", "camshaft", "catalytic", "circuit", "clutch",
+	"coil", "compressor", "connector", "coolant", "crank", "crankshaft", "cv", "cylinder",
+	"diagnostic", "differential", "ecu", "ecm", "engine", "exhaust", "fan", "filter", "fuel",
+	"fuse", "gasket", "gear", "ground", "harness", "headlight", "hose", "idle", "ignition",
+	"injector", "misfire", "module", "motor", "multimeter", "obd", "obd2", "ohm", "ohms",
+	"oil", "pcm", "piston", "pump", "radiator", "relay", "rotor", "sensor", "shock", "solenoid",
+	"spark", "starter", "steering", "strut", "suspension", "switch", "thermostat", "throttle",
+	"timing", "tire", "torque", "transmission", "valve", "voltage", "volts", "wheel", "wire",
+	"wiring",
+)
+
+func toSet(words ...string) map[string]bool {
+	m := make(map[string]bool, len(words))
+	for _, w := range words {
+		m[w] = true
+	}
+	return m
+}
+
+// engagementSources are sources whose Score and reply counts are meaningful.
+var engagementSources = map[string]bool{"reddit": true}
+
+// qualitySignals measures doc. dup is its duplicate-sentence ratio.
+func qualitySignals(doc ParsedDoc, reputation map[string]float64, dup float64) QualitySignals {
+	text := doc.Title + " " + doc.Content
+	for _, a := range doc.Answers {
+		text += " " + a.Text
+	}
+	links := len(urlPattern.FindAllString(text, -1))
+	words := wordPattern.FindAllString(urlPattern.ReplaceAllString(text, " "), -1)
+	n := len(words)
+
+	var s QualitySignals
+	s.Reputation = sourceReputation(reputation, doc.Source)
+	s.Duplicate = dup
+	if n == 0 {
+		return s
+	}
+
+	s.Length = clamp01(math.Log1p(float64(n)) / math.Log1p(300))
+
+	distinct := map[string]bool{}
+	var alpha, auto int
+	for _, w := range words {
+		lw := strings.ToLower(strings.TrimRight(w, "./-'"))
+		distinct[lw] = true
+		if strings.IndexFunc(lw, func(r rune) bool { return r >= 'a' && r <= 'z' }) >= 0 {
+			alpha++
+		}
+		if automotiveTerms[lw] || automotiveTerms[strings.TrimSuffix(lw, "s")] || dtcPattern.MatchString(lw) {
+			auto++
+		}
+	}
+	// Guiraud's index (distinct/sqrt(n)) is stable across lengths, unlike the raw ratio.
+	variety := clamp01(float64(len(distinct)) / math.Sqrt(float64(n)) / 6)
+	s.Density = variety * float64(alpha) / float64(n+links)
+	s.Automotive = clamp01(float64(auto) / float64(n) / 0.05)
+
+	comments := doc.Comments
+	if len(doc.Answers) > comments {
+		comments = len(doc.Answers)
+	}
+	s.Engagement = 0.6*clamp01(math.Log1p(math.Max(float64(doc.Score), 0))/math.Log1p(50)) +
+		0.4*clamp01(math.Log1p(float64(comments))/math.Log1p(20))
+	return s
+}
+
+func sourceReputation(reputation map[string]float64, source string) float64 {
+	if i := strings.IndexByte(source, ':'); i > 0 {
+		source = source[:i]
+	}
+	if r, ok := reputation[source]; ok {
+		return r
+	}
+	return reputation["*"]
+}
+
+func clamp01(x float64) float64 { return math.Max(0, math.Min(1, x)) }
+
+// maxSeenSentences bounds the memory of the duplicate tracker; it starts over when full.
+const maxSeenSentences = 500_000
+
+// dupTracker remembers which document first contained each sentence.
+type dupTracker struct {
+	mu    sync.Mutex
+	owner map[uint64]string
+}
+
+// ratio returns the share of doc's sentences that appear earlier in doc or
+// in another document, and records the new ones. Re-ingesting a document does
+// not count against it.
+func (t *dupTracker) ratio(docID string, sentences []string) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.owner) > maxSeenSentences {
+		t.owner = nil
+	}
+	if t.owner == nil {
+		t.owner = make(map[uint64]string)
+	}
+
+	var total, dup int
+	local := map[uint64]bool{}
+	for _, s := range sentences {
+		norm := strings.Join(strings.Fields(strings.ToLower(s)), " ")
+		if len(norm) < 20 {
+			continue // "Thanks!" and similar are expected everywhere
+		}
+		h := fnv.New64a()
+		h.Write([]byte(norm))
+		key := h.Sum64()
+		total++
+		if local[key] {
+			dup++
+			continue
+		}
+		local[key] = true
+		if owner, ok := t.owner[key]; ok && owner != docID {
+			dup++
+		} else if !ok {
+			t.owner[key] = docID
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(dup) / float64(total)
+}
+
+// NewScore creates a Score stage that rates each document's quality from its
+// length, information density, automotive-term density, source reputation,
+// engagement and duplicate-content ratio. The score is stored in
+// ParsedDoc.Quality and Metadata["quality"]. Documents below opts.Threshold
+// fail with ErrLowQuality unless opts.TagOnly is set, in which case they are
+// kept with Metadata["low_quality"] = "true".
+//
+// The stage remembers sentences across documents, so build one per pipeline.
+func NewScore(opts QualityOptions) fn.Stage[ParsedDoc, ParsedDoc] {
+	reputation := opts.Reputation
+	if reputation == nil {
+		reputation = DefaultSourceReputation
+	}
+	dups := &dupTracker{}
+
+	return func(_ context.Context, doc ParsedDoc) fn.Result[ParsedDoc] {
+		sentences := doc.Sentences
+		for _, a := range doc.Answers {
+			sentences = append(sentences[:len(sentences):len(sentences)], splitSentences(a.Text)...)
+		}
+		signals := qualitySignals(doc, reputation, dups.ratio(doc.ID, sentences))
+		source := doc.Source
+		if i := strings.IndexByte(source, ':'); i > 0 {
+			source = source[:i]
+		}
+		q := math.Round(signals.Score(engagementSources[source])*1000) / 1000
+
+		meta := make(map[string]string, len(doc.Metadata)+2)
+		for k, v := range doc.Metadata {
+			meta[k] = v
+		}
+		meta["quality"] = strconv.FormatFloat(q, 'f', -1, 64)
+		if q < opts.Threshold {
+			if !opts.TagOnly {
+				return fn.Err[ParsedDoc](fmt.Errorf("%w: %s scored %.3f < %.3f", ErrLowQuality, doc.ID, q, opts.Threshold))
+			}
+			meta["low_quality"] = "true"
+		}
+		doc.Quality = q
+		doc.Metadata = meta
+		return fn.Ok(doc)
+	}
+}
@@ -0,0 +1,143 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/WessleyAI/wessley-mvp/engine/scraper"
+)
+
+const repairText = "The 2014 F-150 would crank but not start. I measured 12.6 volts at the battery and " +
+	"checked the starter relay in the fuse box, which clicked normally. The fuel pump was silent, so I " +
+	"traced the wiring harness to the inertia switch behind the kick panel and found a corroded connector. " +
+	"After cleaning the ground and replacing the connector the pump primed and the engine started."
+
+func scoreDoc(t *testing.T, stage func(context.Context, ParsedDoc) (ParsedDoc, error), doc ParsedDoc) ParsedDoc {
+	t.Helper()
+	out, err := stage(context.Background(), doc)
+	if err != nil {
+		t.Fatalf("score: %v", err)
+	}
+	return out
+}
+
+func newScoreFunc(opts QualityOptions) func(context.Context, ParsedDoc) (ParsedDoc, error) {
+	stage := NewScore(opts)
+	return func(ctx context.Context, doc ParsedDoc) (ParsedDoc, error) {
+		return stage(ctx, doc).Unwrap()
+	}
+}
+
+func doc(id, source, title, content string) ParsedDoc {
+	return ParsedDoc{ID: id, Source: source, Title: title, Content: content, Sentences: splitSentences(content), Metadata: map[string]string{}}
+}
+
+func TestScore_RanksSignals(t *testing.T) {
+	score := newScoreFunc(QualityOptions{})
+
+	good := scoreDoc(t, score, doc("ifixit:1", "ifixit", "No start, fuel pump silent", repairText))
+	lowEffort := scoreDoc(t, score, doc("reddit:1", "reddit:cars", "lol", "same lol"))
+	linkOnly := scoreDoc(t, score, doc("reddit:2", "reddit:cars", "look", "https://example.com/video?id=123 https://example.com/other"))
+	offTopic := scoreDoc(t, score, doc("forum:1", "forum", "Vacation photos",
+		"We spent two weeks hiking along the coast and the weather was lovely every single day. "+
+			"The food at the small harbour restaurants was excellent and the people were friendly."))
+
+	if good.Quality < 0.7 {
+		t.Errorf("detailed repair write-up scored %.3f", good.Quality)
+	}
+	for name, d := range map[string]ParsedDoc{"low effort": lowEffort, "link only": linkOnly, "off topic": offTopic} {
+		if d.Quality >= good.Quality || d.Quality > 0.5 {
+			t.Errorf("%s scored %.3f (good %.3f)", name, d.Quality, good.Quality)
+		}
+	}
+	if good.Metadata["quality"] == "" || good.Metadata["low_quality"] != "" {
+		t.Errorf("unexpected metadata %v", good.Metadata)
+	}
+}
+
+func TestScore_RedditEngagement(t *testing.T) {
+	score := newScoreFunc(QualityOptions{})
+	quiet := doc("reddit:a", "reddit:MechanicAdvice", "No start", repairText)
+	popular := quiet
+	popular.ID = "reddit:b"
+	popular.Score = 250
+	popular.Comments = 40
+	popular.Sentences = splitSentences(repairText + " Thanks everyone for the help with this one.")
+
+	q := scoreDoc(t, score, quiet).Quality
+	p := scoreDoc(t, newScoreFunc(QualityOptions{}), popular).Quality
+	if p <= q {
+		t.Fatalf("upvotes and comments should raise quality: %.3f <= %.3f", p, q)
+	}
+}
+
+func TestScore_Duplicates(t *testing.T) {
+	score := newScoreFunc(QualityOptions{})
+	first := scoreDoc(t, score, doc("forum:1", "forum", "No start", repairText))
+	again := scoreDoc(t, score, doc("forum:1", "forum", "No start", repairText))
+	if again.Quality != first.Quality {
+		t.Fatalf("re-ingesting a document must not count as duplicate: %.3f vs %.3f", again.Quality, first.Quality)
+	}
+	copied := scoreDoc(t, score, doc("forum:2", "forum", "No start", repairText))
+	if copied.Quality >= first.Quality {
+		t.Fatalf("copied content should score lower: %.3f >= %.3f", copied.Quality, first.Quality)
+	}
+
+	tr := &dupTracker{}
+	repeated := []string{"Check the ground strap first.", "Check the ground strap first.", "Then test the relay coil."}
+	if r := tr.ratio("x", repeated); r < 0.3 || r > 0.4 {
+		t.Fatalf("expected 1/3 duplicate ratio, got %.2f", r)
+	}
+}
+
+func TestScore_ThresholdDropsOrTags(t *testing.T) {
+	spam := doc("reddit:s", "reddit", "lol", "same lol")
+
+	_, err := newScoreFunc(QualityOptions{Threshold: 0.5})(context.Background(), spam)
+	if !errors.Is(err, ErrLowQuality) {
+		t.Fatalf("expected ErrLowQuality, got %v", err)
+	}
+
+	tagged := scoreDoc(t, newScoreFunc(QualityOptions{Threshold: 0.5, TagOnly: true}), spam)
+	if tagged.Metadata["low_quality"] != "true" {
+		t.Fatalf("expected low_quality tag, got %v", tagged.Metadata)
+	}
+
+	custom := QualityOptions{Reputation: map[string]float64{"reddit": 0, "*": 1}}
+	lowRep := scoreDoc(t, newScoreFunc(custom), doc("reddit:r", "reddit", "No start", repairText))
+	highRep := scoreDoc(t, newScoreFunc(custom), doc("nhtsa:r", "nhtsa", "No start", repairText))
+	if lowRep.Quality >= highRep.Quality {
+		t.Fatalf("source reputation ignored: %.3f >= %.3f", lowRep.Quality, highRep.Quality)
+	}
+}
+
+func TestPipeline_QualityPayloadAndDrop(t *testing.T) {
+	deps := testDeps()
+	deps.Quality = QualityOptions{Threshold: 0.5}
+
+	spam := validPost()
+	spam.Title = "look"
+	spam.Content = "https://example.com/watch?v=abc"
+	spam.Metadata = scraper.Metadata{}
+	rep := RunReported(context.Background(), NewPipeline(deps), spam)
+	if !errors.Is(rep.Err, ErrLowQuality) || rep.Stage != "score" {
+		t.Fatalf("expected drop at score stage, got %+v", rep)
+	}
+
+	post := validPost()
+	post.Content = repairText
+	scored, _ := NewScore(QualityOptions{})(context.Background(), parsedDocFromPost(post)).Unwrap()
+	chunked, _ := ChunkDoc(context.Background(), scored).Unwrap()
+	records := vectorRecords(EmbeddedDoc{ChunkedDoc: chunked, Embeddings: make([][]float32, len(chunked.Chunks))}, deps.Model)
+	if q, ok := records[0].Payload["quality"].(float64); !ok || q != scored.Quality {
+		t.Fatalf("quality not in payload: %v", records[0].Payload)
+	}
+	if _, ok := records[0].Payload["low_quality"]; ok {
+		t.Fatal("low_quality must only be set on tagged documents")
+	}
+	if !strings.Contains(records[0].Payload["content"].(string), "fuel pump") {
+		t.Fatal("unexpected chunk content")
+	}
+}
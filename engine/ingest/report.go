@@ -0,0 +1,48 @@
+package ingest
+
+import (
+	"context"
+
+	"github.com/WessleyAI/wessley-mvp/engine/scraper"
+	"github.com/WessleyAI/wessley-mvp/pkg/fn"
+)
+
+// StageDone is the DocReport stage of a document that made it through Store.
+const StageDone = "done"
+
+// DocReport describes how far one document got through NewPipeline.
+type DocReport struct {
+	DocID  string
+	Stage  string // last stage entered, or StageDone
+	Chunks int    // set once the chunk stage has run
+	Err    error  // error of Stage, if any
+}
+
+type reportKey struct{}
+
+// recordStage updates the DocReport carried by ctx, if any. It is called from
+// the taps NewPipeline puts in front of every stage.
+func recordStage(ctx context.Context, stage string, v any) {
+	rep, ok := ctx.Value(reportKey{}).(*DocReport)
+	if !ok {
+		return
+	}
+	rep.Stage = stage
+	if doc, ok := v.(ChunkedDoc); ok && stage == "embed" {
+		rep.Chunks = len(doc.Chunks)
+	}
+}
+
+// RunReported runs post through pipeline, which must come from NewPipeline,
+// and reports the stage it reached, its chunk count and the stage's error.
+func RunReported(ctx context.Context, pipeline fn.Stage[scraper.ScrapedPost, string], post scraper.ScrapedPost) DocReport {
+	rep := &DocReport{DocID: post.Source + ":" + post.SourceID}
+	docID, err := pipeline(context.WithValue(ctx, reportKey{}, rep), post).Unwrap()
+	if err != nil {
+		rep.Err = err
+		return *rep
+	}
+	rep.DocID = docID
+	rep.Stage = StageDone
+	return *rep
+}
@@ -0,0 +1,39 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRunReported_Success(t *testing.T) {
+	rep := RunReported(context.Background(), NewPipeline(testDeps()), validPost())
+	if rep.Err != nil {
+		t.Fatalf("unexpected error: %v", rep.Err)
+	}
+	if rep.Stage != StageDone || rep.DocID != "reddit:abc123" {
+		t.Fatalf("unexpected report %+v", rep)
+	}
+	if rep.Chunks == 0 {
+		t.Fatal("chunk count not recorded")
+	}
+}
+
+func TestRunReported_StageErrors(t *testing.T) {
+	invalid := validPost()
+	invalid.Content = ""
+	rep := RunReported(context.Background(), NewPipeline(testDeps()), invalid)
+	if rep.Err == nil || rep.Stage != "validate" || rep.Chunks != 0 {
+		t.Fatalf("expected validate failure, got %+v", rep)
+	}
+
+	deps := testDeps()
+	deps.Embedder = &mockEmbedder{err: errors.New("ml-worker down")}
+	rep = RunReported(context.Background(), NewPipeline(deps), validPost())
+	if rep.Err == nil || rep.Stage != "embed" {
+		t.Fatalf("expected embed failure, got %+v", rep)
+	}
+	if rep.Chunks == 0 {
+		t.Fatal("chunks counted before embed should be reported")
+	}
+}
@@ -0,0 +1,101 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/WessleyAI/wessley-mvp/engine/graph"
+	"github.com/WessleyAI/wessley-mvp/engine/semantic"
+)
+
+// RetractResult reports the retraction of one document.
+type RetractResult struct {
+	DocID string             `json:"doc_id"`
+	Graph graph.RetractStats `json:"graph"`
+	Error string             `json:"error,omitempty"`
+}
+
+// Retractor removes documents everywhere ingestion put them: their Qdrant
+// points, their graph document node and the nodes derived only from it.
+// It records a tombstone first, so a concurrent or later re-scrape is
+// dropped by the Tombstone stage instead of resurrecting the document.
+type Retractor struct {
+	vs  *semantic.VectorStore
+	gs  *graph.GraphStore
+	log *slog.Logger
+}
+
+// NewRetractor creates a Retractor over the stores ingestion writes to.
+func NewRetractor(vs *semantic.VectorStore, gs *graph.GraphStore, log *slog.Logger) *Retractor {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &Retractor{vs: vs, gs: gs, log: log}
+}
+
+// Retract tombstones docID and removes its vectors and graph nodes. It is
+// idempotent: retracting an unknown or already retracted document only
+// refreshes the tombstone.
+func (r *Retractor) Retract(ctx context.Context, docID, reason string) RetractResult {
+	res := RetractResult{DocID: docID}
+	if err := r.retract(ctx, docID, reason, &res); err != nil {
+		res.Error = err.Error()
+		r.log.Warn("retract failed", "doc_id", docID, "error", err)
+		return res
+	}
+	r.log.Info("retracted document", "doc_id", docID, "reason", reason, "derived_nodes", res.Graph.Derived)
+	return res
+}
+
+func (r *Retractor) retract(ctx context.Context, docID, reason string, res *RetractResult) error {
+	ts := graph.Tombstone{DocID: docID, Reason: reason, RetractedAt: time.Now()}
+	if err := r.gs.SaveTombstone(ctx, ts); err != nil {
+		return fmt.Errorf("tombstone: %w", err)
+	}
+	if err := r.vs.DeleteByDocID(ctx, docID); err != nil {
+		return err
+	}
+	stats, err := r.gs.RetractDocument(ctx, docID)
+	if err != nil {
+		return err
+	}
+	res.Graph = stats
+	return nil
+}
+
+// RetractWhere retracts every document node matching f. With dryRun it
+// only lists them. Otherwise it first tombstones the filter itself, so
+// documents from the source or URL that are scraped later are dropped too.
+// Per-document failures are reported in the results.
+func (r *Retractor) RetractWhere(ctx context.Context, f graph.DocumentFilter, reason string, dryRun bool) ([]RetractResult, error) {
+	ids, err := r.gs.FindDocumentIDs(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	if !dryRun {
+		ts := graph.Tombstone{
+			DocID:       graph.FilterTombstoneID(f),
+			Source:      f.Source,
+			URLPattern:  f.URLPattern,
+			Reason:      reason,
+			RetractedAt: time.Now(),
+		}
+		if err := r.gs.SaveTombstone(ctx, ts); err != nil {
+			return nil, fmt.Errorf("tombstone: %w", err)
+		}
+	}
+	results := make([]RetractResult, len(ids))
+	for i, id := range ids {
+		if dryRun {
+			results[i] = RetractResult{DocID: id}
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return results[:i], err
+		}
+		results[i] = r.Retract(ctx, id, reason)
+	}
+	return results, nil
+}
@@ -0,0 +1,61 @@
+package ingest
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/WessleyAI/wessley-mvp/engine/graph"
+)
+
+func TestRetractor_Retract(t *testing.T) {
+	deps := testDeps()
+	r := NewRetractor(deps.VectorStore, deps.GraphStore, slog.Default())
+
+	res := r.Retract(context.Background(), "reddit:MechanicAdvice:abc", "deleted by author")
+	if res.Error != "" || res.DocID != "reddit:MechanicAdvice:abc" {
+		t.Fatalf("unexpected result %+v", res)
+	}
+}
+
+func TestRetractor_RetractWhereNeedsFilter(t *testing.T) {
+	deps := testDeps()
+	r := NewRetractor(deps.VectorStore, deps.GraphStore, nil)
+
+	if _, err := r.RetractWhere(context.Background(), graph.DocumentFilter{}, "cleanup", false); err == nil {
+		t.Fatal("expected error for empty filter")
+	}
+	results, err := r.RetractWhere(context.Background(), graph.DocumentFilter{Source: "forum"}, "cleanup", true)
+	if err != nil || len(results) != 0 {
+		t.Fatalf("dry run with no matches: %v %v", results, err)
+	}
+}
+
+func TestRetractor_RetractWhereTombstonesFilter(t *testing.T) {
+	opener := &recordingOpener{}
+	deps := testDeps()
+	r := NewRetractor(deps.VectorStore, graph.NewWithOpener(opener), nil)
+
+	f := graph.DocumentFilter{Source: "forum", URLPattern: "https://spam.example.com/*"}
+	if _, err := r.RetractWhere(context.Background(), f, "spam", true); err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	for _, p := range opener.params {
+		if p["id"] == graph.FilterTombstoneID(f) {
+			t.Fatalf("dry run saved a tombstone: %v", p)
+		}
+	}
+
+	if _, err := r.RetractWhere(context.Background(), f, "spam", false); err != nil {
+		t.Fatalf("RetractWhere: %v", err)
+	}
+	for _, p := range opener.params {
+		if p["id"] == graph.FilterTombstoneID(f) {
+			if p["source"] != "forum" || p["urlPattern"] != "https://spam.example.com/*" || p["reason"] != "spam" {
+				t.Errorf("unexpected filter tombstone %v", p)
+			}
+			return
+		}
+	}
+	t.Errorf("filter not tombstoned: %v", opener.params)
+}
@@ -0,0 +1,228 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/WessleyAI/wessley-mvp/engine/scraper"
+	"github.com/WessleyAI/wessley-mvp/pkg/fn"
+)
+
+// ScrubPolicy selects which personal data is redacted for a source.
+type ScrubPolicy struct {
+	Usernames bool `json:"usernames"` // the author's name, u/name and @name mentions
+	Emails    bool `json:"emails"`
+	Phones    bool `json:"phones"`
+	VINs      bool `json:"vins"` // keeps WMI through model year, masks plant and serial
+	Plates    bool `json:"plates"`
+}
+
+// ScrubPolicies maps a source ("reddit", "nhtsa", ...) to its policy.
+// The "*" entry applies to sources without their own.
+type ScrubPolicies map[string]ScrubPolicy
+
+// scrubAll redacts everything; it is the fallback for unknown sources.
+var scrubAll = ScrubPolicy{Usernames: true, Emails: true, Phones: true, VINs: true, Plates: true}
+
+// DefaultScrubPolicies redacts user-generated sources fully. NHTSA records are
+// anonymised upstream apart from VINs; manuals and guides carry no personal data.
+var DefaultScrubPolicies = ScrubPolicies{
+	"reddit":  scrubAll,
+	"forum":   scrubAll,
+	"youtube": scrubAll,
+	"nhtsa":   {Emails: true, Phones: true, VINs: true, Plates: true},
+	"ifixit":  {Usernames: true, Emails: true, Phones: true},
+	"manual":  {},
+	"*":       scrubAll,
+}
+
+// For returns the policy for source, ignoring any ":subsource" suffix.
+func (p ScrubPolicies) For(source string) ScrubPolicy {
+	if i := strings.IndexByte(source, ':'); i > 0 {
+		source = source[:i]
+	}
+	if pol, ok := p[source]; ok {
+		return pol
+	}
+	if pol, ok := p["*"]; ok {
+		return pol
+	}
+	return scrubAll
+}
+
+// Redaction placeholders.
+const (
+	redactedUser  = "[USER]"
+	redactedEmail = "[EMAIL]"
+	redactedPhone = "[PHONE]"
+	redactedPlate = "[PLATE]"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`(?i)\b[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}\b`)
+
+	// Phone numbers need separators, parentheses or a leading + so bare part
+	// numbers and odometer readings are left alone.
+	phonePattern = regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?(?:\(\d{3}\)\s?|\b\d{3}[\s.-])\d{3}[\s.-]\d{4}\b`)
+
+	// 17 characters, no I/O/Q. Mixed letters and digits are checked separately.
+	vinPattern = regexp.MustCompile(`(?i)\b[A-HJ-NPR-Z0-9]{17}\b`)
+
+	// Plates are only recognisable by context ("plate ABC-1234", "tag# 7XYZ123").
+	platePattern = regexp.MustCompile(`(?i)\b((?:license\s+)?(?:plate|tag)s?\s*(?:number|no\.?|#)?\s*(?:is|was|:)?\s*)([A-Z0-9]{1,4}[\s-]?[A-Z0-9]{2,5})\b`)
+
+	mentionPattern = regexp.MustCompile(`(?i)(^|[^\w/])(/?u/)[a-z0-9_-]{3,20}\b`)
+	atPattern      = regexp.MustCompile(`(^|[^\w@])@[A-Za-z0-9_.]{2,30}\b`)
+)
+
+// scrubber redacts one document and counts what it removed.
+type scrubber struct {
+	policy ScrubPolicy
+	author *regexp.Regexp
+	counts map[string]int
+}
+
+func newScrubber(policy ScrubPolicy, author string) *scrubber {
+	s := &scrubber{policy: policy, counts: map[string]int{}}
+	if policy.Usernames && usableAuthor(author) {
+		s.author = regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(author) + `\b`)
+	}
+	return s
+}
+
+// usableAuthor rejects names too short or generic to replace safely in text.
+func usableAuthor(author string) bool {
+	switch strings.ToLower(author) {
+	case "", "[deleted]", "anonymous", "unknown":
+		return false
+	}
+	return len(author) >= 4
+}
+
+func (s *scrubber) replace(re *regexp.Regexp, kind, text string, repl func(m []string) string) string {
+	return re.ReplaceAllStringFunc(text, func(m string) string {
+		out := repl(re.FindStringSubmatch(m))
+		if out != m {
+			s.counts[kind]++
+		}
+		return out
+	})
+}
+
+// text redacts one string. Emails go first so their local part is not taken
+// for an @mention.
+func (s *scrubber) text(t string) string {
+	if t == "" {
+		return t
+	}
+	if s.policy.Emails {
+		t = s.replace(emailPattern, "email", t, func([]string) string { return redactedEmail })
+	}
+	if s.policy.Usernames {
+		t = s.replace(mentionPattern, "username", t, func(m []string) string { return m[1] + m[2] + redactedUser })
+		t = s.replace(atPattern, "username", t, func(m []string) string { return m[1] + "@" + redactedUser })
+		if s.author != nil {
+			t = s.replace(s.author, "username", t, func([]string) string { return redactedUser })
+		}
+	}
+	if s.policy.VINs {
+		t = s.replace(vinPattern, "vin", t, func(m []string) string { return maskVIN(m[0]) })
+	}
+	if s.policy.Plates {
+		t = s.replace(platePattern, "plate", t, func(m []string) string {
+			if !hasDigit(m[2]) {
+				return m[0] // "plate is bent", not a plate number
+			}
+			return m[1] + redactedPlate
+		})
+	}
+	if s.policy.Phones {
+		t = s.replace(phonePattern, "phone", t, func([]string) string { return redactedPhone })
+	}
+	return t
+}
+
+// maskVIN keeps the WMI, descriptor, check digit and model year (positions
+// 1-10) so the make and year can still be inferred, and masks the plant code
+// and serial number that identify the individual vehicle.
+func maskVIN(vin string) string {
+	if !hasDigit(vin) || !hasLetter(vin) {
+		return vin
+	}
+	return strings.ToUpper(vin[:10]) + strings.Repeat("*", 7)
+}
+
+// vin masks a VIN field, such as an NHTSA complaint's, past its model year
+// character. Unlike text it needs no letter-and-digit check to be a VIN.
+func (s *scrubber) vin(v string) string {
+	if !s.policy.VINs || len(v) <= 10 {
+		return v
+	}
+	s.counts["vin"]++
+	return strings.ToUpper(v[:10]) + strings.Repeat("*", 7)
+}
+
+func hasDigit(s string) bool { return strings.ContainsAny(s, "0123456789") }
+func hasLetter(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool { return r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' }) >= 0
+}
+
+// summary renders the counts as "email:1,vin:2" for ParsedDoc.Metadata.
+func (s *scrubber) summary() string {
+	kinds := make([]string, 0, len(s.counts))
+	for k := range s.counts {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	parts := make([]string, len(kinds))
+	for i, k := range kinds {
+		parts[i] = fmt.Sprintf("%s:%d", k, s.counts[k])
+	}
+	return strings.Join(parts, ",")
+}
+
+// NewScrub creates a Scrub stage that redacts personal data from a ParsedDoc
+// according to the policy for its source. It runs before chunking, so nothing
+// unredacted reaches the embedder, Qdrant or Neo4j. What was removed is
+// recorded in Metadata["redactions"].
+func NewScrub(policies ScrubPolicies) fn.Stage[ParsedDoc, ParsedDoc] {
+	if policies == nil {
+		policies = DefaultScrubPolicies
+	}
+	return func(_ context.Context, doc ParsedDoc) fn.Result[ParsedDoc] {
+		s := newScrubber(policies.For(doc.Source), doc.Metadata["author"])
+
+		doc.Title = s.text(doc.Title)
+		doc.Content = s.text(doc.Content)
+		if len(doc.Answers) > 0 {
+			answers := make([]scraper.Answer, len(doc.Answers))
+			for i, a := range doc.Answers {
+				a.Text = s.text(a.Text)
+				answers[i] = a
+			}
+			doc.Answers = answers
+		}
+		doc.Sentences = splitSentences(doc.Content)
+		if c := doc.Complaint; c != nil && c.VIN != "" {
+			masked := *c
+			masked.VIN = s.vin(c.VIN)
+			doc.Complaint = &masked
+		}
+
+		meta := make(map[string]string, len(doc.Metadata)+1)
+		for k, v := range doc.Metadata {
+			meta[k] = v
+		}
+		if s.policy.Usernames && meta["author"] != "" {
+			meta["author"] = redactedUser
+		}
+		if sum := s.summary(); sum != "" {
+			meta["redactions"] = sum
+		}
+		doc.Metadata = meta
+		return fn.Ok(doc)
+	}
+}
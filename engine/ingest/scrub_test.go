@@ -0,0 +1,138 @@
+package ingest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/WessleyAI/wessley-mvp/engine/scraper"
+)
+
+func scrubDoc(t *testing.T, policies ScrubPolicies, doc ParsedDoc) ParsedDoc {
+	t.Helper()
+	out, err := NewScrub(policies)(context.Background(), doc).Unwrap()
+	if err != nil {
+		t.Fatalf("scrub: %v", err)
+	}
+	return out
+}
+
+func TestScrub_RedditPost(t *testing.T) {
+	content := "Thanks u/wrenchmonkey77 and @sparky_tech! Email me at jdoe42@gmail.com or call (555) 867-5309. " +
+		"VIN 1FTFW1E50JFA12345, license plate 7ABC123. Signed, carguy_jim."
+	doc := ParsedDoc{
+		ID:       "reddit:abc",
+		Source:   "reddit:MechanicAdvice",
+		Title:    "carguy_jim here: no crank",
+		Content:  content,
+		Metadata: map[string]string{"author": "carguy_jim"},
+		Answers:  []scraper.Answer{{Text: "Text 555-123-4567 if stuck"}},
+	}
+	out := scrubDoc(t, nil, doc)
+
+	for _, leaked := range []string{"wrenchmonkey77", "sparky_tech", "jdoe42", "867-5309", "A12345", "7ABC123", "carguy_jim", "555-123-4567"} {
+		if strings.Contains(out.Title+out.Content+out.Answers[0].Text, leaked) {
+			t.Errorf("%q not redacted: %q", leaked, out.Content)
+		}
+	}
+	if !strings.Contains(out.Content, "1FTFW1E50J*******") {
+		t.Errorf("VIN prefix should be kept: %q", out.Content)
+	}
+	if !strings.Contains(out.Content, "u/[USER]") || !strings.Contains(out.Content, "[EMAIL]") || !strings.Contains(out.Content, "plate [PLATE]") {
+		t.Errorf("unexpected placeholders: %q", out.Content)
+	}
+	if out.Metadata["author"] != "[USER]" {
+		t.Errorf("author metadata not redacted: %q", out.Metadata["author"])
+	}
+	if got := out.Metadata["redactions"]; got != "email:1,phone:2,plate:1,username:4,vin:1" {
+		t.Errorf("unexpected redaction summary %q", got)
+	}
+	for _, s := range out.Sentences {
+		if strings.Contains(s, "jdoe42") {
+			t.Error("sentences must be rebuilt from scrubbed content")
+		}
+	}
+	if doc.Metadata["author"] != "carguy_jim" {
+		t.Error("input metadata must not be mutated")
+	}
+}
+
+func TestScrub_LeavesTechnicalTextAlone(t *testing.T) {
+	content := "Torque to 100-120 ft-lbs. Part F81Z-2C405-AA at 125000 miles. Code P0301. The plate is bent."
+	out := scrubDoc(t, nil, ParsedDoc{Source: "forum", Content: content, Metadata: map[string]string{}})
+	if out.Content != content {
+		t.Fatalf("technical text changed:\n%q\n%q", content, out.Content)
+	}
+	if _, ok := out.Metadata["redactions"]; ok {
+		t.Fatal("no redactions expected")
+	}
+}
+
+func TestScrub_PerSourcePolicy(t *testing.T) {
+	content := "VIN 1HGCM82633A004352 reported by owner, contact owner@example.com"
+
+	nhtsa := scrubDoc(t, nil, ParsedDoc{Source: "nhtsa", Content: content, Metadata: map[string]string{"author": "owner"}})
+	if !strings.Contains(nhtsa.Content, "1HGCM82633*******") || !strings.Contains(nhtsa.Content, "[EMAIL]") {
+		t.Errorf("nhtsa policy not applied: %q", nhtsa.Content)
+	}
+
+	manual := scrubDoc(t, nil, ParsedDoc{Source: "manual", Content: content, Metadata: map[string]string{}})
+	if manual.Content != content {
+		t.Errorf("manual policy should keep text: %q", manual.Content)
+	}
+
+	custom := ScrubPolicies{"*": {Emails: true}}
+	other := scrubDoc(t, custom, ParsedDoc{Source: "youtube", Content: content, Metadata: map[string]string{}})
+	if !strings.Contains(other.Content, "1HGCM82633A004352") || strings.Contains(other.Content, "owner@example.com") {
+		t.Errorf("custom fallback policy not applied: %q", other.Content)
+	}
+}
+
+func TestPipeline_ScrubsBeforeStore(t *testing.T) {
+	post := validPost()
+	post.Content = "My number is 555-867-5309 and the battery light stays on while driving at night."
+	pipeline := NewPipeline(testDeps())
+	if r := pipeline(context.Background(), post); r.IsErr() {
+		_, err := r.Unwrap()
+		t.Fatalf("pipeline failed: %v", err)
+	}
+
+	doc := parsedDocFromPost(post)
+	scrubbed, _ := NewScrub(nil)(context.Background(), doc).Unwrap()
+	chunked, _ := ChunkDoc(context.Background(), scrubbed).Unwrap()
+	for _, c := range chunked.Chunks {
+		if strings.Contains(c.Text, "867-5309") {
+			t.Fatalf("phone number reached a chunk: %q", c.Text)
+		}
+	}
+	records := vectorRecords(EmbeddedDoc{ChunkedDoc: chunked, Embeddings: make([][]float32, len(chunked.Chunks))}, testDeps().Model)
+	if records[0].Payload["redactions"] != "phone:1" {
+		t.Fatalf("redactions not recorded in payload: %v", records[0].Payload)
+	}
+}
+
+func TestScrub_ComplaintVIN(t *testing.T) {
+	post := validPost()
+	post.Source = "nhtsa"
+	post.Metadata.Complaint = &scraper.Complaint{Components: "ELECTRICAL SYSTEM", Crash: true, VIN: "1hgcm82633a004352"}
+
+	scrubbed := scrubDoc(t, nil, parsedDocFromPost(post))
+	if post.Metadata.Complaint.VIN != "1hgcm82633a004352" {
+		t.Errorf("scrub should not modify the post's complaint")
+	}
+	chunked, _ := ChunkDoc(context.Background(), scrubbed).Unwrap()
+	records := vectorRecords(EmbeddedDoc{ChunkedDoc: chunked, Embeddings: make([][]float32, len(chunked.Chunks))}, testDeps().Model)
+	for _, r := range records {
+		if vin := r.Payload["vin"]; vin != "1HGCM82633*******" {
+			t.Fatalf("complaint VIN not masked in payload: %v", vin)
+		}
+	}
+	if scrubbed.Metadata["redactions"] != "vin:1" || !scrubbed.Complaint.Crash {
+		t.Errorf("unexpected scrubbed complaint %+v %v", scrubbed.Complaint, scrubbed.Metadata)
+	}
+
+	kept := scrubDoc(t, ScrubPolicies{"*": {}}, parsedDocFromPost(post))
+	if kept.Complaint.VIN != "1hgcm82633a004352" {
+		t.Errorf("VIN should be kept when the policy allows it: %q", kept.Complaint.VIN)
+	}
+}
@@ -3,6 +3,8 @@ package ingest
 import (
 	"strings"
 	"unicode"
+
+	"github.com/WessleyAI/wessley-mvp/engine/scraper"
 )
 
 const (
@@ -12,6 +14,19 @@ const (
 	DefaultOverlap = 50
 )
 
+// chunkPolicy returns the chunking policy of source with the defaults filled in.
+func chunkPolicy(source string) scraper.ChunkPolicy {
+	src, _ := scraper.LookupSource(source)
+	p := src.Chunking
+	if p.Size <= 0 {
+		p.Size = DefaultChunkSize
+	}
+	if p.Overlap <= 0 {
+		p.Overlap = DefaultOverlap
+	}
+	return p
+}
+
 // splitSentences splits text into sentences using punctuation and newlines.
 func splitSentences(text string) []string {
 	var sentences []string
@@ -99,3 +114,59 @@ func chunkSentences(docID string, sentences []string, chunkSize, overlap int) []
 func wordCount(s string) int {
 	return len(strings.Fields(s))
 }
+
+// maxQuestionTokens caps how much of the question is repeated in front of
+// each answer chunk.
+const maxQuestionTokens = 128
+
+// chunkThread chunks a question-and-answers document. The question body is
+// chunked on its own, then every answer chain is chunked with a truncated copy
+// of the question in front so retrieval returns the problem and its fix together.
+func chunkThread(doc ParsedDoc, chunkSize int) []Chunk {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+
+	chunks := chunkSentences(doc.ID, doc.Sentences, chunkSize, DefaultOverlap)
+	for i := range chunks {
+		chunks[i].Score = doc.Score
+	}
+
+	question := truncateWords(strings.TrimSpace(doc.Title+"\n"+doc.Content), maxQuestionTokens)
+	budget := chunkSize - wordCount(question)
+	if budget < chunkSize/2 {
+		budget = chunkSize / 2
+	}
+
+	for _, ans := range doc.Answers {
+		parts := []string{ans.Text}
+		if wordCount(ans.Text) > budget {
+			parts = parts[:0]
+			for _, c := range chunkSentences(doc.ID, splitSentences(ans.Text), budget, 0) {
+				parts = append(parts, c.Text)
+			}
+		}
+		for _, p := range parts {
+			chunks = append(chunks, Chunk{
+				Text:     "Q: " + question + "\n\nA: " + p,
+				DocID:    doc.ID,
+				Score:    ans.Score,
+				Resolved: ans.Resolved,
+			})
+		}
+	}
+
+	for i := range chunks {
+		chunks[i].Index = i
+	}
+	return chunks
+}
+
+// truncateWords keeps the first n words of s.
+func truncateWords(s string, n int) string {
+	words := strings.Fields(s)
+	if len(words) <= n {
+		return strings.Join(words, " ")
+	}
+	return strings.Join(words[:n], " ") + " ..."
+}
@@ -1,6 +1,10 @@
 package ingest
 
-import "github.com/WessleyAI/wessley-mvp/engine/scraper"
+import (
+	"github.com/WessleyAI/wessley-mvp/engine/dtc"
+	"github.com/WessleyAI/wessley-mvp/engine/graph"
+	"github.com/WessleyAI/wessley-mvp/engine/scraper"
+)
 
 // ParsedDoc represents a scraped post after parsing/extraction.
 type ParsedDoc struct {
@@ -12,6 +16,14 @@ type ParsedDoc struct {
 	VehicleInfo *scraper.VehicleInfo
 	Sentences   []string
 	Metadata    map[string]string
+	Score       int
+	Comments    int
+	Answers     []scraper.Answer
+	Procedure   *graph.Procedure // step-by-step structure of a repair guide
+	Recall      *graph.Recall    // NHTSA recall or investigation the doc describes
+	Complaint   *graph.Complaint // crash/fire/injury record of an NHTSA complaint
+	Quality     float64          // set by the Score stage, in [0,1]
+	DTCs        []dtc.Mention    // set by the ExtractDTCs stage
 }
 
 // ChunkedDoc is a parsed document split into embeddable chunks.
@@ -22,9 +34,11 @@ type ChunkedDoc struct {
 
 // Chunk is a text segment ready for embedding.
 type Chunk struct {
-	Text  string
-	Index int
-	DocID string
+	Text     string
+	Index    int
+	DocID    string
+	Score    int  // ranking signal carried into the vector payload
+	Resolved bool // chunk contains an answer the asker confirmed as the fix
 }
 
 // EmbeddedDoc is a chunked document with embeddings.
@@ -43,6 +57,15 @@ func parsedDocFromPost(post scraper.ScrapedPost) ParsedDoc {
 		"components": post.Metadata.Components,
 		"section":    post.Metadata.Section,
 	}
+	for k, v := range map[string]string{
+		"manual_id":     post.Metadata.ManualID,
+		"section_title": post.Metadata.SectionTitle,
+		"page_range":    post.Metadata.PageRange,
+	} {
+		if v != "" {
+			meta[k] = v
+		}
+	}
 	return ParsedDoc{
 		ID:          post.Source + ":" + post.SourceID,
 		Source:      post.Source,
@@ -52,5 +75,61 @@ func parsedDocFromPost(post scraper.ScrapedPost) ParsedDoc {
 		VehicleInfo: post.Metadata.VehicleInfo,
 		Sentences:   splitSentences(post.Content),
 		Metadata:    meta,
+		Score:       post.Metadata.Score,
+		Comments:    post.Metadata.Comments,
+		Answers:     post.Metadata.Answers,
+		Procedure:   graphProcedure(post.Metadata.Procedure),
+		Recall:      graphRecall(post.Metadata.Recall),
+		Complaint:   graphComplaint(post.Metadata.Complaint),
+	}
+}
+
+// graphProcedure converts a scraped repair guide for the graph.
+func graphProcedure(p *scraper.Procedure) *graph.Procedure {
+	if p == nil {
+		return nil
+	}
+	steps := make([]graph.ProcedureStep, len(p.Steps))
+	for i, s := range p.Steps {
+		steps[i] = graph.ProcedureStep(s)
+	}
+	return &graph.Procedure{
+		Title:      p.Title,
+		Operation:  p.Operation,
+		Components: p.Components,
+		Steps:      steps,
+		Tools:      p.Tools,
+		Warnings:   p.Warnings,
+		Source:     p.Source,
+		URL:        p.URL,
+	}
+}
+
+// graphRecall converts a scraped recall or investigation for the graph.
+func graphRecall(r *scraper.Recall) *graph.Recall {
+	if r == nil {
+		return nil
+	}
+	return &graph.Recall{
+		Campaign:     r.Campaign,
+		Kind:         r.Kind,
+		Component:    r.Component,
+		Summary:      r.Summary,
+		Consequence:  r.Consequence,
+		Remedy:       r.Remedy,
+		Manufacturer: r.Manufacturer,
+		Date:         r.Date,
+		Status:       r.Status,
+		Units:        r.Units,
+		URL:          r.URL,
+	}
+}
+
+// graphComplaint converts a scraped complaint's severity for the graph.
+func graphComplaint(c *scraper.Complaint) *graph.Complaint {
+	if c == nil {
+		return nil
 	}
+	gc := graph.Complaint(*c)
+	return &gc
 }
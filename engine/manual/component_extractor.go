@@ -1,4 +1,4 @@
-package manuals
+package manual
 
 import (
 	"regexp"
@@ -81,7 +81,7 @@ func ExtractComponents(text string) []graph.ExtractedComponent {
 		seen[key] = true
 		specs := extractSpecs(text)
 		components = append(components, graph.ExtractedComponent{
-			Name:        normalizeComponentName(name),
+			Name:        NormalizeComponentName(name),
 			PartNumber:  partNum,
 			Description: desc,
 			Specs:       specs,
@@ -145,8 +145,8 @@ func findNearbyPartNumber(text, component string) string {
 	return ""
 }
 
-// normalizeComponentName cleans up and title-cases a component name.
-func normalizeComponentName(name string) string {
+// NormalizeComponentName cleans up and title-cases a component name.
+func NormalizeComponentName(name string) string {
 	name = strings.TrimSpace(name)
 	words := strings.Fields(strings.ToLower(name))
 	for i, w := range words {
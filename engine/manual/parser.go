@@ -0,0 +1,113 @@
+package manual
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/WessleyAI/wessley-mvp/pkg/pdf"
+)
+
+// pageSeparator separates pages in extracted text. ParseSections counts
+// form feeds to assign real page numbers to sections.
+const pageSeparator = "\n\f\n"
+
+// PDFContent is the text and tables read from a manual PDF.
+type PDFContent struct {
+	Text   string // one block per page, separated by form feeds
+	Tables []Table
+}
+
+// ExtractTextFromPDF extracts the text of a PDF file, one block per page
+// separated by form feeds.
+func ExtractTextFromPDF(path string) (string, error) {
+	c, err := ReadPDF(path)
+	return c.Text, err
+}
+
+// ReadPDF extracts the text and tables of a PDF file.
+func ReadPDF(path string) (PDFContent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return PDFContent{}, fmt.Errorf("read pdf: %w", err)
+	}
+	return ParsePDF(data)
+}
+
+// ParsePDF decodes data with the pdf package, joining the page texts with
+// pageSeparator and detecting tables on each page. Data without parseable
+// PDF objects falls back to scanning for uncompressed BT/ET blocks;
+// encrypted documents are an error.
+func ParsePDF(data []byte) (PDFContent, error) {
+	pages, err := pdf.ExtractPages(data)
+	if errors.Is(err, pdf.ErrEncrypted) {
+		return PDFContent{}, err
+	}
+	if err != nil {
+		return PDFContent{Text: extractPDFText(data)}, nil
+	}
+	var c PDFContent
+	texts := make([]string, len(pages))
+	for i, p := range pages {
+		texts[i] = p.Text()
+		c.Tables = append(c.Tables, DetectTables(p)...)
+	}
+	if strings.TrimSpace(strings.Join(texts, "")) != "" {
+		c.Text = strings.Join(texts, pageSeparator)
+	}
+	return c, nil
+}
+
+// extractPDFText scans raw PDF bytes for strings in uncompressed BT/ET
+// blocks. It is the fallback for data pdf.Open cannot parse.
+func extractPDFText(data []byte) string {
+	var texts []string
+
+	// Look for text between parentheses in BT...ET blocks
+	// This is a simplified approach for basic PDF text extraction
+	inText := false
+	for i := 0; i < len(data)-1; i++ {
+		// Detect BT (Begin Text)
+		if data[i] == 'B' && data[i+1] == 'T' && (i == 0 || !isAlpha(data[i-1])) {
+			inText = true
+			continue
+		}
+		// Detect ET (End Text)
+		if data[i] == 'E' && data[i+1] == 'T' && inText && (i+2 >= len(data) || !isAlpha(data[i+2])) {
+			inText = false
+			continue
+		}
+
+		if inText && data[i] == '(' {
+			// Extract text between parentheses
+			end := bytes.IndexByte(data[i+1:], ')')
+			if end >= 0 {
+				text := string(data[i+1 : i+1+end])
+				text = cleanPDFText(text)
+				if text != "" {
+					texts = append(texts, text)
+				}
+				i += end + 1
+			}
+		}
+	}
+
+	return strings.Join(texts, " ")
+}
+
+func isAlpha(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+// cleanPDFText removes common PDF escape sequences.
+func cleanPDFText(s string) string {
+	s = strings.ReplaceAll(s, "\\n", "\n")
+	s = strings.ReplaceAll(s, "\\r", "\r")
+	s = strings.ReplaceAll(s, "\\t", "\t")
+	s = strings.ReplaceAll(s, "\\(", "(")
+	s = strings.ReplaceAll(s, "\\)", ")")
+	s = strings.ReplaceAll(s, "\\\\", "\\")
+	return strings.TrimSpace(s)
+}
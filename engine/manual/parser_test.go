@@ -0,0 +1,140 @@
+package manual
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestExtractPDFText(t *testing.T) {
+	// Minimal PDF-like content with BT/ET text blocks
+	data := []byte("BT (Hello World) ET")
+	got := extractPDFText(data)
+	if got != "Hello World" {
+		t.Fatalf("expected 'Hello World', got %q", got)
+	}
+}
+
+func TestExtractPDFText_Empty(t *testing.T) {
+	got := extractPDFText([]byte("no pdf content"))
+	if got != "" {
+		t.Fatalf("expected empty, got %q", got)
+	}
+}
+
+func TestCleanPDFText(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"hello\\nworld", "hello\nworld"},
+		{"paren\\(test\\)", "paren(test)"},
+		{"back\\\\slash", "back\\slash"},
+		{"  spaces  ", "spaces"},
+	}
+	for _, tt := range tests {
+		got := cleanPDFText(tt.input)
+		if got != tt.want {
+			t.Errorf("cleanPDFText(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+// testPDF builds a PDF with one Flate-compressed content stream per page.
+// Each page is a list of text lines drawn top-down in Helvetica.
+func testPDF(pages ...[]string) []byte {
+	var objs []string
+	kids := make([]string, len(pages))
+	for i, lines := range pages {
+		var content strings.Builder
+		content.WriteString("BT /F1 11 Tf 72 720 Td 14 TL")
+		for _, l := range lines {
+			fmt.Fprintf(&content, " (%s) Tj T*", l)
+		}
+		content.WriteString(" ET")
+		var z bytes.Buffer
+		w := zlib.NewWriter(&z)
+		w.Write([]byte(content.String()))
+		w.Close()
+
+		pageNum, streamNum := 4+2*i, 5+2*i
+		kids[i] = fmt.Sprintf("%d 0 R", pageNum)
+		objs = append(objs,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /Contents %d 0 R >>", streamNum),
+			fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", z.Len(), z.Bytes()))
+	}
+	objs = append([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /Resources << /Font << /F1 3 0 R >> >> >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+	}, objs...)
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objs))
+	for i, o := range objs {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, xref)
+	return b.Bytes()
+}
+
+func TestPDFText_CompressedPages(t *testing.T) {
+	data := testPDF(
+		[]string{"2019 Honda Civic Owner's Manual"},
+		[]string{"ENGINE", "Check the oil level monthly."},
+		[]string{"Use 0W-20 oil.", "BRAKES"},
+		[]string{"Inspect the brake pads."},
+	)
+	pc, err := ParsePDF(data)
+	if err != nil {
+		t.Fatalf("ParsePDF: %v", err)
+	}
+	text := pc.Text
+	if strings.Count(text, "\f") != 3 || !strings.Contains(text, "Check the oil level monthly.") {
+		t.Fatalf("unexpected text %q", text)
+	}
+
+	posts, err := PostsFromPDF(data, "civic.pdf", "", nil)
+	if err != nil {
+		t.Fatalf("PostsFromPDF: %v", err)
+	}
+	if len(posts) != 3 || posts[0].Metadata.VehicleInfo == nil || posts[0].Metadata.VehicleInfo.Year != 2019 {
+		t.Fatalf("unexpected posts %+v", posts)
+	}
+}
+
+func TestParseSections_PageRanges(t *testing.T) {
+	text := strings.Join([]string{
+		"Read this first.",
+		"ENGINE",
+		"Check the oil.",
+		"\f",
+		"Use 0W-20 oil.",
+		"\f",
+		"BRAKES",
+		"Inspect the pads.",
+	}, "\n")
+	sections := ParseSections(text)
+	want := map[string]string{"Untitled Section": "1", "Engine": "1-2", "Brakes": "3"}
+	if len(sections) != len(want) {
+		t.Fatalf("expected %d sections, got %+v", len(want), sections)
+	}
+	for _, s := range sections {
+		if s.PageRange != want[s.Title] {
+			t.Errorf("%s: page range %q, want %q", s.Title, s.PageRange, want[s.Title])
+		}
+	}
+
+	labelled := "ENGINE\nCheck the oil.\nPage 12\nUse 0W-20 oil.\nPage 13"
+	if got := ParseSections(labelled); len(got) != 1 || got[0].PageRange != "12-13" || strings.Contains(got[0].Content, "Page") {
+		t.Fatalf("unexpected labelled sections %+v", got)
+	}
+}
@@ -0,0 +1,110 @@
+// Package manual turns vehicle manual PDFs into text, tables, sections and
+// ScrapedPosts. The manuals scraper and the ingest API both use it.
+package manual
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/WessleyAI/wessley-mvp/engine/scraper"
+)
+
+// PostsFromPDF extracts the text of an uploaded PDF manual and returns one
+// ScrapedPost per detected section, or a single post when no sections are
+// found. vi overrides the vehicle tagged from filename and content.
+func PostsFromPDF(data []byte, filename, url string, vi *scraper.VehicleInfo) ([]scraper.ScrapedPost, error) {
+	pc, err := ParsePDF(data)
+	if err != nil {
+		return nil, err
+	}
+	content := strings.TrimSpace(pc.Text)
+	if content == "" {
+		return nil, fmt.Errorf("no text extracted")
+	}
+	if posts := SectionPosts(content, filename, url, vi); len(posts) > 0 {
+		return posts, nil
+	}
+	vi = tagVehicle(filename, content, vi)
+	return []scraper.ScrapedPost{{
+		Source:    "manual",
+		SourceID:  filename,
+		Title:     strings.TrimSuffix(filename, filepath.Ext(filename)),
+		Content:   content,
+		URL:       url,
+		ScrapedAt: time.Now(),
+		Metadata: scraper.Metadata{
+			Vehicle:     vehicleString(vi),
+			VehicleInfo: vi,
+			Keywords:    []string{"manual", "owner's manual"},
+		},
+	}}, nil
+}
+
+// SectionPosts splits manual text into sections and returns one ScrapedPost
+// per section. vi overrides the vehicle tagged from filename and content.
+func SectionPosts(content, filename, url string, vi *scraper.VehicleInfo) []scraper.ScrapedPost {
+	sections := ParseSections(content)
+	if len(sections) == 0 {
+		return nil
+	}
+	vi = tagVehicle(filename, content, vi)
+	vehicle := vehicleString(vi)
+
+	now := time.Now()
+	baseName := strings.TrimSuffix(filename, filepath.Ext(filename))
+	var posts []scraper.ScrapedPost
+
+	for i, sec := range sections {
+		keywords := []string{"manual", "owner's manual"}
+		if sec.System != "" {
+			keywords = append(keywords, strings.ToLower(sec.System))
+		}
+		if sec.Subsystem != "" {
+			keywords = append(keywords, strings.ToLower(sec.Subsystem))
+		}
+
+		meta := scraper.Metadata{
+			Vehicle:     vehicle,
+			VehicleInfo: vi,
+			Keywords:    keywords,
+		}
+		if sec.System != "" {
+			meta.Section = sec.System
+			if sec.Subsystem != "" {
+				meta.Section = sec.System + "/" + sec.Subsystem
+			}
+		}
+
+		posts = append(posts, scraper.ScrapedPost{
+			Source:    "manual",
+			SourceID:  fmt.Sprintf("%s-sec-%d", filename, i),
+			Title:     fmt.Sprintf("%s - %s", baseName, sec.Title),
+			Content:   sec.Content,
+			URL:       url,
+			ScrapedAt: now,
+			Metadata:  meta,
+		})
+	}
+
+	return posts
+}
+
+// tagVehicle returns vi, or the vehicle tagged from filename and content when vi is nil.
+func tagVehicle(filename, content string, vi *scraper.VehicleInfo) *scraper.VehicleInfo {
+	if vi != nil {
+		return vi
+	}
+	if mk, mdl, year := TagVehicleInfo(filename, content); mk != "" {
+		return &scraper.VehicleInfo{Make: mk, Model: mdl, Year: year}
+	}
+	return nil
+}
+
+func vehicleString(vi *scraper.VehicleInfo) string {
+	if vi == nil || vi.Make == "" || vi.Year == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d-%s-%s", vi.Year, vi.Make, vi.Model)
+}
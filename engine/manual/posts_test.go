@@ -0,0 +1,31 @@
+package manual
+
+import (
+	"testing"
+
+	"github.com/WessleyAI/wessley-mvp/engine/scraper"
+)
+
+func TestPostsFromPDF(t *testing.T) {
+	content := []byte("%PDF-1.4\nBT (2020 Toyota Camry Manual Content) ET\n%%EOF")
+	posts, err := PostsFromPDF(content, "2020_Toyota_Camry.pdf", "upload://2020_Toyota_Camry.pdf", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(posts) != 1 || posts[0].Source != "manual" || posts[0].Metadata.VehicleInfo == nil {
+		t.Fatalf("unexpected posts %+v", posts)
+	}
+	if posts[0].Metadata.Vehicle != "2020-Toyota-Camry" {
+		t.Fatalf("expected tagged vehicle, got %q", posts[0].Metadata.Vehicle)
+	}
+
+	override := &scraper.VehicleInfo{Make: "Honda", Model: "Civic", Year: 2019}
+	posts, _ = PostsFromPDF(content, "2020_Toyota_Camry.pdf", "", override)
+	if posts[0].Metadata.VehicleInfo.Make != "Honda" || posts[0].Metadata.Vehicle != "2019-Honda-Civic" {
+		t.Fatalf("vehicle override ignored: %+v", posts[0].Metadata)
+	}
+
+	if _, err := PostsFromPDF([]byte("%PDF-1.4\n%%EOF"), "empty.pdf", "", nil); err == nil {
+		t.Fatal("expected error for PDF without text")
+	}
+}
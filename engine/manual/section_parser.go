@@ -1,7 +1,9 @@
-package manuals
+package manual
 
 import (
+	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/WessleyAI/wessley-mvp/engine/graph"
@@ -33,7 +35,12 @@ var knownSectionHeaders = []string{
 // pageBreakPattern detects page breaks in extracted PDF text.
 var pageBreakPattern = regexp.MustCompile(`(?m)\f|(?:^-{3,}$)|(?:Page\s+\d+)`)
 
-// ParseSections splits manual text into classified sections.
+// pageLabelPattern reads a printed "Page N" label.
+var pageLabelPattern = regexp.MustCompile(`Page\s+(\d+)`)
+
+// ParseSections splits manual text into classified sections. Text from
+// ExtractTextFromPDF separates pages with form feeds, which give each
+// section its physical PageRange; otherwise printed "Page N" labels are used.
 func ParseSections(text string) []graph.ManualSection {
 	if text == "" {
 		return nil
@@ -43,7 +50,23 @@ func ParseSections(text string) []graph.ManualSection {
 	var sections []graph.ManualSection
 	var currentTitle string
 	var currentLines []string
-	currentPage := ""
+
+	// page is the current physical page, 0 when the text has no form feeds.
+	page := 0
+	paged := strings.Contains(text, "\f")
+	if paged {
+		page = 1
+	}
+	firstPage, lastPage := 0, 0
+	markPage := func(p int) {
+		if p == 0 {
+			return
+		}
+		if firstPage == 0 {
+			firstPage = p
+		}
+		lastPage = p
+	}
 
 	flush := func() {
 		if currentTitle == "" && len(currentLines) == 0 {
@@ -62,7 +85,7 @@ func ParseSections(text string) []graph.ManualSection {
 		sections = append(sections, graph.ManualSection{
 			Title:      title,
 			Content:    content,
-			PageRange:  currentPage,
+			PageRange:  pageRange(firstPage, lastPage),
 			System:     sys,
 			Subsystem:  sub,
 			Components: components,
@@ -70,16 +93,24 @@ func ParseSections(text string) []graph.ManualSection {
 	}
 
 	for _, line := range lines {
+		if n := strings.Count(line, "\f"); n > 0 {
+			page += n
+			line = strings.ReplaceAll(line, "\f", "")
+		}
 		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			if len(currentLines) > 0 {
+				currentLines = append(currentLines, line)
+			}
+			continue
+		}
 
-		// Check for page breaks — update page tracking.
+		// Page markers are not content; printed labels only number pages
+		// when the text carries no form feeds.
 		if pageBreakPattern.MatchString(trimmed) {
-			if m := regexp.MustCompile(`Page\s+(\d+)`).FindStringSubmatch(trimmed); len(m) > 1 {
-				if currentPage == "" {
-					currentPage = m[1]
-				} else {
-					currentPage = strings.Split(currentPage, "-")[0] + "-" + m[1]
-				}
+			if m := pageLabelPattern.FindStringSubmatch(trimmed); len(m) > 1 && !paged {
+				n, _ := strconv.Atoi(m[1])
+				markPage(n)
 			}
 			continue
 		}
@@ -89,10 +120,12 @@ func ParseSections(text string) []graph.ManualSection {
 			flush()
 			currentTitle = cleanHeaderTitle(trimmed)
 			currentLines = nil
-			currentPage = ""
+			firstPage, lastPage = 0, 0
+			markPage(page)
 			continue
 		}
 
+		markPage(page)
 		currentLines = append(currentLines, line)
 	}
 
@@ -100,11 +133,17 @@ func ParseSections(text string) []graph.ManualSection {
 
 	// If no sections detected, return whole text as single section.
 	if len(sections) == 0 && text != "" {
-		sys, sub := graph.ClassifySection("", text)
-		components := ExtractComponents(text)
+		content := strings.TrimSpace(strings.ReplaceAll(text, "\f", ""))
+		sys, sub := graph.ClassifySection("", content)
+		components := ExtractComponents(content)
+		whole := ""
+		if paged {
+			whole = pageRange(1, page)
+		}
 		sections = []graph.ManualSection{{
 			Title:      "Full Document",
-			Content:    text,
+			Content:    content,
+			PageRange:  whole,
 			System:     sys,
 			Subsystem:  sub,
 			Components: components,
@@ -114,6 +153,17 @@ func ParseSections(text string) []graph.ManualSection {
 	return sections
 }
 
+// pageRange formats a section's pages as "5" or "5-7", or "" when unknown.
+func pageRange(first, last int) string {
+	switch {
+	case first == 0:
+		return ""
+	case last <= first:
+		return strconv.Itoa(first)
+	}
+	return fmt.Sprintf("%d-%d", first, last)
+}
+
 // detectSectionHeader checks if a line looks like a section header.
 func detectSectionHeader(line string) bool {
 	if line == "" {
@@ -0,0 +1,193 @@
+package manual
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/WessleyAI/wessley-mvp/pkg/pdf"
+)
+
+// Table is a grid of cells detected on one page of a manual.
+type Table struct {
+	Caption string     `json:"caption,omitempty"` // nearest line above the table
+	Headers []string   `json:"headers"`
+	Rows    [][]string `json:"rows"`
+	Page    int        `json:"page"`
+}
+
+// Layout thresholds, in ems of the surrounding text.
+const (
+	cellGap     = 1.0 // horizontal gap that separates two cells on a line
+	rowGap      = 2.5 // vertical gap that ends a table
+	wrapGap     = 1.3 // vertical gap within which a lone cell continues the row above
+	maxCellText = 40  // average cell length above which aligned lines are prose columns
+)
+
+// cell is a run of text on one line, bounded by wide horizontal gaps.
+type cell struct {
+	text   string
+	x0, x1 float64
+}
+
+// textLine is a set of cells sharing a baseline.
+type textLine struct {
+	y, size float64
+	cells   []cell
+}
+
+// DetectTables finds tables among a page's positioned text runs. Lines
+// with two or more cells separated by wide gaps form rows; consecutive rows
+// become a table whose columns are the gaps shared by all of them. A lone
+// cell just below a row is treated as a wrapped continuation of that row.
+// The first row is taken as the header and the last single-cell line above
+// it as the caption.
+func DetectTables(p pdf.Page) []Table {
+	var tables []Table
+	var block []textLine
+	var caption, blockCaption string
+	flush := func() {
+		if t, ok := buildTable(block); ok {
+			t.Caption = blockCaption
+			t.Page = p.Number
+			tables = append(tables, t)
+		}
+		block = nil
+	}
+
+	for _, l := range pageLines(p.Runs) {
+		var gap float64
+		if n := len(block); n > 0 {
+			gap = (block[n-1].y - l.y) / math.Max(l.size, 1)
+		}
+		switch {
+		case len(l.cells) >= 2:
+			if len(block) > 0 && gap > rowGap {
+				flush()
+			}
+			if len(block) == 0 {
+				blockCaption = caption
+			}
+			block = append(block, l)
+		case len(block) > 0 && gap <= wrapGap && mergeWrapped(&block[len(block)-1], l.cells[0]):
+			block[len(block)-1].y = l.y
+		default:
+			flush()
+			caption = l.cells[0].text
+		}
+	}
+	flush()
+	return tables
+}
+
+// pageLines groups runs into lines, top to bottom, and each line's runs
+// into cells, left to right.
+func pageLines(runs []pdf.TextRun) []textLine {
+	sorted := append([]pdf.TextRun(nil), runs...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Y > sorted[j].Y })
+
+	var lines [][]pdf.TextRun
+	for _, r := range sorted {
+		if n := len(lines); n > 0 {
+			first := lines[n-1][0]
+			if math.Abs(first.Y-r.Y) <= 0.4*math.Max(first.Size, r.Size) {
+				lines[n-1] = append(lines[n-1], r)
+				continue
+			}
+		}
+		lines = append(lines, []pdf.TextRun{r})
+	}
+
+	out := make([]textLine, 0, len(lines))
+	for _, rs := range lines {
+		sort.SliceStable(rs, func(i, j int) bool { return rs[i].X < rs[j].X })
+		l := textLine{y: rs[0].Y, size: rs[0].Size}
+		for _, r := range rs {
+			text := strings.TrimSpace(r.Text)
+			if text == "" {
+				continue
+			}
+			size := math.Max(r.Size, 1)
+			if n := len(l.cells); n > 0 && r.X-l.cells[n-1].x1 < cellGap*size {
+				c := &l.cells[n-1]
+				if r.X-c.x1 > 0.15*size {
+					c.text += " "
+				}
+				c.text += text
+				c.x1 = math.Max(c.x1, r.X+r.W)
+				continue
+			}
+			l.cells = append(l.cells, cell{text: text, x0: r.X, x1: r.X + r.W})
+		}
+		if len(l.cells) > 0 {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// mergeWrapped appends c to the cell of row it sits under, reporting
+// whether one was found.
+func mergeWrapped(row *textLine, c cell) bool {
+	best, overlap := -1, 0.0
+	for i, rc := range row.cells {
+		if o := math.Min(rc.x1, c.x1) - math.Max(rc.x0, c.x0); o > overlap {
+			best, overlap = i, o
+		}
+	}
+	if best < 0 {
+		return false
+	}
+	row.cells[best].text += " " + c.text
+	row.cells[best].x1 = math.Max(row.cells[best].x1, c.x1)
+	return true
+}
+
+// buildTable aligns the cells of block into columns.
+func buildTable(block []textLine) (Table, bool) {
+	if len(block) < 2 {
+		return Table{}, false
+	}
+
+	// Columns are the connected spans of all cell extents projected on x.
+	var spans [][2]float64
+	chars, cells := 0, 0
+	for _, l := range block {
+		for _, c := range l.cells {
+			spans = append(spans, [2]float64{c.x0, c.x1})
+			chars += utf8.RuneCountInString(c.text)
+			cells++
+		}
+	}
+	if chars/cells > maxCellText {
+		return Table{}, false
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	var cols [][2]float64
+	for _, s := range spans {
+		if n := len(cols); n > 0 && s[0] <= cols[n-1][1]+1 {
+			cols[n-1][1] = math.Max(cols[n-1][1], s[1])
+			continue
+		}
+		cols = append(cols, s)
+	}
+	if len(cols) < 2 {
+		return Table{}, false
+	}
+
+	var rows [][]string
+	for _, l := range block {
+		row := make([]string, len(cols))
+		for _, c := range l.cells {
+			for i, col := range cols {
+				if c.x0 >= col[0]-1 && c.x0 <= col[1] {
+					row[i] = strings.TrimSpace(row[i] + " " + c.text)
+					break
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+	return Table{Headers: rows[0], Rows: rows[1:]}, true
+}
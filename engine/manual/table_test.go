@@ -0,0 +1,57 @@
+package manual
+
+import (
+	"testing"
+
+	"github.com/WessleyAI/wessley-mvp/pkg/pdf"
+)
+
+// row lays out cells at the given x positions on baseline y, 10pt text
+// whose width is 5pt per character.
+func row(y float64, cells map[float64]string) []pdf.TextRun {
+	var runs []pdf.TextRun
+	for x, text := range cells {
+		runs = append(runs, pdf.TextRun{Text: text, X: x, Y: y, W: 5 * float64(len(text)), Size: 10})
+	}
+	return runs
+}
+
+func torquePage() pdf.Page {
+	var runs []pdf.TextRun
+	runs = append(runs, row(700, map[float64]string{72: "TORQUE SPECIFICATIONS"})...)
+	runs = append(runs, row(670, map[float64]string{72: "Item", 250: "N·m", 320: "lbf·ft"})...)
+	runs = append(runs, row(656, map[float64]string{72: "Wheel lug nuts", 250: "108", 320: "80"})...)
+	runs = append(runs, row(642, map[float64]string{72: "Oil drain plug", 250: "39", 320: "29"})...)
+	// A wrapped first-column cell continues the row above.
+	runs = append(runs, row(629, map[float64]string{72: "(aluminum pan)"})...)
+	runs = append(runs, row(600, map[float64]string{72: "Always use a torque wrench when tightening fasteners."})...)
+	return pdf.Page{Number: 7, Runs: runs}
+}
+
+func TestDetectTables(t *testing.T) {
+	tables := DetectTables(torquePage())
+	if len(tables) != 1 {
+		t.Fatalf("expected 1 table, got %+v", tables)
+	}
+	tb := tables[0]
+	if tb.Page != 7 || tb.Caption != "TORQUE SPECIFICATIONS" || len(tb.Headers) != 3 || tb.Headers[1] != "N·m" {
+		t.Fatalf("unexpected headers %+v", tb)
+	}
+	if len(tb.Rows) != 2 || tb.Rows[1][0] != "Oil drain plug (aluminum pan)" || tb.Rows[1][2] != "29" {
+		t.Fatalf("unexpected rows %+v", tb.Rows)
+	}
+}
+
+func TestDetectTables_IgnoresProseColumns(t *testing.T) {
+	var runs []pdf.TextRun
+	for i := 0; i < 4; i++ {
+		y := 700 - float64(i)*14
+		runs = append(runs, row(y, map[float64]string{
+			72:  "The quick brown fox jumps over the lazy dog again and",
+			340: "the two column layout keeps running beside it as well",
+		})...)
+	}
+	if tables := DetectTables(pdf.Page{Number: 1, Runs: runs}); len(tables) != 0 {
+		t.Fatalf("two-column prose detected as table: %+v", tables)
+	}
+}
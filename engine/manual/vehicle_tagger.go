@@ -1,4 +1,4 @@
-package manuals
+package manual
 
 import (
 	"regexp"
@@ -0,0 +1,74 @@
+package manual
+
+import "testing"
+
+func TestTagVehicleInfo_Filename(t *testing.T) {
+	make, model, year := TagVehicleInfo("2020_Toyota_Camry_Manual.pdf", "")
+	if make != "Toyota" {
+		t.Fatalf("expected Toyota, got %s", make)
+	}
+	if year != 2020 {
+		t.Fatalf("expected 2020, got %d", year)
+	}
+	if model != "Camry" {
+		t.Fatalf("expected Camry, got %s", model)
+	}
+}
+
+func TestTagVehicleInfo_Content(t *testing.T) {
+	make, _, year := TagVehicleInfo("manual.pdf", "This is the 2019 Honda Civic owner's manual")
+	if make != "Honda" {
+		t.Fatalf("expected Honda, got %s", make)
+	}
+	if year != 2019 {
+		t.Fatalf("expected 2019, got %d", year)
+	}
+}
+
+func TestTagVehicleInfo_NoMatch(t *testing.T) {
+	make, _, year := TagVehicleInfo("random.pdf", "no vehicle info here")
+	if make != "" {
+		t.Fatalf("expected empty make, got %s", make)
+	}
+	if year != 0 {
+		t.Fatalf("expected 0 year, got %d", year)
+	}
+}
+
+func TestTitleCase(t *testing.T) {
+	tests := []struct{ input, want string }{
+		{"CAMRY", "Camry"},
+		{"GRAND CHEROKEE", "Grand Cherokee"},
+		{"", ""},
+		{"f-150", "F-150"},
+	}
+	for _, tt := range tests {
+		got := titleCase(tt.input)
+		if got != tt.want {
+			t.Errorf("titleCase(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestExtractModel(t *testing.T) {
+	got := extractModel("TOYOTA CAMRY 2020", "TOYOTA")
+	if got != "Camry" {
+		t.Fatalf("expected Camry, got %q", got)
+	}
+}
+
+func TestExtractModel_NoMatch(t *testing.T) {
+	got := extractModel("SOMETHING ELSE", "TOYOTA")
+	if got != "" {
+		t.Fatalf("expected empty, got %q", got)
+	}
+}
+
+func TestMin(t *testing.T) {
+	if min(3, 5) != 3 {
+		t.Fatal("min(3,5) should be 3")
+	}
+	if min(5, 3) != 3 {
+		t.Fatal("min(5,3) should be 3")
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -37,6 +40,12 @@ type GraphEnricher interface {
 	FindRelatedComponents(ctx context.Context, keywords []string, vehicle string) ([]graph.Component, []graph.Edge, error)
 }
 
+// ProcedureFinder is optionally implemented by a GraphEnricher to look up a
+// stored step-by-step procedure, e.g. for "how do I replace the alternator".
+type ProcedureFinder interface {
+	FindProcedure(ctx context.Context, vehicle, component, operation string) (*graph.Procedure, error)
+}
+
 // Options configures the RAG pipeline behaviour.
 type Options struct {
 	TopK          int
@@ -46,6 +55,9 @@ type Options struct {
 	SystemPrompt  string
 	UseGraph      bool
 	SearchTimeout time.Duration
+	// QualityWeight blends the ingest-time quality score into ranking:
+	// similarity × (1 − w + w × quality). 0 ranks by similarity alone.
+	QualityWeight float32
 }
 
 // DefaultOptions returns sensible defaults.
@@ -58,6 +70,7 @@ func DefaultOptions() Options {
 		SystemPrompt:  defaultSystemPrompt,
 		UseGraph:      true,
 		SearchTimeout: 5 * time.Second,
+		QualityWeight: 0.3,
 	}
 }
 
@@ -86,6 +99,10 @@ type Answer struct {
 	Sources    []Source `json:"sources"`
 	TokensUsed int32    `json:"tokens_used"`
 	Model      string   `json:"model"`
+
+	// Procedure is set when the question asks how to do a repair and the
+	// graph has a step-by-step procedure for it.
+	Procedure *graph.Procedure `json:"procedure,omitempty"`
 }
 
 // Source represents a citation backing the answer.
@@ -95,6 +112,14 @@ type Source struct {
 	DocID   string  `json:"doc_id"`
 	Source  string  `json:"source"`
 	Score   float32 `json:"score"`
+	Quality float32 `json:"quality,omitempty"`
+
+	// Where a manual citation sits in its PDF; empty for other sources.
+	ManualID  string `json:"manual_id,omitempty"`
+	Section   string `json:"section,omitempty"`    // section title
+	PageRange string `json:"page_range,omitempty"` // e.g. "45-52"
+	Page      int    `json:"page,omitempty"`       // first page, for deep links
+	Link      string `json:"link,omitempty"`       // set by the API to open the cited page
 }
 
 // Query runs the full RAG pipeline for a user question.
@@ -116,20 +141,32 @@ func (s *Service) Query(ctx context.Context, question string, vehicle string) (*
 		filter["vehicle"] = vehicle
 	}
 
-	results, err := s.search.Search(searchCtx, embedResp.GetValues(), s.opts.TopK, filter)
+	// Fetch extra candidates when re-ranking so a high-quality chunk just
+	// outside the top K can still make it in.
+	searchK := s.opts.TopK
+	if s.opts.QualityWeight > 0 {
+		searchK *= qualityOverfetch
+	}
+	results, err := s.search.Search(searchCtx, embedResp.GetValues(), searchK, filter)
 	if err != nil {
 		return nil, fmt.Errorf("rag: semantic search: %w", err)
 	}
+	results = rankByQuality(results, s.opts.QualityWeight, s.opts.TopK)
 	s.logger.Info("rag semantic search done", "results", len(results))
 
 	// 3. Optionally enrich with graph context.
 	var graphContext string
+	var procedure *graph.Procedure
 	if s.opts.UseGraph && s.graph != nil {
 		graphContext = s.enrichWithGraph(ctx, question, vehicle)
+		procedure = s.findProcedure(ctx, question, vehicle)
 	}
 
 	// 4. Build prompt with retrieved context.
 	contextParts := buildContextParts(results, graphContext)
+	if procedure != nil {
+		contextParts = append(contextParts, procedureContext(procedure))
+	}
 
 	// 5. Call ChatService.
 	chatResp, err := s.chat.Chat(ctx, &mlpb.ChatRequest{
@@ -153,7 +190,13 @@ func (s *Service) Query(ctx context.Context, question string, vehicle string) (*
 			DocID:   r.DocID,
 			Source:  r.Source,
 			Score:   r.Score,
+			Quality: resultQuality(r),
+
+			ManualID:  r.Meta["manual_id"],
+			Section:   r.Meta["section_title"],
+			PageRange: r.Meta["page_range"],
 		}
+		sources[i].Page, _ = strconv.Atoi(r.Meta["page"])
 	}
 
 	return &Answer{
@@ -161,6 +204,7 @@ func (s *Service) Query(ctx context.Context, question string, vehicle string) (*
 		Sources:    sources,
 		TokensUsed: chatResp.GetTokensUsed(),
 		Model:      chatResp.GetModel(),
+		Procedure:  procedure,
 	}, nil
 }
 
@@ -195,11 +239,109 @@ func (s *Service) enrichWithGraph(ctx context.Context, question, vehicle string)
 	return b.String()
 }
 
+// howToPattern matches "how do I replace the alternator" and "how to bleed
+// brakes on a 2015 Civic", capturing the verb and what follows it.
+var howToPattern = regexp.MustCompile(`(?i)\bhow\s+(?:do|can|would|should|to)\s+(?:(?:i|you|we|one)\s+)?(\w+)\s+(.+)`)
+
+// procedureQuestion returns the operation and component a how-to question
+// asks about, or ok false if it is not one.
+func procedureQuestion(question string) (operation, component string, ok bool) {
+	m := howToPattern.FindStringSubmatch(question)
+	if m == nil {
+		return "", "", false
+	}
+	operation = graph.NormalizeOperation(m[1])
+	if operation == "" {
+		return "", "", false
+	}
+	_, component = graph.ParseProcedureTitle(m[1] + " " + strings.TrimRight(m[2], "?.! "))
+	if component == "" {
+		return "", "", false
+	}
+	return operation, component, true
+}
+
+// findProcedure looks up the procedure a how-to question asks for when the
+// graph supports it; failures are logged and skipped.
+func (s *Service) findProcedure(ctx context.Context, question, vehicle string) *graph.Procedure {
+	finder, ok := s.graph.(ProcedureFinder)
+	if !ok {
+		return nil
+	}
+	operation, component, ok := procedureQuestion(question)
+	if !ok {
+		return nil
+	}
+	p, err := finder.FindProcedure(ctx, vehicle, component, operation)
+	if err != nil {
+		s.logger.Warn("rag: procedure lookup failed, continuing without", "err", err)
+		return nil
+	}
+	return p
+}
+
+// procedureContext formats a procedure as a numbered list for the prompt.
+func procedureContext(p *graph.Procedure) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Step-by-step procedure: %s\n", p.Title)
+	if len(p.Tools) > 0 {
+		fmt.Fprintf(&b, "Tools: %s\n", strings.Join(p.Tools, ", "))
+	}
+	for _, w := range p.Warnings {
+		fmt.Fprintf(&b, "Warning: %s\n", w)
+	}
+	for _, st := range p.Steps {
+		text := strings.ReplaceAll(st.Text, "\n", " ")
+		if st.Title != "" {
+			text = st.Title + ": " + text
+		}
+		fmt.Fprintf(&b, "%d. %s\n", st.Order, text)
+	}
+	return b.String()
+}
+
+// qualityOverfetch multiplies TopK when results are re-ranked by quality.
+const qualityOverfetch = 2
+
+// unscoredQuality is assumed for points ingested before quality scoring.
+const unscoredQuality = 0.5
+
+// resultQuality returns the quality payload of r, or unscoredQuality.
+func resultQuality(r semantic.SearchResult) float32 {
+	q, err := strconv.ParseFloat(r.Meta["quality"], 32)
+	if err != nil {
+		return unscoredQuality
+	}
+	return float32(q)
+}
+
+// rankByQuality reorders results by similarity scaled with their quality
+// prior and keeps the best topK. With weight 0 it only truncates.
+func rankByQuality(results []semantic.SearchResult, weight float32, topK int) []semantic.SearchResult {
+	if weight > 0 {
+		rank := func(r semantic.SearchResult) float32 {
+			return r.Score * (1 - weight + weight*resultQuality(r))
+		}
+		sort.SliceStable(results, func(i, j int) bool { return rank(results[i]) > rank(results[j]) })
+	}
+	if topK > 0 && len(results) > topK {
+		results = results[:topK]
+	}
+	return results
+}
+
 // buildContextParts formats search results and graph context into context strings.
 func buildContextParts(results []semantic.SearchResult, graphContext string) []string {
 	parts := make([]string, 0, len(results)+1)
 	for _, r := range results {
-		part := fmt.Sprintf("[%s] (source: %s, score: %.3f)\n%s", r.ID, r.Source, r.Score, r.Content)
+		var where string
+		if title := r.Meta["section_title"]; title != "" {
+			where += ", section: " + title
+		}
+		if pages := r.Meta["page_range"]; pages != "" {
+			where += ", pages: " + pages
+		}
+		part := fmt.Sprintf("[%s] (source: %s%s, score: %.3f)\n%s", r.ID, r.Source, where, r.Score, r.Content)
 		parts = append(parts, part)
 	}
 	if graphContext != "" {
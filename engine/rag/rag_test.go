@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 	"testing"
 
 	"github.com/WessleyAI/wessley-mvp/engine/graph"
@@ -248,3 +249,93 @@ func TestBuildContextParts(t *testing.T) {
 		t.Errorf("expected 1 part without graph, got %d", len(parts))
 	}
 }
+
+func TestQuery_ManualCitation(t *testing.T) {
+	embed := &mockEmbedClient{resp: &mlpb.EmbedResponse{Values: []float32{0.1}}}
+	chat := &mockChatClient{resp: &mlpb.ChatResponse{Reply: "Check fuse F12 [m1]."}}
+	search := &mockSearcher{results: []semantic.SearchResult{{
+		ID: "m1", Content: "F12 20A power point", DocID: "manual:owner_01234567-sec-3", Source: "manual", Score: 0.9,
+		Meta: map[string]string{"manual_id": "0123456789abcdef", "section_title": "Fuses", "page_range": "241-245", "page": "241"},
+	}}}
+	svc := &Service{embed: embed, chat: chat, search: search, opts: DefaultOptions(), logger: slog.Default()}
+
+	ans, err := svc.Query(context.Background(), "which fuse is the power point", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := ans.Sources[0]
+	if src.ManualID != "0123456789abcdef" || src.Section != "Fuses" || src.PageRange != "241-245" || src.Page != 241 {
+		t.Errorf("citation not carried to the source: %+v", src)
+	}
+	if ctx := chat.lastReq.GetContext()[0]; !strings.Contains(ctx, "section: Fuses, pages: 241-245") {
+		t.Errorf("expected the pages in the prompt context, got %q", ctx)
+	}
+}
+
+type mockProcedureEnricher struct {
+	mockGraphEnricher
+	procedure              *graph.Procedure
+	vehicle, component, op string
+}
+
+func (m *mockProcedureEnricher) FindProcedure(_ context.Context, vehicle, component, operation string) (*graph.Procedure, error) {
+	m.vehicle, m.component, m.op = vehicle, component, operation
+	return m.procedure, nil
+}
+
+func TestProcedureQuestion(t *testing.T) {
+	tests := []struct {
+		q, op, comp string
+		ok          bool
+	}{
+		{"How do I replace the alternator on my 2018 F-150?", "replace", "alternator", true},
+		{"how to bleed brakes", "bleed", "brakes", true},
+		{"How can I change my cabin air filter?", "replace", "cabin air filter", true},
+		{"How do I know if my alternator is bad?", "", "", false},
+		{"why is my battery dead", "", "", false},
+	}
+	for _, tt := range tests {
+		op, comp, ok := procedureQuestion(tt.q)
+		if op != tt.op || comp != tt.comp || ok != tt.ok {
+			t.Errorf("procedureQuestion(%q) = %q, %q, %v", tt.q, op, comp, ok)
+		}
+	}
+}
+
+func TestQuery_Procedure(t *testing.T) {
+	embed := &mockEmbedClient{resp: &mlpb.EmbedResponse{Values: []float32{0.1}}}
+	chat := &mockChatClient{resp: &mlpb.ChatResponse{Reply: "Follow the steps."}}
+	proc := &graph.Procedure{
+		Title:    "Alternator Replacement",
+		Tools:    []string{"13 mm socket"},
+		Warnings: []string{"Disconnect the battery."},
+		Steps: []graph.ProcedureStep{
+			{Order: 1, Title: "Battery", Text: "Disconnect the negative cable."},
+			{Order: 2, Text: "Release the belt tensioner."},
+		},
+	}
+	ge := &mockProcedureEnricher{procedure: proc}
+	svc := &Service{embed: embed, chat: chat, search: &mockSearcher{}, graph: ge, opts: DefaultOptions(), logger: slog.Default()}
+
+	ans, err := svc.Query(context.Background(), "How do I replace the alternator?", "ford-f-150-2018")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ans.Procedure != proc {
+		t.Fatalf("expected the procedure in the answer, got %+v", ans.Procedure)
+	}
+	if ge.vehicle != "ford-f-150-2018" || ge.component != "alternator" || ge.op != "replace" {
+		t.Errorf("unexpected lookup %q %q %q", ge.vehicle, ge.component, ge.op)
+	}
+	parts := chat.lastReq.GetContext()
+	last := parts[len(parts)-1]
+	if !strings.Contains(last, "1. Battery: Disconnect the negative cable.") || !strings.Contains(last, "Tools: 13 mm socket") {
+		t.Errorf("expected the procedure in the prompt context, got %q", last)
+	}
+
+	// Other questions don't look one up.
+	ge.procedure, ge.op = nil, ""
+	if ans, _ := svc.Query(context.Background(), "why is my battery dead", ""); ans.Procedure != nil || ge.op != "" {
+		t.Errorf("unexpected procedure lookup for a non-how-to question")
+	}
+}
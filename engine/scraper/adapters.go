@@ -0,0 +1,168 @@
+package scraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func init() {
+	RegisterSource(SourceAdapter{
+		Name:     "reddit",
+		Prefixes: []string{"reddit:"},
+		Decode:   decodeReddit,
+		Chunking: ChunkPolicy{Threads: true},
+	})
+	RegisterSource(SourceAdapter{
+		Name:     "youtube",
+		Prefixes: []string{"youtube:"},
+	})
+	RegisterSource(SourceAdapter{
+		Name:     "forum",
+		Prefixes: []string{"forum:"},
+		Chunking: ChunkPolicy{Threads: true},
+	})
+	RegisterSource(SourceAdapter{
+		Name:   "nhtsa",
+		Decode: decodeNHTSA,
+		Enrich: enrichComponents,
+	})
+	RegisterSource(SourceAdapter{
+		Name:   "ifixit",
+		Decode: decodeIFixit,
+		Enrich: enrichComponents,
+	})
+	RegisterSource(SourceAdapter{
+		Name:     "manual",
+		Sections: true,
+	})
+}
+
+// enrichComponents uses the source's component string (NHTSA, iFixit),
+// falling back to the first sentence.
+func enrichComponents(doc EnrichDoc) string {
+	if c := doc.Metadata["components"]; c != "" {
+		return c
+	}
+	if len(doc.Sentences) > 0 {
+		return doc.Sentences[0]
+	}
+	return ""
+}
+
+// redditPost mirrors reddit.Post as written by cmd/scraper-reddit.
+type redditPost struct {
+	ID          string          `json:"id"`
+	Subreddit   string          `json:"subreddit"`
+	Title       string          `json:"title"`
+	Author      string          `json:"author"`
+	SelfText    string          `json:"self_text"`
+	URL         string          `json:"url"`
+	Permalink   string          `json:"permalink"`
+	Score       int             `json:"score"`
+	NumComments int             `json:"num_comments"`
+	Comments    []redditComment `json:"comments"`
+	CreatedUTC  time.Time       `json:"created_utc"`
+	ScrapedAt   time.Time       `json:"scraped_at"`
+}
+
+func decodeReddit(raw json.RawMessage) (ScrapedPost, bool) {
+	var r redditPost
+	if err := json.Unmarshal(raw, &r); err != nil || r.Subreddit == "" {
+		return ScrapedPost{}, false
+	}
+	return r.toScrapedPost(), true
+}
+
+func (r redditPost) toScrapedPost() ScrapedPost {
+	content := r.SelfText
+	if content == "" {
+		content = r.Title
+	}
+	url := r.Permalink
+	if !strings.HasPrefix(url, "http") {
+		url = "https://reddit.com" + url
+	}
+	answers, fixes := buildThread(r)
+	return ScrapedPost{
+		Source:      "reddit:" + r.Subreddit,
+		SourceID:    r.ID,
+		Title:       r.Title,
+		Content:     content,
+		Author:      r.Author,
+		URL:         url,
+		PublishedAt: r.CreatedUTC,
+		ScrapedAt:   r.ScrapedAt,
+		Metadata: Metadata{
+			Fixes:    fixes,
+			Score:    r.Score,
+			Comments: r.NumComments,
+			Answers:  answers,
+		},
+	}
+}
+
+// nhtsaComplaint is a raw NHTSA complaint record.
+type nhtsaComplaint struct {
+	ODINumber string `json:"odi_number"`
+	Make      string `json:"make"`
+	Model     string `json:"model"`
+	Year      int    `json:"year"`
+	Summary   string `json:"summary"`
+	Complaint string `json:"complaint"`
+	DateAdded string `json:"date_added"`
+}
+
+func decodeNHTSA(raw json.RawMessage) (ScrapedPost, bool) {
+	var r nhtsaComplaint
+	if err := json.Unmarshal(raw, &r); err != nil || r.ODINumber == "" {
+		return ScrapedPost{}, false
+	}
+	vehicle := fmt.Sprintf("%d %s %s", r.Year, r.Make, r.Model)
+	content := r.Complaint
+	if content == "" {
+		content = r.Summary
+	}
+	var vi *VehicleInfo
+	if r.Make != "" && r.Model != "" && r.Year > 0 {
+		vi = &VehicleInfo{
+			Make:  r.Make,
+			Model: r.Model,
+			Year:  r.Year,
+		}
+	}
+	return ScrapedPost{
+		Source:   "nhtsa",
+		SourceID: r.ODINumber,
+		Title:    vehicle + " - NHTSA Complaint",
+		Content:  content,
+		URL:      "https://www.nhtsa.gov/",
+		Metadata: Metadata{
+			Vehicle:     vehicle,
+			VehicleInfo: vi,
+			Components:  r.Summary,
+		},
+	}, true
+}
+
+// ifixitGuide is a raw iFixit guide record.
+type ifixitGuide struct {
+	GuideID   int    `json:"guide_id"`
+	GuideName string `json:"guide_name"`
+	Category  string `json:"category"`
+	Summary   string `json:"summary"`
+}
+
+func decodeIFixit(raw json.RawMessage) (ScrapedPost, bool) {
+	var r ifixitGuide
+	if err := json.Unmarshal(raw, &r); err != nil || (r.GuideID == 0 && r.GuideName == "") {
+		return ScrapedPost{}, false
+	}
+	return ScrapedPost{
+		Source:   "ifixit",
+		SourceID: fmt.Sprintf("%d", r.GuideID),
+		Title:    r.GuideName,
+		Content:  r.Summary,
+	}, true
+}
@@ -0,0 +1,72 @@
+package scraper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Cursors holds each source's resume cursor, persisted as a JSON object in
+// a file so a restarted scraper carries on where it stopped.
+type Cursors struct {
+	path string // "" keeps cursors in memory only
+
+	mu      sync.Mutex
+	cursors map[string]string
+}
+
+// OpenCursors loads the cursors saved at path; a missing file starts empty.
+// An empty path keeps cursors in memory.
+func OpenCursors(path string) (*Cursors, error) {
+	c := &Cursors{path: path, cursors: map[string]string{}}
+	if path == "" {
+		return c, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cursors: %w", err)
+	}
+	if err := json.Unmarshal(data, &c.cursors); err != nil {
+		return nil, fmt.Errorf("cursors: %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Get returns source's cursor, or "" if it has none.
+func (c *Cursors) Get(source string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cursors[source]
+}
+
+// Set records source's cursor and saves the file.
+func (c *Cursors) Set(source, cursor string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cursors[source] == cursor {
+		return nil
+	}
+	c.cursors[source] = cursor
+	if c.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(c.cursors, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write then rename so a crash never leaves a truncated file.
+	tmp := filepath.Join(filepath.Dir(c.path), "."+filepath.Base(c.path)+".tmp")
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("cursors: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("cursors: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package scraper
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxThreadAnswers is the number of reply chains kept per post.
+	maxThreadAnswers = 8
+	// minAnswerScore drops low-voted top-level comments (unless they carry the fix).
+	minAnswerScore = 1
+	// maxChainDepth limits how far down a reply chain we follow.
+	maxChainDepth = 4
+	// maxFixLen caps the length of a resolution recorded in Metadata.Fixes.
+	maxFixLen = 300
+)
+
+// resolvedPattern matches the ways askers confirm a fix ("solved", "update: fixed it", ...).
+// An update only counts when it reports the fix, not when it is still looking
+// for one ("update: still need to find a fix").
+var resolvedPattern = regexp.MustCompile(`(?i)\b(?:solved|fixed it|that fixed|this fixed|that did it|that was it|problem (?:is )?(?:solved|fixed|resolved)|issue (?:is )?(?:solved|fixed|resolved))\b|\bupdate\b[:\s-]+.*\b(?:fixed|the fix was)\b`)
+
+// unresolvedPattern catches negated confirmations ("not solved", "still not fixed").
+var unresolvedPattern = regexp.MustCompile(`(?i)\b(?:not|never|still|hasn't|haven't|didn't|isn't|wasn't|unsolved)\b\s+(?:\w+\s+)?(?:solved|fixed|resolved|fix)\b|\bunsolved\b`)
+
+// IsResolution reports whether text confirms that a problem was fixed. The
+// forum scraper uses it too, to find the reply that solved a thread.
+func IsResolution(text string) bool {
+	return resolvedPattern.MatchString(text) && !unresolvedPattern.MatchString(text)
+}
+
+// redditComment mirrors reddit.Comment as written by cmd/scraper-reddit.
+type redditComment struct {
+	ID       string `json:"id"`
+	Author   string `json:"author"`
+	Body     string `json:"body"`
+	Score    int    `json:"score"`
+	ParentID string `json:"parent_id"`
+	Depth    int    `json:"depth"`
+}
+
+// thread is a post's comments indexed for chain reconstruction.
+type thread struct {
+	op       string
+	byID     map[string]redditComment
+	children map[string][]redditComment
+	solved   map[string]bool
+	topLevel []redditComment
+}
+
+// buildThread converts a Reddit post's comments into ranked answer chains and
+// the resolutions found in them. Chains are rebuilt from ParentID, so flat and
+// nested comment listings both work.
+func buildThread(r redditPost) ([]Answer, []string) {
+	t := newThread(r.Author, r.Comments)
+
+	var fixes []string
+	for _, line := range strings.Split(r.SelfText, "\n") {
+		if strings.Contains(strings.ToLower(line), "update") && IsResolution(line) {
+			fixes = append(fixes, Clip(line, maxFixLen))
+		}
+	}
+	for _, c := range t.topLevel {
+		fixes = append(fixes, t.solutionsUnder(c)...)
+	}
+
+	var answers []Answer
+	for _, c := range t.topLevel {
+		resolved := t.hasSolution(c)
+		if c.Score < minAnswerScore && !resolved {
+			continue
+		}
+		answers = append(answers, Answer{
+			Text:     t.chainText(c),
+			Score:    c.Score,
+			Resolved: resolved,
+		})
+	}
+
+	sort.SliceStable(answers, func(i, j int) bool {
+		if answers[i].Resolved != answers[j].Resolved {
+			return answers[i].Resolved
+		}
+		return answers[i].Score > answers[j].Score
+	})
+	if len(answers) > maxThreadAnswers {
+		answers = answers[:maxThreadAnswers]
+	}
+	return answers, fixes
+}
+
+func newThread(op string, comments []redditComment) *thread {
+	t := &thread{
+		op:       op,
+		byID:     make(map[string]redditComment),
+		children: make(map[string][]redditComment),
+		solved:   make(map[string]bool),
+	}
+	for _, c := range comments {
+		if !usableComment(c) {
+			continue
+		}
+		t.byID[c.ID] = c
+	}
+	for _, c := range comments {
+		if _, ok := t.byID[c.ID]; !ok {
+			continue
+		}
+		parent := stripFullname(c.ParentID)
+		if _, isComment := t.byID[parent]; isComment && parent != c.ID {
+			t.children[parent] = append(t.children[parent], c)
+		} else {
+			t.topLevel = append(t.topLevel, c)
+		}
+	}
+	for id := range t.children {
+		sort.SliceStable(t.children[id], func(i, j int) bool {
+			return t.children[id][i].Score > t.children[id][j].Score
+		})
+	}
+
+	// An asker confirming a fix marks the comment they replied to; a top-level
+	// confirmation by the asker is itself the fix (they explain what worked).
+	if op != "" {
+		for _, c := range t.byID {
+			if c.Author != op || !IsResolution(c.Body) {
+				continue
+			}
+			if parent := stripFullname(c.ParentID); t.byID[parent].ID != "" {
+				t.solved[parent] = true
+			} else {
+				t.solved[c.ID] = true
+			}
+		}
+	}
+	return t
+}
+
+// hasSolution reports whether c or any reply under it was confirmed as the fix.
+func (t *thread) hasSolution(c redditComment) bool {
+	if t.solved[c.ID] {
+		return true
+	}
+	for _, child := range t.children[c.ID] {
+		if t.hasSolution(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// solutionsUnder returns the bodies of confirmed fixes in c's subtree.
+func (t *thread) solutionsUnder(c redditComment) []string {
+	var out []string
+	if t.solved[c.ID] {
+		out = append(out, Clip(c.Body, maxFixLen))
+	}
+	for _, child := range t.children[c.ID] {
+		out = append(out, t.solutionsUnder(child)...)
+	}
+	return out
+}
+
+// chainText renders c and its best reply chain. At each level the chain
+// follows the reply leading to a confirmed fix, otherwise the top-scored one.
+func (t *thread) chainText(c redditComment) string {
+	var b strings.Builder
+	cur := c
+	for depth := 0; depth < maxChainDepth; depth++ {
+		if depth > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(strings.Repeat(">", depth))
+		if depth > 0 {
+			b.WriteString(" ")
+		}
+		if cur.Author != "" && cur.Author == t.op {
+			b.WriteString("OP: ")
+		}
+		b.WriteString(strings.TrimSpace(cur.Body))
+
+		kids := t.children[cur.ID]
+		if len(kids) == 0 {
+			break
+		}
+		next := kids[0]
+		for _, k := range kids {
+			if t.hasSolution(k) {
+				next = k
+				break
+			}
+		}
+		cur = next
+	}
+	return b.String()
+}
+
+// usableComment filters deleted, removed and bot comments.
+func usableComment(c redditComment) bool {
+	body := strings.TrimSpace(c.Body)
+	if c.ID == "" || body == "" || body == "[deleted]" || body == "[removed]" {
+		return false
+	}
+	return c.Author != "AutoModerator"
+}
+
+// stripFullname turns a Reddit fullname ("t1_abc", "t3_xyz") into its bare ID.
+func stripFullname(id string) string {
+	if len(id) > 3 && id[0] == 't' && id[2] == '_' {
+		return id[3:]
+	}
+	return id
+}
+
+// Clip trims s and shortens it to at most n bytes followed by "...",
+// cutting on a rune boundary.
+func Clip(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return fmt.Sprintf("%s...", strings.TrimSpace(s[:n]))
+}
@@ -0,0 +1,128 @@
+package scraper
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func redditThread() redditPost {
+	return redditPost{
+		ID:        "abc123",
+		Subreddit: "MechanicAdvice",
+		Title:     "2015 Civic no crank, clicks once",
+		Author:    "asker",
+		SelfText:  "Battery tests fine at 12.6V. Starter clicks once and nothing.",
+		Permalink: "https://www.reddit.com/r/MechanicAdvice/comments/abc123/",
+		Score:     57,
+		Comments: []redditComment{
+			{ID: "c1", Author: "mech1", Body: "Check the starter relay first.", Score: 40, ParentID: "t3_abc123"},
+			{ID: "c2", Author: "mech2", Body: "Clean the main ground strap to the block.", Score: 12, ParentID: "t3_abc123"},
+			{ID: "c3", Author: "asker", Body: "Update: that fixed it, the ground was corroded!", Score: 9, ParentID: "t1_c2", Depth: 1},
+			{ID: "c4", Author: "troll", Body: "buy a toyota", Score: -5, ParentID: "t3_abc123"},
+			{ID: "c5", Author: "mech3", Body: "[deleted]", Score: 3, ParentID: "t3_abc123"},
+			{ID: "c6", Author: "mech1", Body: "Relay is under the dash on the driver side.", Score: 15, ParentID: "t1_c1", Depth: 1},
+		},
+	}
+}
+
+func TestIsResolution(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"Solved! thanks everyone", true},
+		{"UPDATE: fixed it, was the ground strap", true},
+		{"that did it, runs great now", true},
+		{"still not fixed after replacing the relay", false},
+		{"hasn't solved anything", false},
+		{"what could cause a no crank?", false},
+		{"Update: the fix was a new crank sensor", true},
+		{"Update: still need to find a fix", false},
+		{"update - no fix yet, ordering a relay", false},
+		{"Update: haven't fixed it yet", false},
+	}
+	for _, tt := range tests {
+		if got := IsResolution(tt.text); got != tt.want {
+			t.Errorf("IsResolution(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestClip(t *testing.T) {
+	if got := Clip("  short  ", 10); got != "short" {
+		t.Errorf("Clip = %q", got)
+	}
+	// "é" is two bytes; a cut inside it must back off to the rune start.
+	got := Clip("caféine", 4)
+	if got != "caf..." || !utf8.ValidString(got) {
+		t.Errorf("Clip = %q, want %q", got, "caf...")
+	}
+}
+
+func TestBuildThread_ResolvedChainRanksFirst(t *testing.T) {
+	answers, fixes := buildThread(redditThread())
+
+	if len(answers) != 2 {
+		t.Fatalf("expected 2 answers (low score and deleted dropped), got %d", len(answers))
+	}
+	if !answers[0].Resolved || answers[0].Score != 12 {
+		t.Fatalf("expected resolved ground-strap chain first, got %+v", answers[0])
+	}
+	if !strings.Contains(answers[0].Text, "ground strap") || !strings.Contains(answers[0].Text, "> OP: Update") {
+		t.Fatalf("chain not reconstructed: %q", answers[0].Text)
+	}
+	if answers[1].Resolved || !strings.Contains(answers[1].Text, "> Relay is under the dash") {
+		t.Fatalf("expected unresolved relay chain with reply, got %+v", answers[1])
+	}
+
+	if len(fixes) != 1 || !strings.Contains(fixes[0], "ground strap") {
+		t.Fatalf("expected solving comment recorded as fix, got %v", fixes)
+	}
+}
+
+func TestBuildThread_SelfTextUpdate(t *testing.T) {
+	r := redditThread()
+	r.Comments = nil
+	r.SelfText += "\n\nEDIT/UPDATE: fixed, it was a blown 40A fuse."
+	answers, fixes := buildThread(r)
+	if len(answers) != 0 {
+		t.Fatalf("expected no answers, got %d", len(answers))
+	}
+	if len(fixes) != 1 || !strings.Contains(fixes[0], "40A fuse") {
+		t.Fatalf("expected update line as fix, got %v", fixes)
+	}
+}
+
+func TestBuildThread_TopLevelOPResolution(t *testing.T) {
+	r := redditThread()
+	r.Comments = []redditComment{
+		{ID: "c1", Author: "asker", Body: "Solved - replaced the neutral safety switch.", Score: 0, ParentID: "t3_abc123"},
+	}
+	answers, fixes := buildThread(r)
+	if len(answers) != 1 || !answers[0].Resolved {
+		t.Fatalf("expected OP resolution kept despite low score, got %+v", answers)
+	}
+	if len(fixes) != 1 {
+		t.Fatalf("expected 1 fix, got %v", fixes)
+	}
+}
+
+func TestRawPost_RedditToScrapedPost(t *testing.T) {
+	post := redditThread().toScrapedPost()
+	if post.Source != "reddit:MechanicAdvice" || post.SourceID != "abc123" {
+		t.Fatalf("unexpected identity %s/%s", post.Source, post.SourceID)
+	}
+	if post.URL != "https://www.reddit.com/r/MechanicAdvice/comments/abc123/" {
+		t.Fatalf("unexpected url %s", post.URL)
+	}
+	if post.Metadata.Score != 57 || len(post.Metadata.Answers) != 2 || len(post.Metadata.Fixes) != 1 {
+		t.Fatalf("thread metadata not carried: %+v", post.Metadata)
+	}
+}
+
+func TestStripFullname(t *testing.T) {
+	if stripFullname("t1_abc") != "abc" || stripFullname("t3_xyz") != "xyz" || stripFullname("plain") != "plain" {
+		t.Fatal("stripFullname failed")
+	}
+}
@@ -0,0 +1,212 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/WessleyAI/wessley-mvp/pkg/metrics"
+)
+
+// Source is a scraper the Runtime can schedule. Fetch returns the posts
+// published since cursor and the cursor to resume from on the next fetch;
+// an empty cursor means start from scratch. Sources that cannot resume
+// return "" and fetch everything each time. Posts returned with an error
+// are still written.
+type Source interface {
+	Name() string
+	Fetch(ctx context.Context, cursor string) ([]ScrapedPost, string, error)
+}
+
+type fetchAllSource struct {
+	name  string
+	fetch func(context.Context) ([]ScrapedPost, error)
+}
+
+// FetchAllSource adapts a scraper without cursors, such as one with a
+// FetchAll method, to a Source.
+func FetchAllSource(name string, fetch func(context.Context) ([]ScrapedPost, error)) Source {
+	return fetchAllSource{name: name, fetch: fetch}
+}
+
+func (s fetchAllSource) Name() string { return s.name }
+
+func (s fetchAllSource) Fetch(ctx context.Context, _ string) ([]ScrapedPost, string, error) {
+	posts, err := s.fetch(ctx)
+	return posts, "", err
+}
+
+// Job schedules one Source.
+type Job struct {
+	Source   Source
+	Interval time.Duration // between fetches; 0 fetches once
+	Rate     time.Duration // minimum spacing of the source's requests; 0 leaves them unpaced
+}
+
+// RuntimeConfig configures a Runtime.
+type RuntimeConfig struct {
+	Sinks   []Sink            // every fetched batch is written to each sink
+	Cursors *Cursors          // nil keeps cursors in memory
+	Metrics *metrics.Registry // nil disables metrics
+}
+
+// Runtime runs Sources on their schedules: it paces their requests,
+// persists their cursors, writes what they fetch to the sinks and records
+// the same metrics, labelled by source, for every scraper.
+type Runtime struct {
+	cfg  RuntimeConfig
+	jobs []Job
+}
+
+// NewRuntime creates a Runtime for jobs.
+func NewRuntime(cfg RuntimeConfig, jobs ...Job) *Runtime {
+	if cfg.Cursors == nil {
+		cfg.Cursors, _ = OpenCursors("")
+	}
+	return &Runtime{cfg: cfg, jobs: jobs}
+}
+
+// Run runs every job concurrently until the one-shot jobs are done and the
+// repeating ones are stopped by cancelling ctx. It returns the errors of the
+// last fetch of the one-shot jobs.
+func (r *Runtime) Run(ctx context.Context) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, job := range r.jobs {
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			if err := r.runJob(ctx, job); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(job)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func (r *Runtime) runJob(ctx context.Context, job Job) error {
+	limit := rate.Inf
+	if job.Rate > 0 {
+		limit = rate.Every(job.Rate)
+	}
+	ctx = WithRateLimiter(ctx, rate.NewLimiter(limit, 1))
+
+	err := r.RunOnce(ctx, job.Source)
+	if job.Interval <= 0 {
+		return err
+	}
+	if err != nil {
+		log.Printf("%s: %v", job.Source.Name(), err)
+	}
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.RunOnce(ctx, job.Source); err != nil {
+				log.Printf("%s: %v", job.Source.Name(), err)
+			}
+		}
+	}
+}
+
+// RunOnce fetches src once from its saved cursor and writes the posts to
+// the sinks. The cursor only advances after a clean fetch that every sink
+// took, so a failed write is fetched again next time.
+func (r *Runtime) RunOnce(ctx context.Context, src Source) error {
+	name := src.Name()
+	m := r.metricsFor(name)
+
+	start := time.Now()
+	posts, next, fetchErr := src.Fetch(ctx, r.cfg.Cursors.Get(name))
+	if fetchErr != nil {
+		m.errors.Inc()
+	} else {
+		m.duration.Since(start)
+		m.last.Set(time.Now().Unix())
+	}
+	m.docs.Add(int64(len(posts)))
+	log.Printf("%s: fetched %d posts", name, len(posts))
+
+	// Posts fetched before an error are kept; the cursor stays put so the
+	// next fetch retries the rest.
+	if len(posts) > 0 {
+		for _, sink := range r.cfg.Sinks {
+			if err := sink.Write(ctx, name, posts); err != nil {
+				m.errors.Inc()
+				return fmt.Errorf("write: %w", err)
+			}
+		}
+	}
+	if fetchErr != nil {
+		return fmt.Errorf("fetch: %w", fetchErr)
+	}
+	if err := r.cfg.Cursors.Set(name, next); err != nil {
+		m.errors.Inc()
+		return fmt.Errorf("save cursor: %w", err)
+	}
+	return nil
+}
+
+// Close closes the sinks.
+func (r *Runtime) Close() error {
+	var errs []error
+	for _, sink := range r.cfg.Sinks {
+		errs = append(errs, sink.Close())
+	}
+	return errors.Join(errs...)
+}
+
+type sourceMetrics struct {
+	docs, errors *metrics.Counter
+	duration     *metrics.Histogram
+	last         *metrics.Gauge
+}
+
+func (r *Runtime) metricsFor(source string) sourceMetrics {
+	reg := r.cfg.Metrics
+	if reg == nil {
+		reg = metrics.New() // discarded
+	}
+	label := func(name string) string { return metrics.WithLabels(name, "source", source) }
+	return sourceMetrics{
+		docs:     reg.Counter(label("wessley_scraper_docs_total"), "Docs scraped by source"),
+		errors:   reg.Counter(label("wessley_scraper_errors_total"), "Scraper errors by source"),
+		duration: reg.Histogram(label("wessley_scraper_fetch_duration_seconds"), "Fetch duration by source", nil),
+		last:     reg.Gauge(label("wessley_scraper_last_scrape_timestamp"), "Epoch of the last successful fetch by source"),
+	}
+}
+
+type rateLimiterKey struct{}
+
+// WithRateLimiter returns a context whose scrapers pace their requests
+// with l. The Runtime sets one per source.
+func WithRateLimiter(ctx context.Context, l *rate.Limiter) context.Context {
+	return context.WithValue(ctx, rateLimiterKey{}, l)
+}
+
+// RateLimiter returns the limiter a scraper should wait on before each
+// request: the Runtime's for the source, or, when the scraper runs on its
+// own, a new one allowing a request every fallback (unpaced if 0).
+func RateLimiter(ctx context.Context, fallback time.Duration) *rate.Limiter {
+	if l, ok := ctx.Value(rateLimiterKey{}).(*rate.Limiter); ok {
+		return l
+	}
+	if fallback <= 0 {
+		return rate.NewLimiter(rate.Inf, 1)
+	}
+	return rate.NewLimiter(rate.Every(fallback), 1)
+}
@@ -0,0 +1,296 @@
+package scraper
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+
+	"github.com/WessleyAI/wessley-mvp/pkg/metrics"
+)
+
+// pagedSource returns one post per fetch, numbered by its cursor.
+type pagedSource struct {
+	mu      sync.Mutex
+	cursors []string // cursor seen by each fetch
+	fail    bool
+}
+
+func (s *pagedSource) Name() string { return "forum" }
+
+func (s *pagedSource) fetches() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.cursors)
+}
+
+func (s *pagedSource) Fetch(ctx context.Context, cursor string) ([]ScrapedPost, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cursors = append(s.cursors, cursor)
+	post := ScrapedPost{Source: "forum:test", SourceID: "p" + cursor, Content: "text"}
+	if s.fail {
+		return []ScrapedPost{post}, "bad", errors.New("boom")
+	}
+	return []ScrapedPost{post}, cursor + "x", nil
+}
+
+type memorySink struct {
+	posts []ScrapedPost
+	err   error
+}
+
+func (s *memorySink) Write(_ context.Context, _ string, posts []ScrapedPost) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.posts = append(s.posts, posts...)
+	return nil
+}
+
+func (s *memorySink) Close() error { return nil }
+
+func TestRuntime_RunOnce(t *testing.T) {
+	ctx := context.Background()
+	src := &pagedSource{}
+	sink := &memorySink{}
+	reg := metrics.New()
+	rt := NewRuntime(RuntimeConfig{Sinks: []Sink{sink}, Metrics: reg})
+
+	for i := 0; i < 2; i++ {
+		if err := rt.RunOnce(ctx, src); err != nil {
+			t.Fatalf("RunOnce: %v", err)
+		}
+	}
+	if strings.Join(src.cursors, ",") != ",x" {
+		t.Errorf("cursor should advance between fetches, got %q", src.cursors)
+	}
+	if len(sink.posts) != 2 {
+		t.Errorf("expected 2 posts written, got %d", len(sink.posts))
+	}
+
+	sink.err = errors.New("disk full")
+	if err := rt.RunOnce(ctx, src); err == nil {
+		t.Fatal("expected write error")
+	}
+	sink.err = nil
+	src.fail = true
+	if err := rt.RunOnce(ctx, src); err == nil {
+		t.Fatal("expected fetch error")
+	}
+	if src.cursors[3] != "xx" {
+		t.Errorf("a failed write must not advance the cursor, got %q", src.cursors)
+	}
+	if len(sink.posts) != 3 {
+		t.Errorf("posts fetched before an error should be written, got %d", len(sink.posts))
+	}
+	if rt.cfg.Cursors.Get("forum") != "xx" {
+		t.Errorf("a failed fetch must not advance the cursor, got %q", rt.cfg.Cursors.Get("forum"))
+	}
+
+	out := reg.Render()
+	for _, want := range []string{
+		`wessley_scraper_docs_total{source="forum"} 4`,
+		`wessley_scraper_errors_total{source="forum"} 2`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRuntime_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	once, repeat := &pagedSource{}, &pagedSource{}
+	repeatSink := &memorySink{}
+	rt := NewRuntime(RuntimeConfig{Sinks: []Sink{repeatSink}},
+		Job{Source: once},
+		Job{Source: repeat, Interval: time.Millisecond},
+	)
+
+	done := make(chan error)
+	go func() { done <- rt.Run(ctx) }()
+	deadline := time.After(5 * time.Second)
+	for repeat.fetches() < 3 {
+		select {
+		case <-deadline:
+			t.Fatal("repeating job did not run")
+		case <-time.After(time.Millisecond):
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run: %v", err)
+	}
+	if once.fetches() != 1 {
+		t.Errorf("one-shot job should fetch once, fetched %d times", once.fetches())
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	ctx := context.Background()
+	if l := RateLimiter(ctx, 0); !l.Allow() || !l.Allow() {
+		t.Error("a zero fallback should not pace")
+	}
+	if l := RateLimiter(ctx, time.Hour); !l.Allow() || l.Allow() {
+		t.Error("the fallback should allow one request per interval")
+	}
+
+	l := RateLimiter(ctx, time.Hour)
+	if RateLimiter(WithRateLimiter(ctx, l), 0) != l {
+		t.Error("the runtime's limiter should win over the fallback")
+	}
+}
+
+func TestDirSink(t *testing.T) {
+	dir := t.TempDir()
+	sink, err := NewDirSink(dir, DirSinkOpts{MaxBytes: 400})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+	sink.now = func() time.Time { now = now.Add(time.Second); return now }
+
+	post := ScrapedPost{Source: "reddit:cars", SourceID: "1", Content: "x"}
+	ctx := context.Background()
+	if err := sink.Write(ctx, "reddit", []ScrapedPost{post}); err != nil {
+		t.Fatal(err)
+	}
+	if files := visible(t, dir); len(files) != 0 {
+		t.Fatalf("a file being written should stay hidden, got %v", files)
+	}
+	if err := sink.Write(ctx, "reddit", []ScrapedPost{post, post}); err != nil {
+		t.Fatal(err)
+	}
+	files := visible(t, dir)
+	if len(files) != 1 || files[0] != "reddit-1700000001000000000.json" {
+		t.Fatalf("expected one rotated file, got %v", files)
+	}
+	if n := countLines(t, filepath.Join(dir, files[0])); n != 3 {
+		t.Errorf("expected 3 JSON lines, got %d", n)
+	}
+
+	if err := sink.Write(ctx, "nhtsa", []ScrapedPost{post}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if files := visible(t, dir); len(files) != 2 {
+		t.Errorf("Close should rotate open files, got %v", files)
+	}
+	if src, ok := SourceForFile(visible(t, dir)[0]); !ok || src != "nhtsa" {
+		t.Errorf("ingest should recognise the file's source, got %q", src)
+	}
+}
+
+func TestDirSink_RecoversLeftovers(t *testing.T) {
+	dir := t.TempDir()
+	line := `{"source":"reddit","source_id":"1"}` + "\n"
+	os.WriteFile(filepath.Join(dir, ".reddit-1700000000000000000.json"), []byte(line+line+`{"source":"red`), 0o644)
+	os.WriteFile(filepath.Join(dir, ".nhtsa-1700000000000000000.json"), []byte(`{"sou`), 0o644)
+	os.WriteFile(filepath.Join(dir, ".keep.json"), []byte("x"), 0o644)
+
+	if _, err := NewDirSink(dir, DirSinkOpts{}); err != nil {
+		t.Fatal(err)
+	}
+	files := visible(t, dir)
+	if len(files) != 1 || files[0] != "reddit-1700000000000000000.json" {
+		t.Fatalf("expected the interrupted file to be published, got %v", files)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, files[0])); string(data) != line+line {
+		t.Errorf("the partial last line should be cut, got %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".nhtsa-1700000000000000000.json")); !os.IsNotExist(err) {
+		t.Error("a leftover without a complete line should be removed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".keep.json")); err != nil {
+		t.Error("files the sink did not write should be left alone")
+	}
+}
+
+func TestNATSSink_ReturnsPublishErrors(t *testing.T) {
+	srv, err := natsserver.NewServer(&natsserver.Options{Port: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.Start()
+	defer srv.Shutdown()
+	if !srv.ReadyForConnections(3 * time.Second) {
+		t.Fatal("nats not ready")
+	}
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := NewNATSSink(nc, "posts")
+	post := ScrapedPost{Source: "reddit", SourceID: "1"}
+	if err := sink.Write(context.Background(), "reddit", []ScrapedPost{post}); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+
+	nc.Close()
+	if err := sink.Write(context.Background(), "reddit", []ScrapedPost{post}); !errors.Is(err, nats.ErrConnectionClosed) {
+		t.Errorf("expected the publish error, got %v", err)
+	}
+}
+
+func visible(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name(), ".") {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
+
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	n := 0
+	for sc := bufio.NewScanner(f); sc.Scan(); {
+		n++
+	}
+	return n
+}
+
+func TestCursors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cursors.json")
+	c, err := OpenCursors(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set("reddit", "t3_abc"); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err = OpenCursors(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Get("reddit"); got != "t3_abc" {
+		t.Errorf("cursor not persisted, got %q", got)
+	}
+	if got := c.Get("nhtsa"); got != "" {
+		t.Errorf("unknown source should have no cursor, got %q", got)
+	}
+}
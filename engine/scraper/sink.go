@@ -0,0 +1,223 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/WessleyAI/wessley-mvp/pkg/natsutil"
+)
+
+// Sink receives the posts a source fetched.
+type Sink interface {
+	Write(ctx context.Context, source string, posts []ScrapedPost) error
+	Close() error
+}
+
+// WriterSink writes posts to w as indented JSON, one after another.
+type WriterSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewWriterSink creates a WriterSink; os.Stdout is the usual w.
+func NewWriterSink(w io.Writer) *WriterSink {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return &WriterSink{enc: enc}
+}
+
+func (s *WriterSink) Write(_ context.Context, _ string, posts []ScrapedPost) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range posts {
+		if err := s.enc.Encode(p); err != nil {
+			return fmt.Errorf("encode: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *WriterSink) Close() error { return nil }
+
+// NATSSink publishes each post to a NATS subject. A failed publish doesn't
+// hold back the rest of the batch, but Write returns the errors so the
+// source's cursor is not advanced past posts that were lost.
+type NATSSink struct {
+	nc      *nats.Conn
+	subject string
+}
+
+// NewNATSSink creates a NATSSink. The caller owns nc.
+func NewNATSSink(nc *nats.Conn, subject string) *NATSSink {
+	return &NATSSink{nc: nc, subject: subject}
+}
+
+func (s *NATSSink) Write(ctx context.Context, _ string, posts []ScrapedPost) error {
+	var errs []error
+	for _, p := range posts {
+		if err := natsutil.Publish(ctx, s.nc, s.subject, p); err != nil {
+			errs = append(errs, fmt.Errorf("nats publish %s: %w", p.SourceID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *NATSSink) Close() error { return s.nc.Flush() }
+
+// DirSinkOpts configures a DirSink. A file is rotated once it reaches
+// MaxBytes or MaxAge; with neither set every write gets its own file.
+type DirSinkOpts struct {
+	MaxBytes int64
+	MaxAge   time.Duration
+}
+
+// DirSink writes posts as JSON lines into files named
+// "<source>-<unixnano>.json" for cmd/ingest. The file being written is
+// hidden (a leading dot) until it is rotated, so ingest never reads half a
+// file. Every Write is synced to disk before it returns.
+type DirSink struct {
+	dir  string
+	opts DirSinkOpts
+	now  func() time.Time
+
+	mu   sync.Mutex
+	open map[string]*dirFile
+}
+
+type dirFile struct {
+	f       *os.File
+	name    string // final name, without the dot
+	size    int64
+	created time.Time
+}
+
+// leftoverName matches a hidden file that a DirSink stopped before rotating.
+var leftoverName = regexp.MustCompile(`^\..+-\d+\.json$`)
+
+// NewDirSink creates a DirSink writing into dir, creating it if needed.
+// Hidden files left in dir by a sink that did not close are recovered
+// first: cut back to their last complete line and given their visible name.
+func NewDirSink(dir string, opts DirSinkOpts) (*DirSink, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("dir sink: %w", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("dir sink: %w", err)
+	}
+	for _, e := range entries {
+		if e.Type().IsRegular() && leftoverName.MatchString(e.Name()) {
+			if err := recoverFile(dir, e.Name()); err != nil {
+				return nil, fmt.Errorf("dir sink: recover %s: %w", e.Name(), err)
+			}
+		}
+	}
+	return &DirSink{dir: dir, opts: opts, now: time.Now, open: map[string]*dirFile{}}, nil
+}
+
+// recoverFile publishes the hidden file name in dir without a partly
+// written last line, or removes it when no line is complete.
+func recoverFile(dir, name string) error {
+	path := filepath.Join(dir, name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	keep := bytes.LastIndexByte(data, '\n') + 1
+	if keep == 0 {
+		return os.Remove(path)
+	}
+	if keep < len(data) {
+		if err := os.Truncate(path, int64(keep)); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(path, filepath.Join(dir, name[1:])); err != nil {
+		return err
+	}
+	log.Printf("recovered %d bytes to %s", keep, name[1:])
+	return nil
+}
+
+func (s *DirSink) Write(_ context.Context, source string, posts []ScrapedPost) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	df := s.open[source]
+	if df == nil {
+		now := s.now()
+		// Colons are not portable in file names: "reddit:cars" → "reddit-cars".
+		name := fmt.Sprintf("%s-%d.json", strings.ReplaceAll(source, ":", "-"), now.UnixNano())
+		f, err := os.Create(filepath.Join(s.dir, "."+name))
+		if err != nil {
+			return fmt.Errorf("dir sink: %w", err)
+		}
+		df = &dirFile{f: f, name: name, created: now}
+		s.open[source] = df
+	}
+
+	for _, p := range posts {
+		data, err := json.Marshal(p)
+		if err != nil {
+			return fmt.Errorf("dir sink: encode: %w", err)
+		}
+		n, err := df.f.Write(append(data, '\n'))
+		df.size += int64(n)
+		if err != nil {
+			return fmt.Errorf("dir sink: %w", err)
+		}
+	}
+	if err := df.f.Sync(); err != nil {
+		return fmt.Errorf("dir sink: %w", err)
+	}
+
+	// Other sources' files age too; rotate them here rather than wait for
+	// their next write.
+	var errs []error
+	for src, df := range s.open {
+		full := s.opts.MaxBytes > 0 && df.size >= s.opts.MaxBytes
+		old := s.opts.MaxAge > 0 && s.now().Sub(df.created) >= s.opts.MaxAge
+		if full || old || s.opts.MaxBytes <= 0 && s.opts.MaxAge <= 0 {
+			errs = append(errs, s.rotate(src))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// rotate closes source's file and gives it its visible name. Must hold mu.
+func (s *DirSink) rotate(source string) error {
+	df := s.open[source]
+	delete(s.open, source)
+	if err := df.f.Close(); err != nil {
+		return fmt.Errorf("dir sink: %w", err)
+	}
+	if err := os.Rename(df.f.Name(), filepath.Join(s.dir, df.name)); err != nil {
+		return fmt.Errorf("dir sink: %w", err)
+	}
+	log.Printf("wrote %d bytes to %s", df.size, df.name)
+	return nil
+}
+
+// Close rotates every open file.
+func (s *DirSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var errs []error
+	for source := range s.open {
+		errs = append(errs, s.rotate(source))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,146 @@
+package scraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SourceAdapter describes one scrape source: how its records are named,
+// decoded, linked into the graph and chunked. Validation, cmd/ingest, the
+// ingest pipeline and the API all dispatch through the registered adapters,
+// so adding a source means registering one adapter.
+type SourceAdapter struct {
+	Name     string
+	Prefixes []string // other accepted Source forms, e.g. "reddit:" for "reddit:MechanicAdvice"
+
+	// Decode converts one record in the source's native scrape format. It
+	// reports false for records it does not recognise. Nil means the source
+	// only writes ScrapedPost records.
+	Decode func(raw json.RawMessage) (ScrapedPost, bool)
+
+	// Enrich returns the component or system string the document is linked
+	// under in the graph, or "" to skip enrichment. Nil skips enrichment.
+	Enrich func(doc EnrichDoc) string
+
+	// Sections marks sources whose documents carry a "section" heading
+	// (manuals); ingest links them under the vehicle system it classifies
+	// the section into instead of calling Enrich.
+	Sections bool
+
+	Chunking ChunkPolicy
+}
+
+// EnrichDoc is the part of a parsed document an enrichment hook sees.
+type EnrichDoc struct {
+	Metadata  map[string]string
+	Content   string
+	Sentences []string
+}
+
+// ChunkPolicy selects how a source's documents are split for embedding.
+// Zero sizes use the ingest defaults.
+type ChunkPolicy struct {
+	Size    int  // target words per chunk
+	Overlap int  // words repeated between consecutive chunks
+	Threads bool // chunk each answer together with the question
+}
+
+var (
+	sourcesMu sync.RWMutex
+	sources   []SourceAdapter
+)
+
+// RegisterSource adds a source adapter. It panics if the name is empty or
+// already registered, as that is a programming error.
+func RegisterSource(a SourceAdapter) {
+	if a.Name == "" {
+		panic("scraper: RegisterSource with empty name")
+	}
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+	for _, s := range sources {
+		if s.Name == a.Name {
+			panic(fmt.Sprintf("scraper: source %q registered twice", a.Name))
+		}
+	}
+	sources = append(sources, a)
+}
+
+// LookupSource returns the adapter for a post's Source, matching either the
+// adapter name or one of its prefixes.
+func LookupSource(src string) (SourceAdapter, bool) {
+	sourcesMu.RLock()
+	defer sourcesMu.RUnlock()
+	for _, s := range sources {
+		if s.matches(src) {
+			return s, true
+		}
+	}
+	return SourceAdapter{}, false
+}
+
+func (a SourceAdapter) matches(src string) bool {
+	if src == a.Name {
+		return true
+	}
+	for _, p := range a.Prefixes {
+		if strings.HasPrefix(src, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// SourceNames returns the registered source names, sorted.
+func SourceNames() []string {
+	sourcesMu.RLock()
+	defer sourcesMu.RUnlock()
+	names := make([]string, len(sources))
+	for i, s := range sources {
+		names[i] = s.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SourceForFile returns the source name of a scraped data file. Scrapers
+// name files "<source>-<suffix>.json" with ":" replaced by "-", e.g.
+// "reddit-1712345.json" or "forum-toyotanation-1712345.json".
+func SourceForFile(name string) (string, bool) {
+	stem := strings.TrimSuffix(name, ".json")
+	base, _, _ := strings.Cut(stem, "-")
+	if a, ok := LookupSource(base); ok {
+		return a.Name, true
+	}
+	return "", false
+}
+
+// DecodeRecord converts one JSON record from a scraped data file. Records
+// already in ScrapedPost form are returned as is; anything else is offered to
+// each adapter's Decode in registration order. It reports false for records
+// no adapter recognises or that lack an ID or content.
+func DecodeRecord(raw json.RawMessage) (ScrapedPost, bool) {
+	var post ScrapedPost
+	if err := json.Unmarshal(raw, &post); err == nil && post.Source != "" && post.SourceID != "" && post.Content != "" {
+		return post, true
+	}
+
+	sourcesMu.RLock()
+	decoders := make([]func(json.RawMessage) (ScrapedPost, bool), 0, len(sources))
+	for _, s := range sources {
+		if s.Decode != nil {
+			decoders = append(decoders, s.Decode)
+		}
+	}
+	sourcesMu.RUnlock()
+
+	for _, decode := range decoders {
+		if post, ok := decode(raw); ok && post.SourceID != "" && post.Content != "" {
+			return post, true
+		}
+	}
+	return ScrapedPost{}, false
+}
@@ -0,0 +1,111 @@
+package scraper
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLookupSource(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+		ok   bool
+	}{
+		{"reddit", "reddit", true},
+		{"reddit:MechanicAdvice", "reddit", true},
+		{"forum:toyotanation", "forum", true},
+		{"manual", "manual", true},
+		{"nhtsa", "nhtsa", true},
+		{"tiktok", "", false},
+		{"redditor", "", false},
+	}
+	for _, tt := range tests {
+		a, ok := LookupSource(tt.src)
+		if ok != tt.ok || a.Name != tt.want {
+			t.Errorf("LookupSource(%q) = %q, %v; want %q, %v", tt.src, a.Name, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRegisterSource_Duplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic on duplicate source")
+		}
+	}()
+	RegisterSource(SourceAdapter{Name: "reddit"})
+}
+
+func TestSourceForFile(t *testing.T) {
+	tests := map[string]string{
+		"reddit-1712345.json":             "reddit",
+		"reddit-posts.json":               "reddit",
+		"nhtsa-complaints.json":           "nhtsa",
+		"ifixit-guides.json":              "ifixit",
+		"forum-toyotanation-1712345.json": "forum",
+		"manual.json":                     "manual",
+	}
+	for name, want := range tests {
+		if got, ok := SourceForFile(name); !ok || got != want {
+			t.Errorf("SourceForFile(%q) = %q, %v; want %q", name, got, ok, want)
+		}
+	}
+	if _, ok := SourceForFile("2019-civic-sec-3.json"); ok {
+		t.Error("expected unknown source for manual section file")
+	}
+}
+
+func TestDecodeRecord(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		source   string
+		sourceID string
+	}{
+		{"scraped post", `{"source":"forum:civicx","source_id":"f1","title":"T","content":"C"}`, "forum:civicx", "f1"},
+		{"reddit", `{"id":"abc","subreddit":"MechanicAdvice","title":"No crank","self_text":"Clicks once"}`, "reddit:MechanicAdvice", "abc"},
+		{"nhtsa", `{"odi_number":"1234","make":"HONDA","model":"CIVIC","year":2019,"complaint":"Stalls at idle"}`, "nhtsa", "1234"},
+		{"ifixit", `{"guide_id":42,"guide_name":"Starter Replacement","summary":"Remove the starter"}`, "ifixit", "42"},
+	}
+	for _, tt := range tests {
+		post, ok := DecodeRecord(json.RawMessage(tt.raw))
+		if !ok || post.Source != tt.source || post.SourceID != tt.sourceID {
+			t.Errorf("%s: got %q/%q ok=%v", tt.name, post.Source, post.SourceID, ok)
+		}
+	}
+
+	if _, ok := DecodeRecord(json.RawMessage(`{"foo":"bar"}`)); ok {
+		t.Error("expected unrecognised record to be rejected")
+	}
+}
+
+func TestDecodeRecord_NHTSAVehicle(t *testing.T) {
+	post, ok := DecodeRecord(json.RawMessage(`{"odi_number":"1","make":"FORD","model":"F-150","year":2018,"summary":"ELECTRICAL SYSTEM"}`))
+	if !ok {
+		t.Fatal("expected decode")
+	}
+	if post.Content != "ELECTRICAL SYSTEM" || post.Metadata.Components != "ELECTRICAL SYSTEM" {
+		t.Fatalf("summary not used as content/components: %+v", post)
+	}
+	if vi := post.Metadata.VehicleInfo; vi == nil || vi.Make != "FORD" || vi.Year != 2018 {
+		t.Fatalf("unexpected vehicle info %+v", vi)
+	}
+}
+
+func TestEnrichHooks(t *testing.T) {
+	nhtsa, _ := LookupSource("nhtsa")
+	if got := nhtsa.Enrich(EnrichDoc{Metadata: map[string]string{"components": "ENGINE"}}); got != "ENGINE" {
+		t.Errorf("nhtsa enrich = %q", got)
+	}
+	if got := nhtsa.Enrich(EnrichDoc{Sentences: []string{"Fuel pump failed.", "Car stalled."}}); got != "Fuel pump failed." {
+		t.Errorf("nhtsa enrich fallback = %q", got)
+	}
+
+	if manual, _ := LookupSource("manual"); manual.Enrich != nil || !manual.Sections {
+		t.Error("manual sections should be classified by ingest")
+	}
+
+	if reddit, _ := LookupSource("reddit"); reddit.Enrich != nil || !reddit.Chunking.Threads {
+		t.Error("reddit should chunk threads and skip enrichment")
+	}
+}
@@ -1,6 +1,8 @@
 package scraper
 
-import "time"
+import (
+	"time"
+)
 
 // ScrapedPost represents a scraped and processed content item.
 type ScrapedPost struct {
@@ -30,8 +32,83 @@ type Metadata struct {
 	Symptoms    []string     `json:"symptoms,omitempty"`
 	Fixes       []string     `json:"fixes,omitempty"`
 	Keywords    []string     `json:"keywords,omitempty"`
-	Section     string       `json:"section,omitempty"`     // system/subsystem classification
-	Components  string       `json:"components,omitempty"`  // raw component string (e.g. from NHTSA)
+	Section     string       `json:"section,omitempty"`      // system/subsystem classification
+	Components  string       `json:"components,omitempty"`   // raw component string (e.g. from NHTSA)
+	Score       int          `json:"score,omitempty"`        // source-native ranking signal (e.g. Reddit upvotes)
+	Comments    int          `json:"num_comments,omitempty"` // reply count reported by the source
+	Answers     []Answer     `json:"answers,omitempty"`      // reply chains kept alongside the question
+
+	// Set on manual sections so citations can point at the page.
+	ManualID     string `json:"manual_id,omitempty"`     // manual registry ID of the source PDF
+	SectionTitle string `json:"section_title,omitempty"` // heading of the section in the manual
+	PageRange    string `json:"page_range,omitempty"`    // physical PDF pages, e.g. "45-52" or "45"
+
+	// Set on step-by-step repair guides; ingest stores it as a Procedure.
+	Procedure *Procedure `json:"procedure,omitempty"`
+
+	// Set on NHTSA recalls and investigations; ingest stores it as a Recall.
+	Recall *Recall `json:"recall,omitempty"`
+
+	// Set on NHTSA complaints; ingest adds it to the ComplaintStats counters
+	// and the vector payload.
+	Complaint *Complaint `json:"complaint,omitempty"`
+}
+
+// Procedure is the step-by-step structure of a repair guide, e.g. an iFixit
+// guide. Ingest converts it into a graph Procedure.
+type Procedure struct {
+	Title      string          `json:"title"`
+	Operation  string          `json:"operation,omitempty"`  // normalized verb, e.g. "replace"
+	Components []string        `json:"components,omitempty"` // what the procedure works on
+	Steps      []ProcedureStep `json:"steps"`
+	Tools      []string        `json:"tools,omitempty"`
+	Warnings   []string        `json:"warnings,omitempty"`
+	Source     string          `json:"source,omitempty"` // manual, ifixit
+	URL        string          `json:"url,omitempty"`
+}
+
+// ProcedureStep is one numbered step of a Procedure.
+type ProcedureStep struct {
+	Order int    `json:"order"` // 1-based
+	Title string `json:"title,omitempty"`
+	Text  string `json:"text"`
+}
+
+// Recall is an NHTSA safety recall campaign or defect investigation as
+// scraped. Ingest converts it into a graph Recall.
+type Recall struct {
+	Campaign     string `json:"campaign"`       // NHTSA campaign or ODI action number
+	Kind         string `json:"kind,omitempty"` // "recall" (default) or "investigation"
+	Component    string `json:"component,omitempty"`
+	Summary      string `json:"summary,omitempty"`
+	Consequence  string `json:"consequence,omitempty"`
+	Remedy       string `json:"remedy,omitempty"`
+	Manufacturer string `json:"manufacturer,omitempty"`
+	Date         string `json:"date,omitempty"`   // report or open date, "2006-01-02"
+	Status       string `json:"status,omitempty"` // investigations: "open" or "closed"
+	Units        int    `json:"units,omitempty"`  // potentially affected vehicles
+	URL          string `json:"url,omitempty"`
+}
+
+// Complaint is the severity record of one NHTSA complaint. Ingest converts
+// it into a graph Complaint.
+type Complaint struct {
+	Components   string `json:"components"` // raw NHTSA components, e.g. "ELECTRICAL SYSTEM,FUEL SYSTEM, GASOLINE"
+	Crash        bool   `json:"crash,omitempty"`
+	Fire         bool   `json:"fire,omitempty"`
+	Injuries     int    `json:"injuries,omitempty"`
+	Deaths       int    `json:"deaths,omitempty"`
+	IncidentDate string `json:"incident_date,omitempty"` // "2006-01-02"
+	VIN          string `json:"vin,omitempty"`           // as published, usually truncated
+}
+
+// Answer is a reply chain (a top-level reply plus its follow-ups) attached to
+// a question-style post. It is chunked together with the question so fixes
+// stay next to the problem they solve.
+type Answer struct {
+	Text     string `json:"text"`
+	Score    int    `json:"score"`
+	Resolved bool   `json:"resolved,omitempty"` // the asker confirmed this chain fixed the problem
 }
 
 // ScrapeOpts configures a scrape run.
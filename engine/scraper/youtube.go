@@ -5,8 +5,10 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -147,6 +149,9 @@ func (s *YouTubeScraper) SearchVideos(ctx context.Context, query string, max int
 // ErrQuotaExhausted is returned when YouTube API quota is exceeded.
 var ErrQuotaExhausted = fmt.Errorf("youtube API quota exhausted")
 
+// errDuplicateVideo is returned by ScrapeVideo for a video already scraped.
+var errDuplicateVideo = errors.New("duplicate video")
+
 // Scrape runs a full scrape based on options.
 func (s *YouTubeScraper) Scrape(ctx context.Context, opts ScrapeOpts) <-chan fn.Result[ScrapedPost] {
 	ch := make(chan fn.Result[ScrapedPost], 32)
@@ -206,7 +211,7 @@ func (s *YouTubeScraper) Scrape(ctx context.Context, opts ScrapeOpts) <-chan fn.
 func (s *YouTubeScraper) ScrapeVideo(ctx context.Context, videoID, title string) fn.Result[ScrapedPost] {
 	// Dedup
 	if _, loaded := s.seen.LoadOrStore(videoID, true); loaded {
-		return fn.Err[ScrapedPost](fmt.Errorf("duplicate video %s", videoID))
+		return fn.Err[ScrapedPost](fmt.Errorf("%w %s", errDuplicateVideo, videoID))
 	}
 
 	transcriptResult := GetTranscript(ctx, s.httpClient, videoID)
@@ -253,6 +258,41 @@ func (s *YouTubeScraper) ScrapeVideoIDs(ctx context.Context, ids []string) <-cha
 	return ch
 }
 
+// YouTubeSource runs a YouTubeScraper under the Runtime, scraping VideoIDs
+// when set and otherwise searching with Opts (which needs an API key).
+// Videos already scraped by an earlier fetch are skipped.
+type YouTubeSource struct {
+	Scraper  *YouTubeScraper
+	Opts     ScrapeOpts
+	VideoIDs []string
+}
+
+func (y YouTubeSource) Name() string { return "youtube" }
+
+func (y YouTubeSource) Fetch(ctx context.Context, _ string) ([]ScrapedPost, string, error) {
+	var results <-chan fn.Result[ScrapedPost]
+	if len(y.VideoIDs) > 0 {
+		results = y.Scraper.ScrapeVideoIDs(ctx, y.VideoIDs)
+	} else {
+		results = y.Scraper.Scrape(ctx, y.Opts)
+	}
+
+	var posts []ScrapedPost
+	for r := range results {
+		post, err := r.Unwrap()
+		switch {
+		case errors.Is(err, ErrQuotaExhausted):
+			return posts, "", err
+		case errors.Is(err, errDuplicateVideo):
+		case err != nil:
+			log.Printf("youtube: %v", err)
+		default:
+			posts = append(posts, post)
+		}
+	}
+	return posts, "", ctx.Err()
+}
+
 // extractMetadata parses vehicle info, symptoms, and fixes from text.
 func extractMetadata(title, transcript string) Metadata {
 	combined := title + " " + transcript
@@ -0,0 +1,312 @@
+package semantic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	pb "github.com/qdrant/go-client/qdrant"
+	"google.golang.org/grpc"
+)
+
+// Payload keys recording which model produced a point's vector.
+const (
+	PayloadEmbeddingModel   = "embedding_model"
+	PayloadEmbeddingVersion = "embedding_version"
+)
+
+// PointsReader is the part of pb.PointsClient used to read stored points back.
+// It is optional so test doubles that only search and upsert need not implement it.
+type PointsReader interface {
+	Scroll(ctx context.Context, in *pb.ScrollPoints, opts ...grpc.CallOption) (*pb.ScrollResponse, error)
+	Count(ctx context.Context, in *pb.CountPoints, opts ...grpc.CallOption) (*pb.CountResponse, error)
+	Get(ctx context.Context, in *pb.GetPoints, opts ...grpc.CallOption) (*pb.GetResponse, error)
+}
+
+// AliasesAPI is the part of pb.CollectionsClient used to manage collection aliases.
+type AliasesAPI interface {
+	UpdateAliases(ctx context.Context, in *pb.ChangeAliases, opts ...grpc.CallOption) (*pb.CollectionOperationResponse, error)
+	ListAliases(ctx context.Context, in *pb.ListAliasesRequest, opts ...grpc.CallOption) (*pb.ListAliasesResponse, error)
+}
+
+// EmbeddingModel identifies the model (and its revision) behind a set of vectors.
+// Version is bumped when the model is retrained or its preprocessing changes
+// without a new name.
+type EmbeddingModel struct {
+	Name    string
+	Version int
+	Dims    int
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slug reduces a model name ("nomic-embed-text:v1.5") to collection-name characters.
+func (m EmbeddingModel) slug() string {
+	return strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(m.Name), "-"), "-")
+}
+
+// CollectionName is the concrete collection holding this model's vectors behind alias,
+// e.g. "wessley_nomic-embed-text_v1".
+func (m EmbeddingModel) CollectionName(alias string) string {
+	return fmt.Sprintf("%s_%s_v%d", alias, m.slug(), m.Version)
+}
+
+// Matches reports whether collection was named by CollectionName for this model.
+func (m EmbeddingModel) Matches(collection string) bool {
+	return strings.HasSuffix(collection, fmt.Sprintf("_%s_v%d", m.slug(), m.Version))
+}
+
+// ErrModelMismatch is returned by CheckModel when an alias points at a
+// collection built by another embedding model.
+var ErrModelMismatch = errors.New("semantic: alias points at a collection built by a different embedding model")
+
+// Payload returns the payload fields stamped on every point.
+func (m EmbeddingModel) Payload() map[string]any {
+	return map[string]any{
+		PayloadEmbeddingModel:   m.Name,
+		PayloadEmbeddingVersion: m.Version,
+	}
+}
+
+// Collection returns the collection (or alias) this store reads and writes.
+func (v *VectorStore) Collection() string { return v.collection }
+
+// WithCollection returns a store sharing this store's connection but targeting
+// another collection. The returned store must not be closed separately.
+func (v *VectorStore) WithCollection(name string) *VectorStore {
+	return &VectorStore{
+		points:      v.points,
+		collections: v.collections,
+		collection:  name,
+	}
+}
+
+func (v *VectorStore) aliases() (AliasesAPI, error) {
+	a, ok := v.collections.(AliasesAPI)
+	if !ok {
+		return nil, fmt.Errorf("semantic: collections client does not support aliases")
+	}
+	return a, nil
+}
+
+func (v *VectorStore) reader() (PointsReader, error) {
+	r, ok := v.points.(PointsReader)
+	if !ok {
+		return nil, fmt.Errorf("semantic: points client does not support reads")
+	}
+	return r, nil
+}
+
+// ResolveAlias returns the collection alias points to, or "" if no such alias exists.
+func (v *VectorStore) ResolveAlias(ctx context.Context, alias string) (string, error) {
+	a, err := v.aliases()
+	if err != nil {
+		return "", err
+	}
+	resp, err := a.ListAliases(ctx, &pb.ListAliasesRequest{})
+	if err != nil {
+		return "", fmt.Errorf("semantic: list aliases: %w", err)
+	}
+	for _, d := range resp.GetAliases() {
+		if d.GetAliasName() == alias {
+			return d.GetCollectionName(), nil
+		}
+	}
+	return "", nil
+}
+
+// CheckModel resolves the store's collection as an alias and returns
+// ErrModelMismatch if its target was not built by model. A plain collection
+// (no alias) passes. Vectors from one model are meaningless to another, so
+// callers check before writing or serving, since cmd/reembed may switch the
+// alias at any time.
+func (v *VectorStore) CheckModel(ctx context.Context, model EmbeddingModel) error {
+	target, err := v.ResolveAlias(ctx, v.collection)
+	if err != nil {
+		return err
+	}
+	if target != "" && !model.Matches(target) {
+		return fmt.Errorf("%w: %s -> %s, model %s v%d", ErrModelMismatch, v.collection, target, model.Name, model.Version)
+	}
+	return nil
+}
+
+// SwitchAlias points alias at collection. The delete and create are sent as one
+// request, which Qdrant applies atomically, so readers never see a missing alias.
+func (v *VectorStore) SwitchAlias(ctx context.Context, alias, collection string) error {
+	a, err := v.aliases()
+	if err != nil {
+		return err
+	}
+	current, err := v.ResolveAlias(ctx, alias)
+	if err != nil {
+		return err
+	}
+	var actions []*pb.AliasOperations
+	if current != "" {
+		actions = append(actions, &pb.AliasOperations{
+			Action: &pb.AliasOperations_DeleteAlias{DeleteAlias: &pb.DeleteAlias{AliasName: alias}},
+		})
+	}
+	actions = append(actions, &pb.AliasOperations{
+		Action: &pb.AliasOperations_CreateAlias{CreateAlias: &pb.CreateAlias{CollectionName: collection, AliasName: alias}},
+	})
+	if _, err := a.UpdateAliases(ctx, &pb.ChangeAliases{Actions: actions}); err != nil {
+		return fmt.Errorf("semantic: switch alias %s -> %s: %w", alias, collection, err)
+	}
+	return nil
+}
+
+// EnsureAlias makes alias usable for reads and writes. An existing alias is
+// left alone; a legacy collection named alias is used as-is; otherwise the
+// model's versioned collection is created and alias pointed at it.
+// It returns the concrete collection behind alias.
+func (v *VectorStore) EnsureAlias(ctx context.Context, alias string, model EmbeddingModel) (string, error) {
+	target, err := v.ResolveAlias(ctx, alias)
+	if err != nil || target != "" {
+		return target, err
+	}
+	exists, err := v.collectionExists(ctx, alias)
+	if err != nil || exists {
+		return alias, err
+	}
+	name := model.CollectionName(alias)
+	if err := v.WithCollection(name).EnsureCollection(ctx, model.Dims); err != nil {
+		return "", err
+	}
+	if err := v.SwitchAlias(ctx, alias, name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+func (v *VectorStore) collectionExists(ctx context.Context, name string) (bool, error) {
+	list, err := v.collections.List(ctx, &pb.ListCollectionsRequest{})
+	if err != nil {
+		return false, fmt.Errorf("semantic: list collections: %w", err)
+	}
+	for _, c := range list.GetCollections() {
+		if c.GetName() == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Count returns the exact number of points in the collection.
+func (v *VectorStore) Count(ctx context.Context) (uint64, error) {
+	r, err := v.reader()
+	if err != nil {
+		return 0, err
+	}
+	exact := true
+	resp, err := r.Count(ctx, &pb.CountPoints{CollectionName: v.collection, Exact: &exact})
+	if err != nil {
+		return 0, fmt.Errorf("semantic: count %s: %w", v.collection, err)
+	}
+	return resp.GetResult().GetCount(), nil
+}
+
+// Scroll returns up to limit points (payload only, no vectors) starting at
+// offset, plus the offset of the next page ("" when exhausted).
+func (v *VectorStore) Scroll(ctx context.Context, offset string, limit int) ([]VectorRecord, string, error) {
+	r, err := v.reader()
+	if err != nil {
+		return nil, "", err
+	}
+	lim := uint32(limit)
+	req := &pb.ScrollPoints{
+		CollectionName: v.collection,
+		Limit:          &lim,
+		WithPayload:    &pb.WithPayloadSelector{SelectorOptions: &pb.WithPayloadSelector_Enable{Enable: true}},
+	}
+	if offset != "" {
+		req.Offset = &pb.PointId{PointIdOptions: &pb.PointId_Uuid{Uuid: offset}}
+	}
+	resp, err := r.Scroll(ctx, req)
+	if err != nil {
+		return nil, "", fmt.Errorf("semantic: scroll %s: %w", v.collection, err)
+	}
+	records := make([]VectorRecord, len(resp.GetResult()))
+	for i, p := range resp.GetResult() {
+		records[i] = VectorRecord{ID: p.GetId().GetUuid(), Payload: payloadToMap(p.GetPayload())}
+	}
+	return records, resp.GetNextPageOffset().GetUuid(), nil
+}
+
+// Existing returns the subset of ids already stored in the collection.
+func (v *VectorStore) Existing(ctx context.Context, ids []string) (map[string]bool, error) {
+	points, err := v.get(ctx, ids, false)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]bool, len(points))
+	for _, p := range points {
+		found[p.GetId().GetUuid()] = true
+	}
+	return found, nil
+}
+
+// Get returns the stored points among ids, with their payload but no vectors.
+func (v *VectorStore) Get(ctx context.Context, ids []string) ([]VectorRecord, error) {
+	points, err := v.get(ctx, ids, true)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]VectorRecord, len(points))
+	for i, p := range points {
+		records[i] = VectorRecord{ID: p.GetId().GetUuid(), Payload: payloadToMap(p.GetPayload())}
+	}
+	return records, nil
+}
+
+func (v *VectorStore) get(ctx context.Context, ids []string, withPayload bool) ([]*pb.RetrievedPoint, error) {
+	r, err := v.reader()
+	if err != nil {
+		return nil, err
+	}
+	pids := make([]*pb.PointId, len(ids))
+	for i, id := range ids {
+		pids[i] = &pb.PointId{PointIdOptions: &pb.PointId_Uuid{Uuid: id}}
+	}
+	resp, err := r.Get(ctx, &pb.GetPoints{
+		CollectionName: v.collection,
+		Ids:            pids,
+		WithPayload:    &pb.WithPayloadSelector{SelectorOptions: &pb.WithPayloadSelector_Enable{Enable: withPayload}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("semantic: get %d points: %w", len(ids), err)
+	}
+	return resp.GetResult(), nil
+}
+
+// payloadToMap converts a Qdrant payload back into the types Upsert accepts.
+// Kinds Upsert never writes (null, structs, lists of non-strings) are dropped.
+func payloadToMap(payload map[string]*pb.Value) map[string]any {
+	out := make(map[string]any, len(payload))
+	for k, val := range payload {
+		switch kind := val.GetKind().(type) {
+		case *pb.Value_StringValue:
+			out[k] = kind.StringValue
+		case *pb.Value_IntegerValue:
+			out[k] = kind.IntegerValue
+		case *pb.Value_DoubleValue:
+			out[k] = kind.DoubleValue
+		case *pb.Value_BoolValue:
+			out[k] = kind.BoolValue
+		case *pb.Value_ListValue:
+			var list []string
+			for _, v := range kind.ListValue.GetValues() {
+				if s, ok := v.GetKind().(*pb.Value_StringValue); ok {
+					list = append(list, s.StringValue)
+				}
+			}
+			if list != nil {
+				out[k] = list
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,133 @@
+package semantic
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pb "github.com/qdrant/go-client/qdrant"
+	"google.golang.org/grpc"
+)
+
+type mockAliases struct {
+	mockCollections
+	aliases []*pb.AliasDescription
+	changed *pb.ChangeAliases
+}
+
+func (m *mockAliases) UpdateAliases(_ context.Context, in *pb.ChangeAliases, _ ...grpc.CallOption) (*pb.CollectionOperationResponse, error) {
+	m.changed = in
+	return &pb.CollectionOperationResponse{Result: true}, nil
+}
+
+func (m *mockAliases) ListAliases(_ context.Context, _ *pb.ListAliasesRequest, _ ...grpc.CallOption) (*pb.ListAliasesResponse, error) {
+	return &pb.ListAliasesResponse{Aliases: m.aliases}, nil
+}
+
+func TestEmbeddingModel_CollectionName(t *testing.T) {
+	m := EmbeddingModel{Name: "nomic-embed-text:v1.5", Version: 2}
+	name := m.CollectionName("wessley")
+	if name != "wessley_nomic-embed-text-v1-5_v2" {
+		t.Fatalf("unexpected name %s", name)
+	}
+	if !m.Matches(name) || m.Matches("wessley_nomic-embed-text-v1-5_v1") {
+		t.Fatal("Matches disagrees with CollectionName")
+	}
+	if p := m.Payload(); p[PayloadEmbeddingModel] != "nomic-embed-text:v1.5" || p[PayloadEmbeddingVersion] != 2 {
+		t.Fatalf("unexpected payload %v", p)
+	}
+}
+
+func TestCheckModel(t *testing.T) {
+	m := EmbeddingModel{Name: "nomic-embed-text", Version: 1}
+	cols := &mockAliases{aliases: []*pb.AliasDescription{{AliasName: "wessley", CollectionName: m.CollectionName("wessley")}}}
+	vs := NewWithClients(&mockPoints{}, cols, "wessley")
+	if err := vs.CheckModel(context.Background(), m); err != nil {
+		t.Fatalf("matching model: %v", err)
+	}
+
+	// cmd/reembed switched the alias to the next model.
+	cols.aliases[0].CollectionName = EmbeddingModel{Name: "nomic-embed-text", Version: 2}.CollectionName("wessley")
+	if err := vs.CheckModel(context.Background(), m); !errors.Is(err, ErrModelMismatch) {
+		t.Fatalf("expected ErrModelMismatch, got %v", err)
+	}
+
+	// A plain collection without an alias is not checked.
+	if err := vs.WithCollection("legacy").CheckModel(context.Background(), m); err != nil {
+		t.Fatalf("plain collection: %v", err)
+	}
+}
+
+func TestSwitchAlias_Atomic(t *testing.T) {
+	cols := &mockAliases{aliases: []*pb.AliasDescription{{AliasName: "wessley", CollectionName: "old"}}}
+	vs := NewWithClients(&mockPoints{}, cols, "wessley")
+
+	target, err := vs.ResolveAlias(context.Background(), "wessley")
+	if err != nil || target != "old" {
+		t.Fatalf("ResolveAlias = %q, %v", target, err)
+	}
+	if err := vs.SwitchAlias(context.Background(), "wessley", "new"); err != nil {
+		t.Fatal(err)
+	}
+	actions := cols.changed.GetActions()
+	if len(actions) != 2 || actions[0].GetDeleteAlias() == nil || actions[1].GetCreateAlias().GetCollectionName() != "new" {
+		t.Fatalf("expected delete+create in one request, got %v", actions)
+	}
+}
+
+func TestEnsureAlias_CreatesVersionedCollection(t *testing.T) {
+	cols := &mockAliases{mockCollections: mockCollections{
+		listResp:   &pb.ListCollectionsResponse{},
+		createResp: &pb.CollectionOperationResponse{Result: true},
+	}}
+	vs := NewWithClients(&mockPoints{}, cols, "wessley")
+	target, err := vs.EnsureAlias(context.Background(), "wessley", EmbeddingModel{Name: "nomic-embed-text", Version: 1, Dims: 768})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target != "wessley_nomic-embed-text_v1" || cols.changed == nil {
+		t.Fatalf("expected alias onto versioned collection, got %q", target)
+	}
+}
+
+func TestEnsureAlias_LegacyCollection(t *testing.T) {
+	cols := &mockAliases{mockCollections: mockCollections{
+		listResp: &pb.ListCollectionsResponse{Collections: []*pb.CollectionDescription{{Name: "wessley"}}},
+	}}
+	vs := NewWithClients(&mockPoints{}, cols, "wessley")
+	target, err := vs.EnsureAlias(context.Background(), "wessley", EmbeddingModel{Name: "nomic-embed-text", Version: 1})
+	if err != nil || target != "wessley" || cols.changed != nil {
+		t.Fatalf("legacy collection should be used as-is, got %q %v", target, err)
+	}
+}
+
+func TestAliasesUnsupported(t *testing.T) {
+	vs := NewWithClients(&mockPoints{}, &mockCollections{}, "test")
+	if _, err := vs.ResolveAlias(context.Background(), "x"); err == nil {
+		t.Fatal("expected error without alias support")
+	}
+	if _, _, err := vs.Scroll(context.Background(), "", 10); err == nil {
+		t.Fatal("expected error without read support")
+	}
+}
+
+func TestPayloadToMap(t *testing.T) {
+	m := payloadToMap(map[string]*pb.Value{
+		"s": {Kind: &pb.Value_StringValue{StringValue: "x"}},
+		"i": {Kind: &pb.Value_IntegerValue{IntegerValue: 3}},
+		"b": {Kind: &pb.Value_BoolValue{BoolValue: true}},
+		"n": {Kind: &pb.Value_NullValue{}},
+		"l": {Kind: &pb.Value_ListValue{ListValue: &pb.ListValue{Values: []*pb.Value{
+			{Kind: &pb.Value_StringValue{StringValue: "P0420"}},
+		}}}},
+	})
+	if m["s"] != "x" || m["i"] != int64(3) || m["b"] != true {
+		t.Fatalf("unexpected %v", m)
+	}
+	if l, ok := m["l"].([]string); !ok || len(l) != 1 || l[0] != "P0420" {
+		t.Fatalf("unexpected list %v", m["l"])
+	}
+	if _, ok := m["n"]; ok {
+		t.Fatal("null should be dropped")
+	}
+}
@@ -3,6 +3,7 @@ package semantic
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	pb "github.com/qdrant/go-client/qdrant"
 	"google.golang.org/grpc"
@@ -124,6 +125,12 @@ func (v *VectorStore) Upsert(ctx context.Context, records []VectorRecord) error
 				payload[k] = &pb.Value{Kind: &pb.Value_DoubleValue{DoubleValue: tv}}
 			case bool:
 				payload[k] = &pb.Value{Kind: &pb.Value_BoolValue{BoolValue: tv}}
+			case []string:
+				list := &pb.ListValue{Values: make([]*pb.Value, len(tv))}
+				for j, s := range tv {
+					list.Values[j] = &pb.Value{Kind: &pb.Value_StringValue{StringValue: s}}
+				}
+				payload[k] = &pb.Value{Kind: &pb.Value_ListValue{ListValue: list}}
 			default:
 				payload[k] = &pb.Value{Kind: &pb.Value_StringValue{StringValue: fmt.Sprint(tv)}}
 			}
@@ -176,6 +183,29 @@ func (v *VectorStore) DeleteByDocID(ctx context.Context, docID string) error {
 	return nil
 }
 
+// Delete removes the points with the given ids.
+func (v *VectorStore) Delete(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	pids := make([]*pb.PointId, len(ids))
+	for i, id := range ids {
+		pids[i] = &pb.PointId{PointIdOptions: &pb.PointId_Uuid{Uuid: id}}
+	}
+	wait := true
+	_, err := v.points.Delete(ctx, &pb.DeletePoints{
+		CollectionName: v.collection,
+		Wait:           &wait,
+		Points: &pb.PointsSelector{
+			PointsSelectorOneOf: &pb.PointsSelector_Points{Points: &pb.PointsIdsList{Ids: pids}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("semantic: delete %d points: %w", len(ids), err)
+	}
+	return nil
+}
+
 // Search performs k-NN similarity search.
 func (v *VectorStore) Search(ctx context.Context, embedding []float32, topK int) ([]SearchResult, error) {
 	return v.SearchFiltered(ctx, embedding, topK, nil)
@@ -211,7 +241,7 @@ func (v *VectorStore) SearchFiltered(ctx context.Context, embedding []float32, t
 			Meta:  make(map[string]string),
 		}
 		for k, val := range r.GetPayload() {
-			s := val.GetStringValue()
+			s := payloadString(val)
 			switch k {
 			case "content":
 				sr.Content = s
@@ -228,6 +258,20 @@ func (v *VectorStore) SearchFiltered(ctx context.Context, embedding []float32, t
 	return results, nil
 }
 
+// payloadString renders a payload value for SearchResult.Meta. Numbers and
+// booleans (score, quality, resolved, ...) are formatted rather than dropped.
+func payloadString(val *pb.Value) string {
+	switch kind := val.GetKind().(type) {
+	case *pb.Value_IntegerValue:
+		return strconv.FormatInt(kind.IntegerValue, 10)
+	case *pb.Value_DoubleValue:
+		return strconv.FormatFloat(kind.DoubleValue, 'f', -1, 64)
+	case *pb.Value_BoolValue:
+		return strconv.FormatBool(kind.BoolValue)
+	}
+	return val.GetStringValue()
+}
+
 func fieldMatch(key, value string) *pb.Condition {
 	return &pb.Condition{
 		ConditionOneOf: &pb.Condition_Field{
@@ -0,0 +1,87 @@
+// Package blob stores immutable files by the SHA-256 of their content, on
+// the local filesystem or in an S3-compatible bucket.
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned for a digest the store does not hold.
+var ErrNotFound = errors.New("blob: not found")
+
+// Info describes a stored blob.
+type Info struct {
+	SHA256  string    `json:"sha256"` // hex digest, the blob's key
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"` // when the blob was stored
+}
+
+// BlobStore is a content-addressed store: a blob's key is the hex SHA-256
+// of its bytes, so storing the same content twice keeps one copy.
+type BlobStore interface {
+	// Put stores r's content and returns its Info.
+	Put(ctx context.Context, r io.Reader) (Info, error)
+	// Get opens the blob with the given digest.
+	Get(ctx context.Context, sum string) (io.ReadCloser, error)
+	Stat(ctx context.Context, sum string) (Info, error)
+	// Delete removes a blob; deleting a missing blob is not an error.
+	Delete(ctx context.Context, sum string) error
+	List(ctx context.Context) ([]Info, error)
+}
+
+// Open returns the store described by spec: a directory (optionally as a
+// file:// URL) for a LocalStore, or s3://bucket/prefix for an S3Store. S3
+// options are query parameters: endpoint (default AWS), region (default
+// us-east-1) and path_style=true for MinIO and other stand-ins; the
+// credentials come from AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.
+func Open(spec string) (BlobStore, error) {
+	if !strings.Contains(spec, "://") {
+		return NewLocalStore(spec)
+	}
+	u, err := url.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("blob: parse %q: %w", spec, err)
+	}
+	switch u.Scheme {
+	case "file":
+		return NewLocalStore(u.Path)
+	case "s3":
+		q := u.Query()
+		return NewS3Store(S3Config{
+			Endpoint:  q.Get("endpoint"),
+			Bucket:    u.Host,
+			Prefix:    strings.TrimPrefix(u.Path, "/"),
+			Region:    q.Get("region"),
+			AccessKey: os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			PathStyle: q.Get("path_style") == "true",
+		})
+	}
+	return nil, fmt.Errorf("blob: unsupported store %q", spec)
+}
+
+// validSum reports whether s is a lowercase hex SHA-256.
+func validSum(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// shardPath spreads blobs over two directory levels, "ab/cd/abcd…", so no
+// directory grows past 256 entries per level.
+func shardPath(sum string) string {
+	return sum[:2] + "/" + sum[2:4] + "/" + sum
+}
@@ -0,0 +1,125 @@
+package blob
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// LocalStore keeps blobs in a directory, sharded by digest.
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore creates the directory if needed and returns a store in it.
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if dir == "" {
+		return nil, errors.New("blob: local store needs a directory")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("blob: %w", err)
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+// Path returns where the blob with the given digest is kept.
+func (s *LocalStore) Path(sum string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(shardPath(sum)))
+}
+
+// Put writes r to a temporary file while hashing it, then renames it into
+// place, so a blob is never visible half-written.
+func (s *LocalStore) Put(_ context.Context, r io.Reader) (Info, error) {
+	tmp, err := os.CreateTemp(s.dir, ".put-*")
+	if err != nil {
+		return Info{}, fmt.Errorf("blob: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op after the rename
+
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmp, h), r)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return Info{}, fmt.Errorf("blob: write: %w", err)
+	}
+
+	sum := hex.EncodeToString(h.Sum(nil))
+	path := s.Path(sum)
+	if info, err := os.Stat(path); err == nil {
+		return Info{SHA256: sum, Size: info.Size(), ModTime: info.ModTime()}, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return Info{}, fmt.Errorf("blob: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return Info{}, fmt.Errorf("blob: %w", err)
+	}
+	return s.Stat(context.Background(), sum)
+}
+
+// Get opens the blob. The returned file is an io.ReadSeeker, so it can be
+// served with http.ServeContent.
+func (s *LocalStore) Get(_ context.Context, sum string) (io.ReadCloser, error) {
+	if !validSum(sum) {
+		return nil, ErrNotFound
+	}
+	f, err := os.Open(s.Path(sum))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (s *LocalStore) Stat(_ context.Context, sum string) (Info, error) {
+	if !validSum(sum) {
+		return Info{}, ErrNotFound
+	}
+	info, err := os.Stat(s.Path(sum))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Info{}, ErrNotFound
+	}
+	if err != nil {
+		return Info{}, err
+	}
+	return Info{SHA256: sum, Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+func (s *LocalStore) Delete(_ context.Context, sum string) error {
+	if !validSum(sum) {
+		return nil
+	}
+	if err := os.Remove(s.Path(sum)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStore) List(ctx context.Context) ([]Info, error) {
+	var out []Info
+	err := filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() || !validSum(d.Name()) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		out = append(out, Info{SHA256: d.Name(), Size: info.Size(), ModTime: info.ModTime()})
+		return nil
+	})
+	return out, err
+}
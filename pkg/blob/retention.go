@@ -0,0 +1,52 @@
+package blob
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Retention bounds a store. Zero fields are unlimited.
+type Retention struct {
+	MaxAge   time.Duration // blobs stored longer ago are removed
+	MaxBytes int64         // oldest blobs are removed until the total fits
+}
+
+// Prune applies r to s and returns the blobs it removed, oldest first.
+// Blobs for which pinned returns true are never removed, and count toward
+// MaxBytes. A nil pinned pins nothing.
+func Prune(ctx context.Context, s BlobStore, r Retention, pinned func(sum string) bool) ([]Info, error) {
+	if r.MaxAge <= 0 && r.MaxBytes <= 0 {
+		return nil, nil
+	}
+	all, err := s.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("blob: prune: %w", err)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].ModTime.Before(all[j].ModTime) })
+
+	var total int64
+	for _, b := range all {
+		total += b.Size
+	}
+	cutoff := time.Now().Add(-r.MaxAge)
+
+	var removed []Info
+	for _, b := range all {
+		expired := r.MaxAge > 0 && b.ModTime.Before(cutoff)
+		over := r.MaxBytes > 0 && total > r.MaxBytes
+		if !expired && !over {
+			break // sorted oldest first: nothing later is expired either
+		}
+		if pinned != nil && pinned(b.SHA256) {
+			continue
+		}
+		if err := s.Delete(ctx, b.SHA256); err != nil {
+			return removed, fmt.Errorf("blob: prune %s: %w", b.SHA256, err)
+		}
+		total -= b.Size
+		removed = append(removed, b)
+	}
+	return removed, nil
+}
@@ -0,0 +1,340 @@
+package blob
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Config configures an S3Store.
+type S3Config struct {
+	Endpoint  string // e.g. "http://localhost:9000"; default "https://s3.<region>.amazonaws.com"
+	Bucket    string
+	Prefix    string // key prefix inside the bucket, e.g. "manuals/"
+	Region    string // default "us-east-1"
+	AccessKey string
+	SecretKey string
+	PathStyle bool // address the bucket as endpoint/bucket instead of bucket.endpoint
+	Client    *http.Client
+}
+
+// S3Store keeps blobs in an S3-compatible bucket under Prefix, sharded by
+// digest like LocalStore. Requests are signed with AWS Signature V4, which
+// MinIO, Ceph and the other stand-ins accept.
+type S3Store struct {
+	cfg      S3Config
+	endpoint *url.URL
+	now      func() time.Time // for testing
+}
+
+// NewS3Store validates cfg and returns a store.
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("blob: s3 store needs a bucket")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	if cfg.Prefix != "" && !strings.HasSuffix(cfg.Prefix, "/") {
+		cfg.Prefix += "/"
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 5 * time.Minute}
+	}
+	ep, err := url.Parse(cfg.Endpoint)
+	if err != nil || ep.Host == "" {
+		return nil, fmt.Errorf("blob: bad s3 endpoint %q", cfg.Endpoint)
+	}
+	return &S3Store{cfg: cfg, endpoint: ep, now: time.Now}, nil
+}
+
+// Put spools r to a temporary file to learn its digest, which is both the
+// key and the payload hash S3 verifies, then uploads it unless the bucket
+// already holds it.
+func (s *S3Store) Put(ctx context.Context, r io.Reader) (Info, error) {
+	tmp, err := os.CreateTemp("", "blob-put-*")
+	if err != nil {
+		return Info{}, fmt.Errorf("blob: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tmp, h), r)
+	if err != nil {
+		return Info{}, fmt.Errorf("blob: spool: %w", err)
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	if info, err := s.Stat(ctx, sum); err == nil {
+		return info, nil
+	} else if !errors.Is(err, ErrNotFound) {
+		return Info{}, err
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return Info{}, fmt.Errorf("blob: %w", err)
+	}
+	req, err := s.request(ctx, http.MethodPut, s.key(sum), nil, tmp, sum)
+	if err != nil {
+		return Info{}, err
+	}
+	req.ContentLength = n
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := s.do(req)
+	if err != nil {
+		return Info{}, err
+	}
+	resp.Body.Close()
+	return Info{SHA256: sum, Size: n, ModTime: s.now()}, nil
+}
+
+func (s *S3Store) Get(ctx context.Context, sum string) (io.ReadCloser, error) {
+	if !validSum(sum) {
+		return nil, ErrNotFound
+	}
+	req, err := s.request(ctx, http.MethodGet, s.key(sum), nil, nil, emptySHA256)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *S3Store) Stat(ctx context.Context, sum string) (Info, error) {
+	if !validSum(sum) {
+		return Info{}, ErrNotFound
+	}
+	req, err := s.request(ctx, http.MethodHead, s.key(sum), nil, nil, emptySHA256)
+	if err != nil {
+		return Info{}, err
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return Info{}, err
+	}
+	resp.Body.Close()
+	info := Info{SHA256: sum, Size: resp.ContentLength}
+	info.ModTime, _ = http.ParseTime(resp.Header.Get("Last-Modified"))
+	return info, nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, sum string) error {
+	if !validSum(sum) {
+		return nil
+	}
+	req, err := s.request(ctx, http.MethodDelete, s.key(sum), nil, nil, emptySHA256)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// listResult is the part of a ListObjectsV2 response the store reads.
+type listResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List pages through ListObjectsV2 under the store's prefix.
+func (s *S3Store) List(ctx context.Context) ([]Info, error) {
+	var out []Info
+	token := ""
+	for {
+		q := url.Values{"list-type": {"2"}}
+		if s.cfg.Prefix != "" {
+			q.Set("prefix", s.cfg.Prefix)
+		}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+		req, err := s.request(ctx, http.MethodGet, "", q, nil, emptySHA256)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := s.do(req)
+		if err != nil {
+			return nil, err
+		}
+		var page listResult
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("blob: decode list: %w", err)
+		}
+		for _, c := range page.Contents {
+			sum := c.Key[strings.LastIndexByte(c.Key, '/')+1:]
+			if validSum(sum) {
+				out = append(out, Info{SHA256: sum, Size: c.Size, ModTime: c.LastModified})
+			}
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return out, nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+func (s *S3Store) key(sum string) string {
+	return s.cfg.Prefix + shardPath(sum)
+}
+
+// request builds a signed request for key ("" for the bucket itself).
+// payloadHash is the hex SHA-256 of body.
+func (s *S3Store) request(ctx context.Context, method, key string, query url.Values, body io.Reader, payloadHash string) (*http.Request, error) {
+	u := *s.endpoint
+	if s.cfg.PathStyle {
+		u.Path = "/" + s.cfg.Bucket + "/" + key
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+		u.Path = "/" + key
+	}
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("blob: %w", err)
+	}
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	signV4(req, payloadHash, s.cfg.AccessKey, s.cfg.SecretKey, s.cfg.Region, s.now())
+	return req, nil
+}
+
+// do sends req, mapping 404 to ErrNotFound and other failures to errors
+// carrying S3's message.
+func (s *S3Store) do(req *http.Request) (*http.Response, error) {
+	resp, err := s.cfg.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("blob: s3 %s: %w", req.Method, err)
+	}
+	if resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("blob: s3 %s %s: status %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(msg)))
+}
+
+// emptySHA256 is the payload hash of a request without a body.
+const emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// signV4 adds the x-amz-date and Authorization headers of AWS Signature
+// Version 4 for the s3 service. It signs the host, Range and x-amz-*
+// headers.
+func signV4(req *http.Request, payloadHash, accessKey, secretKey, region string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	req.Header.Set("x-amz-date", amzDate)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") || lower == "range" {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonHeaders strings.Builder
+	for _, name := range names {
+		canonHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signed := strings.Join(names, ";")
+
+	canonical := strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		canonHeaders.String(),
+		signed,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + region + "/s3/aws4_request"
+	digest := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(digest[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), day)
+	for _, part := range []string{region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	sig := hex.EncodeToString(hmacSHA256(key, toSign))
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKey+"/"+scope+
+		", SignedHeaders="+signed+", Signature="+sig)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(data))
+	return m.Sum(nil)
+}
+
+func canonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		vals := append([]string(nil), q[k]...)
+		sort.Strings(vals)
+		for _, v := range vals {
+			parts = append(parts, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes everything but RFC 3986 unreserved characters
+// (and "/" unless encodeSlash), as Signature V4 requires.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -2,9 +2,11 @@
 package mid
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
@@ -92,6 +94,22 @@ func CORS(origin string) Middleware {
 	}
 }
 
+// BearerToken returns middleware that rejects requests without
+// "Authorization: Bearer <token>". An empty token rejects every request, so
+// protected routes stay closed until a token is configured.
+func BearerToken(token string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if token == "" || !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // OTel returns middleware that creates OpenTelemetry spans for each request.
 func OTel(serviceName string) Middleware {
 	return func(next http.Handler) http.Handler {
@@ -90,3 +90,30 @@ func TestCORSNonOptionsPassesThrough(t *testing.T) {
 		t.Fatal("missing CORS origin header")
 	}
 }
+
+func TestBearerToken(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	tests := []struct {
+		token, header string
+		want          int
+	}{
+		{"s3cret", "Bearer s3cret", http.StatusOK},
+		{"s3cret", "Bearer wrong", http.StatusUnauthorized},
+		{"s3cret", "s3cret", http.StatusUnauthorized},
+		{"s3cret", "", http.StatusUnauthorized},
+		{"", "Bearer ", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/", nil)
+		if tt.header != "" {
+			req.Header.Set("Authorization", tt.header)
+		}
+		rec := httptest.NewRecorder()
+		BearerToken(tt.token)(ok).ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("token %q header %q: got %d, want %d", tt.token, tt.header, rec.Code, tt.want)
+		}
+	}
+}
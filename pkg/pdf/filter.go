@@ -0,0 +1,265 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"fmt"
+	"io"
+)
+
+// decodeStream applies the stream's filters in order.
+func (r *Reader) decodeStream(s Stream) ([]byte, error) {
+	data := s.Raw
+	filters := r.Resolve(s.Dict[Name("Filter")])
+	params := r.Resolve(s.Dict[Name("DecodeParms")])
+	if filters == nil {
+		return data, nil
+	}
+
+	var names []Name
+	var parms []Dict
+	switch f := filters.(type) {
+	case Name:
+		names = []Name{f}
+		p, _ := params.(Dict)
+		parms = []Dict{p}
+	case Array:
+		pa, _ := params.(Array)
+		for i, v := range f {
+			n, _ := r.Resolve(v).(Name)
+			names = append(names, n)
+			var p Dict
+			if i < len(pa) {
+				p, _ = r.Resolve(pa[i]).(Dict)
+			}
+			parms = append(parms, p)
+		}
+	}
+
+	var err error
+	for i, name := range names {
+		if data, err = applyFilter(name, data, parms[i]); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+func applyFilter(name Name, data []byte, parms Dict) ([]byte, error) {
+	switch name {
+	case "FlateDecode", "Fl":
+		out, err := inflate(data)
+		if err != nil {
+			return nil, err
+		}
+		return unpredict(out, parms)
+	case "LZWDecode", "LZW":
+		early := 1
+		if v, ok := intVal(parms[Name("EarlyChange")]); ok {
+			early = v
+		}
+		return unpredict(lzwDecode(data, early), parms)
+	case "ASCII85Decode", "A85":
+		return ascii85Decode(data)
+	case "ASCIIHexDecode", "AHx":
+		l := &lexer{data: append(append([]byte{'<'}, data...), '>')}
+		return []byte(l.hexString()), nil
+	case "RunLengthDecode", "RL":
+		return runLengthDecode(data), nil
+	}
+	return nil, fmt.Errorf("pdf: unsupported filter %s", name)
+}
+
+// inflate decompresses zlib data, keeping whatever was recovered from a
+// truncated or checksum-damaged stream.
+func inflate(data []byte) ([]byte, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("pdf: flate: %w", err)
+	}
+	defer zr.Close()
+	out, err := io.ReadAll(zr)
+	if err != nil && len(out) == 0 {
+		return nil, fmt.Errorf("pdf: flate: %w", err)
+	}
+	return out, nil
+}
+
+// unpredict reverses PNG (10-15) and TIFF (2) predictors.
+func unpredict(data []byte, parms Dict) ([]byte, error) {
+	predictor, _ := intVal(parms[Name("Predictor")])
+	if predictor <= 1 {
+		return data, nil
+	}
+	colors, columns, bpc := 1, 1, 8
+	if v, ok := intVal(parms[Name("Colors")]); ok && v > 0 {
+		colors = v
+	}
+	if v, ok := intVal(parms[Name("Columns")]); ok && v > 0 {
+		columns = v
+	}
+	if v, ok := intVal(parms[Name("BitsPerComponent")]); ok && v > 0 {
+		bpc = v
+	}
+	bpp := (colors*bpc + 7) / 8
+	rowLen := (colors*bpc*columns + 7) / 8
+
+	if predictor == 2 {
+		if bpc != 8 {
+			return nil, fmt.Errorf("pdf: unsupported TIFF predictor depth %d", bpc)
+		}
+		out := append([]byte(nil), data...)
+		for row := 0; row+rowLen <= len(out); row += rowLen {
+			for i := bpp; i < rowLen; i++ {
+				out[row+i] += out[row+i-bpp]
+			}
+		}
+		return out, nil
+	}
+
+	var out []byte
+	prev := make([]byte, rowLen)
+	for pos := 0; pos+1+rowLen <= len(data); pos += 1 + rowLen {
+		kind := data[pos]
+		cur := append([]byte(nil), data[pos+1:pos+1+rowLen]...)
+		for i := range cur {
+			var left, upLeft byte
+			if i >= bpp {
+				left, upLeft = cur[i-bpp], prev[i-bpp]
+			}
+			up := prev[i]
+			switch kind {
+			case 1:
+				cur[i] += left
+			case 2:
+				cur[i] += up
+			case 3:
+				cur[i] += byte((int(left) + int(up)) / 2)
+			case 4:
+				cur[i] += paeth(left, up, upLeft)
+			}
+		}
+		out = append(out, cur...)
+		prev = cur
+	}
+	return out, nil
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	switch {
+	case pa <= pb && pa <= pc:
+		return a
+	case pb <= pc:
+		return b
+	}
+	return c
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// lzwDecode implements the PDF LZW variant. early is the EarlyChange
+// parameter: 1 widens codes one entry before the table fills, like TIFF.
+func lzwDecode(data []byte, early int) []byte {
+	const clear, eod = 256, 257
+	var out []byte
+	table := make([][]byte, 258, 4096)
+	reset := func() {
+		table = table[:258]
+		for i := 0; i < 256; i++ {
+			table[i] = []byte{byte(i)}
+		}
+	}
+	reset()
+
+	width := 9
+	var bitBuf uint32
+	bits := 0
+	pos := 0
+	var prev []byte
+	for {
+		for bits < width && pos < len(data) {
+			bitBuf = bitBuf<<8 | uint32(data[pos])
+			pos++
+			bits += 8
+		}
+		if bits < width {
+			return out
+		}
+		code := int(bitBuf>>(bits-width)) & (1<<width - 1)
+		bits -= width
+
+		switch {
+		case code == clear:
+			reset()
+			width = 9
+			prev = nil
+			continue
+		case code == eod:
+			return out
+		}
+
+		var entry []byte
+		switch {
+		case code < len(table):
+			entry = table[code]
+		case code == len(table) && prev != nil:
+			entry = append(append([]byte(nil), prev...), prev[0])
+		default:
+			return out
+		}
+		out = append(out, entry...)
+		if prev != nil && len(table) < 4096 {
+			table = append(table, append(append([]byte(nil), prev...), entry[0]))
+		}
+		prev = entry
+
+		// The encoder is one entry ahead of the table rebuilt here.
+		if n := len(table) + 1 + early; n >= 1<<width && width < 12 {
+			width++
+		}
+	}
+}
+
+func ascii85Decode(data []byte) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	data = bytes.TrimPrefix(data, []byte("<~"))
+	if i := bytes.Index(data, []byte("~>")); i >= 0 {
+		data = data[:i]
+	}
+	out := make([]byte, 4*len(data)+4) // 'z' expands one character to four bytes
+	n, _, err := ascii85.Decode(out, data, true)
+	if err != nil {
+		return nil, fmt.Errorf("pdf: ascii85: %w", err)
+	}
+	return out[:n], nil
+}
+
+func runLengthDecode(data []byte) []byte {
+	var out []byte
+	for i := 0; i < len(data); {
+		n := int(data[i])
+		i++
+		switch {
+		case n == 128:
+			return out
+		case n < 128:
+			end := min(i+n+1, len(data))
+			out = append(out, data[i:end]...)
+			i = end
+		default:
+			if i < len(data) {
+				out = append(out, bytes.Repeat(data[i:i+1], 257-n)...)
+			}
+			i++
+		}
+	}
+	return out
+}
@@ -0,0 +1,438 @@
+package pdf
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// glyph is one decoded character code.
+type glyph struct {
+	code  uint32
+	text  string
+	width float64 // advance in text space units (1/1000 em already applied)
+	space bool    // single-byte code 32, which also receives word spacing
+}
+
+// codeRange is a codespace range from a CMap: codes of n bytes in [lo, hi].
+type codeRange struct {
+	n      int
+	lo, hi uint32
+}
+
+// font maps character codes to text and advance widths.
+type font struct {
+	composite  bool
+	codespace  []codeRange
+	toUnicode  map[uint32]string
+	encoding   [256]string // simple fonts
+	ucs2       bool        // composite font whose CMap yields UCS-2 codes
+	widths     map[uint32]float64
+	defWidth   float64
+	widthScale float64 // glyph space to text space, 0.001 except for Type3
+}
+
+// loadFont builds a font from a /Font resource dictionary.
+func (r *Reader) loadFont(d Dict) *font {
+	f := &font{widths: map[uint32]float64{}, defWidth: 500, widthScale: 0.001}
+	subtype, _ := r.Resolve(d[Name("Subtype")]).(Name)
+
+	if stm, ok := r.Resolve(d[Name("ToUnicode")]).(Stream); ok {
+		if data, err := r.decodeStream(stm); err == nil {
+			f.toUnicode, f.codespace = parseCMap(data)
+		}
+	}
+
+	if subtype == "Type0" {
+		f.composite = true
+		f.defWidth = 1000
+		if enc, ok := r.Resolve(d[Name("Encoding")]).(Name); ok {
+			f.ucs2 = strings.Contains(string(enc), "UCS2") || strings.Contains(string(enc), "UTF16")
+		}
+		if desc, ok := r.Resolve(d[Name("DescendantFonts")]).(Array); ok && len(desc) > 0 {
+			if cid, ok := r.Resolve(desc[0]).(Dict); ok {
+				r.loadCIDWidths(f, cid)
+			}
+		}
+		return f
+	}
+
+	if subtype == "Type3" {
+		if m, ok := r.Resolve(d[Name("FontMatrix")]).(Array); ok && len(m) > 0 {
+			if s, ok := num(r.Resolve(m[0])); ok {
+				f.widthScale = s
+			}
+		}
+	}
+	r.loadEncoding(f, d)
+	first, _ := intVal(r.Resolve(d[Name("FirstChar")]))
+	if ws, ok := r.Resolve(d[Name("Widths")]).(Array); ok {
+		for i, w := range ws {
+			if v, ok := num(r.Resolve(w)); ok {
+				f.widths[uint32(first+i)] = v
+			}
+		}
+		f.defWidth = 0
+	}
+	if fd, ok := r.Resolve(d[Name("FontDescriptor")]).(Dict); ok {
+		if v, ok := num(r.Resolve(fd[Name("MissingWidth")])); ok && v > 0 {
+			f.defWidth = v
+		}
+	}
+	return f
+}
+
+func (r *Reader) loadCIDWidths(f *font, cid Dict) {
+	if v, ok := num(r.Resolve(cid[Name("DW")])); ok {
+		f.defWidth = v
+	}
+	w, _ := r.Resolve(cid[Name("W")]).(Array)
+	for i := 0; i < len(w); {
+		first, ok := intVal(r.Resolve(w[i]))
+		if !ok || i+1 >= len(w) {
+			return
+		}
+		if list, ok := r.Resolve(w[i+1]).(Array); ok {
+			for k, v := range list {
+				if n, ok := num(r.Resolve(v)); ok {
+					f.widths[uint32(first+k)] = n
+				}
+			}
+			i += 2
+			continue
+		}
+		if i+2 >= len(w) {
+			return
+		}
+		last, _ := intVal(r.Resolve(w[i+1]))
+		n, _ := num(r.Resolve(w[i+2]))
+		for c := first; c <= last && c-first < 65536; c++ {
+			f.widths[uint32(c)] = n
+		}
+		i += 3
+	}
+}
+
+// loadEncoding fills the code-to-text table of a simple font from its
+// base encoding and /Differences.
+func (r *Reader) loadEncoding(f *font, d Dict) {
+	base := Name("StandardEncoding")
+	if fd, ok := r.Resolve(d[Name("FontDescriptor")]).(Dict); ok {
+		// Symbolic fonts without an explicit encoding use their built-in one,
+		// which is usually Latin text in practice.
+		if flags, _ := intVal(r.Resolve(fd[Name("Flags")])); flags&4 != 0 {
+			base = "WinAnsiEncoding"
+		}
+	}
+	var diffs Array
+	switch enc := r.Resolve(d[Name("Encoding")]).(type) {
+	case Name:
+		base = enc
+	case Dict:
+		if b, ok := r.Resolve(enc[Name("BaseEncoding")]).(Name); ok {
+			base = b
+		}
+		diffs, _ = r.Resolve(enc[Name("Differences")]).(Array)
+	}
+
+	for c := 0; c < 256; c++ {
+		f.encoding[c] = baseEncoding(base, byte(c))
+	}
+	code := 0
+	for _, v := range diffs {
+		switch t := r.Resolve(v).(type) {
+		case int64, float64:
+			code, _ = intVal(t)
+		case Name:
+			if code >= 0 && code < 256 {
+				if s, ok := glyphText(string(t)); ok {
+					f.encoding[code] = s
+				}
+			}
+			code++
+		}
+	}
+}
+
+func baseEncoding(enc Name, c byte) string {
+	switch enc {
+	case "MacRomanEncoding":
+		if c >= 0x80 {
+			return string(macRomanHigh[c-0x80])
+		}
+	case "WinAnsiEncoding", "PDFDocEncoding":
+		if c >= 0x80 {
+			if r := winAnsiHigh[c-0x80]; r != 0 {
+				return string(r)
+			}
+			return ""
+		}
+	default: // StandardEncoding
+		switch {
+		case c == 0x27:
+			return "’"
+		case c == 0x60:
+			return "‘"
+		case c >= 0x80:
+			return standardHigh[c]
+		}
+	}
+	if c < 0x20 || c == 0x7f {
+		return ""
+	}
+	return string(rune(c))
+}
+
+// standardHigh holds the StandardEncoding codes above 0x7F.
+var standardHigh = map[byte]string{
+	0xa1: "¡", 0xa2: "¢", 0xa3: "£", 0xa4: "⁄", 0xa5: "¥", 0xa6: "ƒ", 0xa7: "§",
+	0xa8: "¤", 0xa9: "'", 0xaa: "“", 0xab: "«", 0xac: "‹", 0xad: "›", 0xae: "fi",
+	0xaf: "fl", 0xb1: "–", 0xb2: "†", 0xb3: "‡", 0xb4: "·", 0xb6: "¶", 0xb7: "•",
+	0xb8: "‚", 0xb9: "„", 0xba: "”", 0xbb: "»", 0xbc: "…", 0xbd: "‰", 0xbf: "¿",
+	0xc1: "`", 0xc2: "´", 0xc3: "ˆ", 0xc4: "˜", 0xc5: "¯", 0xc6: "˘", 0xc7: "˙",
+	0xc8: "¨", 0xca: "˚", 0xcb: "¸", 0xcd: "˝", 0xce: "˛", 0xcf: "ˇ", 0xd0: "—",
+	0xe1: "Æ", 0xe3: "ª", 0xe8: "Ł", 0xe9: "Ø", 0xea: "Œ", 0xeb: "º", 0xf1: "æ",
+	0xf5: "ı", 0xf8: "ł", 0xf9: "ø", 0xfa: "œ", 0xfb: "ß",
+}
+
+// namedGlyphs maps common Adobe glyph names that are not single letters.
+var namedGlyphs = map[string]string{
+	"space": " ", "exclam": "!", "quotedbl": "\"", "numbersign": "#", "dollar": "$",
+	"percent": "%", "ampersand": "&", "quotesingle": "'", "quoteright": "’",
+	"quoteleft": "‘", "parenleft": "(", "parenright": ")", "asterisk": "*",
+	"plus": "+", "comma": ",", "hyphen": "-", "minus": "−", "period": ".",
+	"slash": "/", "colon": ":", "semicolon": ";", "less": "<", "equal": "=",
+	"greater": ">", "question": "?", "at": "@", "bracketleft": "[",
+	"backslash": "\\", "bracketright": "]", "asciicircum": "^", "underscore": "_",
+	"grave": "`", "braceleft": "{", "bar": "|", "braceright": "}", "asciitilde": "~",
+	"zero": "0", "one": "1", "two": "2", "three": "3", "four": "4", "five": "5",
+	"six": "6", "seven": "7", "eight": "8", "nine": "9",
+	"bullet": "•", "endash": "–", "emdash": "—", "ellipsis": "…",
+	"quotedblleft": "“", "quotedblright": "”", "quotesinglbase": "‚",
+	"quotedblbase": "„", "degree": "°", "plusminus": "±", "multiply": "×",
+	"divide": "÷", "copyright": "©", "registered": "®", "trademark": "™",
+	"section": "§", "paragraph": "¶", "mu": "µ", "periodcentered": "·",
+	"onehalf": "½", "onequarter": "¼", "threequarters": "¾", "fi": "fi",
+	"fl": "fl", "ff": "ff", "ffi": "ffi", "ffl": "ffl", "germandbls": "ß",
+	"ae": "æ", "AE": "Æ", "oe": "œ", "OE": "Œ", "dotlessi": "ı",
+	"nbspace": " ", "uni00A0": " ", "arrowright": "→", "arrowleft": "←",
+	"lessequal": "≤", "greaterequal": "≥", "notequal": "≠", "infinity": "∞",
+	"dagger": "†", "daggerdbl": "‡", "Euro": "€", "sterling": "£", "yen": "¥",
+	"cent": "¢", "florin": "ƒ", "perthousand": "‰", "guillemotleft": "«",
+	"guillemotright": "»", "exclamdown": "¡", "questiondown": "¿",
+}
+
+// glyphText returns the text of an Adobe glyph name, handling uniXXXX,
+// uXXXX[XX], ligature (f_i) and suffixed (a.sc) names.
+func glyphText(name string) (string, bool) {
+	if i := strings.IndexByte(name, '.'); i > 0 {
+		name = name[:i]
+	}
+	if strings.Contains(name, "_") {
+		var b strings.Builder
+		for _, part := range strings.Split(name, "_") {
+			s, ok := glyphText(part)
+			if !ok {
+				return "", false
+			}
+			b.WriteString(s)
+		}
+		return b.String(), true
+	}
+	if s, ok := namedGlyphs[name]; ok {
+		return s, true
+	}
+	if r, ok := accentedGlyphs[name]; ok {
+		return string(r), true
+	}
+	if len(name) == 1 && (name[0] >= 'a' && name[0] <= 'z' || name[0] >= 'A' && name[0] <= 'Z') {
+		return name, true
+	}
+	if strings.HasPrefix(name, "uni") && len(name) >= 7 && (len(name)-3)%4 == 0 {
+		var b strings.Builder
+		for i := 3; i < len(name); i += 4 {
+			v, err := strconv.ParseUint(name[i:i+4], 16, 16)
+			if err != nil {
+				return "", false
+			}
+			b.WriteRune(rune(v))
+		}
+		return b.String(), true
+	}
+	if strings.HasPrefix(name, "u") && len(name) >= 5 && len(name) <= 7 {
+		if v, err := strconv.ParseUint(name[1:], 16, 32); err == nil {
+			return string(rune(v)), true
+		}
+	}
+	return "", false
+}
+
+// decode splits a shown string into glyphs.
+func (f *font) decode(s []byte) []glyph {
+	var out []glyph
+	for i := 0; i < len(s); {
+		n := f.codeLen(s[i:])
+		var code uint32
+		for _, b := range s[i : i+n] {
+			code = code<<8 | uint32(b)
+		}
+		i += n
+
+		g := glyph{code: code, space: n == 1 && code == 32}
+		if t, ok := f.toUnicode[code]; ok {
+			g.text = t
+		} else if !f.composite {
+			g.text = f.encoding[code]
+		} else if f.ucs2 {
+			g.text = string(rune(code))
+		}
+		w, ok := f.widths[code]
+		if !ok {
+			w = f.defWidth
+		}
+		g.width = w * f.widthScale
+		out = append(out, g)
+	}
+	return out
+}
+
+// codeLen returns the byte length of the code starting at s.
+func (f *font) codeLen(s []byte) int {
+	for _, cr := range f.codespace {
+		if cr.n > len(s) {
+			continue
+		}
+		var code uint32
+		for _, b := range s[:cr.n] {
+			code = code<<8 | uint32(b)
+		}
+		if code >= cr.lo && code <= cr.hi {
+			return cr.n
+		}
+	}
+	if f.composite && len(s) >= 2 {
+		return 2
+	}
+	return 1
+}
+
+// parseCMap reads the bfchar/bfrange mappings and codespace ranges of a
+// ToUnicode CMap.
+func parseCMap(data []byte) (map[uint32]string, []codeRange) {
+	m := map[uint32]string{}
+	var space []codeRange
+	l := &lexer{data: data}
+	var ops []any
+	mode := ""
+	for {
+		tok, ok := l.token()
+		if !ok {
+			break
+		}
+		if tok == keyword("[") {
+			arr, _ := l.objectFrom(tok)
+			ops = append(ops, arr)
+			continue
+		}
+		kw, isKw := tok.(keyword)
+		if !isKw {
+			ops = append(ops, tok)
+			continue
+		}
+		switch kw {
+		case "begincodespacerange", "beginbfchar", "beginbfrange":
+			mode = string(kw)
+			ops = ops[:0]
+			continue
+		case "endcodespacerange":
+			for i := 0; i+1 < len(ops); i += 2 {
+				lo, ok1 := ops[i].(String)
+				hi, ok2 := ops[i+1].(String)
+				if ok1 && ok2 && len(lo) > 0 && len(lo) == len(hi) {
+					space = append(space, codeRange{n: len(lo), lo: codeOf(lo), hi: codeOf(hi)})
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(ops); i += 2 {
+				src, ok := ops[i].(String)
+				if !ok {
+					continue
+				}
+				switch dst := ops[i+1].(type) {
+				case String:
+					m[codeOf(src)] = utf16Text(dst)
+				case Name:
+					if s, ok := glyphText(string(dst)); ok {
+						m[codeOf(src)] = s
+					}
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(ops); i += 3 {
+				lo, ok1 := ops[i].(String)
+				hi, ok2 := ops[i+1].(String)
+				if !ok1 || !ok2 {
+					continue
+				}
+				start, end := codeOf(lo), codeOf(hi)
+				if end < start || end-start > 65535 {
+					continue
+				}
+				switch dst := ops[i+2].(type) {
+				case String:
+					for c := start; c <= end; c++ {
+						m[c] = utf16Text(incrementLast(dst, int(c-start)))
+					}
+				case Array:
+					for k, v := range dst {
+						if s, ok := v.(String); ok && start+uint32(k) <= end {
+							m[start+uint32(k)] = utf16Text(s)
+						}
+					}
+				}
+			}
+		}
+		if strings.HasPrefix(string(kw), "end") {
+			mode = ""
+		}
+		if mode == "" {
+			ops = ops[:0]
+		}
+	}
+	return m, space
+}
+
+func codeOf(s String) uint32 {
+	var c uint32
+	for i := 0; i < len(s); i++ {
+		c = c<<8 | uint32(s[i])
+	}
+	return c
+}
+
+// incrementLast adds n to the last byte of a bfrange destination, carrying
+// into the byte before it.
+func incrementLast(s String, n int) String {
+	b := []byte(s)
+	if len(b) == 0 {
+		return s
+	}
+	v := int(b[len(b)-1]) + n
+	b[len(b)-1] = byte(v)
+	if len(b) > 1 {
+		b[len(b)-2] += byte(v >> 8)
+	}
+	return String(b)
+}
+
+// utf16Text decodes a big-endian UTF-16 destination string.
+func utf16Text(s String) string {
+	if len(s) == 1 {
+		return string(rune(s[0]))
+	}
+	u := make([]uint16, 0, len(s)/2)
+	for i := 0; i+1 < len(s); i += 2 {
+		u = append(u, uint16(s[i])<<8|uint16(s[i+1]))
+	}
+	return string(utf16.Decode(u))
+}
@@ -0,0 +1,369 @@
+// Package pdf is a small pure-Go PDF reader for text extraction. It parses
+// classic and stream cross-reference tables, object streams, the common
+// stream filters and font encodings, and returns positioned text runs per
+// page. No external dependencies.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// Name is a PDF name object without its leading slash.
+type Name string
+
+// Ref is an indirect object reference.
+type Ref struct {
+	Num, Gen int
+}
+
+// Dict is a PDF dictionary.
+type Dict map[Name]any
+
+// Array is a PDF array.
+type Array []any
+
+// String is a PDF literal or hex string, kept as raw bytes.
+type String string
+
+// Stream is a PDF stream with its undecoded data.
+type Stream struct {
+	Dict Dict
+	Raw  []byte
+}
+
+// keyword is a bare token such as obj, R or a content stream operator.
+type keyword string
+
+// lexer tokenizes PDF object syntax and content streams.
+type lexer struct {
+	data []byte
+	pos  int
+}
+
+func isSpace(b byte) bool {
+	switch b {
+	case ' ', '\t', '\r', '\n', '\f', 0:
+		return true
+	}
+	return false
+}
+
+func isDelim(b byte) bool {
+	switch b {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+// skipSpace skips whitespace and comments.
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isSpace(c) {
+			l.pos++
+			continue
+		}
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		return
+	}
+}
+
+// token returns the next token: a Name, String, number (int64 or float64),
+// bool, nil, keyword, or one of the delimiter keywords "[", "]", "<<", ">>".
+// ok is false at end of input.
+func (l *lexer) token() (tok any, ok bool) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, false
+	}
+	c := l.data[l.pos]
+	switch c {
+	case '/':
+		l.pos++
+		start := l.pos
+		for l.pos < len(l.data) && !isSpace(l.data[l.pos]) && !isDelim(l.data[l.pos]) {
+			l.pos++
+		}
+		return Name(unescapeName(l.data[start:l.pos])), true
+	case '(':
+		return l.literalString(), true
+	case '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			l.pos += 2
+			return keyword("<<"), true
+		}
+		return l.hexString(), true
+	case '>':
+		l.pos++
+		if l.pos < len(l.data) && l.data[l.pos] == '>' {
+			l.pos++
+		}
+		return keyword(">>"), true
+	case '[', ']', '{', '}':
+		l.pos++
+		return keyword(string(c)), true
+	case ')':
+		l.pos++
+		return l.token()
+	}
+
+	start := l.pos
+	for l.pos < len(l.data) && !isSpace(l.data[l.pos]) && !isDelim(l.data[l.pos]) {
+		l.pos++
+	}
+	word := string(l.data[start:l.pos])
+	if n, ok := parseNumber(word); ok {
+		return n, true
+	}
+	switch word {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	case "null":
+		return nil, true
+	}
+	return keyword(word), true
+}
+
+func parseNumber(s string) (any, bool) {
+	if s == "" {
+		return nil, false
+	}
+	c := s[0]
+	if !(c >= '0' && c <= '9') && c != '-' && c != '+' && c != '.' {
+		return nil, false
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i, true
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f, true
+	}
+	// Tolerate malformed reals such as "--1" or "1.2.3" written by some producers.
+	if f, err := strconv.ParseFloat(fixNumber(s), 64); err == nil {
+		return f, true
+	}
+	return nil, false
+}
+
+func fixNumber(s string) string {
+	neg := false
+	for len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		neg = neg || s[0] == '-'
+		s = s[1:]
+	}
+	if i := bytes.IndexByte([]byte(s), '.'); i >= 0 {
+		s = s[:i+1] + string(bytes.ReplaceAll([]byte(s[i+1:]), []byte("."), nil))
+	}
+	if neg {
+		return "-" + s
+	}
+	return s
+}
+
+func unescapeName(b []byte) string {
+	if bytes.IndexByte(b, '#') < 0 {
+		return string(b)
+	}
+	var out []byte
+	for i := 0; i < len(b); i++ {
+		if b[i] == '#' && i+2 < len(b) {
+			if v, err := strconv.ParseUint(string(b[i+1:i+3]), 16, 8); err == nil {
+				out = append(out, byte(v))
+				i += 2
+				continue
+			}
+		}
+		out = append(out, b[i])
+	}
+	return string(out)
+}
+
+func (l *lexer) literalString() String {
+	l.pos++ // (
+	var out []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return String(out)
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return String(out)
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b':
+				out = append(out, '\b')
+			case 'f':
+				out = append(out, '\f')
+			case '\r':
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for k := 0; k < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; k++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					out = append(out, byte(v))
+				} else {
+					out = append(out, e)
+				}
+			}
+			continue
+		}
+		out = append(out, c)
+	}
+	return String(out)
+}
+
+func (l *lexer) hexString() String {
+	l.pos++ // <
+	var out []byte
+	var hi byte
+	half := false
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		if c == '>' {
+			break
+		}
+		v, ok := hexVal(c)
+		if !ok {
+			continue
+		}
+		if half {
+			out = append(out, hi<<4|v)
+		} else {
+			hi = v
+		}
+		half = !half
+	}
+	if half {
+		out = append(out, hi<<4)
+	}
+	return String(out)
+}
+
+func hexVal(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// object parses one complete object, folding "n g R" into a Ref.
+func (l *lexer) object() (any, error) {
+	tok, ok := l.token()
+	if !ok {
+		return nil, fmt.Errorf("pdf: unexpected end of data")
+	}
+	return l.objectFrom(tok)
+}
+
+func (l *lexer) objectFrom(tok any) (any, error) {
+	switch t := tok.(type) {
+	case keyword:
+		switch t {
+		case "[":
+			var arr Array
+			for {
+				next, ok := l.token()
+				if !ok {
+					return arr, fmt.Errorf("pdf: unterminated array")
+				}
+				if next == keyword("]") {
+					return arr, nil
+				}
+				v, err := l.objectFrom(next)
+				if err != nil {
+					return arr, err
+				}
+				arr = append(arr, v)
+			}
+		case "<<":
+			d := Dict{}
+			for {
+				next, ok := l.token()
+				if !ok {
+					return d, fmt.Errorf("pdf: unterminated dictionary")
+				}
+				if next == keyword(">>") {
+					return d, nil
+				}
+				key, isName := next.(Name)
+				if !isName {
+					continue
+				}
+				v, err := l.object()
+				if err != nil {
+					return d, err
+				}
+				d[key] = v
+			}
+		}
+		return t, nil
+	case int64:
+		// Look ahead for "gen R".
+		save := l.pos
+		if gen, ok := l.token(); ok {
+			if g, isInt := gen.(int64); isInt {
+				if r, ok := l.token(); ok && r == keyword("R") {
+					return Ref{Num: int(t), Gen: int(g)}, nil
+				}
+			}
+		}
+		l.pos = save
+		return t, nil
+	}
+	return tok, nil
+}
+
+// num converts a PDF number to float64.
+func num(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// intVal converts a PDF number to int.
+func intVal(v any) (int, bool) {
+	f, ok := num(v)
+	return int(f), ok
+}
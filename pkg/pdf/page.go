@@ -0,0 +1,365 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// TextRun is a piece of text shown with one font along one baseline.
+type TextRun struct {
+	Text string
+	X, Y float64 // start of the baseline in page space, origin bottom-left
+	W    float64 // advance width in page space
+	Size float64 // font size in page space
+}
+
+// Page is the text content of one page.
+type Page struct {
+	Number        int // 1-based
+	Width, Height float64
+	Runs          []TextRun // in content stream order
+}
+
+// Page returns the text runs of page n (1-based).
+func (r *Reader) Page(n int) (Page, error) {
+	if n < 1 || n > len(r.pages) {
+		return Page{}, fmt.Errorf("pdf: page %d out of range (1-%d)", n, len(r.pages))
+	}
+	d := r.pages[n-1]
+	p := Page{Number: n}
+	if box, ok := r.Resolve(d[Name("MediaBox")]).(Array); ok && len(box) == 4 {
+		x0, _ := num(r.Resolve(box[0]))
+		y0, _ := num(r.Resolve(box[1]))
+		x1, _ := num(r.Resolve(box[2]))
+		y1, _ := num(r.Resolve(box[3]))
+		p.Width, p.Height = math.Abs(x1-x0), math.Abs(y1-y0)
+	}
+
+	var content []byte
+	switch c := r.Resolve(d[Name("Contents")]).(type) {
+	case Stream:
+		content, _ = r.decodeStream(c)
+	case Array:
+		// Operators may span stream boundaries, so the parts are concatenated.
+		for _, part := range c {
+			if s, ok := r.Resolve(part).(Stream); ok {
+				if data, err := r.decodeStream(s); err == nil {
+					content = append(append(content, data...), '\n')
+				}
+			}
+		}
+	}
+	res, _ := r.Resolve(d[Name("Resources")]).(Dict)
+	in := &interp{r: r}
+	in.run(content, res, identity, 0)
+	p.Runs = in.runs
+	return p, nil
+}
+
+// Pages returns the text runs of every page.
+func (r *Reader) Pages() []Page {
+	pages := make([]Page, 0, len(r.pages))
+	for i := range r.pages {
+		p, _ := r.Page(i + 1)
+		pages = append(pages, p)
+	}
+	return pages
+}
+
+// ExtractPages opens data and returns the text runs of every page.
+func ExtractPages(data []byte) ([]Page, error) {
+	r, err := Open(data)
+	if err != nil {
+		return nil, err
+	}
+	return r.Pages(), nil
+}
+
+// Text joins the page's runs into lines. Runs on the same baseline are
+// separated by a space when the gap between them is wider than a fraction
+// of the font size; a baseline change starts a new line.
+func (p Page) Text() string {
+	var b strings.Builder
+	var prev *TextRun
+	for i := range p.Runs {
+		run := &p.Runs[i]
+		if prev != nil {
+			size := math.Max(math.Max(prev.Size, run.Size), 1)
+			// Fake bold draws the same text twice with a tiny offset.
+			if run.Text == prev.Text && math.Abs(run.X-prev.X) < size*0.2 && math.Abs(run.Y-prev.Y) < size*0.2 {
+				continue
+			}
+			switch {
+			case math.Abs(run.Y-prev.Y) > size*0.5:
+				b.WriteByte('\n')
+			case run.X-(prev.X+prev.W) > size*wordGap && !endsSpace(b.String()) && !strings.HasPrefix(run.Text, " "):
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(run.Text)
+		prev = run
+	}
+	lines := strings.Split(b.String(), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " ")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// wordGap is the gap, in ems, that separates words within a line or a TJ array.
+const wordGap = 0.15
+
+// splitGap is the TJ gap, in ems, that starts a new run, typically a table column.
+const splitGap = 1.0
+
+func endsSpace(s string) bool {
+	return s == "" || strings.HasSuffix(s, " ") || strings.HasSuffix(s, "\n")
+}
+
+// matrix is an affine transform [a b c d e f].
+type matrix [6]float64
+
+var identity = matrix{1, 0, 0, 1, 0, 0}
+
+func (m matrix) mul(n matrix) matrix {
+	return matrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+type gstate struct {
+	ctm                      matrix
+	font                     *font
+	size, tc, tw, th, tl, ts float64
+}
+
+// interp executes content streams and records text runs.
+type interp struct {
+	r    *Reader
+	runs []TextRun
+}
+
+func (in *interp) font(res Dict, name Name) *font {
+	fonts, _ := in.r.Resolve(res[Name("Font")]).(Dict)
+	v := fonts[name]
+	ref, isRef := v.(Ref)
+	if f, ok := in.r.fonts[ref]; isRef && ok {
+		return f
+	}
+	d, ok := in.r.Resolve(v).(Dict)
+	if !ok {
+		return nil
+	}
+	f := in.r.loadFont(d)
+	if isRef {
+		in.r.fonts[ref] = f
+	}
+	return f
+}
+
+func (in *interp) run(content []byte, res Dict, ctm matrix, depth int) {
+	gs := gstate{ctm: ctm, th: 1}
+	var stack []gstate
+	var tm, tlm matrix
+	var ops []any
+	l := &lexer{data: content}
+
+	show := func(items Array) {
+		if gs.font == nil {
+			return
+		}
+		var run *TextRun
+		var text strings.Builder
+		flush := func() {
+			if run != nil {
+				run.Text = text.String()
+				end := matrix{1, 0, 0, 1, 0, gs.ts}.mul(tm).mul(gs.ctm)
+				run.W = math.Hypot(end[4]-run.X, end[5]-run.Y)
+				if strings.TrimSpace(run.Text) != "" {
+					in.runs = append(in.runs, *run)
+				}
+			}
+			run = nil
+			text.Reset()
+		}
+		for _, item := range items {
+			if adj, ok := num(item); ok {
+				if gap := -adj / 1000; gap >= splitGap {
+					flush()
+				} else if gap >= wordGap && run != nil && !strings.HasSuffix(text.String(), " ") {
+					text.WriteByte(' ')
+				}
+				tm = matrix{1, 0, 0, 1, -adj / 1000 * gs.size * gs.th, 0}.mul(tm)
+				continue
+			}
+			s, ok := item.(String)
+			if !ok {
+				continue
+			}
+			for _, g := range gs.font.decode([]byte(s)) {
+				if run == nil {
+					trm := matrix{gs.size * gs.th, 0, 0, gs.size, 0, gs.ts}.mul(tm).mul(gs.ctm)
+					run = &TextRun{X: trm[4], Y: trm[5], Size: math.Hypot(trm[2], trm[3])}
+				}
+				text.WriteString(g.text)
+				tx := g.width*gs.size + gs.tc
+				if g.space {
+					tx += gs.tw
+				}
+				tm = matrix{1, 0, 0, 1, tx * gs.th, 0}.mul(tm)
+			}
+		}
+		flush()
+	}
+
+	for {
+		tok, ok := l.token()
+		if !ok {
+			return
+		}
+		kw, isOp := tok.(keyword)
+		if !isOp || kw == "[" || kw == "<<" {
+			v, err := l.objectFrom(tok)
+			if err != nil {
+				return
+			}
+			ops = append(ops, v)
+			continue
+		}
+		arg := func(i int) float64 {
+			if i < len(ops) {
+				v, _ := num(ops[i])
+				return v
+			}
+			return 0
+		}
+
+		switch kw {
+		case "q":
+			stack = append(stack, gs)
+		case "Q":
+			if n := len(stack); n > 0 {
+				gs, stack = stack[n-1], stack[:n-1]
+			}
+		case "cm":
+			if len(ops) == 6 {
+				gs.ctm = matrix{arg(0), arg(1), arg(2), arg(3), arg(4), arg(5)}.mul(gs.ctm)
+			}
+		case "BT":
+			tm, tlm = identity, identity
+		case "Tf":
+			if len(ops) == 2 {
+				name, _ := ops[0].(Name)
+				gs.font = in.font(res, name)
+				gs.size = arg(1)
+			}
+		case "Tc":
+			gs.tc = arg(0)
+		case "Tw":
+			gs.tw = arg(0)
+		case "Tz":
+			gs.th = arg(0) / 100
+		case "TL":
+			gs.tl = arg(0)
+		case "Ts":
+			gs.ts = arg(0)
+		case "Td", "TD":
+			if kw == "TD" {
+				gs.tl = -arg(1)
+			}
+			tlm = matrix{1, 0, 0, 1, arg(0), arg(1)}.mul(tlm)
+			tm = tlm
+		case "Tm":
+			if len(ops) == 6 {
+				tlm = matrix{arg(0), arg(1), arg(2), arg(3), arg(4), arg(5)}
+				tm = tlm
+			}
+		case "T*":
+			tlm = matrix{1, 0, 0, 1, 0, -gs.tl}.mul(tlm)
+			tm = tlm
+		case "Tj":
+			if len(ops) > 0 {
+				show(Array{ops[len(ops)-1]})
+			}
+		case "TJ":
+			if len(ops) > 0 {
+				arr, _ := ops[len(ops)-1].(Array)
+				show(arr)
+			}
+		case "'", "\"":
+			if kw == "\"" && len(ops) == 3 {
+				gs.tw, gs.tc = arg(0), arg(1)
+			}
+			tlm = matrix{1, 0, 0, 1, 0, -gs.tl}.mul(tlm)
+			tm = tlm
+			if len(ops) > 0 {
+				show(Array{ops[len(ops)-1]})
+			}
+		case "Do":
+			if len(ops) == 1 && depth < 8 {
+				name, _ := ops[0].(Name)
+				in.form(res, name, gs.ctm, depth)
+			}
+		case "BI":
+			skipInlineImage(l)
+		}
+		ops = ops[:0]
+	}
+}
+
+// form executes a Form XObject.
+func (in *interp) form(res Dict, name Name, ctm matrix, depth int) {
+	xobjs, _ := in.r.Resolve(res[Name("XObject")]).(Dict)
+	stm, ok := in.r.Resolve(xobjs[name]).(Stream)
+	if !ok || stm.Dict[Name("Subtype")] != Name("Form") {
+		return
+	}
+	data, err := in.r.decodeStream(stm)
+	if err != nil {
+		return
+	}
+	if m, ok := in.r.Resolve(stm.Dict[Name("Matrix")]).(Array); ok && len(m) == 6 {
+		var fm matrix
+		for i := range fm {
+			fm[i], _ = num(in.r.Resolve(m[i]))
+		}
+		ctm = fm.mul(ctm)
+	}
+	if fres, ok := in.r.Resolve(stm.Dict[Name("Resources")]).(Dict); ok {
+		res = fres
+	}
+	in.run(data, res, ctm, depth+1)
+}
+
+// skipInlineImage moves the lexer past the binary data of an inline image.
+func skipInlineImage(l *lexer) {
+	for {
+		tok, ok := l.token()
+		if !ok {
+			return
+		}
+		if tok == keyword("ID") {
+			break
+		}
+	}
+	l.pos++ // single whitespace after ID
+	for l.pos < len(l.data) {
+		i := bytes.Index(l.data[l.pos:], []byte("EI"))
+		if i < 0 {
+			l.pos = len(l.data)
+			return
+		}
+		at := l.pos + i
+		l.pos = at + 2
+		if at > 0 && isSpace(l.data[at-1]) && (l.pos >= len(l.data) || isSpace(l.data[l.pos])) {
+			return
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// buildPDF writes objs as objects 1..n with a classic xref table. Object 1
+// must be the catalog.
+func buildPDF(objs ...string) []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objs))
+	for i, o := range objs {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, xref)
+	return b.Bytes()
+}
+
+func deflate(s string) []byte {
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	w.Write([]byte(s))
+	w.Close()
+	return b.Bytes()
+}
+
+func stream(dict string, data []byte) string {
+	return fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+}
+
+const helvetica = "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>"
+
+func twoPageDoc() []byte {
+	page1 := "BT /F1 12 Tf 72 720 Td (ENGINE) Tj 0 -14 Td [(Check the ) -50 (oil)] TJ ET"
+	page2 := "BT /F1 12 Tf 72 720 Td (Page two text) Tj ET"
+	return buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 /Resources << /Font << /F1 7 0 R >> >> /MediaBox [0 0 612 792] >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 5 0 R >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 6 0 R >>",
+		stream("/Filter /FlateDecode", deflate(page1)),
+		stream("", []byte(page2)),
+		helvetica,
+	)
+}
+
+func TestExtractPages_FlateAndInheritedResources(t *testing.T) {
+	pages, err := ExtractPages(twoPageDoc())
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("expected 2 pages, got %d", len(pages))
+	}
+	if got := pages[0].Text(); got != "ENGINE\nCheck the oil" {
+		t.Fatalf("page 1 text = %q", got)
+	}
+	if pages[1].Number != 2 || pages[1].Text() != "Page two text" {
+		t.Fatalf("page 2 = %d %q", pages[1].Number, pages[1].Text())
+	}
+	if pages[0].Width != 612 || pages[0].Height != 792 {
+		t.Fatalf("media box not inherited: %vx%v", pages[0].Width, pages[0].Height)
+	}
+	run := pages[0].Runs[0]
+	if run.X != 72 || run.Y != 720 || run.Size != 12 {
+		t.Fatalf("unexpected run position %+v", run)
+	}
+}
+
+func TestTJKerningSpacing(t *testing.T) {
+	content := "BT /F1 10 Tf 0 0 Td [(Fu) 20 (el) -300 (pump) -2000 (12) (V)] TJ ET"
+	doc := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		stream("", []byte(content)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /FirstChar 32 /LastChar 126 /Widths ["+strings.Repeat("600 ", 95)+"] >>",
+	)
+	pages, err := ExtractPages(doc)
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	runs := pages[0].Runs
+	if len(runs) != 2 || runs[0].Text != "Fuel pump" || runs[1].Text != "12V" {
+		t.Fatalf("unexpected runs %+v", runs)
+	}
+	// "Fuelpump" is 8 glyphs of 6pt plus the 0.3em gap, less the 0.02em kern.
+	if want := 8*6.0 + 3 - 0.2; runs[0].W < want-0.01 || runs[0].W > want+0.01 {
+		t.Fatalf("run width = %v, want %v", runs[0].W, want)
+	}
+	if got := pages[0].Text(); got != "Fuel pump 12V" {
+		t.Fatalf("text = %q", got)
+	}
+}
+
+func TestToUnicodeType0(t *testing.T) {
+	cmap := `/CIDInit /ProcSet findresource begin
+12 dict begin begincmap
+1 begincodespacerange <0000> <FFFF> endcodespacerange
+2 beginbfchar <0003> <0020> <0010> <00660069> endbfchar
+1 beginbfrange <0024> <0026> <0041> endbfrange
+1 beginbfrange <0030> <0031> [<00E9> <2013>] endbfrange
+endcmap end end`
+	content := "BT /F0 9 Tf 10 10 Td <0024002500260003001000030030 0031> Tj ET"
+	doc := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R /Resources << /Font << /F0 5 0 R >> >> >>",
+		stream("/Filter /FlateDecode", deflate(content)),
+		"<< /Type /Font /Subtype /Type0 /BaseFont /ABC+Arial /Encoding /Identity-H /DescendantFonts [6 0 R] /ToUnicode 7 0 R >>",
+		"<< /Type /Font /Subtype /CIDFontType2 /DW 500 /W [3 [250] 16 16 600] >>",
+		stream("/Filter /FlateDecode", deflate(cmap)),
+	)
+	pages, err := ExtractPages(doc)
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	if got := pages[0].Text(); got != "ABC fi é–" {
+		t.Fatalf("text = %q", got)
+	}
+}
+
+func TestDifferencesEncoding(t *testing.T) {
+	font := "<< /Type /Font /Subtype /Type1 /Encoding << /BaseEncoding /WinAnsiEncoding /Differences [65 /eacute /uni2022 /f_l] >> >>"
+	doc := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		stream("", []byte(`BT /F1 10 Tf (ABC\223x\224) Tj ET`)),
+		font,
+	)
+	pages, err := ExtractPages(doc)
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	if got := pages[0].Text(); got != "é•fl“x”" {
+		t.Fatalf("text = %q", got)
+	}
+}
+
+// TestXrefStreamAndObjectStream builds a PDF 1.5 file whose objects live in a
+// compressed object stream, indexed by a PNG-predicted xref stream.
+func TestXrefStreamAndObjectStream(t *testing.T) {
+	objs := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 5 0 R /Resources << /Font << /F1 4 0 R >> >> >>",
+		helvetica,
+	}
+	var header, body bytes.Buffer
+	for i, o := range objs {
+		fmt.Fprintf(&header, "%d %d ", i+1, body.Len())
+		body.WriteString(o + "\n")
+	}
+	objStm := header.String() + body.String()
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.5\n")
+	off5 := b.Len()
+	fmt.Fprintf(&b, "5 0 obj\n%s\nendobj\n", stream("", []byte("BT /F1 11 Tf 50 50 Td (Compressed objects) Tj ET")))
+	off6 := b.Len()
+	fmt.Fprintf(&b, "6 0 obj\n%s\nendobj\n", stream(fmt.Sprintf("/Type /ObjStm /N 4 /First %d /Filter /FlateDecode", header.Len()), deflate(objStm)))
+	off7 := b.Len()
+
+	// Rows: type (1 byte), offset or stream number (2 bytes), index (1 byte).
+	rows := [][4]byte{{0, 0, 0, 0}}
+	for i := range objs {
+		rows = append(rows, [4]byte{2, 0, 6, byte(i)})
+	}
+	rows = append(rows,
+		[4]byte{1, byte(off5 >> 8), byte(off5), 0},
+		[4]byte{1, byte(off6 >> 8), byte(off6), 0},
+		[4]byte{1, byte(off7 >> 8), byte(off7), 0},
+	)
+	var raw []byte
+	prev := [4]byte{}
+	for _, row := range rows {
+		raw = append(raw, 2) // PNG Up
+		for i := range row {
+			raw = append(raw, row[i]-prev[i])
+		}
+		prev = row
+	}
+	xref := stream("/Type /XRef /Size 8 /W [1 2 1] /Root 1 0 R /Filter /FlateDecode /DecodeParms << /Predictor 12 /Columns 4 >>", deflate(string(raw)))
+	fmt.Fprintf(&b, "7 0 obj\n%s\nendobj\nstartxref\n%d\n%%%%EOF\n", xref, off7)
+
+	r, err := Open(b.Bytes())
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if _, ok := r.xref[1]; !ok || !r.xref[1].inStream {
+		t.Fatalf("catalog not read from the xref stream: %+v", r.xref)
+	}
+	p, err := r.Page(1)
+	if err != nil || p.Text() != "Compressed objects" {
+		t.Fatalf("page 1 = %q, %v", p.Text(), err)
+	}
+	if _, err := r.Page(2); err == nil {
+		t.Fatal("expected out of range error")
+	}
+}
+
+func TestOpen_ReconstructsBrokenXref(t *testing.T) {
+	doc := twoPageDoc()
+	i := bytes.LastIndex(doc, []byte("startxref"))
+	broken := append(append([]byte(nil), doc[:i]...), []byte("startxref\n999999\n%%EOF\n")...)
+	pages, err := ExtractPages(broken)
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	if len(pages) != 2 || pages[1].Text() != "Page two text" {
+		t.Fatalf("unexpected pages %+v", pages)
+	}
+}
+
+func TestOpen_RejectsBadXrefSubsection(t *testing.T) {
+	doc := twoPageDoc()
+	xref := bytes.Index(doc, []byte("xref\n"))
+	header := doc[xref : xref+bytes.IndexByte(doc[xref+5:], '\n')+6]
+	for name, bad := range map[string][]byte{
+		"oversized": bytes.Replace(doc, header, []byte("xref\n0 99999999999\n"), 1),
+		"truncated": append(append([]byte(nil), doc[:xref]...), "xref\n0 3\n0000000000 65535 f \n0000000009"...),
+	} {
+		r := &Reader{data: bad, xref: map[int]xrefEntry{}}
+		if _, err := r.readXrefSection(int64(xref)); err == nil {
+			t.Errorf("%s: expected an xref error", name)
+		}
+		if len(r.xref) > 3 {
+			t.Errorf("%s: read %d xref entries", name, len(r.xref))
+		}
+	}
+
+	// The objects are still found by scanning the file.
+	pages, err := ExtractPages(bytes.Replace(doc, header, []byte("xref\n0 99999999999\n"), 1))
+	if err != nil || len(pages) != 2 {
+		t.Fatalf("expected the reconstructed pages, got %d, %v", len(pages), err)
+	}
+}
+
+func TestOpen_Errors(t *testing.T) {
+	if _, err := Open([]byte("not a pdf")); err != ErrNotPDF {
+		t.Fatalf("expected ErrNotPDF, got %v", err)
+	}
+	doc := bytes.Replace(twoPageDoc(), []byte("/Root 1 0 R"), []byte("/Root 1 0 R /Encrypt 9 0 R"), 1)
+	if _, err := Open(doc); err != ErrEncrypted {
+		t.Fatalf("expected ErrEncrypted, got %v", err)
+	}
+}
+
+func TestFormXObjectAndInlineImage(t *testing.T) {
+	content := "q 1 0 0 1 100 0 cm /Fm1 Do Q BI /W 2 /H 1 /BPC 8 /CS /G ID \x00EI\xff EI BT /F1 10 Tf 0 0 Td (after) Tj ET"
+	doc := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> /XObject << /Fm1 6 0 R >> >> >>",
+		stream("", []byte(content)),
+		helvetica,
+		stream("/Type /XObject /Subtype /Form /Matrix [1 0 0 1 0 500]", []byte("BT /F1 10 Tf 5 5 Td (in form) Tj ET")),
+	)
+	pages, err := ExtractPages(doc)
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	runs := pages[0].Runs
+	if len(runs) != 2 || runs[0].Text != "in form" || runs[0].X != 105 || runs[0].Y != 505 || runs[1].Text != "after" {
+		t.Fatalf("unexpected runs %+v", runs)
+	}
+}
+
+func TestFilters(t *testing.T) {
+	var a85 bytes.Buffer
+	enc := ascii85.NewEncoder(&a85)
+	enc.Write([]byte("BT (hi) Tj ET"))
+	enc.Close()
+	if out, err := applyFilter("ASCII85Decode", append(a85.Bytes(), '~', '>'), nil); err != nil || string(out) != "BT (hi) Tj ET" {
+		t.Fatalf("ascii85 = %q, %v", out, err)
+	}
+	if out, _ := applyFilter("ASCIIHexDecode", []byte("48 65 6C6c 6F>"), nil); string(out) != "Hello" {
+		t.Fatalf("asciihex = %q", out)
+	}
+	if out := runLengthDecode([]byte{2, 'a', 'b', 'c', 253, 'x', 128}); string(out) != "abcxxxx" {
+		t.Fatalf("runlength = %q", out)
+	}
+	input := "TOBEORNOTTOBEORTOBEORNOT"
+	for _, early := range []int{0, 1} {
+		if out := lzwDecode(lzwEncode([]byte(input), early), early); string(out) != input {
+			t.Fatalf("lzw early=%d = %q", early, out)
+		}
+	}
+}
+
+// lzwEncode is a minimal PDF LZW encoder used to exercise lzwDecode.
+func lzwEncode(data []byte, early int) []byte {
+	dict := map[string]int{}
+	for i := 0; i < 256; i++ {
+		dict[string([]byte{byte(i)})] = i
+	}
+	next, width := 258, 9
+	var out []byte
+	var acc uint32
+	bits := 0
+	emit := func(code int) {
+		acc = acc<<width | uint32(code)
+		bits += width
+		for bits >= 8 {
+			out = append(out, byte(acc>>(bits-8)))
+			bits -= 8
+		}
+	}
+	emit(256)
+	w := ""
+	for _, c := range data {
+		wc := w + string([]byte{c})
+		if _, ok := dict[wc]; ok {
+			w = wc
+			continue
+		}
+		emit(dict[w])
+		dict[wc] = next
+		next++
+		if next+early >= 1<<width && width < 12 {
+			width++
+		}
+		w = string([]byte{c})
+	}
+	emit(dict[w])
+	emit(257)
+	if bits > 0 {
+		out = append(out, byte(acc<<(8-bits)))
+	}
+	return out
+}
@@ -0,0 +1,486 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// ErrEncrypted is returned for encrypted documents, which are not supported.
+var ErrEncrypted = errors.New("pdf: encrypted documents are not supported")
+
+// ErrNotPDF is returned when no objects can be found in the data.
+var ErrNotPDF = errors.New("pdf: no PDF objects found")
+
+// xrefEntry locates an object either at a byte offset or inside an object stream.
+type xrefEntry struct {
+	offset   int64
+	stream   int // object stream number when compressed
+	index    int // index inside the object stream
+	inStream bool
+}
+
+// Reader gives access to the objects and pages of a PDF document.
+type Reader struct {
+	data    []byte
+	xref    map[int]xrefEntry
+	trailer Dict
+	cache   map[int]any
+	objStms map[int]*objStm
+	fonts   map[Ref]*font
+	pages   []Dict
+}
+
+// objStm is a decoded object stream with its (number, offset) header pairs.
+type objStm struct {
+	data  []byte
+	pairs []int
+	first int
+}
+
+// Open parses the cross-reference data of a PDF document. When the xref
+// table is missing or damaged the objects are located by scanning the file.
+func Open(data []byte) (*Reader, error) {
+	r := &Reader{data: data, xref: map[int]xrefEntry{}, cache: map[int]any{}, objStms: map[int]*objStm{}, fonts: map[Ref]*font{}}
+	if err := r.readXref(); err != nil || r.trailer[Name("Root")] == nil {
+		r.xref = map[int]xrefEntry{}
+		r.trailer = nil
+		if err := r.reconstruct(); err != nil {
+			return nil, err
+		}
+	}
+	if r.trailer[Name("Encrypt")] != nil {
+		return nil, ErrEncrypted
+	}
+	root, ok := r.Resolve(r.trailer[Name("Root")]).(Dict)
+	if !ok {
+		return nil, fmt.Errorf("pdf: missing document catalog")
+	}
+	seen := map[Ref]bool{}
+	r.collectPages(root[Name("Pages")], nil, seen, 0)
+	return r, nil
+}
+
+// NumPages returns the number of pages in the document.
+func (r *Reader) NumPages() int { return len(r.pages) }
+
+var startxrefPattern = regexp.MustCompile(`startxref\s+(\d+)`)
+
+func (r *Reader) readXref() error {
+	tail := r.data
+	if len(tail) > 2048 {
+		tail = tail[len(tail)-2048:]
+	}
+	matches := startxrefPattern.FindAllSubmatch(tail, -1)
+	if len(matches) == 0 {
+		return fmt.Errorf("pdf: startxref not found")
+	}
+	off, _ := strconv.ParseInt(string(matches[len(matches)-1][1]), 10, 64)
+
+	visited := map[int64]bool{}
+	for off > 0 && !visited[off] {
+		visited[off] = true
+		trailer, err := r.readXrefSection(off)
+		if err != nil {
+			return err
+		}
+		if r.trailer == nil {
+			r.trailer = trailer
+		}
+		// Hybrid files keep compressed entries in a separate xref stream.
+		if stm, ok := intVal(trailer[Name("XRefStm")]); ok {
+			if _, err := r.readXrefSection(int64(stm)); err != nil {
+				return err
+			}
+		}
+		prev, ok := intVal(trailer[Name("Prev")])
+		if !ok {
+			break
+		}
+		off = int64(prev)
+	}
+	return nil
+}
+
+// readXrefSection reads one xref table or xref stream at off and returns its
+// trailer. Entries already known from a newer section are kept.
+func (r *Reader) readXrefSection(off int64) (Dict, error) {
+	if off < 0 || off >= int64(len(r.data)) {
+		return nil, fmt.Errorf("pdf: xref offset %d out of range", off)
+	}
+	l := &lexer{data: r.data, pos: int(off)}
+	tok, _ := l.token()
+	if tok == keyword("xref") {
+		return r.readXrefTable(l)
+	}
+	l.pos = int(off)
+	_, _, obj, err := r.parseIndirect(l)
+	if err != nil {
+		return nil, err
+	}
+	stm, ok := obj.(Stream)
+	if !ok || stm.Dict[Name("Type")] != Name("XRef") {
+		return nil, fmt.Errorf("pdf: no xref at offset %d", off)
+	}
+	return stm.Dict, r.readXrefStream(stm)
+}
+
+// xrefEntrySize is the length of one xref table entry, end of line included.
+const xrefEntrySize = 20
+
+func (r *Reader) readXrefTable(l *lexer) (Dict, error) {
+	for {
+		tok, ok := l.token()
+		if !ok {
+			return nil, fmt.Errorf("pdf: unterminated xref table")
+		}
+		if tok == keyword("trailer") {
+			obj, err := l.object()
+			if err != nil {
+				return nil, err
+			}
+			d, ok := obj.(Dict)
+			if !ok {
+				return nil, fmt.Errorf("pdf: malformed trailer")
+			}
+			return d, nil
+		}
+		start, ok1 := tok.(int64)
+		cnt, _ := l.token()
+		count, ok2 := cnt.(int64)
+		if !ok1 || !ok2 || start < 0 || count < 0 {
+			return nil, fmt.Errorf("pdf: malformed xref subsection")
+		}
+		// Each entry takes 20 bytes, so a count the rest of the file cannot
+		// hold is corrupt, not a reason to loop.
+		if count > int64(len(l.data)-l.pos)/xrefEntrySize {
+			return nil, fmt.Errorf("pdf: xref subsection of %d entries overruns the file", count)
+		}
+		for i := int64(0); i < count; i++ {
+			o, ok1 := l.token()
+			_, ok2 := l.token() // generation
+			kind, ok3 := l.token()
+			if !ok1 || !ok2 || !ok3 {
+				return nil, fmt.Errorf("pdf: truncated xref subsection")
+			}
+			offset, _ := o.(int64)
+			n := int(start + i)
+			if _, known := r.xref[n]; known {
+				continue
+			}
+			if kind == keyword("n") {
+				r.xref[n] = xrefEntry{offset: offset}
+			} else {
+				r.xref[n] = xrefEntry{offset: -1}
+			}
+		}
+	}
+}
+
+func (r *Reader) readXrefStream(stm Stream) error {
+	data, err := r.decodeStream(stm)
+	if err != nil {
+		return err
+	}
+	w, _ := stm.Dict[Name("W")].(Array)
+	if len(w) != 3 {
+		return fmt.Errorf("pdf: malformed xref stream /W")
+	}
+	var widths [3]int
+	for i := range widths {
+		widths[i], _ = intVal(w[i])
+	}
+	size, _ := intVal(stm.Dict[Name("Size")])
+	index := []int{0, size}
+	if idx, ok := stm.Dict[Name("Index")].(Array); ok {
+		index = index[:0]
+		for _, v := range idx {
+			n, _ := intVal(v)
+			index = append(index, n)
+		}
+	}
+
+	rowLen := widths[0] + widths[1] + widths[2]
+	if rowLen == 0 {
+		return fmt.Errorf("pdf: empty xref stream rows")
+	}
+	field := func(row []byte, i int) int64 {
+		start := 0
+		for k := 0; k < i; k++ {
+			start += widths[k]
+		}
+		var v int64
+		for _, b := range row[start : start+widths[i]] {
+			v = v<<8 | int64(b)
+		}
+		return v
+	}
+
+	pos := 0
+	for s := 0; s+1 < len(index); s += 2 {
+		first, count := index[s], index[s+1]
+		for i := 0; i < count && pos+rowLen <= len(data); i++ {
+			row := data[pos : pos+rowLen]
+			pos += rowLen
+			n := first + i
+			if _, known := r.xref[n]; known {
+				continue
+			}
+			kind := int64(1)
+			if widths[0] > 0 {
+				kind = field(row, 0)
+			}
+			switch kind {
+			case 0:
+				r.xref[n] = xrefEntry{offset: -1}
+			case 1:
+				r.xref[n] = xrefEntry{offset: field(row, 1)}
+			case 2:
+				r.xref[n] = xrefEntry{stream: int(field(row, 1)), index: int(field(row, 2)), inStream: true}
+			}
+		}
+	}
+	return nil
+}
+
+var objPattern = regexp.MustCompile(`(?m)(\d+)\s+(\d+)\s+obj\b`)
+
+// reconstruct rebuilds the xref by scanning for "n g obj" headers. Later
+// definitions win, matching incremental updates.
+func (r *Reader) reconstruct() error {
+	for _, m := range objPattern.FindAllSubmatchIndex(r.data, -1) {
+		n, _ := strconv.Atoi(string(r.data[m[2]:m[3]]))
+		r.xref[n] = xrefEntry{offset: int64(m[0])}
+	}
+	if len(r.xref) == 0 {
+		return ErrNotPDF
+	}
+	r.cache = map[int]any{}
+
+	// Objects inside object streams are only reachable through the streams.
+	for n := range r.xref {
+		stm, ok := r.object(n).(Stream)
+		if !ok || stm.Dict[Name("Type")] != Name("ObjStm") {
+			continue
+		}
+		count, _ := intVal(stm.Dict[Name("N")])
+		for i := 0; i < count; i++ {
+			if num, ok := r.objStmNumber(n, i); ok {
+				if _, known := r.xref[num]; !known {
+					r.xref[num] = xrefEntry{stream: n, index: i, inStream: true}
+				}
+			}
+		}
+	}
+
+	r.trailer = Dict{}
+	if i := bytes.LastIndex(r.data, []byte("trailer")); i >= 0 {
+		l := &lexer{data: r.data, pos: i + len("trailer")}
+		if obj, err := l.object(); err == nil {
+			if d, ok := obj.(Dict); ok {
+				r.trailer = d
+			}
+		}
+	}
+	if r.trailer[Name("Root")] == nil {
+		for n := range r.xref {
+			switch d := r.object(n).(type) {
+			case Dict:
+				if d[Name("Type")] == Name("Catalog") {
+					r.trailer[Name("Root")] = Ref{Num: n}
+				}
+			case Stream:
+				if d.Dict[Name("Type")] == Name("XRef") && d.Dict[Name("Root")] != nil {
+					r.trailer = d.Dict
+				}
+			}
+			if r.trailer[Name("Root")] != nil {
+				break
+			}
+		}
+	}
+	if r.trailer[Name("Root")] == nil {
+		return fmt.Errorf("pdf: document catalog not found")
+	}
+	return nil
+}
+
+// parseIndirect parses "n g obj ... endobj" at the lexer position.
+func (r *Reader) parseIndirect(l *lexer) (num, gen int, obj any, err error) {
+	n, _ := l.token()
+	g, _ := l.token()
+	kw, _ := l.token()
+	nv, ok1 := n.(int64)
+	gv, ok2 := g.(int64)
+	if !ok1 || !ok2 || kw != keyword("obj") {
+		return 0, 0, nil, fmt.Errorf("pdf: malformed object header")
+	}
+	obj, err = l.object()
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	if d, ok := obj.(Dict); ok {
+		save := l.pos
+		if tok, _ := l.token(); tok == keyword("stream") {
+			obj = Stream{Dict: d, Raw: r.streamData(l, d)}
+		} else {
+			l.pos = save
+		}
+	}
+	return int(nv), int(gv), obj, nil
+}
+
+// streamData returns the raw bytes after the "stream" keyword, trusting
+// /Length only when "endstream" follows it.
+func (r *Reader) streamData(l *lexer, d Dict) []byte {
+	start := l.pos
+	if start < len(r.data) && r.data[start] == '\r' {
+		start++
+	}
+	if start < len(r.data) && r.data[start] == '\n' {
+		start++
+	}
+	if length, ok := intVal(r.Resolve(d[Name("Length")])); ok && length >= 0 && start+length <= len(r.data) {
+		rest := r.data[start+length:]
+		if i := bytes.Index(rest, []byte("endstream")); i >= 0 && len(bytes.TrimSpace(rest[:i])) == 0 {
+			l.pos = start + length + i + len("endstream")
+			return r.data[start : start+length]
+		}
+	}
+	end := bytes.Index(r.data[start:], []byte("endstream"))
+	if end < 0 {
+		l.pos = len(r.data)
+		return r.data[start:]
+	}
+	l.pos = start + end + len("endstream")
+	return bytes.TrimRight(r.data[start:start+end], "\r\n")
+}
+
+// Resolve follows indirect references until a direct object is reached.
+func (r *Reader) Resolve(v any) any {
+	for i := 0; i < 32; i++ {
+		ref, ok := v.(Ref)
+		if !ok {
+			return v
+		}
+		v = r.object(ref.Num)
+	}
+	return nil
+}
+
+func (r *Reader) object(n int) any {
+	if v, ok := r.cache[n]; ok {
+		return v
+	}
+	r.cache[n] = nil // guards against reference cycles
+	e, ok := r.xref[n]
+	var v any
+	switch {
+	case !ok:
+	case e.inStream:
+		v = r.objStmObject(e.stream, e.index)
+	case e.offset >= 0 && e.offset < int64(len(r.data)):
+		l := &lexer{data: r.data, pos: int(e.offset)}
+		if _, _, obj, err := r.parseIndirect(l); err == nil {
+			v = obj
+		}
+	}
+	r.cache[n] = v
+	return v
+}
+
+// objStmHeader returns the decoded object stream n, or nil.
+func (r *Reader) objStmHeader(n int) *objStm {
+	if o, ok := r.objStms[n]; ok {
+		return o
+	}
+	r.objStms[n] = nil
+	stm, ok := r.object(n).(Stream)
+	if !ok {
+		return nil
+	}
+	data, err := r.decodeStream(stm)
+	if err != nil {
+		return nil
+	}
+	o := &objStm{data: data}
+	o.first, _ = intVal(stm.Dict[Name("First")])
+	count, _ := intVal(stm.Dict[Name("N")])
+	l := &lexer{data: data}
+	for i := 0; i < 2*count; i++ {
+		tok, _ := l.token()
+		v, ok := tok.(int64)
+		if !ok {
+			return nil
+		}
+		o.pairs = append(o.pairs, int(v))
+	}
+	r.objStms[n] = o
+	return o
+}
+
+func (r *Reader) objStmNumber(n, index int) (int, bool) {
+	o := r.objStmHeader(n)
+	if o == nil || 2*index >= len(o.pairs) {
+		return 0, false
+	}
+	return o.pairs[2*index], true
+}
+
+func (r *Reader) objStmObject(n, index int) any {
+	o := r.objStmHeader(n)
+	if o == nil || 2*index+1 >= len(o.pairs) {
+		return nil
+	}
+	off := o.first + o.pairs[2*index+1]
+	if off < 0 || off >= len(o.data) {
+		return nil
+	}
+	l := &lexer{data: o.data, pos: off}
+	obj, err := l.object()
+	if err != nil {
+		return nil
+	}
+	return obj
+}
+
+// collectPages walks the page tree, copying inherited attributes onto each page.
+func (r *Reader) collectPages(node any, inherited Dict, seen map[Ref]bool, depth int) {
+	if ref, ok := node.(Ref); ok {
+		if seen[ref] {
+			return
+		}
+		seen[ref] = true
+	}
+	d, ok := r.Resolve(node).(Dict)
+	if !ok || depth > 64 {
+		return
+	}
+	attrs := Dict{}
+	for k, v := range inherited {
+		attrs[k] = v
+	}
+	for _, k := range []Name{"Resources", "MediaBox", "CropBox", "Rotate"} {
+		if v, ok := d[k]; ok {
+			attrs[k] = v
+		}
+	}
+	if kids, ok := r.Resolve(d[Name("Kids")]).(Array); ok && d[Name("Type")] != Name("Page") {
+		for _, kid := range kids {
+			r.collectPages(kid, attrs, seen, depth+1)
+		}
+		return
+	}
+	page := Dict{}
+	for k, v := range d {
+		page[k] = v
+	}
+	for k, v := range attrs {
+		if _, ok := page[k]; !ok {
+			page[k] = v
+		}
+	}
+	r.pages = append(r.pages, page)
+}
@@ -0,0 +1,219 @@
+package pdf
+
+// Encoding tables derived from the cp1252 and Mac OS Roman code pages and
+// the Unicode names of accented Latin letters.
+
+// winAnsiHigh maps WinAnsiEncoding bytes 0x80-0xFF to runes (0 = undefined).
+var winAnsiHigh = [128]rune{
+	0x20ac, 0, 0x201a, 0x0192, 0x201e, 0x2026, 0x2020, 0x2021,
+	0x02c6, 0x2030, 0x0160, 0x2039, 0x0152, 0, 0x017d, 0,
+	0, 0x2018, 0x2019, 0x201c, 0x201d, 0x2022, 0x2013, 0x2014,
+	0x02dc, 0x2122, 0x0161, 0x203a, 0x0153, 0, 0x017e, 0x0178,
+	0x00a0, 0x00a1, 0x00a2, 0x00a3, 0x00a4, 0x00a5, 0x00a6, 0x00a7,
+	0x00a8, 0x00a9, 0x00aa, 0x00ab, 0x00ac, 0x00ad, 0x00ae, 0x00af,
+	0x00b0, 0x00b1, 0x00b2, 0x00b3, 0x00b4, 0x00b5, 0x00b6, 0x00b7,
+	0x00b8, 0x00b9, 0x00ba, 0x00bb, 0x00bc, 0x00bd, 0x00be, 0x00bf,
+	0x00c0, 0x00c1, 0x00c2, 0x00c3, 0x00c4, 0x00c5, 0x00c6, 0x00c7,
+	0x00c8, 0x00c9, 0x00ca, 0x00cb, 0x00cc, 0x00cd, 0x00ce, 0x00cf,
+	0x00d0, 0x00d1, 0x00d2, 0x00d3, 0x00d4, 0x00d5, 0x00d6, 0x00d7,
+	0x00d8, 0x00d9, 0x00da, 0x00db, 0x00dc, 0x00dd, 0x00de, 0x00df,
+	0x00e0, 0x00e1, 0x00e2, 0x00e3, 0x00e4, 0x00e5, 0x00e6, 0x00e7,
+	0x00e8, 0x00e9, 0x00ea, 0x00eb, 0x00ec, 0x00ed, 0x00ee, 0x00ef,
+	0x00f0, 0x00f1, 0x00f2, 0x00f3, 0x00f4, 0x00f5, 0x00f6, 0x00f7,
+	0x00f8, 0x00f9, 0x00fa, 0x00fb, 0x00fc, 0x00fd, 0x00fe, 0x00ff,
+}
+
+// macRomanHigh maps MacRomanEncoding bytes 0x80-0xFF to runes.
+var macRomanHigh = [128]rune{
+	0x00c4, 0x00c5, 0x00c7, 0x00c9, 0x00d1, 0x00d6, 0x00dc, 0x00e1,
+	0x00e0, 0x00e2, 0x00e4, 0x00e3, 0x00e5, 0x00e7, 0x00e9, 0x00e8,
+	0x00ea, 0x00eb, 0x00ed, 0x00ec, 0x00ee, 0x00ef, 0x00f1, 0x00f3,
+	0x00f2, 0x00f4, 0x00f6, 0x00f5, 0x00fa, 0x00f9, 0x00fb, 0x00fc,
+	0x2020, 0x00b0, 0x00a2, 0x00a3, 0x00a7, 0x2022, 0x00b6, 0x00df,
+	0x00ae, 0x00a9, 0x2122, 0x00b4, 0x00a8, 0x2260, 0x00c6, 0x00d8,
+	0x221e, 0x00b1, 0x2264, 0x2265, 0x00a5, 0x00b5, 0x2202, 0x2211,
+	0x220f, 0x03c0, 0x222b, 0x00aa, 0x00ba, 0x03a9, 0x00e6, 0x00f8,
+	0x00bf, 0x00a1, 0x00ac, 0x221a, 0x0192, 0x2248, 0x2206, 0x00ab,
+	0x00bb, 0x2026, 0x00a0, 0x00c0, 0x00c3, 0x00d5, 0x0152, 0x0153,
+	0x2013, 0x2014, 0x201c, 0x201d, 0x2018, 0x2019, 0x00f7, 0x25ca,
+	0x00ff, 0x0178, 0x2044, 0x20ac, 0x2039, 0x203a, 0xfb01, 0xfb02,
+	0x2021, 0x00b7, 0x201a, 0x201e, 0x2030, 0x00c2, 0x00ca, 0x00c1,
+	0x00cb, 0x00c8, 0x00cd, 0x00ce, 0x00cf, 0x00cc, 0x00d3, 0x00d4,
+	0xf8ff, 0x00d2, 0x00da, 0x00db, 0x00d9, 0x0131, 0x02c6, 0x02dc,
+	0x00af, 0x02d8, 0x02d9, 0x02da, 0x00b8, 0x02dd, 0x02db, 0x02c7,
+}
+
+// accentedGlyphs maps Adobe glyph names of accented Latin letters to runes.
+var accentedGlyphs = map[string]rune{
+	"Aacute":        0x00c1,
+	"Abreve":        0x0102,
+	"Acircumflex":   0x00c2,
+	"Adieresis":     0x00c4,
+	"Agrave":        0x00c0,
+	"Amacron":       0x0100,
+	"Aogonek":       0x0104,
+	"Aring":         0x00c5,
+	"Atilde":        0x00c3,
+	"Cacute":        0x0106,
+	"Ccaron":        0x010c,
+	"Ccedilla":      0x00c7,
+	"Ccircumflex":   0x0108,
+	"Cdotaccent":    0x010a,
+	"Dcaron":        0x010e,
+	"Dslash":        0x0110,
+	"Eacute":        0x00c9,
+	"Ebreve":        0x0114,
+	"Ecaron":        0x011a,
+	"Ecircumflex":   0x00ca,
+	"Edieresis":     0x00cb,
+	"Edotaccent":    0x0116,
+	"Egrave":        0x00c8,
+	"Emacron":       0x0112,
+	"Eogonek":       0x0118,
+	"Gbreve":        0x011e,
+	"Gcedilla":      0x0122,
+	"Gcircumflex":   0x011c,
+	"Gdotaccent":    0x0120,
+	"Hcircumflex":   0x0124,
+	"Hslash":        0x0126,
+	"Iacute":        0x00cd,
+	"Ibreve":        0x012c,
+	"Icircumflex":   0x00ce,
+	"Idieresis":     0x00cf,
+	"Idotaccent":    0x0130,
+	"Igrave":        0x00cc,
+	"Imacron":       0x012a,
+	"Iogonek":       0x012e,
+	"Itilde":        0x0128,
+	"Jcircumflex":   0x0134,
+	"Kcedilla":      0x0136,
+	"Lacute":        0x0139,
+	"Lcaron":        0x013d,
+	"Lcedilla":      0x013b,
+	"Lslash":        0x0141,
+	"Nacute":        0x0143,
+	"Ncaron":        0x0147,
+	"Ncedilla":      0x0145,
+	"Ntilde":        0x00d1,
+	"Oacute":        0x00d3,
+	"Obreve":        0x014e,
+	"Ocircumflex":   0x00d4,
+	"Odieresis":     0x00d6,
+	"Ograve":        0x00d2,
+	"Ohungarumlaut": 0x0150,
+	"Omacron":       0x014c,
+	"Oslash":        0x00d8,
+	"Otilde":        0x00d5,
+	"Racute":        0x0154,
+	"Rcaron":        0x0158,
+	"Rcedilla":      0x0156,
+	"Sacute":        0x015a,
+	"Scaron":        0x0160,
+	"Scedilla":      0x015e,
+	"Scircumflex":   0x015c,
+	"Tcaron":        0x0164,
+	"Tcedilla":      0x0162,
+	"Tslash":        0x0166,
+	"Uacute":        0x00da,
+	"Ubreve":        0x016c,
+	"Ucircumflex":   0x00db,
+	"Udieresis":     0x00dc,
+	"Ugrave":        0x00d9,
+	"Uhungarumlaut": 0x0170,
+	"Umacron":       0x016a,
+	"Uogonek":       0x0172,
+	"Uring":         0x016e,
+	"Utilde":        0x0168,
+	"Wcircumflex":   0x0174,
+	"Yacute":        0x00dd,
+	"Ycircumflex":   0x0176,
+	"Ydieresis":     0x0178,
+	"Zacute":        0x0179,
+	"Zcaron":        0x017d,
+	"Zdotaccent":    0x017b,
+	"aacute":        0x00e1,
+	"abreve":        0x0103,
+	"acircumflex":   0x00e2,
+	"adieresis":     0x00e4,
+	"agrave":        0x00e0,
+	"amacron":       0x0101,
+	"aogonek":       0x0105,
+	"aring":         0x00e5,
+	"atilde":        0x00e3,
+	"cacute":        0x0107,
+	"ccaron":        0x010d,
+	"ccedilla":      0x00e7,
+	"ccircumflex":   0x0109,
+	"cdotaccent":    0x010b,
+	"dcaron":        0x010f,
+	"dslash":        0x0111,
+	"eacute":        0x00e9,
+	"ebreve":        0x0115,
+	"ecaron":        0x011b,
+	"ecircumflex":   0x00ea,
+	"edieresis":     0x00eb,
+	"edotaccent":    0x0117,
+	"egrave":        0x00e8,
+	"emacron":       0x0113,
+	"eogonek":       0x0119,
+	"gbreve":        0x011f,
+	"gcedilla":      0x0123,
+	"gcircumflex":   0x011d,
+	"gdotaccent":    0x0121,
+	"hcircumflex":   0x0125,
+	"hslash":        0x0127,
+	"iacute":        0x00ed,
+	"ibreve":        0x012d,
+	"icircumflex":   0x00ee,
+	"idieresis":     0x00ef,
+	"igrave":        0x00ec,
+	"imacron":       0x012b,
+	"iogonek":       0x012f,
+	"itilde":        0x0129,
+	"jcircumflex":   0x0135,
+	"kcedilla":      0x0137,
+	"lacute":        0x013a,
+	"lcaron":        0x013e,
+	"lcedilla":      0x013c,
+	"lslash":        0x0142,
+	"nacute":        0x0144,
+	"ncaron":        0x0148,
+	"ncedilla":      0x0146,
+	"ntilde":        0x00f1,
+	"oacute":        0x00f3,
+	"obreve":        0x014f,
+	"ocircumflex":   0x00f4,
+	"odieresis":     0x00f6,
+	"ograve":        0x00f2,
+	"ohungarumlaut": 0x0151,
+	"omacron":       0x014d,
+	"oslash":        0x00f8,
+	"otilde":        0x00f5,
+	"racute":        0x0155,
+	"rcaron":        0x0159,
+	"rcedilla":      0x0157,
+	"sacute":        0x015b,
+	"scaron":        0x0161,
+	"scedilla":      0x015f,
+	"scircumflex":   0x015d,
+	"tcaron":        0x0165,
+	"tcedilla":      0x0163,
+	"tslash":        0x0167,
+	"uacute":        0x00fa,
+	"ubreve":        0x016d,
+	"ucircumflex":   0x00fb,
+	"udieresis":     0x00fc,
+	"ugrave":        0x00f9,
+	"uhungarumlaut": 0x0171,
+	"umacron":       0x016b,
+	"uogonek":       0x0173,
+	"uring":         0x016f,
+	"utilde":        0x0169,
+	"wcircumflex":   0x0175,
+	"yacute":        0x00fd,
+	"ycircumflex":   0x0177,
+	"ydieresis":     0x00ff,
+	"zacute":        0x017a,
+	"zcaron":        0x017e,
+	"zdotaccent":    0x017c,
+}